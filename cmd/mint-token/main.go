@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/auth"
+	"github.com/cohesion-education/api/pkg/cohesioned/config"
+	"github.com/joho/godotenv"
+)
+
+//mint-token prints a token signed by the local issuer so the API can be called without Auth0 when AUTH_MODE=local
+func main() {
+	email := flag.String("email", "", "email claim of the token (required)")
+	name := flag.String("name", "", "name claim of the token")
+	sub := flag.String("sub", "", "sub claim of the token - defaults to local|<email>")
+	roles := flag.String("roles", "", "comma separated roles to include in the token, e.g. admin,content_editor")
+	ttl := flag.Duration("ttl", 24*time.Hour, "how long the token is valid for")
+	flag.Parse()
+
+	if len(*email) == 0 {
+		log.Fatal("-email is required")
+	}
+
+	godotenv.Load()
+
+	authConfig, err := config.NewAuthConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if !authConfig.IsLocal() {
+		log.Fatalf("AUTH_MODE must be %s to mint tokens", config.AuthModeLocal)
+	}
+
+	issuer, err := auth.NewLocalIssuer(authConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	claims := auth.LocalClaims{
+		Email:         *email,
+		EmailVerified: true,
+		FullName:      *name,
+	}
+
+	for _, r := range strings.Split(*roles, ",") {
+		r = strings.TrimSpace(r)
+		if len(r) == 0 {
+			continue
+		}
+
+		role := cohesioned.Role(r)
+		if !role.IsValid() {
+			log.Fatalf("Unknown role %s - expected one of %v", r, cohesioned.Roles())
+		}
+
+		claims.Roles = append(claims.Roles, role)
+	}
+
+	if len(*sub) == 0 {
+		*sub = "local|" + *email
+	}
+
+	token, err := issuer.Mint(*sub, claims, *ttl)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(token)
+}
@@ -0,0 +1,66 @@
+package fakes
+
+import (
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+type FakeAPIKeyRepo struct {
+	k           *cohesioned.APIKey
+	list        []*cohesioned.APIKey
+	id          int64
+	err         error
+	SavedHash   string
+	TouchedWith time.Time
+}
+
+func (r *FakeAPIKeyRepo) GetReturns(k *cohesioned.APIKey, err error) {
+	r.k = k
+	r.err = err
+}
+
+func (r *FakeAPIKeyRepo) FindByHashReturns(k *cohesioned.APIKey, err error) {
+	r.k = k
+	r.err = err
+}
+
+func (r *FakeAPIKeyRepo) ListReturns(list []*cohesioned.APIKey, err error) {
+	r.list = list
+	r.err = err
+}
+
+func (r *FakeAPIKeyRepo) SaveReturns(id int64, err error) {
+	r.id = id
+	r.err = err
+}
+
+func (r *FakeAPIKeyRepo) Get(id int64) (*cohesioned.APIKey, error) {
+	return r.k, r.err
+}
+
+func (r *FakeAPIKeyRepo) FindByHash(hash string) (*cohesioned.APIKey, error) {
+	return r.k, r.err
+}
+
+func (r *FakeAPIKeyRepo) List() ([]*cohesioned.APIKey, error) {
+	return r.list, r.err
+}
+
+func (r *FakeAPIKeyRepo) Save(k *cohesioned.APIKey, hash string) (int64, error) {
+	r.SavedHash = hash
+	return r.id, r.err
+}
+
+func (r *FakeAPIKeyRepo) SetPermissions(id int64, permissions []cohesioned.Permission) error {
+	return r.err
+}
+
+func (r *FakeAPIKeyRepo) Revoke(id int64, revokedBy int64) error {
+	return r.err
+}
+
+func (r *FakeAPIKeyRepo) Touch(id int64, lastUsed time.Time) error {
+	r.TouchedWith = lastUsed
+	return r.err
+}
@@ -0,0 +1,50 @@
+package fakes
+
+import (
+	"fmt"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/audit"
+)
+
+type FakeAuditRepo struct {
+	list    []*cohesioned.ImpersonationEvent
+	err     error
+	saveErr error
+	Saved   []*cohesioned.ImpersonationEvent
+}
+
+//SaveReturns sets the error returned by Save
+func (r *FakeAuditRepo) SaveReturns(err error) {
+	r.saveErr = err
+}
+
+func (r *FakeAuditRepo) ListReturns(list []*cohesioned.ImpersonationEvent, err error) {
+	r.list = list
+	r.err = err
+}
+
+func (r *FakeAuditRepo) Save(e *cohesioned.ImpersonationEvent) (int64, error) {
+	if r.saveErr != nil {
+		return 0, r.saveErr
+	}
+
+	//a copy is kept so the status it was saved with can be checked
+	saved := *e
+	r.Saved = append(r.Saved, &saved)
+	return int64(len(r.Saved)), nil
+}
+
+//UpdateStatus sets the status of the saved event with the given id
+func (r *FakeAuditRepo) UpdateStatus(id int64, status int) error {
+	if id < 1 || int(id) > len(r.Saved) {
+		return fmt.Errorf("there is no impersonation event %d", id)
+	}
+
+	r.Saved[id-1].Status = status
+	return nil
+}
+
+func (r *FakeAuditRepo) List(filter audit.Filter) ([]*cohesioned.ImpersonationEvent, error) {
+	return r.list, r.err
+}
@@ -2,22 +2,34 @@ package fakes
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/cohesion-education/api/pkg/cohesioned/config"
 )
 
 type FakeAwsConfig struct {
 	err         error
-	signedURL   string
 	videoBucket string
+	transcoding *config.TranscodingConfig
+	storage     *config.StorageConfig
+	upload      *config.UploadConfig
+}
+
+func (cfg *FakeAwsConfig) GetTranscodingConfigReturns(transcoding *config.TranscodingConfig) {
+	cfg.transcoding = transcoding
 }
 
 func (cfg *FakeAwsConfig) GetVideoBucketReturns(bucket string) {
 	cfg.videoBucket = bucket
 }
-func (cfg *FakeAwsConfig) GetSignedURLReturns(signedURL string, err error) {
-	cfg.signedURL = signedURL
-	cfg.err = err
+
+func (cfg *FakeAwsConfig) GetStorageConfigReturns(storage *config.StorageConfig) {
+	cfg.storage = storage
+}
+
+func (cfg *FakeAwsConfig) GetUploadConfigReturns(upload *config.UploadConfig) {
+	cfg.upload = upload
 }
 
 func (cfg *FakeAwsConfig) NewSession() (*session.Session, error) {
@@ -29,6 +41,27 @@ func (cfg *FakeAwsConfig) DialRDS() (*sql.DB, error) {
 func (cfg *FakeAwsConfig) GetVideoBucket() string {
 	return cfg.videoBucket
 }
-func (cfg *FakeAwsConfig) GetSignedURL(bucket, objectName string) (string, error) {
-	return cfg.signedURL, nil
+func (cfg *FakeAwsConfig) GetTranscodingConfig() *config.TranscodingConfig {
+	if cfg.transcoding == nil {
+		return &config.TranscodingConfig{Transcoder: config.TranscoderNone}
+	}
+
+	return cfg.transcoding
+}
+
+func (cfg *FakeAwsConfig) GetStorageConfig() *config.StorageConfig {
+	if cfg.storage == nil {
+		return &config.StorageConfig{Backend: config.StorageS3, BaseURL: "http://localhost:3001", SignedURLTTL: 15 * time.Minute, UploadURLTTL: time.Hour}
+	}
+
+	return cfg.storage
+}
+
+//GetUploadConfig allows any file by default
+func (cfg *FakeAwsConfig) GetUploadConfig() *config.UploadConfig {
+	if cfg.upload == nil {
+		return &config.UploadConfig{}
+	}
+
+	return cfg.upload
 }
@@ -0,0 +1,125 @@
+package fakes
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned/storage"
+)
+
+type FakeBlobStore struct {
+	info      *storage.ObjectInfo
+	list      []*storage.ObjectInfo
+	signedURL string
+	uploadID  string
+	err       error
+	//Stored holds what was written with Put, by key
+	Stored map[string][]byte
+	//Deleted holds the keys passed to Delete
+	Deleted []string
+	//Completed holds the parts passed to CompleteMultipartUpload
+	Completed []*storage.CompletedPart
+	Aborted   bool
+}
+
+func (s *FakeBlobStore) HeadReturns(info *storage.ObjectInfo, err error) {
+	s.info = info
+	s.err = err
+}
+
+func (s *FakeBlobStore) ListReturns(list []*storage.ObjectInfo, err error) {
+	s.list = list
+	s.err = err
+}
+
+func (s *FakeBlobStore) SignedURLReturns(signedURL string, err error) {
+	s.signedURL = signedURL
+	s.err = err
+}
+
+func (s *FakeBlobStore) CreateMultipartUploadReturns(uploadID string, err error) {
+	s.uploadID = uploadID
+	s.err = err
+}
+
+func (s *FakeBlobStore) Put(bucket, key string, body io.Reader, contentType string) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if s.Stored == nil {
+		s.Stored = make(map[string][]byte)
+	}
+
+	s.Stored[key] = data
+	return nil
+}
+
+//Get returns what was written with Put, or ErrNotFound
+func (s *FakeBlobStore) Get(bucket, key string) (io.ReadCloser, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	data, ok := s.Stored[key]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *FakeBlobStore) Delete(bucket, key string) error {
+	s.Deleted = append(s.Deleted, key)
+	return s.err
+}
+
+func (s *FakeBlobStore) Head(bucket, key string) (*storage.ObjectInfo, error) {
+	return s.info, s.err
+}
+
+func (s *FakeBlobStore) SignedGetURL(bucket, key string, ttl time.Duration) (string, error) {
+	return s.signedURL, s.err
+}
+
+func (s *FakeBlobStore) SignedPutURL(bucket, key, contentType string, ttl time.Duration) (string, error) {
+	return s.signedURL, s.err
+}
+
+//List returns the objects given to ListReturns that start with prefix
+func (s *FakeBlobStore) List(bucket, prefix string) ([]*storage.ObjectInfo, error) {
+	var list []*storage.ObjectInfo
+	for _, obj := range s.list {
+		if strings.HasPrefix(obj.Key, prefix) {
+			list = append(list, obj)
+		}
+	}
+
+	return list, s.err
+}
+
+func (s *FakeBlobStore) CreateMultipartUpload(bucket, key, contentType string) (string, error) {
+	return s.uploadID, s.err
+}
+
+func (s *FakeBlobStore) SignedPartURL(bucket, key, uploadID string, partNumber int64, ttl time.Duration) (string, error) {
+	return s.signedURL, s.err
+}
+
+func (s *FakeBlobStore) CompleteMultipartUpload(bucket, key, uploadID string, parts []*storage.CompletedPart) error {
+	s.Completed = parts
+	return s.err
+}
+
+func (s *FakeBlobStore) AbortMultipartUpload(bucket, key, uploadID string) error {
+	s.Aborted = true
+	return s.err
+}
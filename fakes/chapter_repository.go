@@ -0,0 +1,53 @@
+package fakes
+
+import (
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+type FakeChapterRepo struct {
+	lastID int64
+	err    error
+	//Chapters holds what was saved with SaveChapter, less any that were deleted
+	Chapters []*cohesioned.Chapter
+}
+
+func (r *FakeChapterRepo) ListChaptersReturns(list []*cohesioned.Chapter, err error) {
+	r.Chapters = list
+	r.err = err
+}
+
+func (r *FakeChapterRepo) ListChapters(videoID int64) ([]*cohesioned.Chapter, error) {
+	return r.Chapters, r.err
+}
+
+func (r *FakeChapterRepo) SaveChapter(c *cohesioned.Chapter) (int64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	r.lastID++
+	r.Chapters = append(r.Chapters, c)
+	return r.lastID, nil
+}
+
+func (r *FakeChapterRepo) UpdateChapter(c *cohesioned.Chapter) error {
+	for i, existing := range r.Chapters {
+		if existing.ID == c.ID {
+			r.Chapters[i] = c
+		}
+	}
+
+	return r.err
+}
+
+func (r *FakeChapterRepo) DeleteChapter(videoID, id int64) error {
+	var kept []*cohesioned.Chapter
+	for _, c := range r.Chapters {
+		if c.ID != id {
+			kept = append(kept, c)
+		}
+	}
+
+	r.Chapters = kept
+	return r.err
+}
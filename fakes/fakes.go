@@ -41,6 +41,18 @@ func FakeAdmin() *cohesioned.Profile {
 		EmailVerified: true,
 		Sub:           "abc|123",
 		PictureURL:    "https://pbs.twimg.com/profile_images/2043299214/Adam_Avatar_Small_400x400.jpg",
+		Roles:         []cohesioned.Role{cohesioned.RoleAdmin},
+	}
+}
+
+func FakeContentEditor() *cohesioned.Profile {
+	return &cohesioned.Profile{
+		ID:            3,
+		FullName:      "Test Editor",
+		Email:         "editor@contractor.com",
+		EmailVerified: true,
+		Sub:           "abc|456",
+		Roles:         []cohesioned.Role{cohesioned.RoleContentEditor},
 	}
 }
 
@@ -0,0 +1,80 @@
+package fakes
+
+import (
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+type FakeFAQRepo struct {
+	lastID int64
+	err    error
+	//FAQs holds what was saved with SaveFAQ or UpdateFAQ, less any that were deleted
+	FAQs []*cohesioned.FAQ
+}
+
+func (r *FakeFAQRepo) ListQuestionsReturns(list []*cohesioned.FAQ, err error) {
+	r.FAQs = list
+	r.err = err
+}
+
+func (r *FakeFAQRepo) ListFAQs(videoID int64, statuses ...cohesioned.FAQStatus) ([]*cohesioned.FAQ, error) {
+	var list []*cohesioned.FAQ
+	for _, f := range r.FAQs {
+		for _, status := range statuses {
+			if f.VideoID == videoID && f.Status == status {
+				list = append(list, f)
+			}
+		}
+	}
+
+	return list, r.err
+}
+
+func (r *FakeFAQRepo) GetFAQ(videoID, id int64) (*cohesioned.FAQ, error) {
+	for _, f := range r.FAQs {
+		if f.VideoID == videoID && f.ID == id {
+			found := *f
+			return &found, r.err
+		}
+	}
+
+	return nil, r.err
+}
+
+func (r *FakeFAQRepo) ListQuestions(opts *cohesioned.ListOptions) ([]*cohesioned.FAQ, int64, error) {
+	return r.FAQs, int64(len(r.FAQs)), r.err
+}
+
+func (r *FakeFAQRepo) SaveFAQ(f *cohesioned.FAQ) (int64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	r.lastID++
+	saved := *f
+	saved.ID = r.lastID
+	r.FAQs = append(r.FAQs, &saved)
+	return r.lastID, nil
+}
+
+func (r *FakeFAQRepo) UpdateFAQ(f *cohesioned.FAQ) error {
+	for i, existing := range r.FAQs {
+		if existing.ID == f.ID {
+			updated := *f
+			r.FAQs[i] = &updated
+		}
+	}
+
+	return r.err
+}
+
+func (r *FakeFAQRepo) DeleteFAQ(videoID, id int64) error {
+	var kept []*cohesioned.FAQ
+	for _, f := range r.FAQs {
+		if f.ID != id {
+			kept = append(kept, f)
+		}
+	}
+
+	r.FAQs = kept
+	return r.err
+}
@@ -0,0 +1,43 @@
+package fakes
+
+import (
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+type FakeNotificationRepo struct {
+	list  []*cohesioned.Notification
+	found bool
+	err   error
+	//Saved holds every notification given to Save
+	Saved []*cohesioned.Notification
+	//ListedFor holds the user ID given to the last call to List
+	ListedFor int64
+}
+
+func (r *FakeNotificationRepo) ListReturns(list []*cohesioned.Notification, err error) {
+	r.list = list
+	r.err = err
+}
+
+func (r *FakeNotificationRepo) MarkReadReturns(found bool, err error) {
+	r.found = found
+	r.err = err
+}
+
+func (r *FakeNotificationRepo) Save(n *cohesioned.Notification) (int64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	r.Saved = append(r.Saved, n)
+	return int64(len(r.Saved)), nil
+}
+
+func (r *FakeNotificationRepo) List(userID int64, opts *cohesioned.ListOptions) ([]*cohesioned.Notification, int64, error) {
+	r.ListedFor = userID
+	return r.list, int64(len(r.list)), r.err
+}
+
+func (r *FakeNotificationRepo) MarkRead(userID, id int64) (bool, error) {
+	return r.found, r.err
+}
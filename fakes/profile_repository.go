@@ -3,43 +3,93 @@ package fakes
 import "github.com/cohesion-education/api/pkg/cohesioned"
 
 type FakeProfileRepo struct {
-	id      int64
-	profile *cohesioned.Profile
-	list    []*cohesioned.Profile
-	err     error
+	id             int64
+	profile        *cohesioned.Profile
+	byEmail        *cohesioned.Profile
+	list           []*cohesioned.Profile
+	roles          []cohesioned.Role
+	saveErr        error
+	listErr        error
+	updateErr      error
+	findByEmailErr error
+	getErr         error
+	listRolesErr   error
+	addRoleErr     error
+	removeRoleErr  error
+	//Granted holds the roles given to AddRole
+	Granted []cohesioned.Role
 }
 
 func (r *FakeProfileRepo) SaveReturns(id int64, e error) {
 	r.id = id
-	r.err = e
+	r.saveErr = e
 }
 
 func (r *FakeProfileRepo) ListReturns(list []*cohesioned.Profile, e error) {
 	r.list = list
-	r.err = e
+	r.listErr = e
 }
 
 func (r *FakeProfileRepo) UpdateReturns(e error) {
-	r.err = e
+	r.updateErr = e
 }
 
 func (r *FakeProfileRepo) FindByEmailReturns(p *cohesioned.Profile, e error) {
+	r.byEmail = p
+	r.findByEmailErr = e
+}
+
+func (r *FakeProfileRepo) GetReturns(p *cohesioned.Profile, e error) {
 	r.profile = p
-	r.err = e
+	r.getErr = e
+}
+
+func (r *FakeProfileRepo) ListRolesReturns(roles []cohesioned.Role, e error) {
+	r.roles = roles
+	r.listRolesErr = e
+}
+
+func (r *FakeProfileRepo) AddRoleReturns(e error) {
+	r.addRoleErr = e
+}
+
+func (r *FakeProfileRepo) RemoveRoleReturns(e error) {
+	r.removeRoleErr = e
 }
 
 func (r *FakeProfileRepo) Save(p *cohesioned.Profile) (int64, error) {
-	return r.id, r.err
+	return r.id, r.saveErr
 }
 
 func (r *FakeProfileRepo) FindByEmail(email string) (*cohesioned.Profile, error) {
-	return r.profile, r.err
+	return r.byEmail, r.findByEmailErr
 }
 
 func (r *FakeProfileRepo) Update(p *cohesioned.Profile) error {
-	return r.err
+	return r.updateErr
+}
+
+func (r *FakeProfileRepo) List(opts *cohesioned.ListOptions) ([]*cohesioned.Profile, int64, error) {
+	return r.list, int64(len(r.list)), r.listErr
+}
+
+func (r *FakeProfileRepo) Get(id int64) (*cohesioned.Profile, error) {
+	return r.profile, r.getErr
+}
+
+func (r *FakeProfileRepo) ListRoles(profileID int64) ([]cohesioned.Role, error) {
+	return r.roles, r.listRolesErr
+}
+
+func (r *FakeProfileRepo) AddRole(profileID int64, role cohesioned.Role, grantedBy int64) error {
+	if r.addRoleErr != nil {
+		return r.addRoleErr
+	}
+
+	r.Granted = append(r.Granted, role)
+	return nil
 }
 
-func (r *FakeProfileRepo) List() ([]*cohesioned.Profile, error) {
-	return r.list, r.err
+func (r *FakeProfileRepo) RemoveRole(profileID int64, role cohesioned.Role) error {
+	return r.removeRoleErr
 }
@@ -0,0 +1,80 @@
+package fakes
+
+import (
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+type FakeQuizRepo struct {
+	q        *cohesioned.Quiz
+	list     []*cohesioned.Quiz
+	attempts []*cohesioned.QuizAttempt
+	results  []*cohesioned.QuizResult
+	id       int64
+	err      error
+	Saved    []*cohesioned.Quiz
+	Updated  []*cohesioned.Quiz
+	Deleted  []int64
+	//Attempted holds the attempts given to SaveAttempt
+	Attempted []*cohesioned.QuizAttempt
+}
+
+func (r *FakeQuizRepo) GetReturns(q *cohesioned.Quiz, err error) {
+	r.q = q
+	r.err = err
+}
+
+func (r *FakeQuizRepo) ListReturns(list []*cohesioned.Quiz, err error) {
+	r.list = list
+	r.err = err
+}
+
+func (r *FakeQuizRepo) SaveReturns(id int64, err error) {
+	r.id = id
+	r.err = err
+}
+
+func (r *FakeQuizRepo) ListAttemptsReturns(list []*cohesioned.QuizAttempt, err error) {
+	r.attempts = list
+	r.err = err
+}
+
+func (r *FakeQuizRepo) ListResultsReturns(list []*cohesioned.QuizResult, err error) {
+	r.results = list
+	r.err = err
+}
+
+func (r *FakeQuizRepo) List(opts *cohesioned.ListOptions) ([]*cohesioned.Quiz, int64, error) {
+	return r.list, int64(len(r.list)), r.err
+}
+
+func (r *FakeQuizRepo) Get(id int64) (*cohesioned.Quiz, error) {
+	return r.q, r.err
+}
+
+func (r *FakeQuizRepo) Save(q *cohesioned.Quiz) (int64, error) {
+	r.Saved = append(r.Saved, q)
+	return r.id, r.err
+}
+
+func (r *FakeQuizRepo) Update(q *cohesioned.Quiz) error {
+	r.Updated = append(r.Updated, q)
+	return r.err
+}
+
+func (r *FakeQuizRepo) Delete(id int64) error {
+	r.Deleted = append(r.Deleted, id)
+	return r.err
+}
+
+func (r *FakeQuizRepo) SaveAttempt(a *cohesioned.QuizAttempt) (int64, error) {
+	r.Attempted = append(r.Attempted, a)
+	return r.id, r.err
+}
+
+func (r *FakeQuizRepo) ListAttempts(studentID int64, opts *cohesioned.ListOptions) ([]*cohesioned.QuizAttempt, int64, error) {
+	return r.attempts, int64(len(r.attempts)), r.err
+}
+
+func (r *FakeQuizRepo) ListResults(studentID int64) ([]*cohesioned.QuizResult, error) {
+	return r.results, r.err
+}
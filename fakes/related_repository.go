@@ -0,0 +1,38 @@
+package fakes
+
+import (
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+type FakeRelatedRepo struct {
+	err error
+	//Related holds the IDs given to the last call to SetRelated
+	Related []int64
+	//Suggested are returned by SuggestRelated
+	Suggested []*cohesioned.RelatedVideo
+}
+
+func (r *FakeRelatedRepo) ListRelatedIDsReturns(ids []int64, err error) {
+	r.Related = ids
+	r.err = err
+}
+
+func (r *FakeRelatedRepo) ListRelatedIDs(videoID int64) ([]int64, error) {
+	return r.Related, r.err
+}
+
+func (r *FakeRelatedRepo) SetRelated(videoID int64, relatedIDs []int64) error {
+	r.Related = relatedIDs
+	return r.err
+}
+
+func (r *FakeRelatedRepo) SuggestRelated(videoID int64, limit int) ([]*cohesioned.RelatedVideo, error) {
+	var list []*cohesioned.RelatedVideo
+	for _, s := range r.Suggested {
+		if len(list) < limit {
+			list = append(list, &cohesioned.RelatedVideo{Video: &cohesioned.Video{ID: s.ID}, Score: s.Score})
+		}
+	}
+
+	return list, r.err
+}
@@ -0,0 +1,70 @@
+package fakes
+
+import (
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/standard"
+)
+
+type FakeStandardRepo struct {
+	s        *cohesioned.Standard
+	list     []*cohesioned.Standard
+	byCodes  []*cohesioned.Standard
+	id       int64
+	result   *standard.ImportResult
+	err      error
+	Imported []*cohesioned.Standard
+}
+
+func (r *FakeStandardRepo) GetReturns(s *cohesioned.Standard, err error) {
+	r.s = s
+	r.err = err
+}
+
+func (r *FakeStandardRepo) ListReturns(list []*cohesioned.Standard, err error) {
+	r.list = list
+	r.err = err
+}
+
+func (r *FakeStandardRepo) FindByCodesReturns(list []*cohesioned.Standard, err error) {
+	r.byCodes = list
+	r.err = err
+}
+
+func (r *FakeStandardRepo) SaveReturns(id int64, err error) {
+	r.id = id
+	r.err = err
+}
+
+func (r *FakeStandardRepo) ImportReturns(result *standard.ImportResult, err error) {
+	r.result = result
+	r.err = err
+}
+
+func (r *FakeStandardRepo) List(opts *cohesioned.ListOptions) ([]*cohesioned.Standard, int64, error) {
+	return r.list, int64(len(r.list)), r.err
+}
+
+func (r *FakeStandardRepo) Get(id int64) (*cohesioned.Standard, error) {
+	return r.s, r.err
+}
+
+func (r *FakeStandardRepo) FindByCodes(codes []string) ([]*cohesioned.Standard, error) {
+	return r.byCodes, r.err
+}
+
+func (r *FakeStandardRepo) Save(s *cohesioned.Standard) (int64, error) {
+	return r.id, r.err
+}
+
+func (r *FakeStandardRepo) Update(s *cohesioned.Standard) error {
+	return r.err
+}
+
+func (r *FakeStandardRepo) Delete(id int64) error {
+	return r.err
+}
+
+func (r *FakeStandardRepo) Import(list []*cohesioned.Standard) (*standard.ImportResult, error) {
+	r.Imported = list
+	return r.result, r.err
+}
@@ -0,0 +1,46 @@
+package fakes
+
+import (
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+type FakeStudentRepo struct {
+	list []*cohesioned.Student
+	id   int64
+	err  error
+}
+
+func (r *FakeStudentRepo) FindByUserIDReturns(list []*cohesioned.Student, err error) {
+	r.list = list
+	r.err = err
+}
+
+func (r *FakeStudentRepo) ListReturns(list []*cohesioned.Student, err error) {
+	r.list = list
+	r.err = err
+}
+
+func (r *FakeStudentRepo) SaveReturns(id int64, err error) {
+	r.id = id
+	r.err = err
+}
+
+func (r *FakeStudentRepo) FindByUserID(parentID int64) ([]*cohesioned.Student, error) {
+	return r.list, r.err
+}
+
+func (r *FakeStudentRepo) List(opts *cohesioned.ListOptions) ([]*cohesioned.Student, int64, error) {
+	return r.list, int64(len(r.list)), r.err
+}
+
+func (r *FakeStudentRepo) Save(s *cohesioned.Student) (int64, error) {
+	return r.id, r.err
+}
+
+func (r *FakeStudentRepo) Update(s *cohesioned.Student) error {
+	return r.err
+}
+
+func (r *FakeStudentRepo) Delete(id int64) error {
+	return r.err
+}
@@ -0,0 +1,59 @@
+package fakes
+
+import (
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+type FakeTeacherRepo struct {
+	t       *cohesioned.Teacher
+	list    []*cohesioned.Teacher
+	byIDs   []*cohesioned.Teacher
+	id      int64
+	err     error
+	Deleted []int64
+}
+
+func (r *FakeTeacherRepo) GetReturns(t *cohesioned.Teacher, err error) {
+	r.t = t
+	r.err = err
+}
+
+func (r *FakeTeacherRepo) ListReturns(list []*cohesioned.Teacher, err error) {
+	r.list = list
+	r.err = err
+}
+
+func (r *FakeTeacherRepo) FindByIDsReturns(list []*cohesioned.Teacher, err error) {
+	r.byIDs = list
+	r.err = err
+}
+
+func (r *FakeTeacherRepo) SaveReturns(id int64, err error) {
+	r.id = id
+	r.err = err
+}
+
+func (r *FakeTeacherRepo) List(opts *cohesioned.ListOptions) ([]*cohesioned.Teacher, int64, error) {
+	return r.list, int64(len(r.list)), r.err
+}
+
+func (r *FakeTeacherRepo) Get(id int64) (*cohesioned.Teacher, error) {
+	return r.t, r.err
+}
+
+func (r *FakeTeacherRepo) FindByIDs(ids []int64) ([]*cohesioned.Teacher, error) {
+	return r.byIDs, r.err
+}
+
+func (r *FakeTeacherRepo) Save(t *cohesioned.Teacher) (int64, error) {
+	return r.id, r.err
+}
+
+func (r *FakeTeacherRepo) Update(t *cohesioned.Teacher) error {
+	return r.err
+}
+
+func (r *FakeTeacherRepo) Delete(id int64) error {
+	r.Deleted = append(r.Deleted, id)
+	return r.err
+}
@@ -3,8 +3,11 @@ package fakes
 import (
 	"context"
 	"io"
+	"sync/atomic"
+	"time"
 
 	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/video"
 )
 
 type FakeVideoAdminService struct {
@@ -13,6 +16,140 @@ type FakeVideoAdminService struct {
 	list                    []*cohesioned.Video
 	videosByGrade map[string][]*cohesioned.Video
 	videosBySubject map[string][]*cohesioned.Video
+	searchResults   []*cohesioned.SearchResult
+	tags            []*cohesioned.Tag
+	FoundByTag      string
+	FoundByTeacher  int64
+	jobs            []*cohesioned.TranscodingJob
+	hlsRenditions   []*cohesioned.Rendition
+	playlist        []byte
+	playlistErr     error
+	SearchedWith    video.SearchQuery
+	target          *video.UploadTarget
+	uploadErr       error
+	abortErr        error
+	Completed       *video.CompletedUpload
+	upload          *cohesioned.VideoUpload
+	report          *video.StorageReport
+	backfill        *video.ThumbnailBackfill
+	thumbnail       *cohesioned.Thumbnail
+	captions        *cohesioned.CaptionTrack
+	chapters        []*cohesioned.Chapter
+	chapterErr      error
+	progress        *cohesioned.WatchProgress
+	progressErr     error
+	related         []*cohesioned.RelatedVideo
+	faqs            []*cohesioned.FAQ
+	faqErr          error
+	//RelatedIDs holds the IDs given to the last call to SetRelated
+	RelatedIDs []int64
+	//AskedBy holds the user given to the last call to AskQuestion
+	AskedBy int64
+	//QuestionOpts holds the options given to the last call to ListQuestions
+	QuestionOpts *cohesioned.ListOptions
+	//Watched holds the event given to the last call to RecordWatchEvent
+	Watched *cohesioned.WatchEvent
+	//CaptionsLanguage and CaptionsLabel hold the arguments of the last call to SetCaptions
+	CaptionsLanguage string
+	CaptionsLabel    string
+	//Refreshes counts the calls to RefreshTranscodingJobs. It is updated atomically, as the calls are made in the background
+	Refreshes int32
+	//ReconciledMinAge and Removed hold the arguments of the last call to ReconcileStorage
+	ReconciledMinAge time.Duration
+	Removed          bool
+}
+
+//SetThumbnailReturns sets what SetThumbnail returns. The error is also returned by DeleteThumbnail
+func (s *FakeVideoAdminService) SetThumbnailReturns(thumbnail *cohesioned.Thumbnail, err error) {
+	s.thumbnail = thumbnail
+	s.uploadErr = err
+}
+
+//SetCaptionsReturns sets what SetCaptions returns. The error is also returned by DeleteCaptions
+func (s *FakeVideoAdminService) SetCaptionsReturns(captions *cohesioned.CaptionTrack, err error) {
+	s.captions = captions
+	s.uploadErr = err
+}
+
+//ChaptersReturns sets the chapters returned by ListChapters. The error is returned by all of the chapter methods
+func (s *FakeVideoAdminService) ChaptersReturns(chapters []*cohesioned.Chapter, err error) {
+	s.chapters = chapters
+	s.chapterErr = err
+}
+
+//FAQsReturns sets the FAQs returned by ListFAQs and ListQuestions. The error is returned by all of the FAQ methods
+func (s *FakeVideoAdminService) FAQsReturns(faqs []*cohesioned.FAQ, err error) {
+	s.faqs = faqs
+	s.faqErr = err
+}
+
+//WatchProgressReturns sets what RecordWatchEvent and WatchProgress return
+func (s *FakeVideoAdminService) WatchProgressReturns(progress *cohesioned.WatchProgress, err error) {
+	s.progress = progress
+	s.progressErr = err
+}
+
+//RelatedVideosReturns sets the videos returned by RelatedVideos. The error is returned by SetRelated
+func (s *FakeVideoAdminService) RelatedVideosReturns(related []*cohesioned.RelatedVideo, err error) {
+	s.related = related
+	s.uploadErr = err
+}
+
+func (s *FakeVideoAdminService) ReconcileStorageReturns(report *video.StorageReport, err error) {
+	s.report = report
+	s.err = err
+}
+
+//UploadProgressReturns sets the upload returned by UploadProgress and WriteChunk. The error is the one returned by CompleteUploadReturns
+func (s *FakeVideoAdminService) UploadProgressReturns(upload *cohesioned.VideoUpload, err error) {
+	s.upload = upload
+	s.uploadErr = err
+}
+
+func (s *FakeVideoAdminService) BackfillThumbnailsReturns(backfill *video.ThumbnailBackfill, err error) {
+	s.backfill = backfill
+	s.err = err
+}
+
+func (s *FakeVideoAdminService) UploadTargetReturns(target *video.UploadTarget, err error) {
+	s.target = target
+	s.err = err
+}
+
+//CompleteUploadReturns sets the error returned by CompleteUpload separately from the one returned by Get
+func (s *FakeVideoAdminService) CompleteUploadReturns(err error) {
+	s.uploadErr = err
+}
+
+//AbortUploadReturns sets the error returned by AbortUpload separately from the one returned by Get
+func (s *FakeVideoAdminService) AbortUploadReturns(err error) {
+	s.abortErr = err
+}
+
+func (s *FakeVideoAdminService) ListTagsReturns(tags []*cohesioned.Tag, err error) {
+	s.tags = tags
+	s.err = err
+}
+
+func (s *FakeVideoAdminService) TranscodingJobsReturns(jobs []*cohesioned.TranscodingJob, err error) {
+	s.jobs = jobs
+	s.err = err
+}
+
+func (s *FakeVideoAdminService) HLSRenditionsReturns(renditions []*cohesioned.Rendition, err error) {
+	s.hlsRenditions = renditions
+	s.err = err
+}
+
+//VariantPlaylistReturns sets the playlist and error returned by VariantPlaylist separately from the one returned by Get
+func (s *FakeVideoAdminService) VariantPlaylistReturns(playlist []byte, err error) {
+	s.playlist = playlist
+	s.playlistErr = err
+}
+
+func (s *FakeVideoAdminService) SearchReturns(results []*cohesioned.SearchResult, err error) {
+	s.searchResults = results
+	s.err = err
 }
 
 func (s *FakeVideoAdminService) FindByTaxonomyIDReturns(list []*cohesioned.Video, err error) {
@@ -52,12 +189,13 @@ func (s *FakeVideoAdminService) SaveReturns(err error) {
 func (s *FakeVideoAdminService) UpdateReturns(err error) {
 	s.err = err
 }
+//SetFileReturns sets the error returned by SetFile separately from the one returned by Get
 func (s *FakeVideoAdminService) SetFileReturns(err error) {
-	s.err = err
+	s.uploadErr = err
 }
 
-func (s *FakeVideoAdminService) List() ([]*cohesioned.Video, error) {
-	return s.list, s.err
+func (s *FakeVideoAdminService) List(opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error) {
+	return s.list, int64(len(s.list)), s.err
 }
 func (s *FakeVideoAdminService) FindByTaxonomyID(taxonomyID int64) ([]*cohesioned.Video, error) {
 	return s.list, s.err
@@ -87,5 +225,206 @@ func (s *FakeVideoAdminService) Update(ctx context.Context, video *cohesioned.Vi
 	return s.err
 }
 func (s *FakeVideoAdminService) SetFile(ctx context.Context, fileReader io.Reader, video *cohesioned.Video) error {
+	return s.uploadErr
+}
+
+func (s *FakeVideoAdminService) Search(query video.SearchQuery, opts *cohesioned.ListOptions) ([]*cohesioned.SearchResult, int64, error) {
+	s.SearchedWith = query
+	return s.searchResults, int64(len(s.searchResults)), s.err
+}
+
+func (s *FakeVideoAdminService) FindByKeyTerm(term string, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error) {
+	s.FoundByTag = term
+	return s.list, int64(len(s.list)), s.err
+}
+
+func (s *FakeVideoAdminService) FindByStandard(standard string, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error) {
+	s.FoundByTag = standard
+	return s.list, int64(len(s.list)), s.err
+}
+
+func (s *FakeVideoAdminService) ListKeyTerms() ([]*cohesioned.Tag, error) {
+	return s.tags, s.err
+}
+
+func (s *FakeVideoAdminService) ListStandards() ([]*cohesioned.Tag, error) {
+	return s.tags, s.err
+}
+
+func (s *FakeVideoAdminService) FindByJurisdiction(jurisdiction string, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error) {
+	s.FoundByTag = jurisdiction
+	return s.list, int64(len(s.list)), s.err
+}
+
+func (s *FakeVideoAdminService) FindByTeacher(teacherID int64, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error) {
+	s.FoundByTeacher = teacherID
+	return s.list, int64(len(s.list)), s.err
+}
+
+func (s *FakeVideoAdminService) ValidateStandards(video, previous *cohesioned.Video) error {
+	return nil
+}
+
+func (s *FakeVideoAdminService) ValidateTeachers(video *cohesioned.Video) error {
+	return nil
+}
+
+func (s *FakeVideoAdminService) TranscodingJobs(videoID int64) ([]*cohesioned.TranscodingJob, error) {
+	return s.jobs, s.err
+}
+
+func (s *FakeVideoAdminService) RefreshTranscodingJobs() error {
+	atomic.AddInt32(&s.Refreshes, 1)
+	return s.err
+}
+
+func (s *FakeVideoAdminService) HLSRenditions(videoID int64) ([]*cohesioned.Rendition, error) {
+	return s.hlsRenditions, s.err
+}
+
+func (s *FakeVideoAdminService) VariantPlaylist(v *cohesioned.Video, renditionID int64) ([]byte, error) {
+	return s.playlist, s.playlistErr
+}
+
+func (s *FakeVideoAdminService) PresignUpload(v *cohesioned.Video, contentType string) (*video.UploadTarget, error) {
+	return s.target, s.err
+}
+
+func (s *FakeVideoAdminService) StartMultipartUpload(v *cohesioned.Video, contentType string, fileSize int64) (*video.UploadTarget, error) {
+	return s.target, s.err
+}
+
+func (s *FakeVideoAdminService) CompleteUpload(ctx context.Context, v *cohesioned.Video, upload *video.CompletedUpload) error {
+	s.Completed = upload
+	return s.uploadErr
+}
+
+func (s *FakeVideoAdminService) AbortUpload(v *cohesioned.Video, objectName, uploadID string) error {
+	return s.abortErr
+}
+
+func (s *FakeVideoAdminService) UploadProgress(videoID int64) (*cohesioned.VideoUpload, error) {
+	return s.upload, s.uploadErr
+}
+
+func (s *FakeVideoAdminService) WriteChunk(ctx context.Context, v *cohesioned.Video, chunk *cohesioned.UploadChunk, total int64, fileType string, body io.Reader) (*cohesioned.VideoUpload, error) {
+	return s.upload, s.uploadErr
+}
+
+func (s *FakeVideoAdminService) CancelUpload(videoID int64) error {
 	return s.err
 }
+
+func (s *FakeVideoAdminService) ReconcileStorage(minAge time.Duration, remove bool) (*video.StorageReport, error) {
+	s.ReconciledMinAge = minAge
+	s.Removed = remove
+	return s.report, s.err
+}
+
+func (s *FakeVideoAdminService) BackfillThumbnails() (*video.ThumbnailBackfill, error) {
+	return s.backfill, s.err
+}
+
+func (s *FakeVideoAdminService) SetThumbnail(v *cohesioned.Video, body io.Reader) (*cohesioned.Thumbnail, error) {
+	return s.thumbnail, s.uploadErr
+}
+
+func (s *FakeVideoAdminService) DeleteThumbnail(v *cohesioned.Video) error {
+	return s.uploadErr
+}
+
+func (s *FakeVideoAdminService) SetCaptions(v *cohesioned.Video, language, label string, body io.Reader) (*cohesioned.CaptionTrack, error) {
+	s.CaptionsLanguage = language
+	s.CaptionsLabel = label
+	return s.captions, s.uploadErr
+}
+
+func (s *FakeVideoAdminService) DeleteCaptions(v *cohesioned.Video, language string) error {
+	return s.uploadErr
+}
+
+func (s *FakeVideoAdminService) ListChapters(videoID int64) ([]*cohesioned.Chapter, error) {
+	return s.chapters, s.chapterErr
+}
+
+func (s *FakeVideoAdminService) SaveChapter(v *cohesioned.Video, chapter *cohesioned.Chapter) error {
+	if s.chapterErr != nil {
+		return s.chapterErr
+	}
+
+	chapter.ID = int64(len(s.chapters) + 1)
+	chapter.VideoID = v.ID
+	s.chapters = append(s.chapters, chapter)
+	return nil
+}
+
+func (s *FakeVideoAdminService) UpdateChapter(v *cohesioned.Video, chapter *cohesioned.Chapter) error {
+	chapter.VideoID = v.ID
+	return s.chapterErr
+}
+
+func (s *FakeVideoAdminService) DeleteChapter(v *cohesioned.Video, chapterID int64) error {
+	return s.chapterErr
+}
+
+func (s *FakeVideoAdminService) ListFAQs(videoID int64) ([]*cohesioned.FAQ, error) {
+	return s.faqs, s.faqErr
+}
+
+func (s *FakeVideoAdminService) ListQuestions(opts *cohesioned.ListOptions) ([]*cohesioned.FAQ, int64, error) {
+	s.QuestionOpts = opts
+	return s.faqs, int64(len(s.faqs)), s.faqErr
+}
+
+func (s *FakeVideoAdminService) AskQuestion(v *cohesioned.Video, faq *cohesioned.FAQ, askedBy int64) error {
+	if s.faqErr != nil {
+		return s.faqErr
+	}
+
+	s.AskedBy = askedBy
+	faq.ID = int64(len(s.faqs) + 1)
+	faq.VideoID = v.ID
+	faq.AskedBy = askedBy
+	faq.Status = cohesioned.FAQPending
+	s.faqs = append(s.faqs, faq)
+	return nil
+}
+
+func (s *FakeVideoAdminService) SaveFAQ(v *cohesioned.Video, faq *cohesioned.FAQ, userID int64) error {
+	if s.faqErr != nil {
+		return s.faqErr
+	}
+
+	faq.ID = int64(len(s.faqs) + 1)
+	faq.VideoID = v.ID
+	s.faqs = append(s.faqs, faq)
+	return nil
+}
+
+func (s *FakeVideoAdminService) UpdateFAQ(v *cohesioned.Video, faq *cohesioned.FAQ, userID int64) error {
+	faq.VideoID = v.ID
+	return s.faqErr
+}
+
+func (s *FakeVideoAdminService) DeleteFAQ(v *cohesioned.Video, faqID int64) error {
+	return s.faqErr
+}
+
+func (s *FakeVideoAdminService) RecordWatchEvent(v *cohesioned.Video, event *cohesioned.WatchEvent) (*cohesioned.WatchProgress, error) {
+	event.VideoID = v.ID
+	s.Watched = event
+	return s.progress, s.progressErr
+}
+
+func (s *FakeVideoAdminService) WatchProgress(videoID, userID, studentID int64) (*cohesioned.WatchProgress, error) {
+	return s.progress, s.progressErr
+}
+
+func (s *FakeVideoAdminService) RelatedVideos(v *cohesioned.Video, limit int) ([]*cohesioned.RelatedVideo, error) {
+	return s.related, nil
+}
+
+func (s *FakeVideoAdminService) SetRelated(v *cohesioned.Video, relatedIDs []int64) error {
+	s.RelatedIDs = relatedIDs
+	return s.uploadErr
+}
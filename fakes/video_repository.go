@@ -7,10 +7,41 @@ import (
 )
 
 type FakeVideoRepo struct {
-	v    *cohesioned.Video
-	id   int64
-	list []*cohesioned.Video
-	err  error
+	v      *cohesioned.Video
+	id     int64
+	list   []*cohesioned.Video
+	upload *cohesioned.VideoUpload
+	chunks []*cohesioned.UploadChunk
+	keys   []string
+	dup    *cohesioned.Video
+	lastID int64
+	err    error
+	//withoutThumbnails are returned by ListWithoutThumbnails
+	withoutThumbnails []*cohesioned.Video
+	//Renditions are returned by ListRenditions until DeleteRenditions is called
+	Renditions []*cohesioned.Rendition
+	//Jobs are returned by ListTranscodingJobs, and those that aren't done by ListUnfinishedTranscodingJobs
+	Jobs []*cohesioned.TranscodingJob
+	//Thumbnails holds what was saved with SaveThumbnail, less any that were deleted
+	Thumbnails []*cohesioned.Thumbnail
+	//Captions holds what was saved with SaveCaptions by language, less any that were deleted
+	Captions map[string]*cohesioned.CaptionTrack
+	//Duration holds the seconds given to the last call to SetDuration
+	Duration float64
+	//UpdateErr is returned by Update when it is set, so Update can fail while the other methods succeed
+	UpdateErr error
+	//Racing is saved as the upload by the next call to SaveUpload before the one it was given
+	Racing *cohesioned.VideoUpload
+}
+
+func (r *FakeVideoRepo) FindByChecksumReturns(v *cohesioned.Video, err error) {
+	r.dup = v
+	r.err = err
+}
+
+func (r *FakeVideoRepo) ListObjectKeysReturns(keys []string, err error) {
+	r.keys = keys
+	r.err = err
 }
 
 func (r *FakeVideoRepo) ListReturns(list []*cohesioned.Video, err error) {
@@ -41,8 +72,8 @@ func (r *FakeVideoRepo) SetFileReturns(v *cohesioned.Video, err error) {
 	r.err = err
 }
 
-func (r *FakeVideoRepo) List() ([]*cohesioned.Video, error) {
-	return r.list, r.err
+func (r *FakeVideoRepo) List(opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error) {
+	return r.list, int64(len(r.list)), r.err
 }
 func (r *FakeVideoRepo) Get(id int64) (*cohesioned.Video, error) {
 	return r.v, r.err
@@ -55,9 +86,234 @@ func (r *FakeVideoRepo) Save(video *cohesioned.Video) (int64, error) {
 }
 
 func (r *FakeVideoRepo) Update(video *cohesioned.Video) error {
+	if r.UpdateErr != nil {
+		return r.UpdateErr
+	}
+
 	return r.err
 }
 
 func (r *FakeVideoRepo) SetFile(fileReader io.Reader, video *cohesioned.Video) (*cohesioned.Video, error) {
 	return r.v, r.err
 }
+
+func (r *FakeVideoRepo) FindByTaxonomyID(id int64) ([]*cohesioned.Video, error) {
+	return r.list, r.err
+}
+
+func (r *FakeVideoRepo) FindByChecksum(checksum string) (*cohesioned.Video, error) {
+	return r.dup, r.err
+}
+
+//FindByIDs returns the videos given to ListReturns that have one of the IDs
+func (r *FakeVideoRepo) FindByIDs(ids []int64) ([]*cohesioned.Video, error) {
+	var list []*cohesioned.Video
+	for _, v := range r.list {
+		for _, id := range ids {
+			if v.ID == id {
+				list = append(list, v)
+			}
+		}
+	}
+
+	return list, r.err
+}
+
+func (r *FakeVideoRepo) Search(terms []string, taxonomyIDs []int64, opts *cohesioned.ListOptions) ([]*cohesioned.SearchResult, int64, error) {
+	return nil, 0, r.err
+}
+
+func (r *FakeVideoRepo) FindByTag(kinds []cohesioned.TagKind, name string, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error) {
+	return r.list, int64(len(r.list)), r.err
+}
+
+func (r *FakeVideoRepo) ListTags(kinds ...cohesioned.TagKind) ([]*cohesioned.Tag, error) {
+	return nil, r.err
+}
+
+func (r *FakeVideoRepo) FindByJurisdiction(jurisdiction string, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error) {
+	return r.list, int64(len(r.list)), r.err
+}
+
+func (r *FakeVideoRepo) FindByTeacher(teacherID int64, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error) {
+	return r.list, int64(len(r.list)), r.err
+}
+
+func (r *FakeVideoRepo) SaveTranscodingJob(job *cohesioned.TranscodingJob) (int64, error) {
+	return r.id, r.err
+}
+
+func (r *FakeVideoRepo) UpdateTranscodingJob(job *cohesioned.TranscodingJob) error {
+	return r.err
+}
+
+func (r *FakeVideoRepo) ListTranscodingJobs(videoID int64) ([]*cohesioned.TranscodingJob, error) {
+	return r.Jobs, r.err
+}
+
+func (r *FakeVideoRepo) ListUnfinishedTranscodingJobs() ([]*cohesioned.TranscodingJob, error) {
+	var unfinished []*cohesioned.TranscodingJob
+	for _, job := range r.Jobs {
+		if !job.Status.IsDone() {
+			unfinished = append(unfinished, job)
+		}
+	}
+
+	return unfinished, r.err
+}
+
+func (r *FakeVideoRepo) SaveRendition(rendition *cohesioned.Rendition) (int64, error) {
+	return r.id, r.err
+}
+
+func (r *FakeVideoRepo) ListRenditions(videoID int64) ([]*cohesioned.Rendition, error) {
+	return r.Renditions, r.err
+}
+
+func (r *FakeVideoRepo) DeleteRenditions(videoID int64) error {
+	r.Renditions = nil
+	return r.err
+}
+
+func (r *FakeVideoRepo) SaveThumbnail(t *cohesioned.Thumbnail) (int64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	r.lastID++
+	r.Thumbnails = append(r.Thumbnails, t)
+	return r.lastID, nil
+}
+
+func (r *FakeVideoRepo) ListThumbnails(videoID int64) ([]*cohesioned.Thumbnail, error) {
+	return r.Thumbnails, r.err
+}
+
+func (r *FakeVideoRepo) DeleteThumbnail(id int64) error {
+	var kept []*cohesioned.Thumbnail
+	for _, t := range r.Thumbnails {
+		if t.ID != id {
+			kept = append(kept, t)
+		}
+	}
+
+	r.Thumbnails = kept
+	return r.err
+}
+
+func (r *FakeVideoRepo) DeleteThumbnails(videoID int64, kind cohesioned.ThumbnailKind) error {
+	var kept []*cohesioned.Thumbnail
+	for _, t := range r.Thumbnails {
+		if t.Kind != kind {
+			kept = append(kept, t)
+		}
+	}
+
+	r.Thumbnails = kept
+	return r.err
+}
+
+func (r *FakeVideoRepo) SaveCaptions(c *cohesioned.CaptionTrack) (int64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	if r.Captions == nil {
+		r.Captions = make(map[string]*cohesioned.CaptionTrack)
+	}
+
+	r.lastID++
+	r.Captions[c.Language] = c
+	return r.lastID, nil
+}
+
+func (r *FakeVideoRepo) ListCaptions(videoID int64) ([]*cohesioned.CaptionTrack, error) {
+	var list []*cohesioned.CaptionTrack
+	for _, c := range r.Captions {
+		list = append(list, c)
+	}
+
+	return list, r.err
+}
+
+func (r *FakeVideoRepo) DeleteCaptions(videoID int64, language string) error {
+	delete(r.Captions, language)
+	return r.err
+}
+
+func (r *FakeVideoRepo) SetDuration(videoID int64, seconds float64) error {
+	r.Duration = seconds
+	return r.err
+}
+
+//ListTranscripts returns the transcripts of the saved captions for every video
+func (r *FakeVideoRepo) ListTranscripts(videoIDs []int64) (map[int64]string, error) {
+	transcripts := make(map[int64]string)
+	for _, id := range videoIDs {
+		for _, c := range r.Captions {
+			transcripts[id] += c.Transcript
+		}
+	}
+
+	return transcripts, r.err
+}
+
+func (r *FakeVideoRepo) GetUpload(videoID int64) (*cohesioned.VideoUpload, error) {
+	return r.upload, r.err
+}
+
+//SaveUpload saves the upload unless there is one already. An upload set as Racing is saved first, as though another request started it
+func (r *FakeVideoRepo) SaveUpload(u *cohesioned.VideoUpload) (bool, error) {
+	if r.Racing != nil {
+		r.upload, r.Racing = r.Racing, nil
+	}
+
+	if r.upload != nil {
+		return false, r.err
+	}
+
+	r.upload = u
+	return true, r.err
+}
+
+func (r *FakeVideoRepo) AddUploadChunk(videoID int64, chunk *cohesioned.UploadChunk) (bool, error) {
+	if r.upload == nil || r.upload.Received != chunk.First {
+		return false, r.err
+	}
+
+	r.upload.Received = chunk.Last + 1
+	r.chunks = append(r.chunks, chunk)
+	return true, r.err
+}
+
+func (r *FakeVideoRepo) ListUploadChunks(videoID int64) ([]*cohesioned.UploadChunk, error) {
+	return r.chunks, r.err
+}
+
+func (r *FakeVideoRepo) DeleteUpload(videoID int64) error {
+	r.upload = nil
+	r.chunks = nil
+	return r.err
+}
+
+func (r *FakeVideoRepo) ListObjectKeys() ([]string, error) {
+	return r.keys, r.err
+}
+
+//ListWithoutThumbnails returns the videos set by ListWithoutThumbnailsReturns
+func (r *FakeVideoRepo) ListWithoutThumbnails() ([]*cohesioned.Video, error) {
+	return r.withoutThumbnails, r.err
+}
+
+func (r *FakeVideoRepo) ListWithoutThumbnailsReturns(videos []*cohesioned.Video) {
+	r.withoutThumbnails = videos
+}
+
+//ListUploadVideoIDs returns the ID of the video with the upload given to SaveUpload, if any
+func (r *FakeVideoRepo) ListUploadVideoIDs() ([]int64, error) {
+	if r.upload == nil {
+		return nil, r.err
+	}
+
+	return []int64{r.upload.VideoID}, r.err
+}
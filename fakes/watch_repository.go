@@ -0,0 +1,61 @@
+package fakes
+
+import (
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+type FakeWatchRepo struct {
+	lastID     int64
+	completion []*cohesioned.CompletionRate
+	err        error
+	//Students maps the IDs of students to the ID of their parent
+	Students map[int64]int64
+	//WatchEvents holds what was saved with SaveWatchEvent
+	WatchEvents []*cohesioned.WatchEvent
+	//Progress holds what was saved with SaveWatchProgress, keyed by video, user and student
+	Progress map[[3]int64]*cohesioned.WatchProgress
+}
+
+func (r *FakeWatchRepo) IsParentOf(userID, studentID int64) (bool, error) {
+	parentID, ok := r.Students[studentID]
+	return ok && parentID == userID, r.err
+}
+
+func (r *FakeWatchRepo) SaveWatchEvent(e *cohesioned.WatchEvent) (int64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	r.lastID++
+	r.WatchEvents = append(r.WatchEvents, e)
+	return r.lastID, nil
+}
+
+func (r *FakeWatchRepo) SaveWatchProgress(p *cohesioned.WatchProgress) error {
+	if r.Progress == nil {
+		r.Progress = make(map[[3]int64]*cohesioned.WatchProgress)
+	}
+
+	key := [3]int64{p.VideoID, p.UserID, p.StudentID}
+	saved := *p
+	if existing, ok := r.Progress[key]; ok {
+		saved.Created = existing.Created
+		saved.Completed = saved.Completed || existing.Completed
+	}
+
+	r.Progress[key] = &saved
+	return r.err
+}
+
+func (r *FakeWatchRepo) GetWatchProgress(videoID, userID, studentID int64) (*cohesioned.WatchProgress, error) {
+	return r.Progress[[3]int64{videoID, userID, studentID}], r.err
+}
+
+func (r *FakeWatchRepo) ListCompletionRatesReturns(list []*cohesioned.CompletionRate, err error) {
+	r.completion = list
+	r.err = err
+}
+
+func (r *FakeWatchRepo) ListCompletionRates(opts *cohesioned.ListOptions) ([]*cohesioned.CompletionRate, int64, error) {
+	return r.completion, int64(len(r.completion)), r.err
+}
@@ -0,0 +1,4 @@
+-- powers /api/videos/search. The column list of video_search_idx must match searchColumns in video/aws_repository.go
+ALTER TABLE `video`
+  ADD FULLTEXT INDEX `video_title_search_idx` (`title`),
+  ADD FULLTEXT INDEX `video_search_idx` (`title`, `key_terms`, `state_standards`, `common_core_standards`);
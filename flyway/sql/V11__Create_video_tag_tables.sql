@@ -0,0 +1,229 @@
+-- -----------------------------------------------------
+-- Table `video_tag`
+-- Key terms and standards that videos are tagged with.
+-- kind is one of key_term, state_standard or common_core_standard
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `video_tag` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `kind` VARCHAR(45) NOT NULL,
+  `name` VARCHAR(191) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `video_tag_UNIQUE` (`kind` ASC, `name` ASC))
+ENGINE = InnoDB;
+
+-- -----------------------------------------------------
+-- Table `video_tag_map`
+-- position keeps a video's tags in the order they were entered
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `video_tag_map` (
+  `video_id` INT NOT NULL,
+  `tag_id` INT NOT NULL,
+  `position` INT NOT NULL,
+  PRIMARY KEY (`video_id`, `tag_id`),
+  INDEX `fk_video_tag_map_tag_idx` (`tag_id` ASC),
+  CONSTRAINT `fk_video_tag_map_video`
+    FOREIGN KEY (`video_id`)
+    REFERENCES `video` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_video_tag_map_tag`
+    FOREIGN KEY (`tag_id`)
+    REFERENCES `video_tag` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+-- -----------------------------------------------------
+-- Backfill the tag tables from the comma separated
+-- key_terms, state_standards and common_core_standards
+-- columns.
+-- Those columns are kept, newline separated from now on,
+-- only to feed the video_search_idx fulltext index.
+-- -----------------------------------------------------
+
+-- -----------------------------------------------------
+-- tmp_position numbers every entry of the longest column.
+-- Each insert doubles it until it has more positions
+-- than that column has commas, and 32 of them cover a
+-- full LONGTEXT column. It is not a TEMPORARY table
+-- because MySQL can't read those twice in one query.
+-- -----------------------------------------------------
+SET @max_position = (
+  SELECT GREATEST(
+    COALESCE(MAX(LENGTH(key_terms) - LENGTH(REPLACE(key_terms, ',', ''))), 0),
+    COALESCE(MAX(LENGTH(state_standards) - LENGTH(REPLACE(state_standards, ',', ''))), 0),
+    COALESCE(MAX(LENGTH(common_core_standards) - LENGTH(REPLACE(common_core_standards, ',', ''))), 0))
+  FROM `video`);
+
+CREATE TABLE `tmp_position` (
+  `n` INT UNSIGNED NOT NULL,
+  PRIMARY KEY (`n`))
+ENGINE = InnoDB;
+
+INSERT INTO `tmp_position` (`n`) VALUES (0);
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+INSERT INTO `tmp_position` (`n`)
+SELECT p.n + c.size FROM `tmp_position` p, (SELECT COUNT(*) AS size FROM `tmp_position`) c
+WHERE c.size <= @max_position;
+
+CREATE TEMPORARY TABLE `tmp_video_tag` (
+  `video_id` INT NOT NULL,
+  `kind` VARCHAR(45) NOT NULL,
+  `position` INT NOT NULL,
+  `name` VARCHAR(191) NOT NULL);
+
+INSERT INTO `tmp_video_tag` (`video_id`, `kind`, `position`, `name`)
+SELECT v.id, 'key_term', p.n, TRIM(SUBSTRING_INDEX(SUBSTRING_INDEX(v.key_terms, ',', p.n + 1), ',', -1))
+FROM `video` v, `tmp_position` p
+WHERE LENGTH(v.key_terms) > 0
+AND p.n <= LENGTH(v.key_terms) - LENGTH(REPLACE(v.key_terms, ',', ''));
+
+INSERT INTO `tmp_video_tag` (`video_id`, `kind`, `position`, `name`)
+SELECT v.id, 'state_standard', p.n, TRIM(SUBSTRING_INDEX(SUBSTRING_INDEX(v.state_standards, ',', p.n + 1), ',', -1))
+FROM `video` v, `tmp_position` p
+WHERE LENGTH(v.state_standards) > 0
+AND p.n <= LENGTH(v.state_standards) - LENGTH(REPLACE(v.state_standards, ',', ''));
+
+INSERT INTO `tmp_video_tag` (`video_id`, `kind`, `position`, `name`)
+SELECT v.id, 'common_core_standard', p.n, TRIM(SUBSTRING_INDEX(SUBSTRING_INDEX(v.common_core_standards, ',', p.n + 1), ',', -1))
+FROM `video` v, `tmp_position` p
+WHERE LENGTH(v.common_core_standards) > 0
+AND p.n <= LENGTH(v.common_core_standards) - LENGTH(REPLACE(v.common_core_standards, ',', ''));
+
+DELETE FROM `tmp_video_tag` WHERE `name` = '';
+
+INSERT IGNORE INTO `video_tag` (`kind`, `name`)
+SELECT DISTINCT `kind`, `name` FROM `tmp_video_tag`;
+
+INSERT IGNORE INTO `video_tag_map` (`video_id`, `tag_id`, `position`)
+SELECT s.video_id, t.id, s.position
+FROM `tmp_video_tag` s, `video_tag` t
+WHERE s.kind = t.kind
+AND s.name = t.name;
+
+DROP TEMPORARY TABLE `tmp_video_tag`;
+DROP TABLE `tmp_position`;
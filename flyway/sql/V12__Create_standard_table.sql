@@ -0,0 +1,32 @@
+-- -----------------------------------------------------
+-- Table `standard`
+-- The catalog of educational standards. jurisdiction is
+-- CCSS for Common Core or the state, as in user.state
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `standard` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `code` VARCHAR(191) NOT NULL,
+  `description` MEDIUMTEXT NULL,
+  `grade` VARCHAR(255) NULL,
+  `subject` VARCHAR(255) NULL,
+  `jurisdiction` VARCHAR(45) NOT NULL,
+  `created` DATETIME NOT NULL,
+  `created_by` INT NOT NULL,
+  `updated` DATETIME NULL,
+  `updated_by` INT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `standard_UNIQUE` (`jurisdiction` ASC, `code` ASC),
+  INDEX `standard_code_idx` (`code` ASC),
+  INDEX `fk_standard_created_by_idx` (`created_by` ASC),
+  INDEX `fk_standard_updated_by_idx` (`updated_by` ASC),
+  CONSTRAINT `fk_standard_created_by`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_standard_updated_by`
+    FOREIGN KEY (`updated_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
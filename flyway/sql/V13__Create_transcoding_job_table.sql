@@ -0,0 +1,22 @@
+-- -----------------------------------------------------
+-- Table `transcoding_job`
+-- status is one of pending, running, complete or failed
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `transcoding_job` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `video_id` INT NOT NULL,
+  `preset` VARCHAR(255) NOT NULL,
+  `external_id` VARCHAR(255) NULL,
+  `status` VARCHAR(45) NOT NULL,
+  `output_key` VARCHAR(255) NOT NULL,
+  `error` MEDIUMTEXT NULL,
+  `created` DATETIME NOT NULL,
+  `updated` DATETIME NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_transcoding_job_video_idx` (`video_id` ASC),
+  CONSTRAINT `fk_transcoding_job_video`
+    FOREIGN KEY (`video_id`)
+    REFERENCES `video` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
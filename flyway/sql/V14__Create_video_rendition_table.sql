@@ -0,0 +1,23 @@
+-- -----------------------------------------------------
+-- Table `video_rendition`
+-- One row per video and transcoding preset. bitrate is
+-- in bits per second
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `video_rendition` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `video_id` INT NOT NULL,
+  `name` VARCHAR(255) NOT NULL,
+  `width` INT NULL,
+  `height` INT NULL,
+  `bitrate` INT NULL,
+  `object_key` VARCHAR(255) NOT NULL,
+  `size` BIGINT NULL,
+  `created` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `video_rendition_UNIQUE` (`video_id` ASC, `name` ASC),
+  CONSTRAINT `fk_video_rendition_video`
+    FOREIGN KEY (`video_id`)
+    REFERENCES `video` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
@@ -0,0 +1,37 @@
+-- -----------------------------------------------------
+-- Table `video_upload`
+-- A resumable upload of a video's file in progress.
+-- received is the number of bytes received so far
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `video_upload` (
+  `video_id` INT NOT NULL,
+  `size` BIGINT NOT NULL,
+  `received` BIGINT NOT NULL DEFAULT 0,
+  `file_type` VARCHAR(255) NULL,
+  `created` DATETIME NOT NULL,
+  `created_by` INT NOT NULL,
+  `updated` DATETIME NULL,
+  PRIMARY KEY (`video_id`),
+  CONSTRAINT `fk_video_upload_video`
+    FOREIGN KEY (`video_id`)
+    REFERENCES `video` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+-- -----------------------------------------------------
+-- Table `video_upload_chunk`
+-- The byte ranges received for a video_upload, each kept
+-- as its own object until the upload is assembled
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `video_upload_chunk` (
+  `video_id` INT NOT NULL,
+  `first_byte` BIGINT NOT NULL,
+  `last_byte` BIGINT NOT NULL,
+  PRIMARY KEY (`video_id`, `first_byte`),
+  CONSTRAINT `fk_video_upload_chunk_upload`
+    FOREIGN KEY (`video_id`)
+    REFERENCES `video_upload` (`video_id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
@@ -0,0 +1,5 @@
+-- sha-256 of the video's file, hex encoded. Uploading a file that
+-- matches another video's checksum is rejected as a duplicate
+ALTER TABLE `video`
+  ADD COLUMN `checksum` CHAR(64) NULL AFTER `object_key`,
+  ADD UNIQUE INDEX `video_checksum_UNIQUE` (`checksum` ASC);
@@ -0,0 +1,22 @@
+-- -----------------------------------------------------
+-- Table `video_thumbnail`
+-- Thumbnails generated by the transcoder and custom
+-- poster images uploaded by admins
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `video_thumbnail` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `video_id` INT NOT NULL,
+  `kind` VARCHAR(45) NOT NULL,
+  `object_key` VARCHAR(255) NOT NULL,
+  `content_type` VARCHAR(255) NULL,
+  `width` INT NULL,
+  `height` INT NULL,
+  `created` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `video_thumbnail_UNIQUE` (`video_id` ASC, `object_key` ASC),
+  CONSTRAINT `fk_video_thumbnail_video`
+    FOREIGN KEY (`video_id`)
+    REFERENCES `video` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
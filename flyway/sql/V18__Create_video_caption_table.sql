@@ -0,0 +1,23 @@
+-- -----------------------------------------------------
+-- Table `video_caption`
+-- A WebVTT caption track per language. transcript is
+-- the plain text of the cues, indexed for search
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `video_caption` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `video_id` INT NOT NULL,
+  `language` VARCHAR(35) NOT NULL,
+  `label` VARCHAR(255) NOT NULL,
+  `object_key` VARCHAR(255) NOT NULL,
+  `transcript` MEDIUMTEXT NULL,
+  `created` DATETIME NOT NULL,
+  `updated` DATETIME NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `video_caption_UNIQUE` (`video_id` ASC, `language` ASC),
+  FULLTEXT INDEX `video_caption_transcript_idx` (`transcript`),
+  CONSTRAINT `fk_video_caption_video`
+    FOREIGN KEY (`video_id`)
+    REFERENCES `video` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
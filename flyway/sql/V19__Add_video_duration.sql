@@ -0,0 +1,3 @@
+-- length of the video's file in seconds. Set from the transcoder's output, or by an admin
+ALTER TABLE `video`
+  ADD COLUMN `duration` DECIMAL(10,3) NULL AFTER `checksum`;
@@ -0,0 +1,24 @@
+-- -----------------------------------------------------
+-- Table `video_chapter`
+-- start_seconds and end_seconds are offsets into the video
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `video_chapter` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `video_id` INT NOT NULL,
+  `title` VARCHAR(255) NOT NULL,
+  `start_seconds` DECIMAL(10,3) NOT NULL,
+  `end_seconds` DECIMAL(10,3) NOT NULL,
+  `created` DATETIME NOT NULL,
+  `updated` DATETIME NULL,
+  PRIMARY KEY (`id`),
+  INDEX `video_chapter_start_idx` (`video_id` ASC, `start_seconds` ASC),
+  CONSTRAINT `fk_video_chapter_video`
+    FOREIGN KEY (`video_id`)
+    REFERENCES `video` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+-- where in the video a key term is explained. Only used for key terms
+ALTER TABLE `video_tag_map`
+  ADD COLUMN `seconds` DECIMAL(10,3) NULL AFTER `position`;
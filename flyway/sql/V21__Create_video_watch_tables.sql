@@ -0,0 +1,70 @@
+-- -----------------------------------------------------
+-- Table `video_watch_event`
+-- Every play, pause, seek, heartbeat and complete event
+-- sent by the player. student_id is null when the
+-- profile watched for itself
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `video_watch_event` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `video_id` INT NOT NULL,
+  `user_id` INT NOT NULL,
+  `student_id` INT NULL,
+  `event` VARCHAR(16) NOT NULL,
+  `position` DECIMAL(10,3) NOT NULL,
+  `created` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `video_watch_event_video_idx` (`video_id` ASC, `created` ASC),
+  INDEX `fk_video_watch_event_user_idx` (`user_id` ASC),
+  INDEX `fk_video_watch_event_student_idx` (`student_id` ASC),
+  CONSTRAINT `fk_video_watch_event_video`
+    FOREIGN KEY (`video_id`)
+    REFERENCES `video` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_video_watch_event_user`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_video_watch_event_student`
+    FOREIGN KEY (`student_id`)
+    REFERENCES `student` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+-- -----------------------------------------------------
+-- Table `video_watch_progress`
+-- Where each viewer of a video is up to. student_id is
+-- null when the profile watched for itself. student_key
+-- repeats it as 0 rather than null so the primary key
+-- holds
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `video_watch_progress` (
+  `video_id` INT NOT NULL,
+  `user_id` INT NOT NULL,
+  `student_id` INT NULL,
+  `student_key` INT NOT NULL DEFAULT 0,
+  `position` DECIMAL(10,3) NOT NULL,
+  `completed` TINYINT(1) NOT NULL DEFAULT 0,
+  `created` DATETIME NOT NULL,
+  `updated` DATETIME NULL,
+  PRIMARY KEY (`video_id`, `user_id`, `student_key`),
+  INDEX `fk_video_watch_progress_user_idx` (`user_id` ASC),
+  INDEX `fk_video_watch_progress_student_idx` (`student_id` ASC),
+  CONSTRAINT `fk_video_watch_progress_video`
+    FOREIGN KEY (`video_id`)
+    REFERENCES `video` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_video_watch_progress_user`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_video_watch_progress_student`
+    FOREIGN KEY (`student_id`)
+    REFERENCES `student` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
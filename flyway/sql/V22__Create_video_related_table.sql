@@ -0,0 +1,23 @@
+-- -----------------------------------------------------
+-- Table `video_related`
+-- Related videos linked by an admin, shown in position
+-- order before any that are suggested automatically
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `video_related` (
+  `video_id` INT NOT NULL,
+  `related_id` INT NOT NULL,
+  `position` INT NOT NULL,
+  `created` DATETIME NOT NULL,
+  PRIMARY KEY (`video_id`, `related_id`),
+  INDEX `fk_video_related_related_idx` (`related_id` ASC),
+  CONSTRAINT `fk_video_related_video`
+    FOREIGN KEY (`video_id`)
+    REFERENCES `video` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_video_related_related`
+    FOREIGN KEY (`related_id`)
+    REFERENCES `video` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
@@ -0,0 +1,53 @@
+-- -----------------------------------------------------
+-- Table `teacher`
+-- The instructors who present videos. They don't need
+-- to have a user account
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `teacher` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(255) NOT NULL,
+  `bio` MEDIUMTEXT NULL,
+  `avatar_url` VARCHAR(1024) NULL,
+  `credentials` VARCHAR(1024) NULL,
+  `created` DATETIME NOT NULL,
+  `created_by` INT NOT NULL,
+  `updated` DATETIME NULL,
+  `updated_by` INT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `teacher_name_idx` (`name` ASC),
+  INDEX `fk_teacher_created_by_idx` (`created_by` ASC),
+  INDEX `fk_teacher_updated_by_idx` (`updated_by` ASC),
+  CONSTRAINT `fk_teacher_created_by`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_teacher_updated_by`
+    FOREIGN KEY (`updated_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+-- -----------------------------------------------------
+-- Table `video_teacher`
+-- The teachers of each video, in the order they are
+-- credited
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `video_teacher` (
+  `video_id` INT NOT NULL,
+  `teacher_id` INT NOT NULL,
+  `position` INT NOT NULL,
+  PRIMARY KEY (`video_id`, `teacher_id`),
+  INDEX `fk_video_teacher_teacher_idx` (`teacher_id` ASC),
+  CONSTRAINT `fk_video_teacher_video`
+    FOREIGN KEY (`video_id`)
+    REFERENCES `video` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_video_teacher_teacher`
+    FOREIGN KEY (`teacher_id`)
+    REFERENCES `teacher` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
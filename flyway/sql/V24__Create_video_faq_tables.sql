@@ -0,0 +1,74 @@
+-- -----------------------------------------------------
+-- Table `video_faq`
+-- Questions about a video and their answers. asked_by is
+-- the parent who asked, or null when an admin wrote the
+-- FAQ. Only published FAQs are shown with the video
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `video_faq` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `video_id` INT NOT NULL,
+  `question` TEXT NOT NULL,
+  `answer` MEDIUMTEXT NULL,
+  `status` VARCHAR(16) NOT NULL,
+  `position` INT NOT NULL DEFAULT 0,
+  `asked_by` INT NULL,
+  `answered` DATETIME NULL,
+  `answered_by` INT NULL,
+  `created` DATETIME NOT NULL,
+  `created_by` INT NOT NULL,
+  `updated` DATETIME NULL,
+  `updated_by` INT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `video_faq_status_idx` (`video_id` ASC, `status` ASC, `position` ASC),
+  INDEX `video_faq_queue_idx` (`status` ASC, `created` ASC),
+  INDEX `fk_video_faq_asked_by_idx` (`asked_by` ASC),
+  INDEX `fk_video_faq_created_by_idx` (`created_by` ASC),
+  CONSTRAINT `fk_video_faq_video`
+    FOREIGN KEY (`video_id`)
+    REFERENCES `video` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_video_faq_asked_by`
+    FOREIGN KEY (`asked_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_video_faq_created_by`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+-- -----------------------------------------------------
+-- Table `notification`
+-- Messages shown to a user in the app, e.g. that a
+-- question they asked has been answered
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `notification` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `user_id` INT NOT NULL,
+  `kind` VARCHAR(32) NOT NULL,
+  `message` VARCHAR(1024) NOT NULL,
+  `video_id` INT NULL,
+  `faq_id` INT NULL,
+  `is_read` TINYINT(1) NOT NULL DEFAULT 0,
+  `created` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `notification_user_idx` (`user_id` ASC, `is_read` ASC, `created` ASC),
+  CONSTRAINT `fk_notification_user`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_notification_video`
+    FOREIGN KEY (`video_id`)
+    REFERENCES `video` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_notification_faq`
+    FOREIGN KEY (`faq_id`)
+    REFERENCES `video_faq` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
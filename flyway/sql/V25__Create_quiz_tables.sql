@@ -0,0 +1,118 @@
+-- -----------------------------------------------------
+-- Table `quiz`
+-- Practice quizzes. Each is tied to either a video or a
+-- taxonomy node, so exactly one of video_id and
+-- taxonomy_id is set
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `quiz` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `title` VARCHAR(255) NOT NULL,
+  `description` TEXT NULL,
+  `video_id` INT NULL,
+  `taxonomy_id` INT NULL,
+  `created` DATETIME NOT NULL,
+  `created_by` INT NOT NULL,
+  `updated` DATETIME NULL,
+  `updated_by` INT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_quiz_video_idx` (`video_id` ASC),
+  INDEX `fk_quiz_taxonomy_idx` (`taxonomy_id` ASC),
+  INDEX `fk_quiz_created_by_idx` (`created_by` ASC),
+  CONSTRAINT `fk_quiz_video`
+    FOREIGN KEY (`video_id`)
+    REFERENCES `video` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_quiz_taxonomy`
+    FOREIGN KEY (`taxonomy_id`)
+    REFERENCES `taxonomy` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_quiz_created_by`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+-- -----------------------------------------------------
+-- Table `quiz_question`
+-- The questions of a quiz, asked in position order.
+-- choices and answers hold one choice or accepted answer
+-- per line
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `quiz_question` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `quiz_id` INT NOT NULL,
+  `position` INT NOT NULL,
+  `kind` VARCHAR(16) NOT NULL,
+  `prompt` TEXT NOT NULL,
+  `choices` TEXT NULL,
+  `answers` TEXT NOT NULL,
+  `points` INT NOT NULL DEFAULT 1,
+  PRIMARY KEY (`id`),
+  INDEX `quiz_question_position_idx` (`quiz_id` ASC, `position` ASC),
+  CONSTRAINT `fk_quiz_question_quiz`
+    FOREIGN KEY (`quiz_id`)
+    REFERENCES `quiz` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+-- -----------------------------------------------------
+-- Table `quiz_attempt`
+-- A student's scored attempt at a quiz. user_id is the
+-- parent who submitted it
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `quiz_attempt` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `quiz_id` INT NOT NULL,
+  `student_id` INT NOT NULL,
+  `user_id` INT NOT NULL,
+  `score` INT NOT NULL,
+  `max_score` INT NOT NULL,
+  `created` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `quiz_attempt_student_idx` (`student_id` ASC, `created` ASC),
+  INDEX `fk_quiz_attempt_quiz_idx` (`quiz_id` ASC),
+  INDEX `fk_quiz_attempt_user_idx` (`user_id` ASC),
+  CONSTRAINT `fk_quiz_attempt_quiz`
+    FOREIGN KEY (`quiz_id`)
+    REFERENCES `quiz` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_quiz_attempt_student`
+    FOREIGN KEY (`student_id`)
+    REFERENCES `student` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_quiz_attempt_user`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+-- -----------------------------------------------------
+-- Table `quiz_attempt_answer`
+-- The answer given to each question of an attempt. The
+-- prompt is copied from the question so the attempt
+-- still reads correctly after the quiz is edited, and
+-- question_id is kept even if the question is deleted
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `quiz_attempt_answer` (
+  `attempt_id` INT NOT NULL,
+  `position` INT NOT NULL,
+  `question_id` INT NOT NULL,
+  `prompt` TEXT NOT NULL,
+  `answer` TEXT NULL,
+  `correct` TINYINT(1) NOT NULL DEFAULT 0,
+  `points` INT NOT NULL,
+  `max_points` INT NOT NULL,
+  PRIMARY KEY (`attempt_id`, `position`),
+  CONSTRAINT `fk_quiz_attempt_answer_attempt`
+    FOREIGN KEY (`attempt_id`)
+    REFERENCES `quiz_attempt` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
@@ -0,0 +1,31 @@
+-- -----------------------------------------------------
+-- Table `user_role`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `user_role` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `user_id` INT NOT NULL,
+  `role` VARCHAR(45) NOT NULL,
+  `created` DATETIME NOT NULL,
+  `created_by` INT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `user_role_UNIQUE` (`user_id` ASC, `role` ASC),
+  INDEX `fk_user_role_created_by_idx` (`created_by` ASC),
+  CONSTRAINT `fk_user_role_user`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_user_role_created_by`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+-- existing users keep the access they had under the email-suffix check
+INSERT INTO `user_role` (`user_id`, `role`, `created`)
+SELECT `id`, 'admin', now() FROM `user`
+WHERE `verified` = 1 AND `email` LIKE '%@cohesioned.io';
+
+INSERT INTO `user_role` (`user_id`, `role`, `created`)
+SELECT `id`, 'parent', now() FROM `user`;
@@ -0,0 +1,51 @@
+-- -----------------------------------------------------
+-- Table `api_key`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `api_key` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(255) NOT NULL,
+  `prefix` VARCHAR(16) NOT NULL,
+  `key_hash` CHAR(64) NOT NULL,
+  `user_id` INT NOT NULL,
+  `created` DATETIME NOT NULL,
+  `created_by` INT NOT NULL,
+  `last_used` DATETIME NULL,
+  `revoked` DATETIME NULL,
+  `revoked_by` INT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `key_hash_UNIQUE` (`key_hash` ASC),
+  INDEX `fk_api_key_user_idx` (`user_id` ASC),
+  INDEX `fk_api_key_created_by_idx` (`created_by` ASC),
+  INDEX `fk_api_key_revoked_by_idx` (`revoked_by` ASC),
+  CONSTRAINT `fk_api_key_user`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `user` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_api_key_created_by`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_api_key_revoked_by`
+    FOREIGN KEY (`revoked_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+-- -----------------------------------------------------
+-- Table `api_key_permission`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `api_key_permission` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `api_key_id` INT NOT NULL,
+  `permission` VARCHAR(45) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `api_key_permission_UNIQUE` (`api_key_id` ASC, `permission` ASC),
+  CONSTRAINT `fk_api_key_permission_api_key`
+    FOREIGN KEY (`api_key_id`)
+    REFERENCES `api_key` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
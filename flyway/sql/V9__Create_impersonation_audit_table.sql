@@ -0,0 +1,25 @@
+-- -----------------------------------------------------
+-- Table `impersonation_audit`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `impersonation_audit` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `actor_id` INT NOT NULL,
+  `target_id` INT NOT NULL,
+  `method` VARCHAR(10) NOT NULL,
+  `path` VARCHAR(2048) NOT NULL,
+  `status` INT NOT NULL,
+  `created` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_impersonation_audit_actor_idx` (`actor_id` ASC, `created` ASC),
+  INDEX `fk_impersonation_audit_target_idx` (`target_id` ASC, `created` ASC),
+  CONSTRAINT `fk_impersonation_audit_actor`
+    FOREIGN KEY (`actor_id`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_impersonation_audit_target`
+    FOREIGN KEY (`target_id`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
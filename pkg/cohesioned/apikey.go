@@ -0,0 +1,62 @@
+package cohesioned
+
+import "time"
+
+//APIKey lets a service Profile call the API without logging in through Auth0. Only a hash of the key itself is ever stored
+type APIKey struct {
+	ID          int64        `json:"id"`
+	Name        string       `json:"name"`
+	Prefix      string       `json:"prefix"`
+	ProfileID   int64        `json:"profile_id"`
+	Permissions []Permission `json:"permissions"`
+	Created     time.Time    `json:"created"`
+	CreatedBy   int64        `json:"created_by"`
+	LastUsed    time.Time    `json:"last_used"`
+	Revoked     time.Time    `json:"revoked"`
+	RevokedBy   int64        `json:"revoked_by"`
+}
+
+//APIKeyPermissions returns the Permissions an APIKey can be scoped to. Keys manage content - they can never manage roles, other keys or impersonate users
+func APIKeyPermissions() []Permission {
+	return []Permission{
+		PermissionManageTaxonomy,
+		PermissionManageVideos,
+		PermissionManageStandards,
+		PermissionReviewVideos,
+		PermissionViewReports,
+		PermissionManageTeachers,
+		PermissionModerateFAQs,
+		PermissionManageQuizzes,
+	}
+}
+
+//IsAPIKeyPermission returns true if an APIKey can be scoped to the permission
+func (p Permission) IsAPIKeyPermission() bool {
+	for _, permission := range APIKeyPermissions() {
+		if permission == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (k *APIKey) IsRevoked() bool {
+	return k.Revoked != EmptyTime
+}
+
+//Grants returns true if the key has not been revoked and was scoped to the given Permission. Permissions keys can't be scoped to are never granted,
+//even to keys that were created with them
+func (k *APIKey) Grants(p Permission) bool {
+	if k.IsRevoked() || !p.IsAPIKeyPermission() {
+		return false
+	}
+
+	for _, permission := range k.Permissions {
+		if permission == p {
+			return true
+		}
+	}
+
+	return false
+}
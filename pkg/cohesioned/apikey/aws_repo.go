@@ -0,0 +1,238 @@
+package apikey
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/db"
+)
+
+type awsRepo struct {
+	*sql.DB
+}
+
+func NewAwsRepo(db *sql.DB) Repo {
+	return &awsRepo{
+		DB: db,
+	}
+}
+
+const selectQuery = `select
+		id,
+		name,
+		prefix,
+		user_id,
+		created,
+		created_by,
+		last_used,
+		revoked,
+		revoked_by
+	from api_key`
+
+func (repo *awsRepo) Get(id int64) (*cohesioned.APIKey, error) {
+	row := repo.QueryRow(selectQuery+` where id = ?`, id)
+
+	k, err := repo.mapRowToObject(row)
+	if err != nil {
+		return nil, fmt.Errorf("Unexpected error querying for api key by id %d: %v", id, err)
+	}
+
+	return repo.withPermissions(k)
+}
+
+func (repo *awsRepo) FindByHash(hash string) (*cohesioned.APIKey, error) {
+	row := repo.QueryRow(selectQuery+` where key_hash = ?`, hash)
+
+	k, err := repo.mapRowToObject(row)
+	if err != nil {
+		return nil, fmt.Errorf("Unexpected error querying for api key by hash: %v", err)
+	}
+
+	return repo.withPermissions(k)
+}
+
+func (repo *awsRepo) List() ([]*cohesioned.APIKey, error) {
+	var list []*cohesioned.APIKey
+
+	rows, err := repo.Query(selectQuery + ` order by created desc`)
+	if err != nil {
+		return list, fmt.Errorf("Failed to execute query: %v", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		k, err := repo.mapRowToObject(rows)
+		if err != nil {
+			return list, fmt.Errorf("an unexpected error occurred while processing the result set from the db: %v", err)
+		}
+
+		list = append(list, k)
+	}
+
+	if err := rows.Err(); err != nil {
+		return list, fmt.Errorf("rows had an error: %v", err)
+	}
+
+	for _, k := range list {
+		if _, err := repo.withPermissions(k); err != nil {
+			return list, err
+		}
+	}
+
+	return list, nil
+}
+
+func (repo *awsRepo) Save(k *cohesioned.APIKey, hash string) (int64, error) {
+	sql := `insert into api_key
+	(
+		name,
+		prefix,
+		key_hash,
+		user_id,
+		created,
+		created_by
+	) values (?, ?, ?, ?, ?, ?)`
+
+	stmt, err := repo.Prepare(sql)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to prepare statement %s: %v", sql, err)
+	}
+
+	result, err := stmt.Exec(
+		k.Name,
+		k.Prefix,
+		hash,
+		k.ProfileID,
+		k.Created,
+		k.CreatedBy,
+	)
+
+	if err != nil {
+		return 0, fmt.Errorf("Failed to insert api key: %v", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get last insert id from result: %v", err)
+	}
+
+	if err := repo.SetPermissions(id, k.Permissions); err != nil {
+		return id, err
+	}
+
+	return id, nil
+}
+
+//SetPermissions replaces the key's permissions in a single transaction so a key is never briefly unscoped
+func (repo *awsRepo) SetPermissions(id int64, permissions []cohesioned.Permission) error {
+	tx, err := repo.Begin()
+	if err != nil {
+		return fmt.Errorf("Failed to begin transaction: %v", err)
+	}
+
+	if _, err := tx.Exec(`delete from api_key_permission where api_key_id = ?`, id); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("Failed to clear permissions of api key %d: %v", id, err)
+	}
+
+	for _, permission := range permissions {
+		if _, err := tx.Exec(`insert into api_key_permission (api_key_id, permission) values (?, ?)`, id, string(permission)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Failed to grant permission %s to api key %d: %v", permission, id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("Failed to commit permissions of api key %d: %v", id, err)
+	}
+
+	return nil
+}
+
+func (repo *awsRepo) Revoke(id int64, revokedBy int64) error {
+	sql := `update api_key set revoked = ?, revoked_by = ? where id = ? and revoked is null`
+
+	result, err := repo.Exec(sql, time.Now(), revokedBy, id)
+	if err != nil {
+		return fmt.Errorf("Failed to revoke api key %d: %v", id, err)
+	}
+
+	rowsEffected, err := result.RowsAffected()
+	if err != nil || rowsEffected == 0 {
+		return fmt.Errorf("Failed to revoke api key %d - it may not exist or is already revoked: %v", id, err)
+	}
+
+	return nil
+}
+
+func (repo *awsRepo) Touch(id int64, lastUsed time.Time) error {
+	if _, err := repo.Exec(`update api_key set last_used = ? where id = ?`, lastUsed, id); err != nil {
+		return fmt.Errorf("Failed to update last used time of api key %d: %v", id, err)
+	}
+
+	return nil
+}
+
+func (repo *awsRepo) withPermissions(k *cohesioned.APIKey) (*cohesioned.APIKey, error) {
+	if k == nil {
+		return nil, nil
+	}
+
+	rows, err := repo.Query(`select permission from api_key_permission where api_key_id = ? order by permission`, k.ID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list permissions of api key %d: %v", k.ID, err)
+	}
+
+	defer rows.Close()
+	k.Permissions = []cohesioned.Permission{}
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, fmt.Errorf("an unexpected error occurred while processing the api key permission result set from the db: %v", err)
+		}
+
+		k.Permissions = append(k.Permissions, cohesioned.Permission(permission))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("api key permission rows had an error: %v", err)
+	}
+
+	return k, nil
+}
+
+func (repo *awsRepo) mapRowToObject(rs db.RowScanner) (*cohesioned.APIKey, error) {
+	k := new(cohesioned.APIKey)
+
+	var lastUsed db.NullTime
+	var revoked db.NullTime
+	var revokedBy sql.NullInt64
+
+	err := rs.Scan(
+		&k.ID,
+		&k.Name,
+		&k.Prefix,
+		&k.ProfileID,
+		&k.Created,
+		&k.CreatedBy,
+		&lastUsed,
+		&revoked,
+		&revokedBy,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	k.LastUsed = lastUsed.Time
+	k.Revoked = revoked.Time
+	k.RevokedBy = revokedBy.Int64
+
+	return k, nil
+}
@@ -0,0 +1,104 @@
+//+build integration
+
+package apikey_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/apikey"
+	"github.com/cohesion-education/api/pkg/cohesioned/config"
+	"github.com/cohesion-education/api/testutils"
+)
+
+var (
+	repo apikey.Repo
+)
+
+func TestMain(m *testing.M) {
+	awsConfig, err := config.NewAwsConfig()
+	if err != nil {
+		panic("Unexpected error initializing AwsConfig: " + err.Error())
+	}
+
+	db, err := awsConfig.DialRDS()
+	if err != nil {
+		panic("Failed to connect to db " + err.Error())
+	}
+
+	repo = apikey.NewAwsRepo(db)
+
+	if err := testutils.SetupDB(db); err != nil {
+		fmt.Println(err.Error())
+	}
+
+	testResult := m.Run()
+
+	if err := testutils.CleanupDB(db); err != nil {
+		fmt.Println(err.Error())
+	}
+
+	os.Exit(testResult)
+}
+
+func TestRepoSaveFindAndRevoke(t *testing.T) {
+	key, prefix, hash, err := apikey.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	k := &cohesioned.APIKey{
+		Name:        "integration test",
+		Prefix:      prefix,
+		ProfileID:   1,
+		Permissions: []cohesioned.Permission{cohesioned.PermissionManageVideos},
+		Created:     time.Now(),
+		CreatedBy:   1,
+	}
+
+	id, err := repo.Save(k, hash)
+	if err != nil {
+		t.Fatalf("Failed to save api key: %v", err)
+	}
+
+	found, err := repo.FindByHash(apikey.Hash(key))
+	if err != nil {
+		t.Fatalf("Failed to find api key by hash: %v", err)
+	}
+
+	if found == nil || found.ID != id {
+		t.Fatalf("expected to find api key %d but got %v", id, found)
+	}
+
+	if !found.Grants(cohesioned.PermissionManageVideos) {
+		t.Errorf("expected the saved permissions to be loaded but got %v", found.Permissions)
+	}
+
+	if err := repo.SetPermissions(id, []cohesioned.Permission{cohesioned.PermissionManageTaxonomy}); err != nil {
+		t.Fatalf("Failed to set permissions: %v", err)
+	}
+
+	if err := repo.Touch(id, time.Now()); err != nil {
+		t.Fatalf("Failed to touch api key: %v", err)
+	}
+
+	if err := repo.Revoke(id, 1); err != nil {
+		t.Fatalf("Failed to revoke api key: %v", err)
+	}
+
+	revoked, err := repo.Get(id)
+	if err != nil {
+		t.Fatalf("Failed to get api key: %v", err)
+	}
+
+	if !revoked.IsRevoked() || revoked.LastUsed == cohesioned.EmptyTime {
+		t.Errorf("expected api key to be revoked with a last used time but got %v", revoked)
+	}
+
+	if revoked.Grants(cohesioned.PermissionManageTaxonomy) {
+		t.Error("revoked keys should not grant any permission")
+	}
+}
@@ -0,0 +1,319 @@
+package apikey
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/profile"
+	"github.com/gorilla/mux"
+	"github.com/unrolled/render"
+)
+
+const (
+	//serviceEmailDomain is where the emails of service profiles live, so they can never collide with a real user's
+	serviceEmailDomain = "services.cohesioned.io"
+)
+
+var (
+	nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+type APIKeyResponse struct {
+	*cohesioned.APIResponse
+	APIKey *cohesioned.APIKey `json:"api_key,omitempty"`
+	//Key is only ever returned once, when the key is created
+	Key  string               `json:"key,omitempty"`
+	List []*cohesioned.APIKey `json:"api_keys,omitempty"`
+}
+
+func NewAPIKeyResponse() *APIKeyResponse {
+	return &APIKeyResponse{
+		APIResponse: &cohesioned.APIResponse{},
+	}
+}
+
+type createRequest struct {
+	Name        string                  `json:"name"`
+	Service     string                  `json:"service"`
+	Permissions []cohesioned.Permission `json:"permissions"`
+}
+
+func ListHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := NewAPIKeyResponse()
+
+		list, err := repo.List()
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred listing api keys %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		if list == nil {
+			list = []*cohesioned.APIKey{}
+		}
+
+		resp.List = list
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//CreateHandler creates a key for the named service, creating the service's profile the first time a key is created for it
+func CreateHandler(r *render.Render, repo Repo, profileRepo profile.Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := NewAPIKeyResponse()
+
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		defer req.Body.Close()
+		decoder := json.NewDecoder(req.Body)
+
+		incoming := &createRequest{}
+		if err := decoder.Decode(incoming); err != nil {
+			resp.SetErrMsg("failed to unmarshall json %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		if len(strings.TrimSpace(incoming.Name)) == 0 {
+			resp.AddValidationError("name", "name is required")
+		}
+
+		if len(serviceSlug(incoming.Service)) == 0 {
+			resp.AddValidationError("service", "service is required")
+		}
+
+		validatePermissions(resp, incoming.Permissions)
+
+		if len(resp.ValidationErrors) > 0 {
+			resp.SetErrMsg("Invalid api key")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		serviceProfile, err := findOrCreateServiceProfile(profileRepo, incoming.Service, currentUser.ID)
+		if err != nil {
+			resp.SetErrMsg("Failed to set up the profile for service %s: %v", incoming.Service, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		key, prefix, hash, err := Generate()
+		if err != nil {
+			resp.SetErr(err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		k := &cohesioned.APIKey{
+			Name:        strings.TrimSpace(incoming.Name),
+			Prefix:      prefix,
+			ProfileID:   serviceProfile.ID,
+			Permissions: incoming.Permissions,
+			Created:     time.Now(),
+			CreatedBy:   currentUser.ID,
+		}
+
+		id, err := repo.Save(k, hash)
+		k.ID = id
+		if err != nil {
+			resp.SetErrMsg("Failed to save api key: %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		fmt.Printf("user %d created api key %d (%s) for service profile %d with permissions %v\n", currentUser.ID, k.ID, k.Prefix, k.ProfileID, k.Permissions)
+		resp.APIKey = k
+		resp.Key = key
+		r.JSON(w, http.StatusCreated, resp)
+	}
+}
+
+//UpdatePermissionsHandler replaces the permissions a key is scoped to
+func UpdatePermissionsHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		k, resp, status := findKeyFromPath(req, repo)
+		if k == nil {
+			r.JSON(w, status, resp)
+			return
+		}
+
+		defer req.Body.Close()
+		decoder := json.NewDecoder(req.Body)
+
+		incoming := struct {
+			Permissions []cohesioned.Permission `json:"permissions"`
+		}{}
+
+		if err := decoder.Decode(&incoming); err != nil {
+			resp.SetErrMsg("failed to unmarshall json %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		if validatePermissions(resp, incoming.Permissions); len(resp.ValidationErrors) > 0 {
+			resp.SetErrMsg("Invalid permissions")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		if k.IsRevoked() {
+			resp.SetErrMsg("api key %d has been revoked", k.ID)
+			r.JSON(w, http.StatusConflict, resp)
+			return
+		}
+
+		if err := repo.SetPermissions(k.ID, incoming.Permissions); err != nil {
+			resp.SetErrMsg("Failed to update permissions of api key %d: %v", k.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		k.Permissions = incoming.Permissions
+		resp.APIKey = k
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func RevokeHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		k, resp, status := findKeyFromPath(req, repo)
+		if k == nil {
+			r.JSON(w, status, resp)
+			return
+		}
+
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		if k.IsRevoked() {
+			resp.APIKey = k
+			r.JSON(w, http.StatusOK, resp)
+			return
+		}
+
+		if err := repo.Revoke(k.ID, currentUser.ID); err != nil {
+			resp.SetErrMsg("Failed to revoke api key %d: %v", k.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		fmt.Printf("user %d revoked api key %d (%s)\n", currentUser.ID, k.ID, k.Prefix)
+		k.Revoked = time.Now()
+		k.RevokedBy = currentUser.ID
+		resp.APIKey = k
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func validatePermissions(resp *APIKeyResponse, permissions []cohesioned.Permission) {
+	if len(permissions) == 0 {
+		resp.AddValidationError("permissions", "at least one permission is required")
+		return
+	}
+
+	for _, permission := range permissions {
+		if !permission.IsValid() {
+			resp.AddValidationError("permissions", fmt.Sprintf("%s is not a valid permission - permissions must be one of %v", permission, cohesioned.APIKeyPermissions()))
+			continue
+		}
+
+		if !permission.IsAPIKeyPermission() {
+			resp.AddValidationError("permissions", fmt.Sprintf("api keys can't be granted %s - permissions must be one of %v", permission, cohesioned.APIKeyPermissions()))
+		}
+	}
+}
+
+//findKeyFromPath looks up the key identified by the {id} path param. When the key can't be found, the returned APIKeyResponse and status describe why
+func findKeyFromPath(req *http.Request, repo Repo) (*cohesioned.APIKey, *APIKeyResponse, int) {
+	vars := mux.Vars(req)
+	resp := NewAPIKeyResponse()
+
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		resp.SetErrMsg("%s is not a valid id %v", vars["id"], err)
+		return nil, resp, http.StatusBadRequest
+	}
+
+	k, err := repo.Get(id)
+	if err != nil {
+		resp.SetErrMsg("An unexpected error occurred when trying to find api key %d: %v", id, err)
+		fmt.Println(resp.ErrMsg)
+		return nil, resp, http.StatusInternalServerError
+	}
+
+	if k == nil {
+		resp.SetErrMsg("%d is not a valid api key id", id)
+		return nil, resp, http.StatusNotFound
+	}
+
+	return k, resp, http.StatusOK
+}
+
+func serviceSlug(service string) string {
+	return strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(service), "-"), "-")
+}
+
+func findOrCreateServiceProfile(repo profile.Repo, service string, createdBy int64) (*cohesioned.Profile, error) {
+	email := fmt.Sprintf("%s@%s", serviceSlug(service), serviceEmailDomain)
+
+	existing, err := repo.FindByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		if !existing.HasRole(cohesioned.RoleService) {
+			return nil, fmt.Errorf("%s belongs to a profile that is not a service", email)
+		}
+
+		return existing, nil
+	}
+
+	p := &cohesioned.Profile{
+		Created:       time.Now(),
+		Email:         email,
+		FullName:      strings.TrimSpace(service),
+		Enabled:       true,
+		EmailVerified: true,
+		Onboarded:     true,
+	}
+
+	id, err := repo.Save(p)
+	if err != nil {
+		return nil, err
+	}
+
+	p.ID = id
+	if err := repo.AddRole(p.ID, cohesioned.RoleService, createdBy); err != nil {
+		return nil, err
+	}
+
+	p.Roles = []cohesioned.Role{cohesioned.RoleService}
+	return p, nil
+}
@@ -0,0 +1,118 @@
+package apikey_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/apikey"
+)
+
+func TestCreateHandler(t *testing.T) {
+	payload := `{"name":"content pipeline","service":"Content Pipeline","permissions":["videos:manage","taxonomy:manage"]}`
+	req, err := http.NewRequest("POST", "/api/admin/apikeys", strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("Failed to initialize new request %v", err)
+	}
+
+	repo := new(fakes.FakeAPIKeyRepo)
+	repo.SaveReturns(9, nil)
+	profileRepo := new(fakes.FakeProfileRepo)
+	profileRepo.SaveReturns(42, nil)
+
+	ctx := context.WithValue(req.Context(), cohesioned.CurrentUserKey, fakes.FakeAdmin())
+	rr := httptest.NewRecorder()
+	handler := apikey.CreateHandler(fakes.FakeRenderer, repo, profileRepo)
+	handler.ServeHTTP(rr, req.WithContext(ctx))
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v - %s", status, http.StatusCreated, rr.Body.String())
+	}
+
+	resp := apikey.NewAPIKeyResponse()
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("failed to unmarshall json response %v", err)
+	}
+
+	if !apikey.LooksLikeKey(resp.Key) {
+		t.Errorf("expected the new key to be returned but got %q", resp.Key)
+	}
+
+	if apikey.Hash(resp.Key) != repo.SavedHash {
+		t.Error("expected the hash of the returned key to be saved")
+	}
+
+	if strings.Contains(rr.Body.String(), repo.SavedHash) {
+		t.Error("the key hash should never be returned")
+	}
+
+	if resp.APIKey.ID != 9 || resp.APIKey.ProfileID != 42 {
+		t.Errorf("expected key 9 for service profile 42 but got key %d for profile %d", resp.APIKey.ID, resp.APIKey.ProfileID)
+	}
+
+	if !strings.HasPrefix(resp.Key, resp.APIKey.Prefix) {
+		t.Errorf("expected prefix %s to be the start of the key", resp.APIKey.Prefix)
+	}
+}
+
+func TestCreateHandlerWithInvalidPermissions(t *testing.T) {
+	payload := `{"name":"content pipeline","service":"content-pipeline","permissions":["everything"]}`
+	req, err := http.NewRequest("POST", "/api/admin/apikeys", strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("Failed to initialize new request %v", err)
+	}
+
+	ctx := context.WithValue(req.Context(), cohesioned.CurrentUserKey, fakes.FakeAdmin())
+	rr := httptest.NewRecorder()
+	handler := apikey.CreateHandler(fakes.FakeRenderer, new(fakes.FakeAPIKeyRepo), new(fakes.FakeProfileRepo))
+	handler.ServeHTTP(rr, req.WithContext(ctx))
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	resp := apikey.NewAPIKeyResponse()
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("failed to unmarshall json response %v", err)
+	}
+
+	if len(resp.ValidationErrors) != 1 || resp.ValidationErrors[0].Field != "permissions" {
+		t.Errorf("expected a single validation error on permissions but got %v", resp.ValidationErrors)
+	}
+}
+
+func TestCreateHandlerRejectsAdminPermissions(t *testing.T) {
+	payload := `{"name":"content pipeline","service":"content-pipeline","permissions":["videos:manage","roles:manage","apikeys:manage","profiles:impersonate"]}`
+	req, err := http.NewRequest("POST", "/api/admin/apikeys", strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("Failed to initialize new request %v", err)
+	}
+
+	repo := new(fakes.FakeAPIKeyRepo)
+	ctx := context.WithValue(req.Context(), cohesioned.CurrentUserKey, fakes.FakeAdmin())
+	rr := httptest.NewRecorder()
+	handler := apikey.CreateHandler(fakes.FakeRenderer, repo, new(fakes.FakeProfileRepo))
+	handler.ServeHTTP(rr, req.WithContext(ctx))
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	resp := apikey.NewAPIKeyResponse()
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("failed to unmarshall json response %v", err)
+	}
+
+	if len(resp.ValidationErrors) != 3 {
+		t.Errorf("expected a validation error for each admin permission but got %v", resp.ValidationErrors)
+	}
+
+	if len(repo.SavedHash) != 0 {
+		t.Error("a key with admin permissions should not be saved")
+	}
+}
@@ -0,0 +1,39 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	//keyPrefix makes keys easy to recognize, e.g. by secret scanners
+	keyPrefix = "coh_"
+	//displayPrefixLength is how much of a key is stored in the clear so admins can tell keys apart
+	displayPrefixLength = len(keyPrefix) + 8
+)
+
+//Generate creates a new random key, returning the key itself, the prefix that is safe to display and the hash to persist
+func Generate() (key string, prefix string, hash string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", fmt.Errorf("Failed to generate api key: %v", err)
+	}
+
+	key = keyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return key, key[:displayPrefixLength], Hash(key), nil
+}
+
+//Hash is the SHA-256 of the key. Keys carry 256 bits of entropy, so a slow password hash isn't needed
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+//LooksLikeKey returns true if s has the shape of a key created by Generate
+func LooksLikeKey(s string) bool {
+	return strings.HasPrefix(s, keyPrefix) && len(s) > displayPrefixLength
+}
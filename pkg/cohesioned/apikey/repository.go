@@ -0,0 +1,17 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+type Repo interface {
+	Get(id int64) (*cohesioned.APIKey, error)
+	FindByHash(hash string) (*cohesioned.APIKey, error)
+	List() ([]*cohesioned.APIKey, error)
+	Save(k *cohesioned.APIKey, hash string) (int64, error)
+	SetPermissions(id int64, permissions []cohesioned.Permission) error
+	Revoke(id int64, revokedBy int64) error
+	Touch(id int64, lastUsed time.Time) error
+}
@@ -0,0 +1,15 @@
+package cohesioned
+
+import "time"
+
+//ImpersonationEvent records a single request an admin made while acting as another user
+type ImpersonationEvent struct {
+	ID       int64  `json:"id"`
+	ActorID  int64  `json:"actor_id"`
+	TargetID int64  `json:"target_id"`
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	//Status is the status of the response, or 0 if the request hasn't been answered
+	Status  int       `json:"status"`
+	Created time.Time `json:"created"`
+}
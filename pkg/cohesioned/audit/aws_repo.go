@@ -0,0 +1,154 @@
+package audit
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/db"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+type awsRepo struct {
+	*sql.DB
+}
+
+func NewAwsRepo(db *sql.DB) Repo {
+	return &awsRepo{
+		DB: db,
+	}
+}
+
+func (repo *awsRepo) Save(e *cohesioned.ImpersonationEvent) (int64, error) {
+	sql := `insert into impersonation_audit
+	(
+		actor_id,
+		target_id,
+		method,
+		path,
+		status,
+		created
+	) values (?, ?, ?, ?, ?, ?)`
+
+	stmt, err := repo.Prepare(sql)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to prepare statement %s: %v", sql, err)
+	}
+
+	result, err := stmt.Exec(
+		e.ActorID,
+		e.TargetID,
+		e.Method,
+		e.Path,
+		e.Status,
+		e.Created,
+	)
+
+	if err != nil {
+		return 0, fmt.Errorf("Failed to insert impersonation event: %v", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get last insert id from result: %v", err)
+	}
+
+	return id, nil
+}
+
+func (repo *awsRepo) UpdateStatus(id int64, status int) error {
+	if _, err := repo.Exec(`update impersonation_audit set status = ? where id = ?`, status, id); err != nil {
+		return fmt.Errorf("Failed to update status of impersonation event %d: %v", id, err)
+	}
+
+	return nil
+}
+
+func (repo *awsRepo) List(filter Filter) ([]*cohesioned.ImpersonationEvent, error) {
+	var list []*cohesioned.ImpersonationEvent
+
+	var conditions []string
+	var args []interface{}
+	if filter.ActorID != 0 {
+		conditions = append(conditions, "actor_id = ?")
+		args = append(args, filter.ActorID)
+	}
+
+	if filter.TargetID != 0 {
+		conditions = append(conditions, "target_id = ?")
+		args = append(args, filter.TargetID)
+	}
+
+	query := `select
+			id,
+			actor_id,
+			target_id,
+			method,
+			path,
+			status,
+			created
+		from
+			impersonation_audit`
+
+	if len(conditions) > 0 {
+		query += " where " + strings.Join(conditions, " and ")
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	query += " order by created desc, id desc limit ?"
+	args = append(args, limit)
+
+	rows, err := repo.Query(query, args...)
+	if err != nil {
+		return list, fmt.Errorf("Failed to execute query: %v", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		e, err := repo.mapRowToObject(rows)
+		if err != nil {
+			return list, fmt.Errorf("an unexpected error occurred while processing the result set from the db: %v", err)
+		}
+
+		list = append(list, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return list, fmt.Errorf("rows had an error: %v", err)
+	}
+
+	return list, nil
+}
+
+func (repo *awsRepo) mapRowToObject(rs db.RowScanner) (*cohesioned.ImpersonationEvent, error) {
+	e := new(cohesioned.ImpersonationEvent)
+
+	err := rs.Scan(
+		&e.ID,
+		&e.ActorID,
+		&e.TargetID,
+		&e.Method,
+		&e.Path,
+		&e.Status,
+		&e.Created,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/unrolled/render"
+)
+
+type ImpersonationResponse struct {
+	*cohesioned.APIResponse
+	List []*cohesioned.ImpersonationEvent `json:"events"`
+}
+
+func NewImpersonationResponse() *ImpersonationResponse {
+	return &ImpersonationResponse{
+		APIResponse: &cohesioned.APIResponse{},
+		List:        []*cohesioned.ImpersonationEvent{},
+	}
+}
+
+//ListImpersonationsHandler lists impersonated requests, newest first, optionally filtered by the actor_id and target_id query params
+func ListImpersonationsHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := NewImpersonationResponse()
+		filter := Filter{}
+
+		for param, dest := range map[string]*int64{"actor_id": &filter.ActorID, "target_id": &filter.TargetID} {
+			value := req.URL.Query().Get(param)
+			if len(value) == 0 {
+				continue
+			}
+
+			id, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				resp.AddValidationError(param, fmt.Sprintf("%s is not a valid id", value))
+				continue
+			}
+
+			*dest = id
+		}
+
+		if limit := req.URL.Query().Get("limit"); len(limit) > 0 {
+			l, err := strconv.Atoi(limit)
+			if err != nil {
+				resp.AddValidationError("limit", fmt.Sprintf("%s is not a valid limit", limit))
+			}
+
+			filter.Limit = l
+		}
+
+		if len(resp.ValidationErrors) > 0 {
+			resp.SetErrMsg("Invalid filter")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		list, err := repo.List(filter)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred listing impersonation events %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		if list != nil {
+			resp.List = list
+		}
+
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,19 @@
+package audit
+
+import (
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+//Filter narrows a listing of impersonation events. Zero values match everything
+type Filter struct {
+	ActorID  int64
+	TargetID int64
+	Limit    int
+}
+
+type Repo interface {
+	Save(e *cohesioned.ImpersonationEvent) (int64, error)
+	//UpdateStatus records the status of the response to an event saved before the request was handled
+	UpdateStatus(id int64, status int) error
+	List(filter Filter) ([]*cohesioned.ImpersonationEvent, error)
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/apikey"
+	"github.com/cohesion-education/api/pkg/cohesioned/profile"
+	"github.com/unrolled/render"
+	"github.com/urfave/negroni"
+)
+
+const (
+	APIKeyHeader = "X-API-Key"
+	//lastUsedResolution limits how often a busy key's last used timestamp is written to the db
+	lastUsedResolution = time.Minute
+)
+
+//CheckAPIKey authenticates requests that carry an API key, either in the X-API-Key header or as the bearer token.
+//Requests without a key are passed on to fallback, normally CheckJwt
+func CheckAPIKey(r *render.Render, repo apikey.Repo, profileRepo profile.Repo, fallback negroni.HandlerFunc) negroni.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+		key, ok := apiKeyFromRequest(req)
+		if !ok {
+			fallback(w, req, next)
+			return
+		}
+
+		k, err := repo.FindByHash(apikey.Hash(key))
+		if err != nil {
+			resp := cohesioned.NewAPIErrorResponse("Failed to look up api key: %v", err)
+			fmt.Printf("%s\n", resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		if k == nil || k.IsRevoked() {
+			r.JSON(w, http.StatusUnauthorized, cohesioned.NewAPIErrorResponse("Invalid or revoked api key"))
+			return
+		}
+
+		p, err := profileRepo.Get(k.ProfileID)
+		if err != nil || p == nil {
+			resp := cohesioned.NewAPIErrorResponse("Failed to find the service profile of api key %d: %v", k.ID, err)
+			fmt.Printf("%s\n", resp.ErrMsg)
+			r.JSON(w, http.StatusUnauthorized, resp)
+			return
+		}
+
+		if now := time.Now(); now.Sub(k.LastUsed) > lastUsedResolution {
+			if err := repo.Touch(k.ID, now); err != nil {
+				fmt.Println(err)
+			}
+			k.LastUsed = now
+		}
+
+		p.APIKey = k
+		ctx := context.WithValue(req.Context(), cohesioned.CurrentUserKey, p)
+		next(w, req.WithContext(ctx))
+	}
+}
+
+func apiKeyFromRequest(req *http.Request) (string, bool) {
+	if key := strings.TrimSpace(req.Header.Get(APIKeyHeader)); len(key) > 0 {
+		return key, true
+	}
+
+	if token, err := bearerToken(req); err == nil && apikey.LooksLikeKey(token) {
+		return token, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,111 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/apikey"
+	"github.com/cohesion-education/api/pkg/cohesioned/auth"
+)
+
+func newAPIKeyRequest(t *testing.T) *http.Request {
+	key, _, _, err := apikey.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate api key: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", "/api/video", nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize new request %v", err)
+	}
+
+	req.Header.Set(auth.APIKeyHeader, key)
+	return req
+}
+
+func TestCheckAPIKeyScopesServiceProfileToKeyPermissions(t *testing.T) {
+	repo := new(fakes.FakeAPIKeyRepo)
+	repo.FindByHashReturns(&cohesioned.APIKey{ID: 3, ProfileID: 42, Permissions: []cohesioned.Permission{cohesioned.PermissionManageVideos}}, nil)
+	profileRepo := new(fakes.FakeProfileRepo)
+	profileRepo.GetReturns(&cohesioned.Profile{ID: 42, Roles: []cohesioned.Role{cohesioned.RoleService}}, nil)
+
+	var current *cohesioned.Profile
+	mockNextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current, _ = cohesioned.FromRequest(r)
+	})
+
+	fallback := func(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+		t.Error("requests with an api key should not fall back to jwt validation")
+	}
+
+	rr := httptest.NewRecorder()
+	handler := auth.CheckAPIKey(fakes.FakeRenderer, repo, profileRepo, fallback)
+	handler.ServeHTTP(rr, newAPIKeyRequest(t), mockNextHandler)
+
+	if current == nil || current.ID != 42 {
+		t.Fatalf("expected service profile 42 to be the current user but got %v", current)
+	}
+
+	if !current.Can(cohesioned.PermissionManageVideos) {
+		t.Error("expected the key's permission to be granted")
+	}
+
+	if current.Can(cohesioned.PermissionManageTaxonomy) {
+		t.Error("expected permissions outside the key's scope to be denied")
+	}
+
+	if repo.TouchedWith == cohesioned.EmptyTime {
+		t.Error("expected the key's last used time to be updated")
+	}
+}
+
+func TestCheckAPIKeyRejectsRevokedKeys(t *testing.T) {
+	repo := new(fakes.FakeAPIKeyRepo)
+	repo.FindByHashReturns(&cohesioned.APIKey{ID: 3, ProfileID: 42, Revoked: time.Now()}, nil)
+
+	mockNextHandlerCalled := false
+	mockNextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mockNextHandlerCalled = true
+	})
+
+	fallback := func(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+		t.Error("requests with an api key should not fall back to jwt validation")
+	}
+
+	rr := httptest.NewRecorder()
+	handler := auth.CheckAPIKey(fakes.FakeRenderer, repo, new(fakes.FakeProfileRepo), fallback)
+	handler.ServeHTTP(rr, newAPIKeyRequest(t), mockNextHandler)
+
+	if mockNextHandlerCalled {
+		t.Errorf("'next' handler should not have been called")
+	}
+
+	if status := rr.Code; status != http.StatusUnauthorized {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+}
+
+func TestCheckAPIKeyFallsBackWithoutKey(t *testing.T) {
+	req, err := http.NewRequest("GET", "/api/profile", nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize new request %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer not.an.apikey")
+
+	fallbackCalled := false
+	fallback := func(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+		fallbackCalled = true
+	}
+
+	rr := httptest.NewRecorder()
+	handler := auth.CheckAPIKey(fakes.FakeRenderer, new(fakes.FakeAPIKeyRepo), new(fakes.FakeProfileRepo), fallback)
+	handler.ServeHTTP(rr, req, func(w http.ResponseWriter, r *http.Request) {})
+
+	if !fallbackCalled {
+		t.Error("expected requests without an api key to fall back to jwt validation")
+	}
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/audit"
+	"github.com/cohesion-education/api/pkg/cohesioned/profile"
+	"github.com/unrolled/render"
+	"github.com/urfave/negroni"
+)
+
+const (
+	//ImpersonateHeader carries the ID of the profile an admin wants to act as
+	ImpersonateHeader = "X-Impersonate-User"
+)
+
+//Impersonate lets admins with PermissionImpersonate act as the profile named in the X-Impersonate-User header.
+//The impersonated profile becomes the current user, with the admin recorded as its ImpersonatedBy. Impersonated requests are read-only and every one is written to the audit trail
+func Impersonate(r *render.Render, profileRepo profile.Repo, auditRepo audit.Repo) negroni.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+		header := strings.TrimSpace(req.Header.Get(ImpersonateHeader))
+		if len(header) == 0 {
+			next(w, req)
+			return
+		}
+
+		actor, ok := cohesioned.FromRequest(req)
+		if !ok {
+			resp := cohesioned.NewAPIErrorResponse("failed to get current user from request context")
+			fmt.Printf("%s\n", resp.ErrMsg)
+			r.JSON(w, http.StatusUnauthorized, resp)
+			return
+		}
+
+		if actor.APIKey != nil || !actor.Can(cohesioned.PermissionImpersonate) {
+			r.JSON(w, http.StatusForbidden, cohesioned.NewAPIErrorResponse("You are not authorized to impersonate other users"))
+			return
+		}
+
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			r.JSON(w, http.StatusForbidden, cohesioned.NewAPIErrorResponse("Impersonated requests are read-only"))
+			return
+		}
+
+		targetID, err := strconv.ParseInt(header, 10, 64)
+		if err != nil {
+			r.JSON(w, http.StatusBadRequest, cohesioned.NewAPIErrorResponse("%s is not a valid user id", header))
+			return
+		}
+
+		if targetID == actor.ID {
+			next(w, req)
+			return
+		}
+
+		target, err := profileRepo.Get(targetID)
+		if err != nil {
+			resp := cohesioned.NewAPIErrorResponse("An unexpected error occurred when trying to find user %d: %v", targetID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		if target == nil {
+			r.JSON(w, http.StatusNotFound, cohesioned.NewAPIErrorResponse("%d is not a valid user id", targetID))
+			return
+		}
+
+		//the event is saved before the request is handled so no impersonated request goes unaudited
+		event := &cohesioned.ImpersonationEvent{
+			ActorID:  actor.ID,
+			TargetID: target.ID,
+			Method:   req.Method,
+			Path:     req.URL.RequestURI(),
+			Created:  time.Now(),
+		}
+
+		if event.ID, err = auditRepo.Save(event); err != nil {
+			resp := cohesioned.NewAPIErrorResponse("Failed to audit user %d impersonating user %d on %s %s: %v", actor.ID, target.ID, event.Method, event.Path, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		target.ImpersonatedBy = actor
+		ctx := context.WithValue(req.Context(), cohesioned.CurrentUserKey, target)
+
+		rw := negroni.NewResponseWriter(w)
+		next(rw, req.WithContext(ctx))
+
+		event.Status = rw.Status()
+		if err := auditRepo.UpdateStatus(event.ID, event.Status); err != nil {
+			fmt.Printf("Failed to record the status of impersonation event %d: %v\n", event.ID, err)
+		}
+	}
+}
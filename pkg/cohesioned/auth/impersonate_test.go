@@ -0,0 +1,134 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/auth"
+)
+
+func newImpersonationRequest(t *testing.T, method string, actor *cohesioned.Profile) *http.Request {
+	req, err := http.NewRequest(method, "/api/profile/students", nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize new request %v", err)
+	}
+
+	req.Header.Set(auth.ImpersonateHeader, "1")
+	ctx := context.WithValue(req.Context(), cohesioned.CurrentUserKey, actor)
+	return req.WithContext(ctx)
+}
+
+func TestImpersonateActsAsTargetAndAudits(t *testing.T) {
+	profileRepo := new(fakes.FakeProfileRepo)
+	profileRepo.GetReturns(fakes.FakeProfile(), nil)
+	auditRepo := new(fakes.FakeAuditRepo)
+	admin := fakes.FakeAdmin()
+
+	var current *cohesioned.Profile
+	auditedFirst := false
+	mockNextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current, _ = cohesioned.FromRequest(r)
+		auditedFirst = len(auditRepo.Saved) == 1
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	rr := httptest.NewRecorder()
+	handler := auth.Impersonate(fakes.FakeRenderer, profileRepo, auditRepo)
+	handler.ServeHTTP(rr, newImpersonationRequest(t, http.MethodGet, admin), mockNextHandler)
+
+	if current == nil || current.ID != fakes.FakeProfile().ID {
+		t.Fatalf("expected the impersonated user to be the current user but got %v", current)
+	}
+
+	if current.Actor() != admin {
+		t.Error("expected the admin to be recorded as the real actor")
+	}
+
+	if !auditedFirst {
+		t.Error("expected the event to be audited before the request was handled")
+	}
+
+	if len(auditRepo.Saved) != 1 {
+		t.Fatalf("expected 1 audit event but got %d", len(auditRepo.Saved))
+	}
+
+	event := auditRepo.Saved[0]
+	if event.TargetID != 1 || event.Path != "/api/profile/students" || event.Status != http.StatusTeapot {
+		t.Errorf("unexpected audit event %+v", event)
+	}
+}
+
+func TestImpersonateRequiresPermission(t *testing.T) {
+	profileRepo := new(fakes.FakeProfileRepo)
+	profileRepo.GetReturns(fakes.FakeProfile(), nil)
+	auditRepo := new(fakes.FakeAuditRepo)
+
+	mockNextHandlerCalled := false
+	mockNextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mockNextHandlerCalled = true
+	})
+
+	rr := httptest.NewRecorder()
+	handler := auth.Impersonate(fakes.FakeRenderer, profileRepo, auditRepo)
+	handler.ServeHTTP(rr, newImpersonationRequest(t, http.MethodGet, fakes.FakeContentEditor()), mockNextHandler)
+
+	if mockNextHandlerCalled {
+		t.Errorf("'next' handler should not have been called")
+	}
+
+	if status := rr.Code; status != http.StatusForbidden {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusForbidden)
+	}
+}
+
+func TestImpersonateIsReadOnly(t *testing.T) {
+	profileRepo := new(fakes.FakeProfileRepo)
+	profileRepo.GetReturns(fakes.FakeProfile(), nil)
+	auditRepo := new(fakes.FakeAuditRepo)
+
+	mockNextHandlerCalled := false
+	mockNextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mockNextHandlerCalled = true
+	})
+
+	rr := httptest.NewRecorder()
+	handler := auth.Impersonate(fakes.FakeRenderer, profileRepo, auditRepo)
+	handler.ServeHTTP(rr, newImpersonationRequest(t, http.MethodPost, fakes.FakeAdmin()), mockNextHandler)
+
+	if mockNextHandlerCalled {
+		t.Errorf("'next' handler should not have been called")
+	}
+
+	if status := rr.Code; status != http.StatusForbidden {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusForbidden)
+	}
+}
+
+func TestImpersonateWhenAuditFailsDoesNotHandleRequest(t *testing.T) {
+	profileRepo := new(fakes.FakeProfileRepo)
+	profileRepo.GetReturns(fakes.FakeProfile(), nil)
+	auditRepo := new(fakes.FakeAuditRepo)
+	auditRepo.SaveReturns(errors.New("database is down"))
+
+	mockNextHandlerCalled := false
+	mockNextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mockNextHandlerCalled = true
+	})
+
+	rr := httptest.NewRecorder()
+	handler := auth.Impersonate(fakes.FakeRenderer, profileRepo, auditRepo)
+	handler.ServeHTTP(rr, newImpersonationRequest(t, http.MethodGet, fakes.FakeAdmin()), mockNextHandler)
+
+	if mockNextHandlerCalled {
+		t.Error("'next' handler was called but the impersonation could not be audited")
+	}
+
+	if status := rr.Code; status != http.StatusInternalServerError {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusInternalServerError)
+	}
+}
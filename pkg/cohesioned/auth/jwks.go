@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+const (
+	//minForcedRefreshInterval stops tokens with made-up key IDs from hammering the JWKS endpoint
+	minForcedRefreshInterval = 30 * time.Second
+)
+
+var (
+	ErrMissingToken = errors.New("no bearer token in Authorization header")
+)
+
+//JWKSProvider is an auth0.SecretProvider that caches the signing keys published at a JWKS endpoint.
+//Keys are refreshed in the background every refreshInterval, and on demand when a token is signed with a key ID we haven't seen yet
+type JWKSProvider struct {
+	uri             string
+	client          *http.Client
+	refreshInterval time.Duration
+
+	mu          sync.RWMutex
+	keys        map[string]jose.JSONWebKey
+	lastRefresh time.Time
+
+	refreshMu sync.Mutex
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+//NewJWKSProvider creates a JWKSProvider for the given JWKS uri and starts the background refresh. A refreshInterval <= 0 disables the background refresh
+func NewJWKSProvider(uri string, refreshInterval time.Duration) *JWKSProvider {
+	p := &JWKSProvider{
+		uri:             uri,
+		client:          &http.Client{Timeout: 10 * time.Second},
+		refreshInterval: refreshInterval,
+		keys:            make(map[string]jose.JSONWebKey),
+		stop:            make(chan struct{}),
+	}
+
+	if refreshInterval > 0 {
+		go p.refreshPeriodically()
+	}
+
+	return p
+}
+
+//GetSecret implements auth0.SecretProvider by returning the public key matching the kid of the request's bearer token
+func (p *JWKSProvider) GetSecret(req *http.Request) (interface{}, error) {
+	raw, err := bearerToken(req)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := jwt.ParseSigned(raw)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse token: %v", err)
+	}
+
+	if len(token.Headers) == 0 {
+		return nil, errors.New("token has no headers")
+	}
+
+	key, err := p.GetKey(token.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return key.Key, nil
+}
+
+//GetKey returns the cached key with the given ID, refreshing the cache if the key ID is unknown
+func (p *JWKSProvider) GetKey(keyID string) (jose.JSONWebKey, error) {
+	if key, ok := p.cachedKey(keyID); ok {
+		return key, nil
+	}
+
+	if err := p.refreshIfStale(minForcedRefreshInterval); err != nil {
+		return jose.JSONWebKey{}, err
+	}
+
+	if key, ok := p.cachedKey(keyID); ok {
+		return key, nil
+	}
+
+	return jose.JSONWebKey{}, fmt.Errorf("no key with kid %s published at %s", keyID, p.uri)
+}
+
+//Close stops the background refresh
+func (p *JWKSProvider) Close() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+}
+
+func (p *JWKSProvider) cachedKey(keyID string) (jose.JSONWebKey, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	key, ok := p.keys[keyID]
+	return key, ok
+}
+
+func (p *JWKSProvider) refreshPeriodically() {
+	ticker := time.NewTicker(p.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.refreshIfStale(0); err != nil {
+				fmt.Printf("Failed to refresh JWKS from %s: %v\n", p.uri, err)
+			}
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+//refreshIfStale fetches the key set unless it was fetched less than maxAge ago. Concurrent callers wait on a single fetch
+func (p *JWKSProvider) refreshIfStale(maxAge time.Duration) error {
+	p.refreshMu.Lock()
+	defer p.refreshMu.Unlock()
+
+	p.mu.RLock()
+	lastRefresh := p.lastRefresh
+	p.mu.RUnlock()
+
+	if maxAge > 0 && time.Since(lastRefresh) < maxAge {
+		return nil
+	}
+
+	keys, err := p.fetch()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	//a failed fetch counts as a refresh too, so requests don't refetch on every unknown key ID while the endpoint is down. The keys we had are kept
+	p.lastRefresh = time.Now()
+	if err != nil {
+		return err
+	}
+
+	p.keys = keys
+	return nil
+}
+
+func (p *JWKSProvider) fetch() (map[string]jose.JSONWebKey, error) {
+	resp, err := p.client.Get(p.uri)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch JWKS from %s: %v", p.uri, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Failed to fetch JWKS from %s: %s", p.uri, resp.Status)
+	}
+
+	keySet := jose.JSONWebKeySet{}
+	if err := json.NewDecoder(resp.Body).Decode(&keySet); err != nil {
+		return nil, fmt.Errorf("Failed to decode JWKS from %s: %v", p.uri, err)
+	}
+
+	keys := make(map[string]jose.JSONWebKey)
+	for _, key := range keySet.Keys {
+		keys[key.KeyID] = key
+	}
+
+	return keys, nil
+}
+
+func bearerToken(req *http.Request) (string, error) {
+	header := req.Header.Get("Authorization")
+	if len(header) < 7 || !strings.EqualFold(header[:7], "bearer ") {
+		return "", ErrMissingToken
+	}
+
+	return strings.TrimSpace(header[7:]), nil
+}
@@ -0,0 +1,171 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned/auth"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+func newJWKSServer(t *testing.T, keys *jose.JSONWebKeySet, fetches *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(fetches, 1)
+		if err := json.NewEncoder(w).Encode(keys); err != nil {
+			t.Fatalf("Failed to encode jwks: %v", err)
+		}
+	}))
+}
+
+func newSigningKey(t *testing.T, keyID string) (*rsa.PrivateKey, jose.JSONWebKey) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate rsa key: %v", err)
+	}
+
+	return privateKey, jose.JSONWebKey{Key: &privateKey.PublicKey, KeyID: keyID, Algorithm: string(jose.RS256), Use: "sig"}
+}
+
+func signedRequest(t *testing.T, privateKey *rsa.PrivateKey, keyID string) *http.Request {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: privateKey}, (&jose.SignerOptions{}).WithHeader("kid", keyID))
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+
+	raw, err := jwt.Signed(signer).Claims(jwt.Claims{Subject: "abc|123"}).CompactSerialize()
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+
+	req, err := http.NewRequest("GET", "/api/profile", nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize new request %v", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+raw)
+	return req
+}
+
+func TestJWKSProviderCachesKeys(t *testing.T) {
+	privateKey, publicKey := newSigningKey(t, "key-1")
+	keys := &jose.JSONWebKeySet{Keys: []jose.JSONWebKey{publicKey}}
+
+	var fetches int32
+	server := newJWKSServer(t, keys, &fetches)
+	defer server.Close()
+
+	provider := auth.NewJWKSProvider(server.URL, 0)
+	defer provider.Close()
+
+	for i := 0; i < 3; i++ {
+		secret, err := provider.GetSecret(signedRequest(t, privateKey, "key-1"))
+		if err != nil {
+			t.Fatalf("Failed to get secret: %v", err)
+		}
+
+		if _, ok := secret.(*rsa.PublicKey); !ok {
+			t.Errorf("expected secret to be an *rsa.PublicKey but got %T", secret)
+		}
+	}
+
+	if fetches != 1 {
+		t.Errorf("expected the jwks to be fetched once but was fetched %d times", fetches)
+	}
+}
+
+func TestJWKSProviderRefreshesOnUnknownKeyID(t *testing.T) {
+	_, publicKey := newSigningKey(t, "key-1")
+	keys := &jose.JSONWebKeySet{Keys: []jose.JSONWebKey{publicKey}}
+
+	var fetches int32
+	server := newJWKSServer(t, keys, &fetches)
+	defer server.Close()
+
+	provider := auth.NewJWKSProvider(server.URL, 0)
+	defer provider.Close()
+
+	if _, err := provider.GetKey("key-2"); err == nil {
+		t.Error("expected an error for a key id that is not published")
+	}
+
+	if fetches != 1 {
+		t.Errorf("expected the jwks to be fetched once but was fetched %d times", fetches)
+	}
+
+	//unknown key ids within the forced refresh window do not trigger another fetch
+	if _, err := provider.GetKey("key-3"); err == nil {
+		t.Error("expected an error for a key id that is not published")
+	}
+
+	if fetches != 1 {
+		t.Errorf("expected the jwks to be fetched once but was fetched %d times", fetches)
+	}
+}
+
+func TestJWKSProviderDoesNotRefetchWhileTheEndpointIsDown(t *testing.T) {
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	provider := auth.NewJWKSProvider(server.URL, 0)
+	defer provider.Close()
+
+	for i := 0; i < 3; i++ {
+		if _, err := provider.GetKey("key-1"); err == nil {
+			t.Error("expected an error while the jwks can't be fetched")
+		}
+	}
+
+	if fetches != 1 {
+		t.Errorf("expected the jwks to be fetched once but was fetched %d times", fetches)
+	}
+}
+
+func TestJWKSProviderRefreshesInBackground(t *testing.T) {
+	_, publicKey := newSigningKey(t, "key-1")
+	keys := &jose.JSONWebKeySet{Keys: []jose.JSONWebKey{publicKey}}
+
+	var fetches int32
+	server := newJWKSServer(t, keys, &fetches)
+	defer server.Close()
+
+	provider := auth.NewJWKSProvider(server.URL, 10*time.Millisecond)
+	defer provider.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&fetches) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if atomic.LoadInt32(&fetches) < 2 {
+		t.Errorf("expected the jwks to be refreshed in the background")
+	}
+
+	if _, err := provider.GetKey("key-1"); err != nil {
+		t.Errorf("expected key-1 to be cached: %v", err)
+	}
+}
+
+func TestJWKSProviderWithoutBearerTokenFails(t *testing.T) {
+	provider := auth.NewJWKSProvider("http://localhost/.well-known/jwks.json", 0)
+	defer provider.Close()
+
+	req, err := http.NewRequest("GET", "/api/profile", nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize new request %v", err)
+	}
+
+	if _, err := provider.GetSecret(req); err != auth.ErrMissingToken {
+		t.Errorf("expected ErrMissingToken but got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/config"
+	"github.com/unrolled/render"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+//LocalIssuer signs and verifies tokens with a local RSA key so the API can be exercised without an Auth0 tenant
+type LocalIssuer struct {
+	key      *rsa.PrivateKey
+	keyID    string
+	issuer   string
+	audience string
+}
+
+//LocalClaims are the user claims written into tokens minted by a LocalIssuer
+type LocalClaims struct {
+	Email         string            `json:"email"`
+	EmailVerified bool              `json:"email_verified"`
+	FullName      string            `json:"name,omitempty"`
+	Roles         []cohesioned.Role `json:"roles,omitempty"`
+}
+
+//NewLocalIssuer reads the RSA key at cfg.LocalKeyFile, generating and saving a new key if the file does not exist yet
+func NewLocalIssuer(cfg *config.AuthConfig) (*LocalIssuer, error) {
+	key, err := loadOrCreateKey(cfg.LocalKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return newLocalIssuer(key, cfg), nil
+}
+
+func newLocalIssuer(key *rsa.PrivateKey, cfg *config.AuthConfig) *LocalIssuer {
+	fingerprint := sha256.Sum256(key.PublicKey.N.Bytes())
+
+	return &LocalIssuer{
+		key:      key,
+		keyID:    base64.RawURLEncoding.EncodeToString(fingerprint[:8]),
+		issuer:   cfg.Issuer(),
+		audience: cfg.ClientID,
+	}
+}
+
+//Mint signs a token for the given subject and claims that expires after ttl
+func (i *LocalIssuer) Mint(sub string, claims LocalClaims, ttl time.Duration) (string, error) {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: i.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", i.keyID),
+	)
+	if err != nil {
+		return "", fmt.Errorf("Failed to create signer: %v", err)
+	}
+
+	now := time.Now()
+	registered := jwt.Claims{
+		Issuer:   i.issuer,
+		Audience: jwt.Audience{i.audience},
+		Subject:  sub,
+		IssuedAt: jwt.NewNumericDate(now),
+		Expiry:   jwt.NewNumericDate(now.Add(ttl)),
+	}
+
+	return jwt.Signed(signer).Claims(registered).Claims(claims).CompactSerialize()
+}
+
+//GetSecret implements auth0.SecretProvider
+func (i *LocalIssuer) GetSecret(req *http.Request) (interface{}, error) {
+	return &i.key.PublicKey, nil
+}
+
+//JWKS is the key set that verifies tokens minted by this issuer
+func (i *LocalIssuer) JWKS() jose.JSONWebKeySet {
+	return jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{
+			{
+				Key:       &i.key.PublicKey,
+				KeyID:     i.keyID,
+				Algorithm: string(jose.RS256),
+				Use:       "sig",
+			},
+		},
+	}
+}
+
+//JWKSHandler serves the issuer's public key the same way Auth0 does at /.well-known/jwks.json
+func JWKSHandler(r *render.Render, issuer *LocalIssuer) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		r.JSON(w, http.StatusOK, issuer.JWKS())
+	}
+}
+
+func loadOrCreateKey(path string) (*rsa.PrivateKey, error) {
+	if len(path) == 0 {
+		return nil, errors.New("no key file configured for the local issuer")
+	}
+
+	pemBytes, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return createKey(path)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read local issuer key %s: %v", path, err)
+	}
+
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("%s does not contain a PEM encoded key", path)
+	}
+
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse local issuer key %s: %v", path, err)
+	}
+
+	return key, nil
+}
+
+func createKey(path string) (*rsa.PrivateKey, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to generate local issuer key: %v", err)
+	}
+
+	block := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	if err := ioutil.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		return nil, fmt.Errorf("Failed to save local issuer key to %s: %v", path, err)
+	}
+
+	fmt.Printf("Generated a new local issuer key at %s\n", path)
+	return key, nil
+}
@@ -0,0 +1,153 @@
+package auth_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/auth"
+	"github.com/cohesion-education/api/pkg/cohesioned/config"
+	"github.com/cohesion-education/api/pkg/cohesioned/profile"
+)
+
+func newLocalAuthConfig(t *testing.T) (*config.AuthConfig, func()) {
+	dir, err := ioutil.TempDir("", "local-issuer")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+
+	cfg := &config.AuthConfig{
+		Mode:            config.AuthModeLocal,
+		ClientID:        "test-client",
+		Domain:          "http://localhost:3001",
+		LocalKeyFile:    filepath.Join(dir, "key.pem"),
+		ProfileCacheTTL: time.Minute,
+	}
+
+	return cfg, func() { os.RemoveAll(dir) }
+}
+
+func TestNewLocalIssuerReusesKeyFile(t *testing.T) {
+	cfg, cleanup := newLocalAuthConfig(t)
+	defer cleanup()
+
+	first, err := auth.NewLocalIssuer(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create local issuer: %v", err)
+	}
+
+	if _, err := os.Stat(cfg.LocalKeyFile); err != nil {
+		t.Fatalf("expected key file to be generated at %s: %v", cfg.LocalKeyFile, err)
+	}
+
+	second, err := auth.NewLocalIssuer(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create local issuer: %v", err)
+	}
+
+	if first.JWKS().Keys[0].KeyID != second.JWKS().Keys[0].KeyID {
+		t.Errorf("expected the same key to be loaded from %s", cfg.LocalKeyFile)
+	}
+}
+
+func TestCheckJwtAcceptsTokensMintedByLocalIssuer(t *testing.T) {
+	cfg, cleanup := newLocalAuthConfig(t)
+	defer cleanup()
+
+	issuer, err := auth.NewLocalIssuer(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create local issuer: %v", err)
+	}
+
+	claims := auth.LocalClaims{Email: "editor@example.com", EmailVerified: true, Roles: []cohesioned.Role{cohesioned.RoleContentEditor}}
+	token, err := issuer.Mint("local|editor@example.com", claims, time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to mint token: %v", err)
+	}
+
+	repo := &fakes.FakeProfileRepo{}
+	repo.FindByEmailReturns(&cohesioned.Profile{ID: 7, Email: "editor@example.com"}, nil)
+
+	req, err := http.NewRequest("GET", "/api/profile", nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize new request %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	var current *cohesioned.Profile
+	mockNextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current, _ = cohesioned.FromRequest(r)
+	})
+
+	rr := httptest.NewRecorder()
+	handler := auth.CheckJwt(fakes.FakeRenderer, repo, profile.NewIdentityCache(time.Minute), cfg, issuer)
+	handler.ServeHTTP(rr, req, mockNextHandler)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v - %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	if current == nil {
+		t.Fatal("'next' handler was not called with the current user")
+	}
+
+	if current.ID != 7 {
+		t.Errorf("expected profile id 7 but got %d", current.ID)
+	}
+
+	if !current.Can(cohesioned.PermissionManageVideos) {
+		t.Errorf("expected roles from the token to be granted but got %v", current.Roles)
+	}
+}
+
+func TestCheckJwtRejectsTokensFromAnotherLocalIssuer(t *testing.T) {
+	cfg, cleanup := newLocalAuthConfig(t)
+	defer cleanup()
+
+	otherCfg, otherCleanup := newLocalAuthConfig(t)
+	defer otherCleanup()
+
+	issuer, err := auth.NewLocalIssuer(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create local issuer: %v", err)
+	}
+
+	other, err := auth.NewLocalIssuer(otherCfg)
+	if err != nil {
+		t.Fatalf("Failed to create local issuer: %v", err)
+	}
+
+	token, err := other.Mint("local|someone@example.com", auth.LocalClaims{Email: "someone@example.com"}, time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to mint token: %v", err)
+	}
+
+	req, err := http.NewRequest("GET", "/api/profile", nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize new request %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	mockNextHandlerCalled := false
+	mockNextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mockNextHandlerCalled = true
+	})
+
+	rr := httptest.NewRecorder()
+	handler := auth.CheckJwt(fakes.FakeRenderer, &fakes.FakeProfileRepo{}, profile.NewIdentityCache(time.Minute), cfg, issuer)
+	handler.ServeHTTP(rr, req, mockNextHandler)
+
+	if mockNextHandlerCalled {
+		t.Errorf("'next' handler should not have been called")
+	}
+
+	if status := rr.Code; status != http.StatusUnauthorized {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+}
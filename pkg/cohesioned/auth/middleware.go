@@ -15,8 +15,6 @@ import (
 	"github.com/urfave/negroni"
 )
 
-var emailToProfileIDCache map[string]int64
-
 func IsAdmin(r *render.Render) negroni.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
 		profile, ok := cohesioned.FromRequest(req)
@@ -36,18 +34,67 @@ func IsAdmin(r *render.Render) negroni.HandlerFunc {
 	}
 }
 
-func CheckJwt(r *render.Render, repo profile.Repo, cfg *config.AuthConfig) negroni.HandlerFunc {
+//RequiresPermission only proceeds to next if one of the current user's roles grants the given permission
+func RequiresPermission(r *render.Render, permission cohesioned.Permission) negroni.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
-		jwksURI := fmt.Sprintf("%s/.well-known/jwks.json", cfg.Domain)
-		client := auth0.NewJWKClient(auth0.JWKClientOptions{URI: jwksURI})
-		audience := []string{cfg.ClientID}
-		issuer := fmt.Sprintf("%s/", cfg.Domain)
+		profile, ok := cohesioned.FromRequest(req)
+		if !ok {
+			resp := cohesioned.NewAPIErrorResponse("failed to get current user from request context")
+			fmt.Printf("%s\n", resp.ErrMsg)
+			r.JSON(w, http.StatusUnauthorized, resp)
+			return
+		}
+
+		if !profile.Can(permission) {
+			r.JSON(w, http.StatusForbidden, &cohesioned.APIResponse{ErrMsg: "You are not authorized to access this resource"})
+			return
+		}
 
-		//fmt.Printf("CheckJwt config::\n\tjwksURI: %s\n\tissuer: %s\n\taudience: %v\n", jwksURI, issuer, audience)
+		next(w, req)
+	}
+}
 
-		configuration := auth0.NewConfiguration(client, audience, issuer, jose.RS256)
-		validator := auth0.NewValidator(configuration)
+//RequiresUser only proceeds to next if the current user logged in, rather than calling with an api key. API keys are only
+//scoped to the permissions they were granted, so they can't use endpoints that just require authentication
+func RequiresUser(r *render.Render) negroni.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+		profile, ok := cohesioned.FromRequest(req)
+		if !ok {
+			resp := cohesioned.NewAPIErrorResponse("failed to get current user from request context")
+			fmt.Printf("%s\n", resp.ErrMsg)
+			r.JSON(w, http.StatusUnauthorized, resp)
+			return
+		}
 
+		if profile.APIKey != nil {
+			r.JSON(w, http.StatusForbidden, &cohesioned.APIResponse{ErrMsg: "API keys are not authorized to access this resource"})
+			return
+		}
+
+		next(w, req)
+	}
+}
+
+//NewSecretProvider returns the source of signing keys for cfg.Mode - a JWKSProvider for Auth0, or a LocalIssuer in local mode
+func NewSecretProvider(cfg *config.AuthConfig) (auth0.SecretProvider, error) {
+	if cfg.IsLocal() {
+		return NewLocalIssuer(cfg)
+	}
+
+	return NewJWKSProvider(cfg.JWKSURI(), cfg.JWKSRefreshInterval), nil
+}
+
+//NewValidator builds a long-lived validator for tokens issued for cfg and signed by a key from provider
+func NewValidator(cfg *config.AuthConfig, provider auth0.SecretProvider) *auth0.JWTValidator {
+	audience := []string{cfg.ClientID}
+	configuration := auth0.NewConfiguration(provider, audience, cfg.Issuer(), jose.RS256)
+	return auth0.NewValidator(configuration)
+}
+
+func CheckJwt(r *render.Render, repo profile.Repo, cache *profile.IdentityCache, cfg *config.AuthConfig, provider auth0.SecretProvider) negroni.HandlerFunc {
+	validator := NewValidator(cfg, provider)
+
+	return func(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
 		token, err := validator.ValidateRequest(req)
 		if err != nil {
 			resp := cohesioned.NewAPIErrorResponse("Missing or invalid token. %v", err)
@@ -62,27 +109,54 @@ func CheckJwt(r *render.Render, repo profile.Repo, cfg *config.AuthConfig) negro
 				return
 			}
 
-			profile.ID = getProfileID(repo, profile.Email)
+			//roles are only ever trusted from our own db, never from the token - unless we minted the token ourselves
+			tokenRoles := profile.Roles
+			identity := getProfileIdentity(repo, cache, profile)
+			profile.ID, profile.Roles = identity.ID, identity.Roles
+			if cfg.IsLocal() {
+				profile.Roles = mergeRoles(profile.Roles, tokenRoles)
+			}
 			ctx := context.WithValue(req.Context(), cohesioned.CurrentUserKey, profile)
 			next(w, req.WithContext(ctx))
 		}
 	}
 }
 
-func getProfileID(repo profile.Repo, email string) int64 {
-	if val, ok := emailToProfileIDCache[email]; ok {
-		return val
+func getProfileIdentity(repo profile.Repo, cache *profile.IdentityCache, p *cohesioned.Profile) profile.Identity {
+	if identity, ok := cache.Get(profile.SubKey(p.Sub), profile.EmailKey(p.Email)); ok {
+		return identity
 	}
 
-	profile, err := repo.FindByEmail(email)
+	existing, err := repo.FindByEmail(p.Email)
 	if err != nil {
 		fmt.Printf("Failed to find user by email address: %v\n", err)
-		return 0
+		return profile.Identity{ID: 0}
 	}
 
-	if profile != nil {
-		return profile.ID
+	if existing == nil {
+		return profile.Identity{ID: -1}
+	}
+
+	identity := profile.Identity{ID: existing.ID, Roles: existing.Roles}
+	cache.Put(identity, profile.EmailKey(existing.Email), profile.SubKey(p.Sub))
+	return identity
+}
+
+func mergeRoles(roles []cohesioned.Role, more []cohesioned.Role) []cohesioned.Role {
+	merged := append([]cohesioned.Role{}, roles...)
+	for _, role := range more {
+		found := false
+		for _, existing := range merged {
+			if existing == role {
+				found = true
+				break
+			}
+		}
+
+		if !found && role.IsValid() {
+			merged = append(merged, role)
+		}
 	}
 
-	return -1
+	return merged
 }
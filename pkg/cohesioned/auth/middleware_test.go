@@ -91,3 +91,150 @@ func TestIsAdminWhenUnauthenticatedReturnsNotAuthorized(t *testing.T) {
 		t.Errorf("handler returned wrong status code: got %v want %v", status, expectedStatus)
 	}
 }
+
+func TestRequiresPermissionWhenRoleGrantsPermissionProceedsToNext(t *testing.T) {
+	req, err := http.NewRequest("POST", "/endpoint-that-requires-permission", nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize new request %v", err)
+	}
+
+	mockNextHandlerCalled := false
+	mockNextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mockNextHandlerCalled = true
+	})
+
+	editor := fakes.FakeContentEditor()
+	ctx := context.WithValue(req.Context(), cohesioned.CurrentUserKey, editor)
+
+	rr := httptest.NewRecorder()
+	handler := auth.RequiresPermission(fakes.FakeRenderer, cohesioned.PermissionManageVideos)
+	handler.ServeHTTP(rr, req.WithContext(ctx), mockNextHandler)
+
+	if !mockNextHandlerCalled {
+		t.Errorf("'next' handler was not called")
+	}
+
+	expectedStatus := http.StatusOK
+	if status := rr.Code; status != expectedStatus {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, expectedStatus)
+	}
+}
+
+func TestRequiresPermissionWhenRoleDoesNotGrantPermissionReturnsForbidden(t *testing.T) {
+	req, err := http.NewRequest("GET", "/endpoint-that-requires-permission", nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize new request %v", err)
+	}
+
+	mockNextHandlerCalled := false
+	mockNextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mockNextHandlerCalled = true
+	})
+
+	editor := fakes.FakeContentEditor()
+	ctx := context.WithValue(req.Context(), cohesioned.CurrentUserKey, editor)
+
+	rr := httptest.NewRecorder()
+	handler := auth.RequiresPermission(fakes.FakeRenderer, cohesioned.PermissionViewReports)
+	handler.ServeHTTP(rr, req.WithContext(ctx), mockNextHandler)
+
+	if mockNextHandlerCalled {
+		t.Errorf("'next' handler was called but it should not have been")
+	}
+
+	expectedStatus := http.StatusForbidden
+	if status := rr.Code; status != expectedStatus {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, expectedStatus)
+	}
+}
+
+func TestRequiresPermissionWhenUnauthenticatedReturnsNotAuthorized(t *testing.T) {
+	req, err := http.NewRequest("GET", "/endpoint-that-requires-permission", nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize new request %v", err)
+	}
+
+	mockNextHandlerCalled := false
+	mockNextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mockNextHandlerCalled = true
+	})
+
+	rr := httptest.NewRecorder()
+	handler := auth.RequiresPermission(fakes.FakeRenderer, cohesioned.PermissionManageVideos)
+	handler.ServeHTTP(rr, req, mockNextHandler)
+
+	if mockNextHandlerCalled {
+		t.Errorf("'next' handler was called but it should not have been")
+	}
+
+	expectedStatus := http.StatusUnauthorized
+	if status := rr.Code; status != expectedStatus {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, expectedStatus)
+	}
+}
+
+func TestRequiresPermissionWhenAPIKeyIsNotScopedToPermissionReturnsForbidden(t *testing.T) {
+	req, err := http.NewRequest("POST", "/endpoint-that-requires-permission", nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize new request %v", err)
+	}
+
+	mockNextHandlerCalled := false
+	mockNextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mockNextHandlerCalled = true
+	})
+
+	//the service profile's role is ignored once it calls with a key
+	service := &cohesioned.Profile{
+		ID:     42,
+		Roles:  []cohesioned.Role{cohesioned.RoleAdmin},
+		APIKey: &cohesioned.APIKey{ID: 3, ProfileID: 42, Permissions: []cohesioned.Permission{cohesioned.PermissionManageVideos, cohesioned.PermissionManageRoles}},
+	}
+	ctx := context.WithValue(req.Context(), cohesioned.CurrentUserKey, service)
+
+	for _, permission := range []cohesioned.Permission{cohesioned.PermissionManageTaxonomy, cohesioned.PermissionManageRoles} {
+		rr := httptest.NewRecorder()
+		handler := auth.RequiresPermission(fakes.FakeRenderer, permission)
+		handler.ServeHTTP(rr, req.WithContext(ctx), mockNextHandler)
+
+		if mockNextHandlerCalled {
+			t.Errorf("'next' handler was called for %s but it should not have been", permission)
+		}
+
+		if status := rr.Code; status != http.StatusForbidden {
+			t.Errorf("handler returned wrong status code for %s: got %v want %v", permission, status, http.StatusForbidden)
+		}
+	}
+}
+
+func TestRequiresUserWhenCalledWithAPIKeyReturnsForbidden(t *testing.T) {
+	req, err := http.NewRequest("GET", "/api/profile/students", nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize new request %v", err)
+	}
+
+	mockNextHandlerCalled := false
+	mockNextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mockNextHandlerCalled = true
+	})
+
+	service := &cohesioned.Profile{ID: 42, APIKey: &cohesioned.APIKey{ID: 3, ProfileID: 42, Permissions: []cohesioned.Permission{cohesioned.PermissionManageVideos}}}
+	rr := httptest.NewRecorder()
+	handler := auth.RequiresUser(fakes.FakeRenderer)
+	handler.ServeHTTP(rr, req.WithContext(context.WithValue(req.Context(), cohesioned.CurrentUserKey, service)), mockNextHandler)
+
+	if mockNextHandlerCalled {
+		t.Errorf("'next' handler was called but it should not have been")
+	}
+
+	if status := rr.Code; status != http.StatusForbidden {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusForbidden)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req.WithContext(context.WithValue(req.Context(), cohesioned.CurrentUserKey, fakes.FakeProfile())), mockNextHandler)
+
+	if !mockNextHandlerCalled {
+		t.Errorf("'next' handler was not called for a logged in user")
+	}
+}
@@ -18,10 +18,8 @@ func NewAwsRepo(db *sql.DB) Repo {
 	}
 }
 
-func (repo *awsRepo) List() ([]*cohesioned.PaymentDetails, error) {
-	var list []*cohesioned.PaymentDetails
-
-	selectQuery := `select
+var listQuery = db.ListQuery{
+	Select: `select
 		id,
 		created,
 		created_by,
@@ -54,30 +52,50 @@ func (repo *awsRepo) List() ([]*cohesioned.PaymentDetails, error) {
 		card_address_state,
 		card_address_city,
 		card_address_zip,
-		card_address_zip_check
-	from
-		payment_detail`
+		card_address_zip_check`,
+	From: `from payment_detail`,
+	Columns: map[string]string{
+		"id":           "id",
+		"created":      "created",
+		"created_by":   "created_by",
+		"card_brand":   "card_brand",
+		"card_country": "card_country",
+	},
+}
+
+func (repo *awsRepo) List(opts *cohesioned.ListOptions) ([]*cohesioned.PaymentDetails, int64, error) {
+	var list []*cohesioned.PaymentDetails
+	var total int64
+
+	selectQuery, args, countQuery, countArgs, err := listQuery.Build(opts)
+	if err != nil {
+		return list, total, err
+	}
+
+	if err := repo.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		return list, total, fmt.Errorf("Failed to count payment details: %v", err)
+	}
 
-	rows, err := repo.Query(selectQuery)
+	rows, err := repo.Query(selectQuery, args...)
 	if err != nil {
-		return list, fmt.Errorf("Failed to execute list payment detail query: %v", err)
+		return list, total, fmt.Errorf("Failed to execute list payment detail query: %v", err)
 	}
 
 	defer rows.Close()
 	for rows.Next() {
 		p, err := repo.mapRowToObject(rows)
 		if err != nil {
-			return list, fmt.Errorf("an unexpected error occurred while processing the list payment detail result set from the db: %v", err)
+			return list, total, fmt.Errorf("an unexpected error occurred while processing the list payment detail result set from the db: %v", err)
 		}
 
 		list = append(list, p)
 	}
 
 	if err := rows.Err(); err != nil {
-		return list, fmt.Errorf("list payment detail rows had an error: %v", err)
+		return list, total, fmt.Errorf("list payment detail rows had an error: %v", err)
 	}
 
-	return list, nil
+	return list, total, nil
 }
 
 func (repo *awsRepo) FindByCreatedByID(id int64) (*cohesioned.PaymentDetails, error) {
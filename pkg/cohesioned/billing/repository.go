@@ -8,5 +8,17 @@ type Repo interface {
 	FindByCreatedByID(id int64) (*cohesioned.PaymentDetails, error)
 	Save(p *cohesioned.PaymentDetails) (int64, error)
 	Update(p *cohesioned.PaymentDetails) error
-	List() ([]*cohesioned.PaymentDetails, error)
+	List(opts *cohesioned.ListOptions) ([]*cohesioned.PaymentDetails, int64, error)
+}
+
+//ListSpec is how the payment detail list can be sorted and filtered. created_by filters by the paying user
+var ListSpec = cohesioned.ListSpec{
+	SortFields:  []string{"id", "created"},
+	DefaultSort: "-created",
+	Filters: map[string]cohesioned.FilterType{
+		"created_by":   cohesioned.FilterInt,
+		"card_brand":   cohesioned.FilterString,
+		"card_country": cohesioned.FilterString,
+		"created":      cohesioned.FilterDateRange,
+	},
 }
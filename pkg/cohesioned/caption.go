@@ -0,0 +1,17 @@
+package cohesioned
+
+import "time"
+
+//CaptionTrack is a video's WebVTT captions in one language. Caption files uploaded as SRT are converted to WebVTT
+type CaptionTrack struct {
+	ID        int64  `json:"id"`
+	VideoID   int64  `json:"video_id"`
+	Language  string `json:"language"`
+	Label     string `json:"label"`
+	ObjectKey string `json:"object_key"`
+	//Transcript is the text of the cues without their timings, which is indexed for search
+	Transcript string    `json:"-"`
+	SignedURL  string    `json:"signed_url,omitempty"`
+	Created    time.Time `json:"created"`
+	Updated    time.Time `json:"updated"`
+}
@@ -0,0 +1,52 @@
+package cohesioned
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+//Chapter is a titled section of a video. Start and End are offsets into the video in seconds
+type Chapter struct {
+	Validatable
+	ID      int64     `json:"id"`
+	VideoID int64     `json:"video_id"`
+	Title   string    `json:"title"`
+	Start   float64   `json:"start"`
+	End     float64   `json:"end"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+//Validate checks the chapter on its own. A chapter must also end within its video and not overlap the video's other chapters - see ValidateAgainst
+func (c *Chapter) Validate() bool {
+	c.Title = strings.TrimSpace(c.Title)
+	if len(c.Title) == 0 {
+		c.AddValidationError("title", "title is required")
+	}
+
+	if c.Start < 0 {
+		c.AddValidationError("start", "start can't be negative")
+	}
+
+	if c.End <= c.Start {
+		c.AddValidationError("end", "end must be after start")
+	}
+
+	return len(c.ValidationErrors) == 0
+}
+
+//ValidateAgainst checks that the chapter ends within the video, if its duration is known, and doesn't overlap any of the video's other chapters
+func (c *Chapter) ValidateAgainst(video *Video, others []*Chapter) bool {
+	if video.Duration > 0 && c.End > video.Duration {
+		c.AddValidationError("end", fmt.Sprintf("end is after the end of the video at %.3f seconds", video.Duration))
+	}
+
+	for _, other := range others {
+		if other.ID != c.ID && c.Start < other.End && other.Start < c.End {
+			c.AddValidationError("start", fmt.Sprintf("the chapter overlaps chapter %d, %s", other.ID, other.Title))
+		}
+	}
+
+	return len(c.ValidationErrors) == 0
+}
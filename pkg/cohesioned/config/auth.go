@@ -3,20 +3,70 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	cfenv "github.com/cloudfoundry-community/go-cfenv"
 )
 
+const (
+	//AuthModeAuth0 validates tokens issued by the Auth0 tenant at Domain
+	AuthModeAuth0 = "auth0"
+	//AuthModeLocal signs and validates tokens with a locally generated RSA key, for development and tests without Auth0
+	AuthModeLocal = "local"
+
+	defaultJWKSRefreshInterval = time.Hour
+	defaultProfileCacheTTL     = 5 * time.Minute
+	defaultLocalDomain         = "http://localhost:3001"
+	defaultLocalClientID       = "cohesion-local"
+	defaultLocalKeyFile        = ".local-jwt-key.pem"
+)
+
 type AuthConfig struct {
+	//Mode is either AuthModeAuth0 (the default) or AuthModeLocal
+	Mode             string
 	ClientID         string
 	ClientSecret     string
 	Domain           string
 	CallbackURL      string
 	LogoutRedirectTo string
+	//JWKSRefreshInterval is how often the signing keys published by Auth0 are re-fetched in the background
+	JWKSRefreshInterval time.Duration
+	//ProfileCacheTTL is how long the auth middleware trusts a cached profile ID and roles before re-reading them from the db
+	ProfileCacheTTL time.Duration
+	//LocalKeyFile is where the RSA signing key is read from, or generated to, in AuthModeLocal
+	LocalKeyFile string
+}
+
+func (c *AuthConfig) IsLocal() bool {
+	return c.Mode == AuthModeLocal
+}
+
+//JWKSURI is the location of the signing keys published for Domain
+func (c *AuthConfig) JWKSURI() string {
+	return fmt.Sprintf("%s/.well-known/jwks.json", c.Domain)
+}
+
+//Issuer is the expected iss claim of tokens issued for Domain
+func (c *AuthConfig) Issuer() string {
+	return fmt.Sprintf("%s/", c.Domain)
 }
 
 func NewAuthConfig() (*AuthConfig, error) {
-	config := &AuthConfig{}
+	config := &AuthConfig{
+		Mode: os.Getenv("AUTH_MODE"),
+	}
+
+	if len(config.Mode) == 0 {
+		config.Mode = AuthModeAuth0
+	}
+
+	if config.IsLocal() {
+		return newLocalAuthConfig(config)
+	}
+
+	if config.Mode != AuthModeAuth0 {
+		return nil, fmt.Errorf("Unsupported AUTH_MODE %s - expected %s or %s", config.Mode, AuthModeAuth0, AuthModeLocal)
+	}
 
 	if appEnv, err := cfenv.Current(); err == nil {
 		if auth0Service, err := appEnv.Services.WithName("auth0-admin"); err == nil {
@@ -35,6 +85,14 @@ func NewAuthConfig() (*AuthConfig, error) {
 			if LogoutRedirectTo, ok := auth0Service.CredentialString("logout-redirect-to"); ok {
 				config.LogoutRedirectTo = LogoutRedirectTo
 			}
+			if jwksRefreshInterval, ok := auth0Service.CredentialString("jwks-refresh-interval"); ok {
+				interval, err := parseRefreshInterval(jwksRefreshInterval)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid jwks-refresh-interval: %v", err)
+				}
+
+				config.JWKSRefreshInterval = interval
+			}
 		}
 	}
 
@@ -58,6 +116,23 @@ func NewAuthConfig() (*AuthConfig, error) {
 		config.LogoutRedirectTo = os.Getenv("LOGOUT_REDIRECT_TO")
 	}
 
+	if config.JWKSRefreshInterval == 0 {
+		if jwksRefreshInterval := os.Getenv("AUTH0_JWKS_REFRESH_INTERVAL"); len(jwksRefreshInterval) > 0 {
+			interval, err := parseRefreshInterval(jwksRefreshInterval)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid AUTH0_JWKS_REFRESH_INTERVAL: %v", err)
+			}
+
+			config.JWKSRefreshInterval = interval
+		}
+	}
+
+	if config.JWKSRefreshInterval == 0 {
+		config.JWKSRefreshInterval = defaultJWKSRefreshInterval
+	}
+
+	config.ProfileCacheTTL = profileCacheTTL()
+
 	var missingConfig []string
 	if len(config.ClientID) == 0 {
 		missingConfig = append(missingConfig, "ClientID")
@@ -85,3 +160,49 @@ func NewAuthConfig() (*AuthConfig, error) {
 
 	return config, nil
 }
+
+//newLocalAuthConfig fills in config for AuthModeLocal from env vars, falling back to defaults suitable for running cmd/srv on localhost
+func newLocalAuthConfig(config *AuthConfig) (*AuthConfig, error) {
+	config.ClientID = os.Getenv("AUTH0_CLIENT_ID")
+	config.Domain = os.Getenv("AUTH0_DOMAIN")
+	config.CallbackURL = os.Getenv("CALLBACK_URL")
+	config.LogoutRedirectTo = os.Getenv("LOGOUT_REDIRECT_TO")
+	config.LocalKeyFile = os.Getenv("AUTH_LOCAL_KEY_FILE")
+
+	if len(config.ClientID) == 0 {
+		config.ClientID = defaultLocalClientID
+	}
+
+	if len(config.Domain) == 0 {
+		config.Domain = defaultLocalDomain
+	}
+
+	if len(config.LocalKeyFile) == 0 {
+		config.LocalKeyFile = defaultLocalKeyFile
+	}
+
+	config.ProfileCacheTTL = profileCacheTTL()
+	return config, nil
+}
+
+//parseRefreshInterval parses a positive duration such as 1h or 30m
+func parseRefreshInterval(value string) (time.Duration, error) {
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s is not a valid duration - use e.g. 1h or 30m", value)
+	}
+
+	if interval <= 0 {
+		return 0, fmt.Errorf("%s must be longer than 0", value)
+	}
+
+	return interval, nil
+}
+
+func profileCacheTTL() time.Duration {
+	if ttl, err := time.ParseDuration(os.Getenv("PROFILE_CACHE_TTL")); err == nil && ttl > 0 {
+		return ttl
+	}
+
+	return defaultProfileCacheTTL
+}
@@ -77,3 +77,49 @@ func TestNewAuthConfigViaPartialEnvVars(t *testing.T) {
 
 	os.Clearenv()
 }
+
+func TestNewAuthConfigInLocalModeDoesNotRequireAuth0(t *testing.T) {
+	os.Setenv("AUTH_MODE", "local")
+
+	cfg, err := config.NewAuthConfig()
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	if !cfg.IsLocal() {
+		t.Errorf("expected local mode but got %s", cfg.Mode)
+	}
+
+	if len(cfg.ClientID) == 0 || len(cfg.Domain) == 0 || len(cfg.LocalKeyFile) == 0 {
+		t.Errorf("expected local defaults to be set but got %+v", cfg)
+	}
+
+	os.Clearenv()
+}
+
+func TestNewAuthConfigWithUnknownModeFails(t *testing.T) {
+	os.Setenv("AUTH_MODE", "saml")
+
+	if _, err := config.NewAuthConfig(); err == nil {
+		t.Error("expected an error for an unsupported auth mode")
+	}
+
+	os.Clearenv()
+}
+
+func TestNewAuthConfigWithInvalidJWKSRefreshIntervalFails(t *testing.T) {
+	for _, interval := range []string{"hourly", "0s", "-5m"} {
+		os.Setenv("AUTH0_CLIENT_ID", "test-client")
+		os.Setenv("AUTH0_CLIENT_SECRET", "test-secret")
+		os.Setenv("AUTH0_DOMAIN", "test-domain")
+		os.Setenv("CALLBACK_URL", "test-callback-url")
+		os.Setenv("LOGOUT_REDIRECT_TO", "test-logout-url")
+		os.Setenv("AUTH0_JWKS_REFRESH_INTERVAL", interval)
+
+		if _, err := config.NewAuthConfig(); err == nil {
+			t.Errorf("expected an error for a refresh interval of %s", interval)
+		}
+
+		os.Clearenv()
+	}
+}
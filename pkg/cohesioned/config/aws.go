@@ -5,12 +5,10 @@ import (
 	"fmt"
 	"os"
 	"strconv"
-	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
 	cfenv "github.com/cloudfoundry-community/go-cfenv"
 	_ "github.com/go-sql-driver/mysql"
 )
@@ -19,7 +17,9 @@ type AwsConfig interface {
 	NewSession() (*session.Session, error)
 	DialRDS() (*sql.DB, error)
 	GetVideoBucket() string
-	GetSignedURL(bucket, objectName string) (string, error)
+	GetTranscodingConfig() *TranscodingConfig
+	GetStorageConfig() *StorageConfig
+	GetUploadConfig() *UploadConfig
 }
 
 type config struct {
@@ -29,6 +29,9 @@ type config struct {
 	secretAccessKey string
 	sessionToken    string
 	videoBucket     string
+	transcoding     *TranscodingConfig
+	storage         *StorageConfig
+	upload          *UploadConfig
 }
 
 type rds struct {
@@ -63,6 +66,18 @@ func (c *config) GetVideoBucket() string {
 	return c.videoBucket
 }
 
+func (c *config) GetTranscodingConfig() *TranscodingConfig {
+	return c.transcoding
+}
+
+func (c *config) GetStorageConfig() *StorageConfig {
+	return c.storage
+}
+
+func (c *config) GetUploadConfig() *UploadConfig {
+	return c.upload
+}
+
 func (c *config) NewSession() (*session.Session, error) {
 	sess, err := session.NewSession(&aws.Config{
 		Region:      aws.String(c.region),
@@ -72,27 +87,9 @@ func (c *config) NewSession() (*session.Session, error) {
 	return sess, err
 }
 
-func (c *config) GetSignedURL(bucket, objectKey string) (string, error) {
-	session, err := c.NewSession()
-	if err != nil {
-		return "", fmt.Errorf("Failed to initialize session: %v", err)
-	}
-	svc := s3.New(session)
-	req, _ := svc.GetObjectRequest(&s3.GetObjectInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(objectKey),
-	})
-
-	signedURL, err := req.Presign(15 * time.Minute)
-	if err != nil {
-		return "", fmt.Errorf("Failed to sign request: %v", err)
-	}
-
-	return signedURL, nil
-}
-
 func NewAwsConfig() (AwsConfig, error) {
 	config := &config{}
+	var transcoder, pipelineID, outputPrefix, presets, hlsPresets, pollInterval string
 
 	if appEnv, err := cfenv.Current(); err == nil {
 		if awsService, err := appEnv.Services.WithName("aws"); err == nil {
@@ -111,6 +108,24 @@ func NewAwsConfig() (AwsConfig, error) {
 			if s3VideoBucketName, ok := awsService.CredentialString("s3_video_bucket"); ok {
 				config.videoBucket = s3VideoBucketName
 			}
+			if v, ok := awsService.CredentialString("transcoder"); ok {
+				transcoder = v
+			}
+			if v, ok := awsService.CredentialString("transcoder_pipeline_id"); ok {
+				pipelineID = v
+			}
+			if v, ok := awsService.CredentialString("transcoder_output_prefix"); ok {
+				outputPrefix = v
+			}
+			if v, ok := awsService.CredentialString("transcoder_presets"); ok {
+				presets = v
+			}
+			if v, ok := awsService.CredentialString("transcoder_hls_presets"); ok {
+				hlsPresets = v
+			}
+			if v, ok := awsService.CredentialString("transcoder_poll_interval"); ok {
+				pollInterval = v
+			}
 			if rdsUsername, ok := awsService.CredentialString("rds_username"); ok {
 				config.rds.username = rdsUsername
 			}
@@ -151,6 +166,30 @@ func NewAwsConfig() (AwsConfig, error) {
 		config.videoBucket = os.Getenv("AWS_S3_VIDEO_BUCKET")
 	}
 
+	if len(transcoder) == 0 {
+		transcoder = os.Getenv("AWS_TRANSCODER")
+	}
+
+	if len(pipelineID) == 0 {
+		pipelineID = os.Getenv("AWS_TRANSCODER_PIPELINE_ID")
+	}
+
+	if len(outputPrefix) == 0 {
+		outputPrefix = os.Getenv("AWS_TRANSCODER_OUTPUT_PREFIX")
+	}
+
+	if len(presets) == 0 {
+		presets = os.Getenv("AWS_TRANSCODER_PRESETS")
+	}
+
+	if len(hlsPresets) == 0 {
+		hlsPresets = os.Getenv("AWS_TRANSCODER_HLS_PRESETS")
+	}
+
+	if len(pollInterval) == 0 {
+		pollInterval = os.Getenv("AWS_TRANSCODER_POLL_INTERVAL")
+	}
+
 	if len(config.rds.username) == 0 {
 		config.rds.username = os.Getenv("AWS_RDS_USERNAME")
 	}
@@ -174,17 +213,53 @@ func NewAwsConfig() (AwsConfig, error) {
 		config.rds.dbname = os.Getenv("AWS_RDS_DBNAME")
 	}
 
-	var missingConfig []string
-	if len(config.region) == 0 {
-		missingConfig = append(missingConfig, "Region")
+	transcoding, err := newTranscodingConfig(transcoder, pipelineID, outputPrefix, presets, hlsPresets, pollInterval)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid transcoding config: %v", err)
 	}
 
-	if len(config.secretAccessKey) == 0 {
-		missingConfig = append(missingConfig, "SecretAccessKey")
+	config.transcoding = transcoding
+
+	storage, err := newStorageConfig(
+		os.Getenv("STORAGE_BACKEND"),
+		os.Getenv("API_BASE_URL"),
+		os.Getenv("STORAGE_LOCAL_DIR"),
+		os.Getenv("STORAGE_LOCAL_BASE_URL"),
+		os.Getenv("STORAGE_LOCAL_SECRET"),
+		os.Getenv("STORAGE_SIGNED_URL_TTL"),
+		os.Getenv("STORAGE_UPLOAD_URL_TTL"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid storage config: %v", err)
 	}
 
-	if len(config.accessKeyID) == 0 {
-		missingConfig = append(missingConfig, "AccessKeyID")
+	config.storage = storage
+
+	upload, err := newUploadConfig(os.Getenv("UPLOAD_ALLOWED_TYPES"), os.Getenv("UPLOAD_MAX_SIZE"))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid upload config: %v", err)
+	}
+
+	config.upload = upload
+
+	if storage.IsLocal() && len(config.videoBucket) == 0 {
+		config.videoBucket = defaultLocalStorageBucket
+	}
+
+	var missingConfig []string
+	//aws credentials are only needed for the services that use them
+	if !storage.IsLocal() || transcoding.Transcoder == TranscoderElastic {
+		if len(config.region) == 0 {
+			missingConfig = append(missingConfig, "Region")
+		}
+
+		if len(config.secretAccessKey) == 0 {
+			missingConfig = append(missingConfig, "SecretAccessKey")
+		}
+
+		if len(config.accessKeyID) == 0 {
+			missingConfig = append(missingConfig, "AccessKeyID")
+		}
 	}
 
 	if len(config.videoBucket) == 0 {
@@ -21,3 +21,15 @@ func TestNewAwsConfig(t *testing.T) {
 
 	os.Clearenv()
 }
+
+func TestNewAwsConfigWithInvalidTranscoderPollIntervalFails(t *testing.T) {
+	os.Setenv("VCAP_APPLICATION", vcapApplicationPayload)
+	os.Setenv("VCAP_SERVICES", vcapServicesPayload)
+	os.Setenv("AWS_TRANSCODER_POLL_INTERVAL", "often")
+
+	if _, err := config.NewAwsConfig(); err == nil {
+		t.Error("expected an error for an invalid transcoder poll interval")
+	}
+
+	os.Clearenv()
+}
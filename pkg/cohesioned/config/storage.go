@@ -0,0 +1,113 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	//StorageS3 keeps video files in S3 buckets
+	StorageS3 = "s3"
+	//StorageLocal keeps video files under a local directory and serves them through the API, for development and tests without an AWS account
+	StorageLocal = "local"
+
+	defaultLocalStorageDir    = ".local-storage"
+	defaultBaseURL            = "http://localhost:3001"
+	defaultLocalStorageBucket = "videos"
+	defaultSignedURLTTL       = 15 * time.Minute
+	defaultUploadURLTTL       = time.Hour
+)
+
+type StorageConfig struct {
+	//Backend is either StorageS3 (the default) or StorageLocal
+	Backend string
+	//BaseURL is the address of this API, used to build absolute urls to what it serves, such as the placeholder thumbnail
+	BaseURL string
+	//LocalDir is the directory files are kept under with StorageLocal - one sub directory per bucket
+	LocalDir string
+	//LocalBaseURL is the address used to build the signed urls of files kept with StorageLocal. Defaults to BaseURL
+	LocalBaseURL string
+	//LocalSecret signs the urls of files kept with StorageLocal
+	LocalSecret []byte
+	//SignedURLTTL is how long signed urls for downloading files are valid for
+	SignedURLTTL time.Duration
+	//UploadURLTTL is how long signed urls for uploading files are valid for. It is longer than SignedURLTTL so large files have time to upload
+	UploadURLTTL time.Duration
+}
+
+func (c *StorageConfig) IsLocal() bool {
+	return c.Backend == StorageLocal
+}
+
+//newStorageConfig validates the storage settings and fills in defaults. When no secret is given for StorageLocal a random one is generated, so signed urls stop working when the server restarts
+func newStorageConfig(backend, baseURL, localDir, localBaseURL, localSecret, signedURLTTL, uploadURLTTL string) (*StorageConfig, error) {
+	c := &StorageConfig{
+		Backend:      backend,
+		BaseURL:      strings.TrimSuffix(baseURL, "/"),
+		LocalDir:     localDir,
+		LocalBaseURL: strings.TrimSuffix(localBaseURL, "/"),
+		LocalSecret:  []byte(localSecret),
+		SignedURLTTL: defaultSignedURLTTL,
+		UploadURLTTL: defaultUploadURLTTL,
+	}
+
+	if len(c.Backend) == 0 {
+		c.Backend = StorageS3
+	}
+
+	if len(c.BaseURL) == 0 {
+		c.BaseURL = defaultBaseURL
+	}
+
+	var err error
+	if c.SignedURLTTL, err = parseTTL(signedURLTTL, c.SignedURLTTL); err != nil {
+		return nil, err
+	}
+
+	if c.UploadURLTTL, err = parseTTL(uploadURLTTL, c.UploadURLTTL); err != nil {
+		return nil, err
+	}
+
+	switch c.Backend {
+	case StorageS3:
+		return c, nil
+	case StorageLocal:
+	default:
+		return nil, fmt.Errorf("unknown storage backend %s - use %s or %s", c.Backend, StorageS3, StorageLocal)
+	}
+
+	if len(c.LocalDir) == 0 {
+		c.LocalDir = defaultLocalStorageDir
+	}
+
+	if len(c.LocalBaseURL) == 0 {
+		c.LocalBaseURL = c.BaseURL
+	}
+
+	if len(c.LocalSecret) == 0 {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("Failed to generate a local storage secret: %v", err)
+		}
+
+		c.LocalSecret = []byte(hex.EncodeToString(secret))
+	}
+
+	return c, nil
+}
+
+func parseTTL(value string, defaultTTL time.Duration) (time.Duration, error) {
+	if len(value) == 0 {
+		return defaultTTL, nil
+	}
+
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		return 0, fmt.Errorf("%s is not a valid signed url ttl", value)
+	}
+
+	return ttl, nil
+}
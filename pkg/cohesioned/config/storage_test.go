@@ -0,0 +1,50 @@
+package config_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned/config"
+)
+
+func TestNewAwsConfigWithLocalStorageNeedsNoAwsAccount(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+
+	os.Setenv("STORAGE_BACKEND", config.StorageLocal)
+	os.Setenv("STORAGE_SIGNED_URL_TTL", "1h")
+	os.Setenv("AWS_RDS_USERNAME", "cohesion")
+	os.Setenv("AWS_RDS_HOST", "localhost")
+	os.Setenv("AWS_RDS_PORT", "3306")
+	os.Setenv("AWS_RDS_DBNAME", "cohesion")
+
+	cfg, err := config.NewAwsConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error initializing AwsConfig: %v", err)
+	}
+
+	sc := cfg.GetStorageConfig()
+	if !sc.IsLocal() || len(sc.LocalDir) == 0 || len(sc.LocalBaseURL) == 0 || len(sc.LocalSecret) == 0 {
+		t.Errorf("expected local storage with defaults filled in but got %v", sc)
+	}
+
+	if sc.SignedURLTTL != time.Hour {
+		t.Errorf("expected a signed url ttl of 1h but got %v", sc.SignedURLTTL)
+	}
+
+	if len(cfg.GetVideoBucket()) == 0 {
+		t.Error("expected a default video bucket for local storage")
+	}
+}
+
+func TestNewAwsConfigRejectsUnknownStorageBackend(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+
+	os.Setenv("STORAGE_BACKEND", "ftp")
+
+	if _, err := config.NewAwsConfig(); err == nil {
+		t.Error("expected an unknown storage backend to be rejected")
+	}
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	//TranscoderNone skips transcoding. Videos are played from the original file
+	TranscoderNone = "none"
+	//TranscoderElastic submits jobs to the AWS Elastic Transcoder pipeline at PipelineID
+	TranscoderElastic = "elastictranscoder"
+	//TranscoderStub completes every job immediately using the original file as the rendition, so uploads can be exercised offline
+	TranscoderStub = "stub"
+
+	defaultTranscodingOutputPrefix = "transcoded/"
+	defaultTranscodingPollInterval = 30 * time.Second
+	//defaultTranscodingPresets is the Elastic Transcoder system preset for 480p 16:9 video
+	defaultTranscodingPresets = "480p-16x9=1351620000001-000020"
+	//defaultHLSPresets are the Elastic Transcoder system presets for HLS v3 at 400k, 1M and 2M
+	defaultHLSPresets = "hls-400k=1351620000001-200050,hls-1m=1351620000001-200030,hls-2m=1351620000001-200010"
+
+	//HLSPlaylistExtension is appended to the output key of an HLS preset. Its segments are named after the playlist, without the extension
+	HLSPlaylistExtension = ".m3u8"
+)
+
+//TranscodingPreset is a rendition every uploaded video is transcoded to
+type TranscodingPreset struct {
+	//Name is prefixed to the key of the transcoded file, e.g. 480p-16x9
+	Name string
+	//PresetID is the Elastic Transcoder preset
+	PresetID string
+	//HLS presets write a playlist and its segments rather than a single file
+	HLS bool
+}
+
+type TranscodingConfig struct {
+	//Transcoder is one of TranscoderNone, TranscoderElastic or TranscoderStub
+	Transcoder string
+	PipelineID string
+	//OutputPrefix is prepended to the key of every transcoded file and thumbnail
+	OutputPrefix string
+	//Presets are in order of preference - the first complete rendition is the one played back
+	Presets []TranscodingPreset
+	//HLSPresets are the variants listed in a video's HLS master playlist
+	HLSPresets []TranscodingPreset
+	//PollInterval is how often the status of unfinished transcoding jobs is checked in the background
+	PollInterval time.Duration
+}
+
+//AllPresets are the presets every uploaded video is transcoded to - Presets followed by HLSPresets
+func (c *TranscodingConfig) AllPresets() []TranscodingPreset {
+	return append(append([]TranscodingPreset{}, c.Presets...), c.HLSPresets...)
+}
+
+//OutputBase is the prefix of every file the given preset writes for objectName
+func (c *TranscodingConfig) OutputBase(preset TranscodingPreset, objectName string) string {
+	return fmt.Sprintf("%s%s-%s", c.OutputPrefix, preset.Name, objectName)
+}
+
+//OutputKey is where the given preset's rendition of objectName is written. For an HLS preset it is the rendition's playlist
+func (c *TranscodingConfig) OutputKey(preset TranscodingPreset, objectName string) string {
+	if preset.HLS {
+		return c.OutputBase(preset, objectName) + HLSPlaylistExtension
+	}
+
+	return c.OutputBase(preset, objectName)
+}
+
+//ThumbnailPrefix is the prefix of every thumbnail generated from objectName
+func (c *TranscodingConfig) ThumbnailPrefix(objectName string) string {
+	return fmt.Sprintf("%sthumbnails/%s-", c.OutputPrefix, objectName)
+}
+
+//ParseTranscodingPresets parses a comma separated list of name=preset-id pairs
+func ParseTranscodingPresets(value string) ([]TranscodingPreset, error) {
+	var presets []TranscodingPreset
+
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if len(pair) == 0 {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || len(strings.TrimSpace(parts[0])) == 0 || len(strings.TrimSpace(parts[1])) == 0 {
+			return nil, fmt.Errorf("%s is not a valid transcoding preset - use name=preset-id", pair)
+		}
+
+		presets = append(presets, TranscodingPreset{
+			Name:     strings.TrimSpace(parts[0]),
+			PresetID: strings.TrimSpace(parts[1]),
+		})
+	}
+
+	return presets, nil
+}
+
+func newTranscodingConfig(transcoder, pipelineID, outputPrefix, presets, hlsPresets, pollInterval string) (*TranscodingConfig, error) {
+	c := &TranscodingConfig{
+		Transcoder:   transcoder,
+		PipelineID:   pipelineID,
+		OutputPrefix: outputPrefix,
+		PollInterval: defaultTranscodingPollInterval,
+	}
+
+	if len(c.Transcoder) == 0 {
+		c.Transcoder = TranscoderNone
+		if len(c.PipelineID) > 0 {
+			c.Transcoder = TranscoderElastic
+		}
+	}
+
+	switch c.Transcoder {
+	case TranscoderNone, TranscoderStub:
+	case TranscoderElastic:
+		if len(c.PipelineID) == 0 {
+			return nil, fmt.Errorf("a pipeline id is required to use %s", TranscoderElastic)
+		}
+	default:
+		return nil, fmt.Errorf("unknown transcoder %s - use %s, %s or %s", c.Transcoder, TranscoderNone, TranscoderElastic, TranscoderStub)
+	}
+
+	if len(c.OutputPrefix) == 0 {
+		c.OutputPrefix = defaultTranscodingOutputPrefix
+	}
+
+	if len(pollInterval) > 0 {
+		interval, err := time.ParseDuration(pollInterval)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("%s is not a valid poll interval - use a duration such as 30s", pollInterval)
+		}
+
+		c.PollInterval = interval
+	}
+
+	if len(presets) == 0 {
+		presets = defaultTranscodingPresets
+	}
+
+	var err error
+	if c.Presets, err = ParseTranscodingPresets(presets); err != nil {
+		return nil, err
+	}
+
+	//the stub transcoder can't write playlists, so HLS is only on by default with Elastic Transcoder
+	if len(hlsPresets) == 0 && c.Transcoder == TranscoderElastic {
+		hlsPresets = defaultHLSPresets
+	}
+
+	if c.HLSPresets, err = ParseTranscodingPresets(hlsPresets); err != nil {
+		return nil, err
+	}
+
+	for i := range c.HLSPresets {
+		c.HLSPresets[i].HLS = true
+	}
+
+	names := make(map[string]bool)
+	for _, preset := range c.AllPresets() {
+		if names[preset.Name] {
+			return nil, fmt.Errorf("%s is used for more than one transcoding preset", preset.Name)
+		}
+
+		names[preset.Name] = true
+	}
+
+	return c, nil
+}
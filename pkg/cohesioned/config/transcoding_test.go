@@ -0,0 +1,59 @@
+package config_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cohesion-education/api/pkg/cohesioned/config"
+)
+
+func TestParseTranscodingPresets(t *testing.T) {
+	presets, err := config.ParseTranscodingPresets("720p=1351620000001-000010, 480p-16x9=1351620000001-000020")
+	if err != nil {
+		t.Fatalf("Unexpected error parsing presets: %v", err)
+	}
+
+	expected := []config.TranscodingPreset{
+		{Name: "720p", PresetID: "1351620000001-000010"},
+		{Name: "480p-16x9", PresetID: "1351620000001-000020"},
+	}
+
+	if !reflect.DeepEqual(expected, presets) {
+		t.Errorf("expected presets %v but got %v", expected, presets)
+	}
+
+	if _, err := config.ParseTranscodingPresets("720p"); err == nil {
+		t.Error("expected a preset without an id to be rejected")
+	}
+}
+
+func TestTranscodingOutputKey(t *testing.T) {
+	tc := &config.TranscodingConfig{OutputPrefix: "transcoded/"}
+	key := tc.OutputKey(config.TranscodingPreset{Name: "480p-16x9"}, "1-video.mp4")
+
+	if expected := "transcoded/480p-16x9-1-video.mp4"; key != expected {
+		t.Errorf("expected %s but got %s", expected, key)
+	}
+}
+
+func TestTranscodingThumbnailPrefix(t *testing.T) {
+	tc := &config.TranscodingConfig{OutputPrefix: "transcoded/"}
+	prefix := tc.ThumbnailPrefix("1-video.mp4")
+
+	if expected := "transcoded/thumbnails/1-video.mp4-"; prefix != expected {
+		t.Errorf("expected %s but got %s", expected, prefix)
+	}
+}
+
+func TestTranscodingOutputKeyOfHLSPreset(t *testing.T) {
+	tc := &config.TranscodingConfig{OutputPrefix: "transcoded/"}
+	preset := config.TranscodingPreset{Name: "hls-1m", HLS: true}
+
+	if expected := "transcoded/hls-1m-1-video.mp4.m3u8"; tc.OutputKey(preset, "1-video.mp4") != expected {
+		t.Errorf("expected %s but got %s", expected, tc.OutputKey(preset, "1-video.mp4"))
+	}
+
+	if expected := "transcoded/hls-1m-1-video.mp4"; tc.OutputBase(preset, "1-video.mp4") != expected {
+		t.Errorf("expected %s but got %s", expected, tc.OutputBase(preset, "1-video.mp4"))
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultUploadAllowedTypes = "video/mp4,video/quicktime,video/webm,video/x-matroska"
+	defaultUploadMaxSize      = "5GB"
+)
+
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"TB", 1 << 40},
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+//UploadConfig is what an uploaded video file is checked against
+type UploadConfig struct {
+	//AllowedTypes are the content types, sniffed from the file itself, that can be uploaded. Any type is allowed when it is empty
+	AllowedTypes []string
+	//MaxSize is the largest file in bytes that can be uploaded. There is no limit when it is 0
+	MaxSize int64
+}
+
+//Allows returns true if a file of contentType can be uploaded
+func (c *UploadConfig) Allows(contentType string) bool {
+	if len(c.AllowedTypes) == 0 {
+		return true
+	}
+
+	for _, t := range c.AllowedTypes {
+		if t == contentType {
+			return true
+		}
+	}
+
+	return false
+}
+
+//ParseSize parses a number of bytes with an optional KB, MB, GB or TB suffix, e.g. 500MB
+func ParseSize(size string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(size))
+	multiplier := int64(1)
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			multiplier = unit.bytes
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s is not a valid size - use a number of bytes, KB, MB, GB or TB", size)
+	}
+
+	return n * multiplier, nil
+}
+
+func newUploadConfig(allowedTypes, maxSize string) (*UploadConfig, error) {
+	if len(allowedTypes) == 0 {
+		allowedTypes = defaultUploadAllowedTypes
+	}
+
+	if len(maxSize) == 0 {
+		maxSize = defaultUploadMaxSize
+	}
+
+	c := &UploadConfig{}
+	for _, t := range strings.Split(allowedTypes, ",") {
+		if t = strings.ToLower(strings.TrimSpace(t)); len(t) > 0 && t != "*" {
+			c.AllowedTypes = append(c.AllowedTypes, t)
+		}
+	}
+
+	var err error
+	if c.MaxSize, err = ParseSize(maxSize); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
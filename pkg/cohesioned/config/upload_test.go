@@ -0,0 +1,78 @@
+package config_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/cohesion-education/api/pkg/cohesioned/config"
+)
+
+func TestParseSize(t *testing.T) {
+	sizes := map[string]int64{
+		"1024":   1024,
+		"500MB":  500 * 1024 * 1024,
+		"5gb":    5 * 1024 * 1024 * 1024,
+		"10 KB":  10 * 1024,
+		"2TB":    2 * 1024 * 1024 * 1024 * 1024,
+		"100B":   100,
+		" 1 GB ": 1024 * 1024 * 1024,
+	}
+
+	for value, expected := range sizes {
+		size, err := config.ParseSize(value)
+		if err != nil || size != expected {
+			t.Errorf("expected %q to be %d bytes but got %d %v", value, expected, size, err)
+		}
+	}
+
+	for _, value := range []string{"", "big", "-1MB", "1.5GB"} {
+		if _, err := config.ParseSize(value); err == nil {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestNewAwsConfigUploadDefaults(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+
+	os.Setenv("STORAGE_BACKEND", config.StorageLocal)
+	os.Setenv("UPLOAD_ALLOWED_TYPES", "video/mp4, video/webm")
+	os.Setenv("AWS_RDS_USERNAME", "cohesion")
+	os.Setenv("AWS_RDS_HOST", "localhost")
+	os.Setenv("AWS_RDS_PORT", "3306")
+	os.Setenv("AWS_RDS_DBNAME", "cohesion")
+
+	cfg, err := config.NewAwsConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error initializing AwsConfig: %v", err)
+	}
+
+	uc := cfg.GetUploadConfig()
+	if !uc.Allows("video/webm") || uc.Allows("video/quicktime") {
+		t.Errorf("expected only the configured types to be allowed but got %v", uc.AllowedTypes)
+	}
+
+	if uc.MaxSize != 5*1024*1024*1024 {
+		t.Errorf("expected a default max size of 5GB but got %d", uc.MaxSize)
+	}
+}
+
+func TestNewAwsConfigRejectsInvalidUploadMaxSize(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+
+	os.Setenv("STORAGE_BACKEND", config.StorageLocal)
+	os.Setenv("UPLOAD_MAX_SIZE", "huge")
+
+	if _, err := config.NewAwsConfig(); err == nil {
+		t.Error("expected an invalid max size to be rejected")
+	}
+}
+
+func TestUploadConfigWithoutAllowedTypesAllowsAnything(t *testing.T) {
+	uc := &config.UploadConfig{}
+	if !uc.Allows("text/plain") {
+		t.Error("expected any type to be allowed")
+	}
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+//ListQuery builds the paginated select, and the count of all matching rows, for a list endpoint
+type ListQuery struct {
+	//Select is the select clause, e.g. "select v.id, v.title"
+	Select string
+	//From is the from clause, including any joined tables
+	From string
+	//Where holds the conditions that always apply, e.g. join conditions
+	Where []string
+	//Args are the values of any placeholders in Where
+	Args []interface{}
+	//Columns maps the sort and filter fields of a cohesioned.ListSpec onto columns. The "id" field breaks ties so pages are stable
+	Columns map[string]string
+}
+
+//Build returns the select and count queries for opts, along with the args for each
+func (q ListQuery) Build(opts *cohesioned.ListOptions) (string, []interface{}, string, []interface{}, error) {
+	conditions := append([]string{}, q.Where...)
+	args := append([]interface{}{}, q.Args...)
+
+	for _, filter := range opts.Filters {
+		column, ok := q.Columns[filter.Field]
+		if !ok {
+			return "", nil, "", nil, fmt.Errorf("%s can not be filtered on", filter.Field)
+		}
+
+		switch filter.Op {
+		case "=", ">=", "<":
+		default:
+			return "", nil, "", nil, fmt.Errorf("unsupported filter operator %s", filter.Op)
+		}
+
+		conditions = append(conditions, fmt.Sprintf("%s %s ?", column, filter.Op))
+		args = append(args, filter.Value)
+	}
+
+	from := q.From
+	if len(conditions) > 0 {
+		from = fmt.Sprintf("%s where %s", from, strings.Join(conditions, " and "))
+	}
+
+	countQuery := fmt.Sprintf("select count(*) %s", from)
+	countArgs := append([]interface{}{}, args...)
+
+	direction := "asc"
+	if opts.Desc {
+		direction = "desc"
+	}
+
+	idColumn := q.Columns["id"]
+	orderBy := fmt.Sprintf("%s %s", idColumn, direction)
+	if len(opts.Sort) > 0 && opts.Sort != "id" {
+		sortColumn, ok := q.Columns[opts.Sort]
+		if !ok {
+			return "", nil, "", nil, fmt.Errorf("%s can not be sorted on", opts.Sort)
+		}
+
+		orderBy = fmt.Sprintf("%s %s, %s", sortColumn, direction, orderBy)
+	}
+
+	query := fmt.Sprintf("%s %s order by %s limit ? offset ?", q.Select, from, orderBy)
+	args = append(args, opts.Limit, opts.Offset)
+
+	return query, args, countQuery, countArgs, nil
+}
+
+//Placeholders returns n comma separated placeholders for an in (...) clause
+func Placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
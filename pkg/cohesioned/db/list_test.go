@@ -0,0 +1,90 @@
+package db_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/db"
+)
+
+var testQuery = db.ListQuery{
+	Select:  "select v.id, v.title",
+	From:    "from video v, taxonomy t",
+	Where:   []string{"v.taxonomy_id = t.id"},
+	Columns: map[string]string{"id": "v.id", "title": "v.title", "created": "v.created", "taxonomy_id": "v.taxonomy_id"},
+}
+
+func TestListQueryBuild(t *testing.T) {
+	from := time.Date(2017, 9, 1, 0, 0, 0, 0, time.UTC)
+	opts := &cohesioned.ListOptions{
+		Limit:  10,
+		Offset: 20,
+		Sort:   "title",
+		Desc:   true,
+		Filters: []cohesioned.Filter{
+			{Field: "taxonomy_id", Op: "=", Value: int64(4)},
+			{Field: "created", Op: ">=", Value: from},
+		},
+	}
+
+	query, args, countQuery, countArgs, err := testQuery.Build(opts)
+	if err != nil {
+		t.Fatalf("Failed to build query: %v", err)
+	}
+
+	expectedQuery := "select v.id, v.title from video v, taxonomy t where v.taxonomy_id = t.id and v.taxonomy_id = ? and v.created >= ? order by v.title desc, v.id desc limit ? offset ?"
+	if query != expectedQuery {
+		t.Errorf("expected query\n%s\nbut got\n%s", expectedQuery, query)
+	}
+
+	if expectedArgs := []interface{}{int64(4), from, 10, 20}; !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args %v but got %v", expectedArgs, args)
+	}
+
+	expectedCount := "select count(*) from video v, taxonomy t where v.taxonomy_id = t.id and v.taxonomy_id = ? and v.created >= ?"
+	if countQuery != expectedCount {
+		t.Errorf("expected count query\n%s\nbut got\n%s", expectedCount, countQuery)
+	}
+
+	if len(countArgs) != 2 {
+		t.Errorf("expected 2 count args but got %v", countArgs)
+	}
+}
+
+func TestListQueryBuildRejectsUnknownFields(t *testing.T) {
+	opts := &cohesioned.ListOptions{Limit: 10, Sort: "file_size"}
+	if _, _, _, _, err := testQuery.Build(opts); err == nil {
+		t.Error("expected sorting on an unmapped field to fail")
+	}
+
+	opts = &cohesioned.ListOptions{Limit: 10, Filters: []cohesioned.Filter{{Field: "bucket", Op: "=", Value: "x"}}}
+	if _, _, _, _, err := testQuery.Build(opts); err == nil {
+		t.Error("expected filtering on an unmapped field to fail")
+	}
+}
+
+func TestListQueryBuildWithWhereArgs(t *testing.T) {
+	q := testQuery
+	q.Where = []string{"v.taxonomy_id = t.id", "t.name = ?"}
+	q.Args = []interface{}{"Math"}
+
+	opts := &cohesioned.ListOptions{
+		Limit:   10,
+		Filters: []cohesioned.Filter{{Field: "taxonomy_id", Op: "=", Value: int64(4)}},
+	}
+
+	_, args, _, countArgs, err := q.Build(opts)
+	if err != nil {
+		t.Fatalf("Failed to build query: %v", err)
+	}
+
+	if expected := []interface{}{"Math", int64(4), 10, 0}; !reflect.DeepEqual(expected, args) {
+		t.Errorf("expected args %v but got %v", expected, args)
+	}
+
+	if expected := []interface{}{"Math", int64(4)}; !reflect.DeepEqual(expected, countArgs) {
+		t.Errorf("expected count args %v but got %v", expected, countArgs)
+	}
+}
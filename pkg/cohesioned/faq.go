@@ -0,0 +1,85 @@
+package cohesioned
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+//MaxQuestionLength is the longest question in characters that can be asked about a video
+const MaxQuestionLength = 2000
+
+//FAQStatus is how far through moderation an FAQ is
+type FAQStatus string
+
+const (
+	//FAQPending is a question a parent asked that hasn't been answered yet
+	FAQPending FAQStatus = "pending"
+	//FAQAnswered has an answer that only the parent who asked can see
+	FAQAnswered FAQStatus = "answered"
+	//FAQPublished is answered and shown with the video to everyone
+	FAQPublished FAQStatus = "published"
+	//FAQRejected won't be answered, e.g. because it is off topic
+	FAQRejected FAQStatus = "rejected"
+)
+
+//FAQStatuses returns every FAQStatus an FAQ can be in
+func FAQStatuses() []FAQStatus {
+	return []FAQStatus{FAQPending, FAQAnswered, FAQPublished, FAQRejected}
+}
+
+//IsAnswered returns true if an FAQ in this status has an answer
+func (s FAQStatus) IsAnswered() bool {
+	return s == FAQAnswered || s == FAQPublished
+}
+
+//FAQ is a question about a video and its answer. It is either written by an admin or asked by a parent and then answered by an admin
+type FAQ struct {
+	Validatable
+	ID       int64     `json:"id"`
+	VideoID  int64     `json:"video_id"`
+	Question string    `json:"question"`
+	Answer   string    `json:"answer,omitempty"`
+	Status   FAQStatus `json:"status"`
+	//Position orders the video's published FAQs, lowest first
+	Position int64 `json:"position"`
+	//AskedBy is the parent who asked the question, or 0 if an admin wrote it
+	AskedBy    int64     `json:"asked_by,omitempty"`
+	Answered   time.Time `json:"answered"`
+	AnsweredBy int64     `json:"answered_by,omitempty"`
+	Created    time.Time `json:"created"`
+	CreatedBy  int64     `json:"created_by"`
+	Updated    time.Time `json:"updated"`
+	UpdatedBy  int64     `json:"updated_by"`
+}
+
+//Validate checks that the FAQ has a question, a known status and an answer if it is answered or published
+func (f *FAQ) Validate() bool {
+	f.Question = strings.TrimSpace(f.Question)
+	f.Answer = strings.TrimSpace(f.Answer)
+
+	if len(f.Question) == 0 {
+		f.AddValidationError("question", "question is required")
+	}
+
+	if len([]rune(f.Question)) > MaxQuestionLength {
+		f.AddValidationError("question", fmt.Sprintf("question can't be longer than %d characters", MaxQuestionLength))
+	}
+
+	known := false
+	for _, status := range FAQStatuses() {
+		if f.Status == status {
+			known = true
+		}
+	}
+
+	if !known {
+		f.AddValidationError("status", fmt.Sprintf("%s is not one of %v", f.Status, FAQStatuses()))
+	}
+
+	if f.Status.IsAnswered() && len(f.Answer) == 0 {
+		f.AddValidationError("answer", fmt.Sprintf("an answer is required to mark the question %s", f.Status))
+	}
+
+	return len(f.ValidationErrors) == 0
+}
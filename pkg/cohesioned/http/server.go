@@ -5,13 +5,21 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/apikey"
+	"github.com/cohesion-education/api/pkg/cohesioned/audit"
 	"github.com/cohesion-education/api/pkg/cohesioned/auth"
 	"github.com/cohesion-education/api/pkg/cohesioned/billing"
 	"github.com/cohesion-education/api/pkg/cohesioned/config"
+	"github.com/cohesion-education/api/pkg/cohesioned/notification"
 	"github.com/cohesion-education/api/pkg/cohesioned/profile"
+	"github.com/cohesion-education/api/pkg/cohesioned/quiz"
 	"github.com/cohesion-education/api/pkg/cohesioned/report"
+	"github.com/cohesion-education/api/pkg/cohesioned/standard"
+	"github.com/cohesion-education/api/pkg/cohesioned/storage"
 	"github.com/cohesion-education/api/pkg/cohesioned/student"
 	"github.com/cohesion-education/api/pkg/cohesioned/taxonomy"
+	"github.com/cohesion-education/api/pkg/cohesioned/teacher"
 	"github.com/cohesion-education/api/pkg/cohesioned/video"
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
@@ -49,12 +57,25 @@ func newServer() *negroni.Negroni {
 		log.Fatal(err)
 	}
 
-	profileRepo := profile.NewAwsRepo(db)
+	identityCache := profile.NewIdentityCache(authConfig.ProfileCacheTTL)
+	profileRepo := profile.NewCachingRepo(profile.NewAwsRepo(db), identityCache)
 	taxonomyRepo := taxonomy.NewAwsRepo(db)
 	studentRepo := student.NewAwsRepo(db)
 	videoRepo := video.NewAwsRepo(db, awsConfig)
+	chapterRepo := video.NewAwsChapterRepo(db)
+	faqRepo := video.NewAwsFAQRepo(db)
+	watchRepo := video.NewAwsWatchRepo(db)
+	relatedRepo := video.NewAwsRelatedRepo(db)
 	paymentDetailsRepo := billing.NewAwsRepo(db)
-	adminVideoService := video.NewService(videoRepo, taxonomyRepo, awsConfig)
+	apiKeyRepo := apikey.NewAwsRepo(db)
+	auditRepo := audit.NewAwsRepo(db)
+	standardRepo := standard.NewAwsRepo(db)
+	teacherRepo := teacher.NewAwsRepo(db)
+	notificationRepo := notification.NewAwsRepo(db)
+	quizRepo := quiz.NewAwsRepo(db)
+	blobStore := storage.New(awsConfig)
+	adminVideoService := video.NewService(videoRepo, chapterRepo, faqRepo, watchRepo, relatedRepo, taxonomyRepo, standardRepo, teacherRepo, notificationRepo, blobStore, video.NewTranscoder(awsConfig), awsConfig)
+	video.NewTranscodingPoller(adminVideoService, awsConfig.GetTranscodingConfig().PollInterval)
 
 	n := negroni.Classic()
 	mx := mux.NewRouter()
@@ -69,22 +90,88 @@ func newServer() *negroni.Negroni {
 	mx.Methods(http.MethodGet).Path("/api/taxonomy/{id:[0-9]+}/children").Handler(taxonomy.ListChildrenHandler(apiRenderer, taxonomyRepo))
 	mx.Methods(http.MethodGet).Path("/api/taxonomy/recursive").Handler(taxonomy.RecursiveListHandler(apiRenderer, taxonomyRepo))
 	mx.Methods(http.MethodGet).Path("/api/taxonomy/flatten").Handler(taxonomy.FlatListHandler(apiRenderer, taxonomyRepo))
+	mx.Methods(http.MethodGet).Path(video.PlaceholderThumbnailPath).Handler(video.PlaceholderThumbnailHandler(apiRenderer))
 
+	if localStore, ok := blobStore.(*storage.LocalStore); ok {
+		fmt.Println("STORAGE_BACKEND is local - files are kept on disk and served from " + storage.LocalStorePath)
+		mx.Methods(http.MethodGet, http.MethodHead, http.MethodPut).Path(storage.LocalStorePath + "/{bucket}/{key:.+}").Handler(storage.LocalFileHandler(apiRenderer, localStore, awsConfig.GetUploadConfig().MaxSize))
+	}
+
+	secretProvider, err := auth.NewSecretProvider(authConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if issuer, ok := secretProvider.(*auth.LocalIssuer); ok {
+		fmt.Println("AUTH_MODE is local - tokens are signed and verified with a local key. Use cmd/mint-token to create one")
+		mx.Methods(http.MethodGet).Path("/.well-known/jwks.json").Handler(auth.JWKSHandler(apiRenderer, issuer))
+	}
+
+	checkJwt := auth.CheckJwt(apiRenderer, profileRepo, identityCache, authConfig, secretProvider)
 	authMiddleware := negroni.New(
-		negroni.HandlerFunc(auth.CheckJwt(apiRenderer, profileRepo, authConfig)),
+		negroni.HandlerFunc(auth.CheckAPIKey(apiRenderer, apiKeyRepo, profileRepo, checkJwt)),
+		negroni.HandlerFunc(auth.Impersonate(apiRenderer, profileRepo, auditRepo)),
 	)
 
-	//endpoints that require Admin priveleges
-	requiresAdmin(http.MethodPost, "/api/taxonomy", taxonomy.AddHandler(apiRenderer, taxonomyRepo), mx, authMiddleware)
-	requiresAdmin(http.MethodPut, "/api/taxonomy/{id:[0-9]+}", taxonomy.UpdateHandler(apiRenderer, taxonomyRepo), mx, authMiddleware)
-	requiresAdmin(http.MethodGet, "/api/videos", video.ListHandler(apiRenderer, adminVideoService), mx, authMiddleware)
-	requiresAdmin(http.MethodPost, "/api/video", video.AddHandler(apiRenderer, adminVideoService), mx, authMiddleware)
-	requiresAdmin(http.MethodPost, "/api/video/upload/{id:[0-9]+}", video.UploadHandler(apiRenderer, adminVideoService), mx, authMiddleware)
-	requiresAdmin(http.MethodDelete, "/api/video/{id:[0-9]+}", video.DeleteHandler(apiRenderer, adminVideoService), mx, authMiddleware)
-	requiresAdmin(http.MethodPut, "/api/video/{id:[0-9]+}", video.UpdateHandler(apiRenderer, adminVideoService), mx, authMiddleware)
-	requiresAdmin(http.MethodGet, "/api/report/profiles", report.GetUserList(apiRenderer, profileRepo), mx, authMiddleware)
-	requiresAdmin(http.MethodGet, "/api/report/students", report.GetStudentList(apiRenderer, studentRepo), mx, authMiddleware)
-	requiresAdmin(http.MethodGet, "/api/report/paymentdetails", report.GetPaymentDetailList(apiRenderer, paymentDetailsRepo), mx, authMiddleware)
+	//endpoints that require specific permissions
+	requiresPermission(cohesioned.PermissionManageTaxonomy, http.MethodPost, "/api/taxonomy", taxonomy.AddHandler(apiRenderer, taxonomyRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageTaxonomy, http.MethodPut, "/api/taxonomy/{id:[0-9]+}", taxonomy.UpdateHandler(apiRenderer, taxonomyRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageStandards, http.MethodPost, "/api/standards", standard.AddHandler(apiRenderer, standardRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageStandards, http.MethodPost, "/api/standards/import", standard.ImportHandler(apiRenderer, standardRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageStandards, http.MethodPut, "/api/standards/{id:[0-9]+}", standard.UpdateHandler(apiRenderer, standardRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageStandards, http.MethodDelete, "/api/standards/{id:[0-9]+}", standard.DeleteHandler(apiRenderer, standardRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageTeachers, http.MethodPost, "/api/teachers", teacher.AddHandler(apiRenderer, teacherRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageTeachers, http.MethodPut, "/api/teachers/{id:[0-9]+}", teacher.UpdateHandler(apiRenderer, teacherRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageTeachers, http.MethodDelete, "/api/teachers/{id:[0-9]+}", teacher.DeleteHandler(apiRenderer, teacherRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageQuizzes, http.MethodPost, "/api/quizzes", quiz.AddHandler(apiRenderer, quizRepo, videoRepo, taxonomyRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageQuizzes, http.MethodPut, "/api/quizzes/{id:[0-9]+}", quiz.UpdateHandler(apiRenderer, quizRepo, videoRepo, taxonomyRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageQuizzes, http.MethodDelete, "/api/quizzes/{id:[0-9]+}", quiz.DeleteHandler(apiRenderer, quizRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionReviewVideos, http.MethodGet, "/api/videos", video.ListHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageVideos, http.MethodPost, "/api/video", video.AddHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageVideos, http.MethodPost, "/api/video/upload/{id:[0-9]+}", video.UploadHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageVideos, http.MethodPut, "/api/video/upload/{id:[0-9]+}", video.ResumableUploadHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageVideos, http.MethodGet, "/api/video/upload/{id:[0-9]+}", video.UploadProgressHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageVideos, http.MethodDelete, "/api/video/upload/{id:[0-9]+}", video.CancelUploadHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageVideos, http.MethodPost, "/api/video/upload/{id:[0-9]+}/presigned", video.PresignedUploadHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageVideos, http.MethodPost, "/api/video/upload/{id:[0-9]+}/multipart", video.MultipartUploadHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageVideos, http.MethodDelete, "/api/video/upload/{id:[0-9]+}/multipart/{upload_id}", video.AbortUploadHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageVideos, http.MethodPost, "/api/video/upload/{id:[0-9]+}/complete", video.CompleteUploadHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionReviewVideos, http.MethodGet, "/api/video/{id:[0-9]+}/transcoding", video.TranscodingStatusHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageVideos, http.MethodPost, "/api/video/{id:[0-9]+}/thumbnail", video.ThumbnailUploadHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageVideos, http.MethodDelete, "/api/video/{id:[0-9]+}/thumbnail", video.DeleteThumbnailHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageVideos, http.MethodPut, "/api/video/{id:[0-9]+}/captions/{language}", video.CaptionsUploadHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageVideos, http.MethodDelete, "/api/video/{id:[0-9]+}/captions/{language}", video.DeleteCaptionsHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/video/{id:[0-9]+}/chapters", video.ChaptersHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageVideos, http.MethodPost, "/api/video/{id:[0-9]+}/chapters", video.AddChapterHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageVideos, http.MethodPut, "/api/video/{id:[0-9]+}/chapters/{chapter_id:[0-9]+}", video.UpdateChapterHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageVideos, http.MethodDelete, "/api/video/{id:[0-9]+}/chapters/{chapter_id:[0-9]+}", video.DeleteChapterHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresAuth(http.MethodPost, "/api/video/{id:[0-9]+}/progress", video.WatchEventHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/video/{id:[0-9]+}/related", video.RelatedHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageVideos, http.MethodPut, "/api/video/{id:[0-9]+}/related", video.SetRelatedHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/video/{id:[0-9]+}/faqs", video.FAQsHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionModerateFAQs, http.MethodPost, "/api/video/{id:[0-9]+}/faqs", video.AddFAQHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionModerateFAQs, http.MethodPut, "/api/video/{id:[0-9]+}/faqs/{faq_id:[0-9]+}", video.UpdateFAQHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionModerateFAQs, http.MethodDelete, "/api/video/{id:[0-9]+}/faqs/{faq_id:[0-9]+}", video.DeleteFAQHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresAuth(http.MethodPost, "/api/video/{id:[0-9]+}/questions", video.AskQuestionHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionModerateFAQs, http.MethodGet, "/api/admin/questions", video.QuestionsHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageVideos, http.MethodDelete, "/api/video/{id:[0-9]+}", video.DeleteHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageVideos, http.MethodPut, "/api/video/{id:[0-9]+}", video.UpdateHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionViewReports, http.MethodGet, "/api/report/profiles", report.GetUserList(apiRenderer, profileRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionViewReports, http.MethodGet, "/api/report/students", report.GetStudentList(apiRenderer, studentRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionViewReports, http.MethodGet, "/api/report/paymentdetails", report.GetPaymentDetailList(apiRenderer, paymentDetailsRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionViewReports, http.MethodGet, "/api/report/video_completion", report.GetVideoCompletionList(apiRenderer, watchRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionViewReports, http.MethodGet, "/api/admin/stats/profile_cache", profile.IdentityCacheStatsHandler(apiRenderer, identityCache), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageRoles, http.MethodGet, "/api/admin/profiles/{id:[0-9]+}/roles", profile.ListRolesHandler(apiRenderer, profileRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageRoles, http.MethodPost, "/api/admin/profiles/{id:[0-9]+}/roles", profile.GrantRoleHandler(apiRenderer, profileRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageRoles, http.MethodDelete, "/api/admin/profiles/{id:[0-9]+}/roles/{role}", profile.RevokeRoleHandler(apiRenderer, profileRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageAPIKeys, http.MethodGet, "/api/admin/apikeys", apikey.ListHandler(apiRenderer, apiKeyRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageAPIKeys, http.MethodPost, "/api/admin/apikeys", apikey.CreateHandler(apiRenderer, apiKeyRepo, profileRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageAPIKeys, http.MethodPut, "/api/admin/apikeys/{id:[0-9]+}/permissions", apikey.UpdatePermissionsHandler(apiRenderer, apiKeyRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageAPIKeys, http.MethodDelete, "/api/admin/apikeys/{id:[0-9]+}", apikey.RevokeHandler(apiRenderer, apiKeyRepo), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageStorage, http.MethodGet, "/api/admin/storage/orphans", video.OrphansHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageStorage, http.MethodDelete, "/api/admin/storage/orphans", video.DeleteOrphansHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionManageStorage, http.MethodPost, "/api/admin/storage/thumbnails", video.BackfillThumbnailsHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresPermission(cohesioned.PermissionViewReports, http.MethodGet, "/api/admin/audit/impersonations", audit.ListImpersonationsHandler(apiRenderer, auditRepo), mx, authMiddleware)
 
 	//endpoints that only require Authentication
 	requiresAuth(http.MethodPost, "/api/profile/get_or_create", profile.GetOrCreateHandler(apiRenderer, profileRepo), mx, authMiddleware)
@@ -96,27 +183,50 @@ func newServer() *negroni.Negroni {
 	requiresAuth(http.MethodGet, "/api/profile/students", student.ListHandler(apiRenderer, studentRepo), mx, authMiddleware)
 	requiresAuth(http.MethodPost, "/api/profile/students", student.SaveHandler(apiRenderer, studentRepo), mx, authMiddleware)
 	requiresAuth(http.MethodPost, "/api/profile/preferences", profile.SavePreferencesHandler(apiRenderer, profileRepo), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/profile/questions", video.MyQuestionsHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/profile/notifications", notification.ListHandler(apiRenderer, notificationRepo), mx, authMiddleware)
+	requiresAuth(http.MethodPut, "/api/profile/notifications/{id:[0-9]+}/read", notification.MarkReadHandler(apiRenderer, notificationRepo), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/profile/quiz_results", quiz.ResultsHandler(apiRenderer, quizRepo, studentRepo, taxonomyRepo), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/profile/students/{student_id:[0-9]+}/quiz_attempts", quiz.AttemptsHandler(apiRenderer, quizRepo, studentRepo), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/standards", standard.ListHandler(apiRenderer, standardRepo), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/standards/{id:[0-9]+}", standard.GetHandler(apiRenderer, standardRepo), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/teachers", teacher.ListHandler(apiRenderer, teacherRepo), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/teachers/{id:[0-9]+}", teacher.GetHandler(apiRenderer, teacherRepo), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/quizzes", quiz.ListHandler(apiRenderer, quizRepo), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/quizzes/{id:[0-9]+}", quiz.GetHandler(apiRenderer, quizRepo), mx, authMiddleware)
+	requiresAuth(http.MethodPost, "/api/quizzes/{id:[0-9]+}/attempts", quiz.AttemptHandler(apiRenderer, quizRepo, studentRepo), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/videos/for_my_state", video.ForMyStateHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/videos/search", video.SearchHandler(apiRenderer, adminVideoService), mx, authMiddleware)
 	requiresAuth(http.MethodGet, "/api/videos/by_taxonomy/{taxonomy_id:[0-9]+}", video.FindByTaxonomyHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/videos/by_key_term/{term}", video.FindByKeyTermHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/videos/by_standard/{standard}", video.FindByStandardHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/videos/by_teacher/{teacher_id:[0-9]+}", video.FindByTeacherHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/videos/key_terms", video.KeyTermsHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/videos/standards", video.StandardsHandler(apiRenderer, adminVideoService), mx, authMiddleware)
 	requiresAuth(http.MethodGet, "/api/videos/by_grade/{grade}", video.FindByGradeHandler(apiRenderer, adminVideoService), mx, authMiddleware)
 	requiresAuth(http.MethodGet, "/api/videos/by_grade/{grade}/by_subject/{subject}", video.FindBySubjectHandler(apiRenderer, adminVideoService), mx, authMiddleware)
 	requiresAuth(http.MethodGet, "/api/video/{id:[0-9]+}", video.GetByIDHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/video/{id:[0-9]+}/master.m3u8", video.MasterPlaylistHandler(apiRenderer, adminVideoService), mx, authMiddleware)
+	requiresAuth(http.MethodGet, "/api/video/{id:[0-9]+}/hls/{rendition_id:[0-9]+}.m3u8", video.VariantPlaylistHandler(apiRenderer, adminVideoService), mx, authMiddleware)
 
 	allowedOrigins := handlers.AllowedOrigins([]string{"*"})
-	allowedHeaders := handlers.AllowedHeaders([]string{"authorization", "content-type", "content-length"})
+	allowedHeaders := handlers.AllowedHeaders([]string{"authorization", "content-type", "content-length", "x-api-key", "x-impersonate-user"})
 	allowedMethods := handlers.AllowedMethods([]string{"GET", "POST", "PUT", "DELETE", "HEAD"})
 	n.UseHandler(handlers.CORS(allowedOrigins, allowedHeaders, allowedMethods)(mx))
 	return n
 }
 
+//requiresAuth routes are open to any logged in user, but not to api keys, which can only use the endpoints their permissions allow
 func requiresAuth(method string, uri string, handler http.Handler, mx *mux.Router, authMiddleware *negroni.Negroni) {
 	mx.Methods(method).Path(uri).Handler(authMiddleware.With(
+		negroni.HandlerFunc(auth.RequiresUser(apiRenderer)),
 		negroni.Wrap(handler),
 	))
 }
 
-func requiresAdmin(method string, uri string, handler http.Handler, mx *mux.Router, authMiddleware *negroni.Negroni) {
+func requiresPermission(permission cohesioned.Permission, method string, uri string, handler http.Handler, mx *mux.Router, authMiddleware *negroni.Negroni) {
 	mx.Methods(method).Path(uri).Handler(authMiddleware.With(
-		negroni.HandlerFunc(auth.IsAdmin(apiRenderer)),
+		negroni.HandlerFunc(auth.RequiresPermission(apiRenderer, permission)),
 		negroni.Wrap(handler),
 	))
 }
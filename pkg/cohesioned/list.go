@@ -0,0 +1,209 @@
+package cohesioned
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageLimit = 25
+	MaxPageLimit     = 500
+
+	//rangeFromSuffix and rangeToSuffix turn a FilterDateRange field into the ?<field>_from= and ?<field>_to= query params
+	rangeFromSuffix = "_from"
+	rangeToSuffix   = "_to"
+)
+
+//FilterType determines how a list filter's query param is parsed and compared
+type FilterType int
+
+const (
+	//FilterString matches the field exactly, e.g. ?state=FL
+	FilterString FilterType = iota
+	//FilterInt matches the field exactly and must be a whole number, e.g. ?taxonomy_id=4
+	FilterInt
+	//FilterDateRange matches a field between ?<field>_from= (inclusive) and ?<field>_to= (exclusive). Dates are YYYY-MM-DD or RFC 3339
+	FilterDateRange
+)
+
+//ListSpec declares which fields a list endpoint can be sorted and filtered by
+type ListSpec struct {
+	SortFields []string
+	//DefaultSort is used when no ?sort= is given. Prefix with - for descending order
+	DefaultSort string
+	Filters     map[string]FilterType
+}
+
+//Filter narrows a list to rows where Field compares to Value using Op (=, >= or <)
+type Filter struct {
+	Field string
+	Op    string
+	Value interface{}
+}
+
+//ListOptions is a request for a single page of a sorted and filtered list
+type ListOptions struct {
+	Limit   int
+	Offset  int
+	Sort    string
+	Desc    bool
+	Filters []Filter
+}
+
+//NewListOptions returns the first page of a list sorted by spec's DefaultSort
+func NewListOptions(spec ListSpec) *ListOptions {
+	opts := &ListOptions{Limit: DefaultPageLimit}
+	opts.setSort(spec.DefaultSort)
+	return opts
+}
+
+func (o *ListOptions) setSort(sort string) {
+	o.Desc = strings.HasPrefix(sort, "-")
+	o.Sort = strings.TrimPrefix(sort, "-")
+}
+
+//ListOptionsFromRequest parses ?limit=, ?offset=, ?sort= and the filters declared by spec. Invalid params are added to resp as validation errors
+func ListOptionsFromRequest(req *http.Request, spec ListSpec, resp *APIResponse) *ListOptions {
+	opts := NewListOptions(spec)
+	query := req.URL.Query()
+
+	if limit := query.Get("limit"); len(limit) > 0 {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 1 || l > MaxPageLimit {
+			resp.AddValidationError("limit", fmt.Sprintf("limit must be a number between 1 and %d", MaxPageLimit))
+		} else {
+			opts.Limit = l
+		}
+	}
+
+	if offset := query.Get("offset"); len(offset) > 0 {
+		o, err := strconv.Atoi(offset)
+		if err != nil || o < 0 {
+			resp.AddValidationError("offset", "offset must be a positive number")
+		} else {
+			opts.Offset = o
+		}
+	}
+
+	if sort := query.Get("sort"); len(sort) > 0 {
+		if !containsString(spec.SortFields, strings.TrimPrefix(sort, "-")) {
+			resp.AddValidationError("sort", fmt.Sprintf("sort must be one of %v, optionally prefixed with - for descending order", spec.SortFields))
+		} else {
+			opts.setSort(sort)
+		}
+	}
+
+	for field, filterType := range spec.Filters {
+		switch filterType {
+		case FilterString:
+			if value := query.Get(field); len(value) > 0 {
+				opts.Filters = append(opts.Filters, Filter{Field: field, Op: "=", Value: value})
+			}
+		case FilterInt:
+			if value := query.Get(field); len(value) > 0 {
+				i, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					resp.AddValidationError(field, fmt.Sprintf("%s is not a valid number", value))
+					continue
+				}
+
+				opts.Filters = append(opts.Filters, Filter{Field: field, Op: "=", Value: i})
+			}
+		case FilterDateRange:
+			for param, op := range map[string]string{field + rangeFromSuffix: ">=", field + rangeToSuffix: "<"} {
+				value := query.Get(param)
+				if len(value) == 0 {
+					continue
+				}
+
+				t, err := parseDate(value)
+				if err != nil {
+					resp.AddValidationError(param, fmt.Sprintf("%s is not a valid date - use YYYY-MM-DD or RFC 3339", value))
+					continue
+				}
+
+				opts.Filters = append(opts.Filters, Filter{Field: field, Op: op, Value: t})
+			}
+		}
+	}
+
+	return opts
+}
+
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+//Page is the envelope returned by every paginated list endpoint
+type Page struct {
+	*APIResponse
+	Items  interface{} `json:"items"`
+	Total  int64       `json:"total"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+	Next   string      `json:"next,omitempty"`
+	Prev   string      `json:"prev,omitempty"`
+}
+
+//NewPage wraps items, one page of a list of total rows, with links to the next and previous pages of the same request
+func NewPage(req *http.Request, opts *ListOptions, items interface{}, total int64) *Page {
+	page := &Page{
+		APIResponse: &APIResponse{},
+		Items:       items,
+		Total:       total,
+		Limit:       opts.Limit,
+		Offset:      opts.Offset,
+	}
+
+	if next := opts.Offset + opts.Limit; int64(next) < total {
+		page.Next = pageLink(req.URL, next, opts.Limit)
+	}
+
+	if opts.Offset > 0 {
+		prev := opts.Offset - opts.Limit
+		if prev < 0 {
+			prev = 0
+		}
+
+		page.Prev = pageLink(req.URL, prev, opts.Limit)
+	}
+
+	return page
+}
+
+//NewErrorPage is an empty Page for requests that failed validation or errored
+func NewErrorPage(opts *ListOptions, resp *APIResponse) *Page {
+	return &Page{
+		APIResponse: resp,
+		Items:       []interface{}{},
+		Limit:       opts.Limit,
+		Offset:      opts.Offset,
+	}
+}
+
+func pageLink(u *url.URL, offset, limit int) string {
+	query := u.Query()
+	query.Set("offset", strconv.Itoa(offset))
+	query.Set("limit", strconv.Itoa(limit))
+
+	link := url.URL{Path: u.Path, RawQuery: query.Encode()}
+	return link.String()
+}
@@ -0,0 +1,23 @@
+package cohesioned
+
+import "time"
+
+//NotificationKind is what a Notification tells the user about
+type NotificationKind string
+
+const (
+	//NotificationQuestionAnswered tells a parent that a question they asked about a video has been answered
+	NotificationQuestionAnswered NotificationKind = "question_answered"
+)
+
+//Notification is a message for a user, shown in the app until they read it
+type Notification struct {
+	ID      int64            `json:"id"`
+	UserID  int64            `json:"user_id"`
+	Kind    NotificationKind `json:"kind"`
+	Message string           `json:"message"`
+	VideoID int64            `json:"video_id,omitempty"`
+	FAQID   int64            `json:"faq_id,omitempty"`
+	Read    bool             `json:"read"`
+	Created time.Time        `json:"created"`
+}
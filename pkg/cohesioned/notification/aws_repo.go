@@ -0,0 +1,135 @@
+package notification
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/db"
+)
+
+type awsRepo struct {
+	*sql.DB
+}
+
+func NewAwsRepo(db *sql.DB) Repo {
+	return &awsRepo{
+		DB: db,
+	}
+}
+
+var listQuery = db.ListQuery{
+	Select: `select
+		id,
+		user_id,
+		kind,
+		message,
+		video_id,
+		faq_id,
+		is_read,
+		created`,
+	From:  `from notification`,
+	Where: []string{"user_id = ?"},
+	Columns: map[string]string{
+		"id":      "id",
+		"kind":    "kind",
+		"read":    "is_read",
+		"created": "created",
+	},
+}
+
+func (repo *awsRepo) Save(n *cohesioned.Notification) (int64, error) {
+	insertSql := `insert into notification
+	(
+		user_id,
+		kind,
+		message,
+		video_id,
+		faq_id,
+		is_read,
+		created
+	) values (?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := repo.Exec(
+		insertSql,
+		n.UserID,
+		string(n.Kind),
+		n.Message,
+		sql.NullInt64{Int64: n.VideoID, Valid: n.VideoID > 0},
+		sql.NullInt64{Int64: n.FAQID, Valid: n.FAQID > 0},
+		n.Read,
+		n.Created,
+	)
+
+	if err != nil {
+		return 0, fmt.Errorf("Failed to save %s notification for user %d: %v", n.Kind, n.UserID, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get last insert id from result: %v", err)
+	}
+
+	return id, nil
+}
+
+//List pages through the user's notifications
+func (repo *awsRepo) List(userID int64, opts *cohesioned.ListOptions) ([]*cohesioned.Notification, int64, error) {
+	var list []*cohesioned.Notification
+	var total int64
+
+	q := listQuery
+	q.Args = []interface{}{userID}
+
+	selectQuery, args, countQuery, countArgs, err := q.Build(opts)
+	if err != nil {
+		return list, total, err
+	}
+
+	if err := repo.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		return list, total, fmt.Errorf("Failed to count the notifications of user %d: %v", userID, err)
+	}
+
+	rows, err := repo.Query(selectQuery, args...)
+	if err != nil {
+		return list, total, fmt.Errorf("Failed to list the notifications of user %d: %v", userID, err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		n := &cohesioned.Notification{}
+		var videoID, faqID sql.NullInt64
+
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Kind, &n.Message, &videoID, &faqID, &n.Read, &n.Created); err != nil {
+			return list, total, fmt.Errorf("failed to map row to notification: %v", err)
+		}
+
+		n.VideoID = videoID.Int64
+		n.FAQID = faqID.Int64
+		list = append(list, n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return list, total, fmt.Errorf("notification rows had an error: %v", err)
+	}
+
+	return list, total, nil
+}
+
+//MarkRead marks one of the user's notifications as read. It returns false if the user has no notification with that ID
+func (repo *awsRepo) MarkRead(userID, id int64) (bool, error) {
+	var exists bool
+	if err := repo.QueryRow(`select count(*) > 0 from notification where id = ? and user_id = ?`, id, userID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("Failed to find notification %d of user %d: %v", id, userID, err)
+	}
+
+	if !exists {
+		return false, nil
+	}
+
+	if _, err := repo.Exec(`update notification set is_read = 1 where id = ? and user_id = ?`, id, userID); err != nil {
+		return false, fmt.Errorf("Failed to mark notification %d as read: %v", id, err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,85 @@
+package notification
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/gorilla/mux"
+	"github.com/unrolled/render"
+)
+
+//ListHandler pages through the current user's notifications, newest first
+func ListHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &cohesioned.APIResponse{}
+		opts := cohesioned.ListOptionsFromRequest(req, ListSpec, resp)
+		if len(resp.ValidationErrors) > 0 {
+			resp.SetErrMsg("Invalid list parameters")
+			r.JSON(w, http.StatusBadRequest, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		list, total, err := repo.List(currentUser.ID, opts)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred listing notifications %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		if list == nil {
+			list = []*cohesioned.Notification{}
+		}
+
+		r.JSON(w, http.StatusOK, cohesioned.NewPage(req, opts, list, total))
+	}
+}
+
+//MarkReadHandler marks the notification in the path as read. Users can only mark their own notifications
+func MarkReadHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &cohesioned.APIResponse{}
+
+		vars := mux.Vars(req)
+		id, err := strconv.ParseInt(vars["id"], 10, 64)
+		if err != nil {
+			resp.SetErrMsg("%s is not a valid id %v", vars["id"], err)
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		found, err := repo.MarkRead(currentUser.ID, id)
+		if err != nil {
+			resp.SetErrMsg("Failed to mark notification %d as read: %v", id, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		if !found {
+			resp.SetErrMsg("%d is not a valid notification id", id)
+			r.JSON(w, http.StatusNotFound, resp)
+			return
+		}
+
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,53 @@
+package notification_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/notification"
+	"github.com/gorilla/mux"
+)
+
+func TestListHandlerListsTheCurrentUsersNotifications(t *testing.T) {
+	repo := new(fakes.FakeNotificationRepo)
+	repo.ListReturns([]*cohesioned.Notification{{ID: 1, UserID: fakes.FakeProfile().ID, Kind: cohesioned.NotificationQuestionAnswered}}, nil)
+
+	req := fakes.NewRequestWithContext("GET", "/api/profile/notifications?read=0", nil, fakes.FakeProfile())
+	rr := httptest.NewRecorder()
+	notification.ListHandler(fakes.FakeRenderer, repo).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v - %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	if repo.ListedFor != fakes.FakeProfile().ID {
+		t.Errorf("expected the notifications of user %d to be listed but got %d", fakes.FakeProfile().ID, repo.ListedFor)
+	}
+
+	page := &cohesioned.Page{}
+	if err := json.NewDecoder(rr.Body).Decode(page); err != nil {
+		t.Fatalf("failed to unmarshall json response %v", err)
+	}
+
+	if page.Total != 1 {
+		t.Errorf("expected 1 notification but got %d", page.Total)
+	}
+}
+
+func TestMarkReadHandlerReturnsNotFoundForOtherUsersNotifications(t *testing.T) {
+	repo := new(fakes.FakeNotificationRepo)
+	repo.MarkReadReturns(false, nil)
+
+	req := fakes.NewRequestWithContext("PUT", "/api/profile/notifications/4/read", nil, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "4"})
+	rr := httptest.NewRecorder()
+	notification.MarkReadHandler(fakes.FakeRenderer, repo).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,22 @@
+package notification
+
+import (
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+type Repo interface {
+	Save(n *cohesioned.Notification) (int64, error)
+	List(userID int64, opts *cohesioned.ListOptions) ([]*cohesioned.Notification, int64, error)
+	MarkRead(userID, id int64) (bool, error)
+}
+
+//ListSpec is how a user's notifications can be sorted and filtered. read=0 lists only the ones they haven't read
+var ListSpec = cohesioned.ListSpec{
+	SortFields:  []string{"id", "created"},
+	DefaultSort: "-created",
+	Filters: map[string]cohesioned.FilterType{
+		"kind":    cohesioned.FilterString,
+		"read":    cohesioned.FilterInt,
+		"created": cohesioned.FilterDateRange,
+	},
+}
@@ -3,7 +3,6 @@ package cohesioned
 import (
 	"encoding/json"
 	"fmt"
-	"strings"
 	"time"
 )
 
@@ -54,23 +53,55 @@ type Profile struct {
 	State       string      `json:"state"`
 	County      string      `json:"county"`
 	Students    []*Student  `json:"students"`
+	Roles       []Role      `json:"roles"`
+	//APIKey is set when the request was authenticated with an API key rather than a JWT
+	APIKey *APIKey `json:"-"`
+	//ImpersonatedBy is the admin actually making the request when support is acting as this user
+	ImpersonatedBy *Profile `json:"-"`
 }
 
 func (p *Profile) String() string {
 	return fmt.Sprintf("ID: %d Full Name: %s", p.ID, p.FullName)
 }
 
-//IsAdmin returns true if the user has a verified email address in the cohesioned.io domain
+//Actor is the user actually making the request - the impersonating admin if there is one, otherwise the user themselves
+func (p *Profile) Actor() *Profile {
+	if p.ImpersonatedBy != nil {
+		return p.ImpersonatedBy
+	}
+
+	return p
+}
+
+//IsAdmin returns true if the user has been granted the admin Role
 func (p *Profile) IsAdmin() bool {
-	if len(p.Email) == 0 {
-		return false
+	return p.HasRole(RoleAdmin)
+}
+
+//HasRole returns true if the user has been granted the given Role
+func (p *Profile) HasRole(role Role) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
 	}
 
-	if !p.EmailVerified {
-		return false
+	return false
+}
+
+//Can returns true if any of the user's Roles grants the given Permission. Requests authenticated with an APIKey are limited to the key's permissions
+func (p *Profile) Can(permission Permission) bool {
+	if p.APIKey != nil {
+		return p.APIKey.Grants(permission)
+	}
+
+	for _, r := range p.Roles {
+		if r.Grants(permission) {
+			return true
+		}
 	}
 
-	return strings.HasSuffix(p.Email, "@cohesioned.io")
+	return false
 }
 
 func (p *Profile) InTrial() bool {
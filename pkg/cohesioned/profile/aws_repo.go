@@ -3,6 +3,7 @@ package profile
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/cohesion-education/api/pkg/cohesioned"
 	"github.com/cohesion-education/api/pkg/cohesioned/db"
@@ -18,10 +19,8 @@ func NewAwsRepo(db *sql.DB) Repo {
 	}
 }
 
-func (repo *awsRepo) List() ([]*cohesioned.Profile, error) {
-	var list []*cohesioned.Profile
-
-	selectQuery := `select
+var listQuery = db.ListQuery{
+	Select: `select
 			id,
 			created,
 			updated,
@@ -41,32 +40,56 @@ func (repo *awsRepo) List() ([]*cohesioned.Profile, error) {
 			county,
 			onboarded,
 			billing_status,
-			trial_start
-		from
-			user`
+			trial_start`,
+	From: `from user`,
+	Columns: map[string]string{
+		"id":             "id",
+		"created":        "created",
+		"email":          "email",
+		"full_name":      "full_name",
+		"state":          "state",
+		"county":         "county",
+		"billing_status": "billing_status",
+		"onboarded":      "onboarded",
+	},
+}
+
+func (repo *awsRepo) List(opts *cohesioned.ListOptions) ([]*cohesioned.Profile, int64, error) {
+	var list []*cohesioned.Profile
+	var total int64
+
+	selectQuery, args, countQuery, countArgs, err := listQuery.Build(opts)
+	if err != nil {
+		return list, total, err
+	}
+
+	if err := repo.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		return list, total, fmt.Errorf("Failed to count users: %v", err)
+	}
 
-	rows, err := repo.Query(selectQuery)
+	rows, err := repo.Query(selectQuery, args...)
 	if err != nil {
-		return list, fmt.Errorf("Failed to execute query: %v", err)
+		return list, total, fmt.Errorf("Failed to execute query: %v", err)
 	}
 
 	defer rows.Close()
 	for rows.Next() {
 		p, err := repo.mapRowToObject(rows)
 		if err != nil {
-			return list, fmt.Errorf("an unexpected error occurred while processing the result set from the db: %v", err)
+			return list, total, fmt.Errorf("an unexpected error occurred while processing the result set from the db: %v", err)
 		}
 
 		list = append(list, p)
 	}
 
 	if err := rows.Err(); err != nil {
-		return list, fmt.Errorf("rows had an error: %v", err)
+		return list, total, fmt.Errorf("rows had an error: %v", err)
 	}
 
-	return list, nil
+	return list, total, nil
 }
 
+//Save adds the user and grants them the parent role in the same transaction
 func (repo *awsRepo) Save(p *cohesioned.Profile) (int64, error) {
 	sql := `insert into user
 	(
@@ -90,12 +113,12 @@ func (repo *awsRepo) Save(p *cohesioned.Profile) (int64, error) {
 		trial_start
 	) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	stmt, err := repo.Prepare(sql)
+	tx, err := repo.Begin()
 	if err != nil {
-		return 0, fmt.Errorf("Failed to prepare statement %s: %v", sql, err)
+		return 0, fmt.Errorf("Failed to begin transaction: %v", err)
 	}
 
-	result, err := stmt.Exec(
+	result, err := tx.Exec(sql,
 		p.Created,
 		p.Email,
 		p.FullName,
@@ -117,14 +140,25 @@ func (repo *awsRepo) Save(p *cohesioned.Profile) (int64, error) {
 	)
 
 	if err != nil {
+		tx.Rollback()
 		return 0, fmt.Errorf("Failed to insert user: %v", err)
 	}
 
 	profileID, err := result.LastInsertId()
 	if err != nil {
+		tx.Rollback()
 		return 0, fmt.Errorf("Failed to get last insert id from result: %v", err)
 	}
 
+	if _, err := tx.Exec(addRoleSql, profileID, string(cohesioned.RoleParent), p.Created, profileID); err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("Failed to grant role %s to user %d: %v", cohesioned.RoleParent, profileID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("Failed to commit user: %v", err)
+	}
+
 	return profileID, nil
 }
 
@@ -226,9 +260,123 @@ func (repo *awsRepo) FindByEmail(email string) (*cohesioned.Profile, error) {
 		}
 	}
 
+	if p == nil {
+		return nil, nil
+	}
+
+	if p.Roles, err = repo.ListRoles(p.ID); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (repo *awsRepo) Get(id int64) (*cohesioned.Profile, error) {
+	query := `select
+		id,
+		created,
+		updated,
+		email,
+		full_name,
+		first_name,
+		last_name,
+		nickname,
+		profile_pic_url,
+		locale,
+		enabled,
+		verified,
+		beta_program,
+		newsletter,
+		sub,
+		state,
+		county,
+		onboarded,
+		billing_status,
+		trial_start
+	from user
+		where id = ?`
+
+	row := repo.QueryRow(query, id)
+
+	p, err := repo.mapRowToObject(row)
+	if err != nil {
+		return nil, fmt.Errorf("Unexpected error querying for user by id %d: %v", id, err)
+	}
+
+	if p == nil {
+		return nil, nil
+	}
+
+	if p.Roles, err = repo.ListRoles(p.ID); err != nil {
+		return nil, err
+	}
+
 	return p, nil
 }
 
+func (repo *awsRepo) ListRoles(profileID int64) ([]cohesioned.Role, error) {
+	var roles []cohesioned.Role
+
+	query := `select role from user_role where user_id = ? order by role`
+
+	rows, err := repo.Query(query, profileID)
+	if err != nil {
+		return roles, fmt.Errorf("Failed to list roles for user %d: %v", profileID, err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return roles, fmt.Errorf("an unexpected error occurred while processing the user role result set from the db: %v", err)
+		}
+
+		roles = append(roles, cohesioned.Role(role))
+	}
+
+	if err := rows.Err(); err != nil {
+		return roles, fmt.Errorf("user role rows had an error: %v", err)
+	}
+
+	return roles, nil
+}
+
+const addRoleSql = `insert ignore into user_role
+	(
+		user_id,
+		role,
+		created,
+		created_by
+	) values (?, ?, ?, ?)`
+
+func (repo *awsRepo) AddRole(profileID int64, role cohesioned.Role, grantedBy int64) error {
+	stmt, err := repo.Prepare(addRoleSql)
+	if err != nil {
+		return fmt.Errorf("Failed to prepare statement %s: %v", addRoleSql, err)
+	}
+
+	var createdBy interface{}
+	if grantedBy != 0 {
+		createdBy = grantedBy
+	}
+
+	if _, err := stmt.Exec(profileID, string(role), time.Now(), createdBy); err != nil {
+		return fmt.Errorf("Failed to grant role %s to user %d: %v", role, profileID, err)
+	}
+
+	return nil
+}
+
+func (repo *awsRepo) RemoveRole(profileID int64, role cohesioned.Role) error {
+	deleteSql := `delete from user_role where user_id = ? and role = ?`
+
+	if _, err := repo.Exec(deleteSql, profileID, string(role)); err != nil {
+		return fmt.Errorf("Failed to revoke role %s from user %d: %v", role, profileID, err)
+	}
+
+	return nil
+}
+
 func (repo *awsRepo) mapRowToObject(rs db.RowScanner) (*cohesioned.Profile, error) {
 	profile := new(cohesioned.Profile)
 
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
 	"github.com/cohesion-education/api/pkg/cohesioned/config"
 	"github.com/cohesion-education/api/pkg/cohesioned/profile"
 	"github.com/cohesion-education/api/testutils"
@@ -62,6 +63,15 @@ func TestRepoSave(t *testing.T) {
 	if id == 0 {
 		t.Errorf("Profile ID was zero - expected db to generate a profile id")
 	}
+
+	roles, err := repo.ListRoles(id)
+	if err != nil {
+		t.Fatalf("Failed to list roles: %v", err)
+	}
+
+	if len(roles) != 1 || roles[0] != cohesioned.RoleParent {
+		t.Errorf("expected roles [%s] - got %v", cohesioned.RoleParent, roles)
+	}
 }
 
 func TestRepoUpdate(t *testing.T) {
@@ -111,3 +121,33 @@ func TestRepoFindByEmail(t *testing.T) {
 		t.Errorf("Profile returned from repo had an empty id")
 	}
 }
+
+func TestRepoAddListAndRemoveRoles(t *testing.T) {
+	profileID := int64(1)
+
+	if err := repo.AddRole(profileID, cohesioned.RoleContentEditor, profileID); err != nil {
+		t.Fatalf("Failed to add role: %v", err)
+	}
+
+	roles, err := repo.ListRoles(profileID)
+	if err != nil {
+		t.Fatalf("Failed to list roles: %v", err)
+	}
+
+	if len(roles) != 1 || roles[0] != cohesioned.RoleContentEditor {
+		t.Errorf("expected roles [%s] - got %v", cohesioned.RoleContentEditor, roles)
+	}
+
+	if err := repo.RemoveRole(profileID, cohesioned.RoleContentEditor); err != nil {
+		t.Fatalf("Failed to remove role: %v", err)
+	}
+
+	roles, err = repo.ListRoles(profileID)
+	if err != nil {
+		t.Fatalf("Failed to list roles: %v", err)
+	}
+
+	if len(roles) != 0 {
+		t.Errorf("expected no roles - got %v", roles)
+	}
+}
@@ -0,0 +1,188 @@
+package profile
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+//Identity is the part of a Profile the auth middleware needs on every request
+type Identity struct {
+	ID    int64
+	Roles []cohesioned.Role
+}
+
+//CacheStats reports how effective an IdentityCache has been
+type CacheStats struct {
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Entries int    `json:"entries"`
+}
+
+type identityEntry struct {
+	identity Identity
+	expires  time.Time
+}
+
+//IdentityCache is a concurrency-safe, TTL-bounded cache of profile identities keyed by email and/or sub
+type IdentityCache struct {
+	ttl       time.Duration
+	mu        sync.Mutex
+	byKey     map[string]identityEntry
+	keysByID  map[int64]map[string]bool
+	lastSweep time.Time
+	hits      uint64
+	misses    uint64
+}
+
+func NewIdentityCache(ttl time.Duration) *IdentityCache {
+	return &IdentityCache{
+		ttl:       ttl,
+		byKey:     make(map[string]identityEntry),
+		keysByID:  make(map[int64]map[string]bool),
+		lastSweep: time.Now(),
+	}
+}
+
+//EmailKey is the cache key for a profile's email address, or "" if the email is empty
+func EmailKey(email string) string {
+	if len(email) == 0 {
+		return ""
+	}
+
+	return "email:" + email
+}
+
+//SubKey is the cache key for a profile's sub claim, or "" if the sub is empty
+func SubKey(sub string) string {
+	if len(sub) == 0 {
+		return ""
+	}
+
+	return "sub:" + sub
+}
+
+//Get returns the identity cached under the first of the given keys that hasn't expired. Each call counts as a single hit or miss
+func (c *IdentityCache) Get(keys ...string) (Identity, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for _, key := range keys {
+		entry, ok := c.byKey[key]
+		if !ok {
+			continue
+		}
+
+		if now.After(entry.expires) {
+			c.remove(entry.identity.ID)
+			continue
+		}
+
+		c.hits++
+		return entry.identity, true
+	}
+
+	c.misses++
+	return Identity{}, false
+}
+
+//Put caches the identity under each of the given keys. Empty keys are ignored
+func (c *IdentityCache) Put(identity Identity, keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(c.lastSweep) > c.ttl {
+		c.sweep(now)
+	}
+
+	entry := identityEntry{identity: identity, expires: now.Add(c.ttl)}
+	for _, key := range keys {
+		if len(key) == 0 {
+			continue
+		}
+
+		//a key that moved to another profile, e.g. an email that changed hands, stops being evicted with the old one
+		if previous, ok := c.byKey[key]; ok && previous.identity.ID != identity.ID {
+			delete(c.keysByID[previous.identity.ID], key)
+		}
+
+		if c.keysByID[identity.ID] == nil {
+			c.keysByID[identity.ID] = make(map[string]bool)
+		}
+
+		c.byKey[key] = entry
+		c.keysByID[identity.ID][key] = true
+	}
+}
+
+//Invalidate evicts every key cached for the given profile ID
+func (c *IdentityCache) Invalidate(profileID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.remove(profileID)
+}
+
+func (c *IdentityCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return CacheStats{
+		Hits:    c.hits,
+		Misses:  c.misses,
+		Entries: len(c.byKey),
+	}
+}
+
+func (c *IdentityCache) remove(profileID int64) {
+	for key := range c.keysByID[profileID] {
+		delete(c.byKey, key)
+	}
+
+	delete(c.keysByID, profileID)
+}
+
+func (c *IdentityCache) sweep(now time.Time) {
+	for _, entry := range c.byKey {
+		if now.After(entry.expires) {
+			c.remove(entry.identity.ID)
+		}
+	}
+
+	c.lastSweep = now
+}
+
+//cachingRepo keeps an IdentityCache consistent with writes that change a profile's email or roles
+type cachingRepo struct {
+	Repo
+	cache *IdentityCache
+}
+
+//NewCachingRepo wraps repo so that updates and role changes invalidate the given cache
+func NewCachingRepo(repo Repo, cache *IdentityCache) Repo {
+	return &cachingRepo{
+		Repo:  repo,
+		cache: cache,
+	}
+}
+
+func (repo *cachingRepo) Update(p *cohesioned.Profile) error {
+	err := repo.Repo.Update(p)
+	repo.cache.Invalidate(p.ID)
+	return err
+}
+
+func (repo *cachingRepo) AddRole(profileID int64, role cohesioned.Role, grantedBy int64) error {
+	err := repo.Repo.AddRole(profileID, role, grantedBy)
+	repo.cache.Invalidate(profileID)
+	return err
+}
+
+func (repo *cachingRepo) RemoveRole(profileID int64, role cohesioned.Role) error {
+	err := repo.Repo.RemoveRole(profileID, role)
+	repo.cache.Invalidate(profileID)
+	return err
+}
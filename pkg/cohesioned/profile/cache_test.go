@@ -0,0 +1,112 @@
+package profile_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/profile"
+)
+
+func TestIdentityCacheHitsAndMisses(t *testing.T) {
+	cache := profile.NewIdentityCache(time.Minute)
+
+	if _, ok := cache.Get(profile.EmailKey("hello@domain.com")); ok {
+		t.Error("expected a miss on an empty cache")
+	}
+
+	identity := profile.Identity{ID: 1, Roles: []cohesioned.Role{cohesioned.RoleParent}}
+	cache.Put(identity, profile.EmailKey("hello@domain.com"), profile.SubKey("abc|123"))
+
+	cached, ok := cache.Get(profile.SubKey("abc|123"), profile.EmailKey("hello@domain.com"))
+	if !ok {
+		t.Fatal("expected a hit after Put")
+	}
+
+	if cached.ID != identity.ID {
+		t.Errorf("expected profile id %d - got %d", identity.ID, cached.ID)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("expected 1 hit and 1 miss - got %d hits and %d misses", stats.Hits, stats.Misses)
+	}
+
+	if stats.Entries != 2 {
+		t.Errorf("expected 2 entries - got %d", stats.Entries)
+	}
+}
+
+func TestIdentityCacheInvalidateKeepsKeysMovedToAnotherProfile(t *testing.T) {
+	cache := profile.NewIdentityCache(time.Minute)
+
+	for i := 0; i < 3; i++ {
+		cache.Put(profile.Identity{ID: 1}, profile.EmailKey("hello@domain.com"), profile.SubKey("abc|123"))
+	}
+
+	cache.Put(profile.Identity{ID: 2}, profile.EmailKey("hello@domain.com"))
+	cache.Invalidate(1)
+
+	if _, ok := cache.Get(profile.SubKey("abc|123")); ok {
+		t.Error("expected the keys of the invalidated profile to be evicted")
+	}
+
+	cached, ok := cache.Get(profile.EmailKey("hello@domain.com"))
+	if !ok || cached.ID != 2 {
+		t.Errorf("expected the email to still be cached for profile 2 but got %v, %v", cached, ok)
+	}
+}
+
+func TestIdentityCacheExpiresEntries(t *testing.T) {
+	cache := profile.NewIdentityCache(time.Millisecond)
+	cache.Put(profile.Identity{ID: 1}, profile.EmailKey("hello@domain.com"))
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := cache.Get(profile.EmailKey("hello@domain.com")); ok {
+		t.Error("expected the entry to have expired")
+	}
+
+	if entries := cache.Stats().Entries; entries != 0 {
+		t.Errorf("expected expired entries to be evicted - got %d", entries)
+	}
+}
+
+func TestCachingRepoUpdateInvalidatesIdentity(t *testing.T) {
+	p := fakes.FakeProfile()
+	cache := profile.NewIdentityCache(time.Minute)
+	cache.Put(profile.Identity{ID: p.ID}, profile.EmailKey(p.Email), profile.SubKey(p.Sub))
+
+	fakeRepo := new(fakes.FakeProfileRepo)
+	fakeRepo.UpdateReturns(nil)
+	repo := profile.NewCachingRepo(fakeRepo, cache)
+
+	p.Email = "new@domain.com"
+	if err := repo.Update(p); err != nil {
+		t.Fatalf("Failed to update profile: %v", err)
+	}
+
+	if _, ok := cache.Get(profile.EmailKey("hello@domain.com")); ok {
+		t.Error("expected the old email to be evicted after an update")
+	}
+
+	if _, ok := cache.Get(profile.SubKey(p.Sub)); ok {
+		t.Error("expected the sub to be evicted after an update")
+	}
+}
+
+func TestCachingRepoRoleChangesInvalidateIdentity(t *testing.T) {
+	p := fakes.FakeProfile()
+	cache := profile.NewIdentityCache(time.Minute)
+	cache.Put(profile.Identity{ID: p.ID}, profile.EmailKey(p.Email))
+
+	repo := profile.NewCachingRepo(new(fakes.FakeProfileRepo), cache)
+	if err := repo.AddRole(p.ID, cohesioned.RoleReviewer, 2); err != nil {
+		t.Fatalf("Failed to add role: %v", err)
+	}
+
+	if _, ok := cache.Get(profile.EmailKey(p.Email)); ok {
+		t.Error("expected the identity to be evicted after a role was granted")
+	}
+}
@@ -4,12 +4,28 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/gorilla/mux"
 	"github.com/unrolled/render"
 )
 
+type RolesResponse struct {
+	*cohesioned.APIResponse
+	ProfileID int64             `json:"profile_id"`
+	Roles     []cohesioned.Role `json:"roles"`
+}
+
+func NewRolesResponse(profileID int64) *RolesResponse {
+	return &RolesResponse{
+		APIResponse: &cohesioned.APIResponse{},
+		ProfileID:   profileID,
+		Roles:       []cohesioned.Role{},
+	}
+}
+
 func GetOrCreateHandler(r *render.Render, repo Repo) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		defer req.Body.Close()
@@ -51,6 +67,7 @@ func GetOrCreateHandler(r *render.Render, repo Repo) http.HandlerFunc {
 			return
 		}
 
+		incoming.Roles = []cohesioned.Role{cohesioned.RoleParent}
 		r.JSON(w, http.StatusOK, incoming)
 	}
 }
@@ -93,7 +110,7 @@ func SaveHandler(r *render.Render, repo Repo) http.HandlerFunc {
 
 		incoming.Created = time.Now()
 
-		id, err := repo.Save(existing)
+		id, err := repo.Save(incoming)
 		incoming.ID = id
 		if err != nil {
 			apiResponse := cohesioned.NewAPIErrorResponse("Failed to save User %v", err)
@@ -102,6 +119,7 @@ func SaveHandler(r *render.Render, repo Repo) http.HandlerFunc {
 			return
 		}
 
+		incoming.Roles = []cohesioned.Role{cohesioned.RoleParent}
 		r.JSON(w, http.StatusOK, incoming)
 	}
 }
@@ -227,3 +245,159 @@ func GetCurrentUserHandler(r *render.Render, repo Repo) http.HandlerFunc {
 		r.JSON(w, http.StatusOK, p)
 	}
 }
+
+func ListRolesHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		p, resp, status := findProfileFromPath(req, repo)
+		if p == nil {
+			r.JSON(w, status, resp)
+			return
+		}
+
+		if p.Roles != nil {
+			resp.Roles = p.Roles
+		}
+
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func GrantRoleHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		p, resp, status := findProfileFromPath(req, repo)
+		if p == nil {
+			r.JSON(w, status, resp)
+			return
+		}
+
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		defer req.Body.Close()
+		decoder := json.NewDecoder(req.Body)
+
+		incoming := struct {
+			Role cohesioned.Role `json:"role"`
+		}{}
+
+		if err := decoder.Decode(&incoming); err != nil {
+			resp.SetErrMsg("failed to unmarshall json %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		if !incoming.Role.IsValid() {
+			resp.SetErrMsg("%s is not a valid role", incoming.Role)
+			resp.AddValidationError("role", fmt.Sprintf("role must be one of %v", cohesioned.Roles()))
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		if err := repo.AddRole(p.ID, incoming.Role, currentUser.ID); err != nil {
+			resp.SetErrMsg("Failed to grant role %s: %v", incoming.Role, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		fmt.Printf("user %d granted role %s to user %d\n", currentUser.ID, incoming.Role, p.ID)
+		writeRoles(w, r, repo, resp)
+	}
+}
+
+func RevokeRoleHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		p, resp, status := findProfileFromPath(req, repo)
+		if p == nil {
+			r.JSON(w, status, resp)
+			return
+		}
+
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		role := cohesioned.Role(mux.Vars(req)["role"])
+		if !role.IsValid() {
+			resp.SetErrMsg("%s is not a valid role", role)
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		if role == cohesioned.RoleAdmin && p.ID == currentUser.ID {
+			resp.SetErrMsg("You cannot revoke your own admin role")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		if err := repo.RemoveRole(p.ID, role); err != nil {
+			resp.SetErrMsg("Failed to revoke role %s: %v", role, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		fmt.Printf("user %d revoked role %s from user %d\n", currentUser.ID, role, p.ID)
+		writeRoles(w, r, repo, resp)
+	}
+}
+
+//findProfileFromPath looks up the profile identified by the {id} path param. When the profile can't be found, the returned RolesResponse and status describe why
+func findProfileFromPath(req *http.Request, repo Repo) (*cohesioned.Profile, *RolesResponse, int) {
+	vars := mux.Vars(req)
+	resp := NewRolesResponse(0)
+
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		resp.SetErrMsg("%s is not a valid id %v", vars["id"], err)
+		return nil, resp, http.StatusBadRequest
+	}
+
+	resp.ProfileID = id
+
+	p, err := repo.Get(id)
+	if err != nil {
+		resp.SetErrMsg("An unexpected error occurred when trying to find user %d: %v", id, err)
+		fmt.Println(resp.ErrMsg)
+		return nil, resp, http.StatusInternalServerError
+	}
+
+	if p == nil {
+		resp.SetErrMsg("%d is not a valid user id", id)
+		return nil, resp, http.StatusNotFound
+	}
+
+	return p, resp, http.StatusOK
+}
+
+func writeRoles(w http.ResponseWriter, r *render.Render, repo Repo, resp *RolesResponse) {
+	roles, err := repo.ListRoles(resp.ProfileID)
+	if err != nil {
+		resp.SetErrMsg("Failed to list roles: %v", err)
+		fmt.Println(resp.ErrMsg)
+		r.JSON(w, http.StatusInternalServerError, resp)
+		return
+	}
+
+	if roles != nil {
+		resp.Roles = roles
+	}
+
+	r.JSON(w, http.StatusOK, resp)
+}
+
+func IdentityCacheStatsHandler(r *render.Render, cache *IdentityCache) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		r.JSON(w, http.StatusOK, cache.Stats())
+	}
+}
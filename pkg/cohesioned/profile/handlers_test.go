@@ -3,6 +3,7 @@ package profile_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"github.com/cohesion-education/api/fakes"
 	"github.com/cohesion-education/api/pkg/cohesioned"
 	"github.com/cohesion-education/api/pkg/cohesioned/profile"
+	"github.com/gorilla/mux"
 )
 
 func TestSave(t *testing.T) {
@@ -87,6 +89,30 @@ func TestSave(t *testing.T) {
 	if result.Students[0].School != "Key West Elementary" {
 		t.Error("Students[0].School did not update")
 	}
+
+	if len(result.Roles) != 1 || result.Roles[0] != cohesioned.RoleParent {
+		t.Errorf("expected the response to have the parent role but got %v", result.Roles)
+	}
+}
+
+func TestSaveWhenTheProfileCanNotBeSaved(t *testing.T) {
+	req, err := http.NewRequest("POST", "/api/profile", strings.NewReader(`{"email":"john@doe.com","name":"John Doe"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := new(fakes.FakeProfileRepo)
+	repo.SaveReturns(0, errors.New("db is down"))
+
+	handler := profile.SaveHandler(fakes.FakeRenderer, repo)
+	rr := httptest.NewRecorder()
+
+	ctx := context.WithValue(req.Context(), cohesioned.CurrentUserKey, fakes.FakeProfile())
+	handler.ServeHTTP(rr, req.WithContext(ctx))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusInternalServerError)
+	}
 }
 
 func TestSavePreferences(t *testing.T) {
@@ -168,3 +194,57 @@ func TestGetCurrentUserHandler(t *testing.T) {
 		t.Error("FirstName was not set correctly on the result")
 	}
 }
+
+func TestGrantRoleHandler(t *testing.T) {
+	target := fakes.FakeProfile()
+	target.ID = 7
+
+	req := fakes.NewRequestWithContext("POST", "/api/admin/profiles/7/roles", strings.NewReader(`{"role":"content_editor"}`), fakes.FakeAdmin())
+
+	repo := new(fakes.FakeProfileRepo)
+	repo.GetReturns(target, nil)
+	repo.ListRolesReturns([]cohesioned.Role{cohesioned.RoleContentEditor, cohesioned.RoleParent}, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/admin/profiles/{id:[0-9]+}/roles", profile.GrantRoleHandler(fakes.FakeRenderer, repo))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	expectedStatus := http.StatusOK
+	if status := rr.Code; status != expectedStatus {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, expectedStatus)
+	}
+
+	result := &profile.RolesResponse{}
+	decoder := json.NewDecoder(rr.Body)
+	if err := decoder.Decode(&result); err != nil {
+		t.Fatalf("failed to unmarshall json response %v", err)
+	}
+
+	if result.ProfileID != target.ID {
+		t.Errorf("expected profile id %d - got %d", target.ID, result.ProfileID)
+	}
+
+	if len(result.Roles) != 2 || result.Roles[0] != cohesioned.RoleContentEditor {
+		t.Errorf("expected roles [content_editor parent] - got %v", result.Roles)
+	}
+}
+
+func TestGrantRoleHandlerWithUnknownRoleReturnsBadRequest(t *testing.T) {
+	target := fakes.FakeProfile()
+
+	req := fakes.NewRequestWithContext("POST", "/api/admin/profiles/1/roles", strings.NewReader(`{"role":"superuser"}`), fakes.FakeAdmin())
+
+	repo := new(fakes.FakeProfileRepo)
+	repo.GetReturns(target, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/admin/profiles/{id:[0-9]+}/roles", profile.GrantRoleHandler(fakes.FakeRenderer, repo))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	expectedStatus := http.StatusBadRequest
+	if status := rr.Code; status != expectedStatus {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, expectedStatus)
+	}
+}
@@ -5,8 +5,25 @@ import (
 )
 
 type Repo interface {
+	Get(id int64) (*cohesioned.Profile, error)
 	FindByEmail(email string) (*cohesioned.Profile, error)
+	//Save adds the profile and grants it the parent role
 	Save(p *cohesioned.Profile) (int64, error)
 	Update(p *cohesioned.Profile) error
-	List() ([]*cohesioned.Profile, error)
+	List(opts *cohesioned.ListOptions) ([]*cohesioned.Profile, int64, error)
+	ListRoles(profileID int64) ([]cohesioned.Role, error)
+	AddRole(profileID int64, role cohesioned.Role, grantedBy int64) error
+	RemoveRole(profileID int64, role cohesioned.Role) error
+}
+
+//ListSpec is how the profile list can be sorted and filtered
+var ListSpec = cohesioned.ListSpec{
+	SortFields:  []string{"id", "created", "email", "full_name", "state", "billing_status"},
+	DefaultSort: "-created",
+	Filters: map[string]cohesioned.FilterType{
+		"billing_status": cohesioned.FilterString,
+		"state":          cohesioned.FilterString,
+		"county":         cohesioned.FilterString,
+		"created":        cohesioned.FilterDateRange,
+	},
 }
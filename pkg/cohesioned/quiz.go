@@ -0,0 +1,311 @@
+package cohesioned
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+//MaxQuizQuestions is the most questions a quiz can have
+const MaxQuizQuestions = 100
+
+//QuestionKind is how a quiz question is answered
+type QuestionKind string
+
+const (
+	//MultipleChoice questions are answered by picking one of their choices
+	MultipleChoice QuestionKind = "multiple_choice"
+	//ShortAnswer questions are answered with a word or phrase, which is compared to the accepted answers ignoring case and spacing
+	ShortAnswer QuestionKind = "short_answer"
+)
+
+//QuestionKinds returns every QuestionKind a quiz question can be
+func QuestionKinds() []QuestionKind {
+	return []QuestionKind{MultipleChoice, ShortAnswer}
+}
+
+//Quiz is a practice quiz that checks a student understood a video, or a topic in the taxonomy. It is tied to either VideoID or TaxonomyID, not both
+type Quiz struct {
+	Validatable
+	ID          int64           `json:"id"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	VideoID     int64           `json:"video_id,omitempty"`
+	TaxonomyID  int64           `json:"taxonomy_id,omitempty"`
+	Questions   []*QuizQuestion `json:"questions"`
+	Created     time.Time       `json:"created"`
+	CreatedBy   int64           `json:"created_by"`
+	Updated     time.Time       `json:"updated"`
+	UpdatedBy   int64           `json:"updated_by"`
+}
+
+//QuizQuestion is a question in a quiz, asked in the order it appears in Quiz.Questions
+type QuizQuestion struct {
+	ID     int64        `json:"id"`
+	Kind   QuestionKind `json:"kind"`
+	Prompt string       `json:"prompt"`
+	//Choices are the options of a multiple choice question
+	Choices []string `json:"choices,omitempty"`
+	//Answers are the accepted answers. Each answer to a multiple choice question must be one of its choices. They are hidden from students by HideAnswers
+	Answers []string `json:"answers,omitempty"`
+	//Points are awarded for a correct answer. Defaults to 1
+	Points int64 `json:"points"`
+}
+
+//Validate checks that the quiz has a title, is tied to either a video or a taxonomy node and that each of its questions can be answered
+func (q *Quiz) Validate() bool {
+	q.Title = strings.TrimSpace(q.Title)
+	q.Description = strings.TrimSpace(q.Description)
+
+	if len(q.Title) == 0 {
+		q.AddValidationError("title", "title is required")
+	}
+
+	if (q.VideoID > 0) == (q.TaxonomyID > 0) {
+		q.AddValidationError("video_id", "a quiz must be tied to either a video_id or a taxonomy_id")
+	}
+
+	if len(q.Questions) == 0 {
+		q.AddValidationError("questions", "a quiz needs at least one question")
+	}
+
+	if len(q.Questions) > MaxQuizQuestions {
+		q.AddValidationError("questions", fmt.Sprintf("a quiz can't have more than %d questions", MaxQuizQuestions))
+	}
+
+	for i, question := range q.Questions {
+		if question == nil {
+			q.AddValidationError(fmt.Sprintf("questions[%d]", i), "question is required")
+			continue
+		}
+
+		for _, msg := range question.validate() {
+			q.AddValidationError(fmt.Sprintf("questions[%d]", i), msg)
+		}
+	}
+
+	return len(q.ValidationErrors) == 0
+}
+
+//validate returns what is wrong with the question, trimming its prompt, choices and answers and defaulting its points
+func (question *QuizQuestion) validate() []string {
+	var problems []string
+
+	question.Prompt = strings.TrimSpace(question.Prompt)
+	question.Choices = trimAll(question.Choices)
+	question.Answers = trimAll(question.Answers)
+	if question.Points == 0 {
+		question.Points = 1
+	}
+
+	if len(question.Prompt) == 0 {
+		problems = append(problems, "prompt is required")
+	}
+
+	if question.Points < 0 {
+		problems = append(problems, "points can't be negative")
+	}
+
+	if len(question.Answers) == 0 {
+		problems = append(problems, "at least one accepted answer is required")
+	}
+
+	//choices and answers are stored one per line
+	for _, v := range append(append([]string{}, question.Choices...), question.Answers...) {
+		if strings.ContainsAny(v, "\r\n") {
+			problems = append(problems, "choices and answers can't span more than one line")
+			break
+		}
+	}
+
+	switch question.Kind {
+	case MultipleChoice:
+		if len(question.Choices) < 2 {
+			problems = append(problems, "a multiple choice question needs at least two choices")
+		}
+
+		seen := make(map[string]bool)
+		for _, choice := range question.Choices {
+			if seen[normalizeAnswer(choice)] {
+				problems = append(problems, fmt.Sprintf("%s is repeated in the choices", choice))
+			}
+
+			seen[normalizeAnswer(choice)] = true
+		}
+
+		for _, answer := range question.Answers {
+			if !seen[normalizeAnswer(answer)] {
+				problems = append(problems, fmt.Sprintf("the answer %s is not one of the choices", answer))
+			}
+		}
+	case ShortAnswer:
+		if len(question.Choices) > 0 {
+			problems = append(problems, "a short answer question can't have choices")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("%s is not one of %v", question.Kind, QuestionKinds()))
+	}
+
+	return problems
+}
+
+//MaxScore is the score of an attempt that answers every question correctly
+func (q *Quiz) MaxScore() int64 {
+	var max int64
+	for _, question := range q.Questions {
+		max += question.Points
+	}
+
+	return max
+}
+
+//HideAnswers removes the accepted answers so the quiz can be shown to the students taking it
+func (q *Quiz) HideAnswers() {
+	for _, question := range q.Questions {
+		question.Answers = nil
+	}
+}
+
+//QuizAttempt is a student's answers to a quiz and how they scored. UserID is the parent who submitted it
+type QuizAttempt struct {
+	Validatable
+	ID        int64         `json:"id"`
+	QuizID    int64         `json:"quiz_id"`
+	QuizTitle string        `json:"quiz_title,omitempty"`
+	StudentID int64         `json:"student_id"`
+	UserID    int64         `json:"user_id"`
+	Answers   []*QuizAnswer `json:"answers"`
+	Score     int64         `json:"score"`
+	MaxScore  int64         `json:"max_score"`
+	//Percent is Score as a percentage of MaxScore
+	Percent float64   `json:"percent"`
+	Created time.Time `json:"created"`
+}
+
+//QuizAnswer is the answer given to one question of a quiz. The question's prompt is kept so the attempt still reads correctly after the quiz is edited
+type QuizAnswer struct {
+	QuestionID int64  `json:"question_id"`
+	Prompt     string `json:"prompt,omitempty"`
+	Answer     string `json:"answer"`
+	Correct    bool   `json:"correct"`
+	Points     int64  `json:"points"`
+	MaxPoints  int64  `json:"max_points"`
+}
+
+//Mark scores the attempt's answers against the quiz. Every question of the quiz is recorded in order, with questions that weren't answered marked wrong.
+//It returns false, with ValidationErrors, if the attempt has no student or answers a question that isn't in the quiz or answers one twice
+func (a *QuizAttempt) Mark(quiz *Quiz) bool {
+	if a.StudentID <= 0 {
+		a.AddValidationError("student_id", "student_id is required")
+	}
+
+	given := make(map[int64]string)
+	for _, answer := range a.Answers {
+		if answer == nil {
+			continue
+		}
+
+		if _, ok := given[answer.QuestionID]; ok {
+			a.AddValidationError("answers", fmt.Sprintf("question %d is answered more than once", answer.QuestionID))
+		}
+
+		given[answer.QuestionID] = strings.TrimSpace(answer.Answer)
+	}
+
+	scored := make([]*QuizAnswer, 0, len(quiz.Questions))
+	for _, question := range quiz.Questions {
+		answer := &QuizAnswer{
+			QuestionID: question.ID,
+			Prompt:     question.Prompt,
+			Answer:     given[question.ID],
+			MaxPoints:  question.Points,
+		}
+
+		answer.Correct = question.accepts(answer.Answer)
+		if answer.Correct {
+			answer.Points = question.Points
+		}
+
+		delete(given, question.ID)
+		scored = append(scored, answer)
+	}
+
+	for id := range given {
+		a.AddValidationError("answers", fmt.Sprintf("question %d is not in quiz %d", id, quiz.ID))
+	}
+
+	if len(a.ValidationErrors) > 0 {
+		return false
+	}
+
+	a.QuizID = quiz.ID
+	a.QuizTitle = quiz.Title
+	a.Answers = scored
+	a.Score = 0
+	for _, answer := range scored {
+		a.Score += answer.Points
+	}
+
+	a.MaxScore = quiz.MaxScore()
+	a.Percent = Percent(a.Score, a.MaxScore)
+	return true
+}
+
+//accepts returns true if answer is one of the question's accepted answers
+func (question *QuizQuestion) accepts(answer string) bool {
+	if len(answer) == 0 {
+		return false
+	}
+
+	for _, accepted := range question.Answers {
+		if normalizeAnswer(answer) == normalizeAnswer(accepted) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//QuizResult is how a student has done in the quizzes of a subject. Score and MaxScore are the totals of every attempt
+type QuizResult struct {
+	SubjectID   int64     `json:"subject_id"`
+	Subject     string    `json:"subject"`
+	Quizzes     int64     `json:"quizzes"`
+	Attempts    int64     `json:"attempts"`
+	Score       int64     `json:"score"`
+	MaxScore    int64     `json:"max_score"`
+	Percent     float64   `json:"percent"`
+	LastAttempt time.Time `json:"last_attempt"`
+}
+
+//StudentQuizResults are a student's quiz results in each subject
+type StudentQuizResults struct {
+	Student  *Student      `json:"student"`
+	Subjects []*QuizResult `json:"subjects"`
+}
+
+//Percent is score as a percentage of max, rounded down to one decimal place
+func Percent(score, max int64) float64 {
+	if max <= 0 {
+		return 0
+	}
+
+	return float64(score*1000/max) / 10
+}
+
+//normalizeAnswer lower cases the answer and collapses its whitespace, so answers are compared ignoring case and spacing
+func normalizeAnswer(answer string) string {
+	return strings.ToLower(strings.Join(strings.Fields(answer), " "))
+}
+
+//trimAll trims each of the values, dropping any that are blank
+func trimAll(values []string) []string {
+	var trimmed []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			trimmed = append(trimmed, v)
+		}
+	}
+
+	return trimmed
+}
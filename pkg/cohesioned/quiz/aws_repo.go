@@ -0,0 +1,593 @@
+package quiz
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/db"
+)
+
+type awsRepo struct {
+	*sql.DB
+}
+
+func NewAwsRepo(db *sql.DB) Repo {
+	return &awsRepo{
+		DB: db,
+	}
+}
+
+const selectQuery = `select
+		id,
+		title,
+		description,
+		video_id,
+		taxonomy_id,
+		created,
+		created_by,
+		updated,
+		updated_by
+	from quiz`
+
+var listQuery = db.ListQuery{
+	Select: `select
+		id,
+		title,
+		description,
+		video_id,
+		taxonomy_id,
+		created,
+		created_by,
+		updated,
+		updated_by`,
+	From: `from quiz`,
+	Columns: map[string]string{
+		"id":          "id",
+		"title":       "title",
+		"video_id":    "video_id",
+		"taxonomy_id": "taxonomy_id",
+		"created":     "created",
+	},
+}
+
+var attemptQuery = db.ListQuery{
+	Select: `select
+		a.id,
+		a.quiz_id,
+		q.title,
+		a.student_id,
+		a.user_id,
+		a.score,
+		a.max_score,
+		a.created`,
+	From:  `from quiz_attempt a, quiz q`,
+	Where: []string{`a.quiz_id = q.id`, `a.student_id = ?`},
+	Columns: map[string]string{
+		"id":      "a.id",
+		"quiz_id": "a.quiz_id",
+		"score":   "a.score",
+		"percent": "a.score / a.max_score",
+		"created": "a.created",
+	},
+}
+
+func (repo *awsRepo) List(opts *cohesioned.ListOptions) ([]*cohesioned.Quiz, int64, error) {
+	var list []*cohesioned.Quiz
+	var total int64
+
+	selectQuery, args, countQuery, countArgs, err := listQuery.Build(opts)
+	if err != nil {
+		return list, total, err
+	}
+
+	if err := repo.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		return list, total, fmt.Errorf("Failed to count quizzes: %v", err)
+	}
+
+	if list, err = repo.query(selectQuery, args...); err != nil {
+		return list, total, err
+	}
+
+	return list, total, repo.loadQuestions(list...)
+}
+
+//Get finds the quiz and its questions, or returns nil if there is no quiz with that ID
+func (repo *awsRepo) Get(id int64) (*cohesioned.Quiz, error) {
+	list, err := repo.query(selectQuery+` where id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("Unexpected error querying for quiz by id %d: %v", id, err)
+	}
+
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	return list[0], repo.loadQuestions(list[0])
+}
+
+func (repo *awsRepo) query(query string, args ...interface{}) ([]*cohesioned.Quiz, error) {
+	var list []*cohesioned.Quiz
+
+	rows, err := repo.Query(query, args...)
+	if err != nil {
+		return list, fmt.Errorf("Failed to execute query: %v", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		q, err := repo.mapRowToObject(rows)
+		if err != nil {
+			return list, fmt.Errorf("an unexpected error occurred while processing the result set from the db: %v", err)
+		}
+
+		list = append(list, q)
+	}
+
+	if err := rows.Err(); err != nil {
+		return list, fmt.Errorf("rows had an error: %v", err)
+	}
+
+	return list, nil
+}
+
+//loadQuestions sets the questions of each of the quizzes, in the order they are asked
+func (repo *awsRepo) loadQuestions(quizzes ...*cohesioned.Quiz) error {
+	if len(quizzes) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*cohesioned.Quiz)
+	args := make([]interface{}, len(quizzes))
+	for i, q := range quizzes {
+		byID[q.ID] = q
+		args[i] = q.ID
+		q.Questions = []*cohesioned.QuizQuestion{}
+	}
+
+	selectQuery := fmt.Sprintf(`select
+		quiz_id,
+		id,
+		kind,
+		prompt,
+		choices,
+		answers,
+		points
+	from
+		quiz_question
+	where
+		quiz_id in (%s)
+	order by quiz_id, position`, db.Placeholders(len(quizzes)))
+
+	rows, err := repo.Query(selectQuery, args...)
+	if err != nil {
+		return fmt.Errorf("Failed to load the questions of %d quizzes: %v", len(quizzes), err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var quizID int64
+		var kind, answers string
+		var choices sql.NullString
+		question := new(cohesioned.QuizQuestion)
+		if err := rows.Scan(&quizID, &question.ID, &kind, &question.Prompt, &choices, &answers, &question.Points); err != nil {
+			return fmt.Errorf("an unexpected error occurred while processing the result set from the db: %v", err)
+		}
+
+		question.Kind = cohesioned.QuestionKind(kind)
+		question.Choices = splitLines(choices.String)
+		question.Answers = splitLines(answers)
+		byID[quizID].Questions = append(byID[quizID].Questions, question)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows had an error: %v", err)
+	}
+
+	return nil
+}
+
+func (repo *awsRepo) Save(q *cohesioned.Quiz) (int64, error) {
+	insertSql := `insert into quiz
+	(
+		title,
+		description,
+		video_id,
+		taxonomy_id,
+		created,
+		created_by
+	) values (?, ?, ?, ?, ?, ?)`
+
+	tx, err := repo.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to begin transaction: %v", err)
+	}
+
+	result, err := tx.Exec(insertSql, q.Title, q.Description, nullID(q.VideoID), nullID(q.TaxonomyID), q.Created, q.CreatedBy)
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("Failed to insert quiz %s: %v", q.Title, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("Failed to get last insert id from result: %v", err)
+	}
+
+	if err := setQuestions(tx, id, q.Questions); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("Failed to commit quiz: %v", err)
+	}
+
+	return id, nil
+}
+
+//Update saves the quiz and its questions. Questions with an ID are updated, those without are added and any that were left out are deleted
+func (repo *awsRepo) Update(q *cohesioned.Quiz) error {
+	updateSql := `update quiz set
+		title = ?,
+		description = ?,
+		video_id = ?,
+		taxonomy_id = ?,
+		updated = ?,
+		updated_by = ?
+	where
+		id = ?`
+
+	tx, err := repo.Begin()
+	if err != nil {
+		return fmt.Errorf("Failed to begin transaction: %v", err)
+	}
+
+	result, err := tx.Exec(updateSql, q.Title, q.Description, nullID(q.VideoID), nullID(q.TaxonomyID), q.Updated, q.UpdatedBy, q.ID)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("Failed to update quiz %d: %v", q.ID, err)
+	}
+
+	rowsEffected, err := result.RowsAffected()
+	if err != nil || rowsEffected == 0 {
+		tx.Rollback()
+		return fmt.Errorf("Failed to update quiz %d: %v", q.ID, err)
+	}
+
+	if err := setQuestions(tx, q.ID, q.Questions); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("Failed to commit quiz %d: %v", q.ID, err)
+	}
+
+	return nil
+}
+
+//setQuestions replaces the questions of the quiz, setting the ID of any that are added
+func setQuestions(tx *sql.Tx, quizID int64, questions []*cohesioned.QuizQuestion) error {
+	keep := []interface{}{quizID}
+	for _, question := range questions {
+		if question.ID > 0 {
+			keep = append(keep, question.ID)
+		}
+	}
+
+	deleteSql := `delete from quiz_question where quiz_id = ?`
+	if len(keep) > 1 {
+		deleteSql = fmt.Sprintf(`%s and id not in (%s)`, deleteSql, db.Placeholders(len(keep)-1))
+	}
+
+	if _, err := tx.Exec(deleteSql, keep...); err != nil {
+		return fmt.Errorf("Failed to clear the questions of quiz %d: %v", quizID, err)
+	}
+
+	for position, question := range questions {
+		choices := sql.NullString{String: strings.Join(question.Choices, "\n"), Valid: len(question.Choices) > 0}
+		answers := strings.Join(question.Answers, "\n")
+
+		if question.ID > 0 {
+			updateSql := `update quiz_question set
+				position = ?,
+				kind = ?,
+				prompt = ?,
+				choices = ?,
+				answers = ?,
+				points = ?
+			where
+				id = ? and quiz_id = ?`
+
+			if _, err := tx.Exec(updateSql, position, string(question.Kind), question.Prompt, choices, answers, question.Points, question.ID, quizID); err != nil {
+				return fmt.Errorf("Failed to update question %d of quiz %d: %v", question.ID, quizID, err)
+			}
+
+			continue
+		}
+
+		insertSql := `insert into quiz_question
+		(
+			quiz_id,
+			position,
+			kind,
+			prompt,
+			choices,
+			answers,
+			points
+		) values (?, ?, ?, ?, ?, ?, ?)`
+
+		result, err := tx.Exec(insertSql, quizID, position, string(question.Kind), question.Prompt, choices, answers, question.Points)
+		if err != nil {
+			return fmt.Errorf("Failed to add a question to quiz %d: %v", quizID, err)
+		}
+
+		if question.ID, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("Failed to get last insert id from result: %v", err)
+		}
+	}
+
+	return nil
+}
+
+//Delete deletes the quiz, along with its questions and every attempt at it
+func (repo *awsRepo) Delete(id int64) error {
+	result, err := repo.Exec(`delete from quiz where id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("Failed to delete quiz with id %d: %v", id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to get number of rows affected from result: %v", err)
+	}
+
+	if rowsAffected != 1 {
+		return fmt.Errorf("Failed to delete quiz with id %d (rows affected != 1)", id)
+	}
+
+	return nil
+}
+
+//SaveAttempt records a scored attempt and each of its answers
+func (repo *awsRepo) SaveAttempt(a *cohesioned.QuizAttempt) (int64, error) {
+	insertSql := `insert into quiz_attempt
+	(
+		quiz_id,
+		student_id,
+		user_id,
+		score,
+		max_score,
+		created
+	) values (?, ?, ?, ?, ?, ?)`
+
+	tx, err := repo.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to begin transaction: %v", err)
+	}
+
+	result, err := tx.Exec(insertSql, a.QuizID, a.StudentID, a.UserID, a.Score, a.MaxScore, a.Created)
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("Failed to save the attempt of student %d at quiz %d: %v", a.StudentID, a.QuizID, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("Failed to get last insert id from result: %v", err)
+	}
+
+	answerSql := `insert into quiz_attempt_answer
+	(
+		attempt_id,
+		position,
+		question_id,
+		prompt,
+		answer,
+		correct,
+		points,
+		max_points
+	) values (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	for position, answer := range a.Answers {
+		if _, err := tx.Exec(answerSql, id, position, answer.QuestionID, answer.Prompt, answer.Answer, answer.Correct, answer.Points, answer.MaxPoints); err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("Failed to save the answer to question %d of attempt %d: %v", answer.QuestionID, id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("Failed to commit attempt: %v", err)
+	}
+
+	return id, nil
+}
+
+//ListAttempts pages through the student's attempts at quizzes, with their answers
+func (repo *awsRepo) ListAttempts(studentID int64, opts *cohesioned.ListOptions) ([]*cohesioned.QuizAttempt, int64, error) {
+	var list []*cohesioned.QuizAttempt
+	var total int64
+
+	q := attemptQuery
+	q.Args = []interface{}{studentID}
+	selectQuery, args, countQuery, countArgs, err := q.Build(opts)
+	if err != nil {
+		return list, total, err
+	}
+
+	if err := repo.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		return list, total, fmt.Errorf("Failed to count the quiz attempts of student %d: %v", studentID, err)
+	}
+
+	rows, err := repo.Query(selectQuery, args...)
+	if err != nil {
+		return list, total, fmt.Errorf("Failed to list the quiz attempts of student %d: %v", studentID, err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		a := new(cohesioned.QuizAttempt)
+		if err := rows.Scan(&a.ID, &a.QuizID, &a.QuizTitle, &a.StudentID, &a.UserID, &a.Score, &a.MaxScore, &a.Created); err != nil {
+			return list, total, fmt.Errorf("an unexpected error occurred while processing the result set from the db: %v", err)
+		}
+
+		a.Percent = cohesioned.Percent(a.Score, a.MaxScore)
+		list = append(list, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return list, total, fmt.Errorf("rows had an error: %v", err)
+	}
+
+	return list, total, repo.loadAnswers(list...)
+}
+
+//loadAnswers sets the answers of each of the attempts, in the order the questions were asked
+func (repo *awsRepo) loadAnswers(attempts ...*cohesioned.QuizAttempt) error {
+	if len(attempts) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*cohesioned.QuizAttempt)
+	args := make([]interface{}, len(attempts))
+	for i, a := range attempts {
+		byID[a.ID] = a
+		args[i] = a.ID
+		a.Answers = []*cohesioned.QuizAnswer{}
+	}
+
+	selectQuery := fmt.Sprintf(`select
+		attempt_id,
+		question_id,
+		prompt,
+		answer,
+		correct,
+		points,
+		max_points
+	from
+		quiz_attempt_answer
+	where
+		attempt_id in (%s)
+	order by attempt_id, position`, db.Placeholders(len(attempts)))
+
+	rows, err := repo.Query(selectQuery, args...)
+	if err != nil {
+		return fmt.Errorf("Failed to load the answers of %d quiz attempts: %v", len(attempts), err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var attemptID int64
+		var given sql.NullString
+		answer := new(cohesioned.QuizAnswer)
+		if err := rows.Scan(&attemptID, &answer.QuestionID, &answer.Prompt, &given, &answer.Correct, &answer.Points, &answer.MaxPoints); err != nil {
+			return fmt.Errorf("an unexpected error occurred while processing the result set from the db: %v", err)
+		}
+
+		answer.Answer = given.String
+		byID[attemptID].Answers = append(byID[attemptID].Answers, answer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows had an error: %v", err)
+	}
+
+	return nil
+}
+
+//ListResults totals the student's attempts by the taxonomy node of each quiz, which is the node of its video for quizzes tied to a video.
+//SubjectID is that node and Subject is left empty, as the nodes are rolled up into their subjects by the caller
+func (repo *awsRepo) ListResults(studentID int64) ([]*cohesioned.QuizResult, error) {
+	var list []*cohesioned.QuizResult
+
+	selectQuery := `select
+		coalesce(q.taxonomy_id, v.taxonomy_id),
+		count(distinct a.quiz_id),
+		count(*),
+		sum(a.score),
+		sum(a.max_score),
+		max(a.created)
+	from
+		quiz_attempt a
+		join quiz q on a.quiz_id = q.id
+		left join video v on q.video_id = v.id
+	where
+		a.student_id = ?
+	group by
+		coalesce(q.taxonomy_id, v.taxonomy_id)`
+
+	rows, err := repo.Query(selectQuery, studentID)
+	if err != nil {
+		return list, fmt.Errorf("Failed to total the quiz results of student %d: %v", studentID, err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var taxonomyID sql.NullInt64
+		var lastAttempt db.NullTime
+		result := new(cohesioned.QuizResult)
+		if err := rows.Scan(&taxonomyID, &result.Quizzes, &result.Attempts, &result.Score, &result.MaxScore, &lastAttempt); err != nil {
+			return list, fmt.Errorf("an unexpected error occurred while processing the result set from the db: %v", err)
+		}
+
+		result.SubjectID = taxonomyID.Int64
+		result.LastAttempt = lastAttempt.Time
+		result.Percent = cohesioned.Percent(result.Score, result.MaxScore)
+		list = append(list, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return list, fmt.Errorf("rows had an error: %v", err)
+	}
+
+	return list, nil
+}
+
+func (repo *awsRepo) mapRowToObject(rs db.RowScanner) (*cohesioned.Quiz, error) {
+	q := new(cohesioned.Quiz)
+
+	var description sql.NullString
+	var videoID, taxonomyID, updatedBy sql.NullInt64
+	var updated db.NullTime
+
+	err := rs.Scan(
+		&q.ID,
+		&q.Title,
+		&description,
+		&videoID,
+		&taxonomyID,
+		&q.Created,
+		&q.CreatedBy,
+		&updated,
+		&updatedBy,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	q.Description = description.String
+	q.VideoID = videoID.Int64
+	q.TaxonomyID = taxonomyID.Int64
+	q.Updated = updated.Time
+	q.UpdatedBy = updatedBy.Int64
+
+	return q, nil
+}
+
+//nullID stores an unset ID as null so the foreign key isn't checked
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id > 0}
+}
+
+func splitLines(s string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+
+	return strings.Split(s, "\n")
+}
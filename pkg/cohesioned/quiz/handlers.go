@@ -0,0 +1,510 @@
+package quiz
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/student"
+	"github.com/cohesion-education/api/pkg/cohesioned/taxonomy"
+	"github.com/cohesion-education/api/pkg/cohesioned/video"
+	"github.com/gorilla/mux"
+	"github.com/unrolled/render"
+)
+
+type QuizResponse struct {
+	*cohesioned.APIResponse
+	Quiz *cohesioned.Quiz `json:"quiz,omitempty"`
+}
+
+func NewQuizResponse() *QuizResponse {
+	return &QuizResponse{
+		APIResponse: &cohesioned.APIResponse{},
+	}
+}
+
+type AttemptResponse struct {
+	*cohesioned.APIResponse
+	Attempt *cohesioned.QuizAttempt `json:"attempt,omitempty"`
+}
+
+func NewAttemptResponse() *AttemptResponse {
+	return &AttemptResponse{
+		APIResponse: &cohesioned.APIResponse{},
+	}
+}
+
+type ResultsResponse struct {
+	*cohesioned.APIResponse
+	List []*cohesioned.StudentQuizResults `json:"students"`
+}
+
+func NewResultsResponse() *ResultsResponse {
+	return &ResultsResponse{
+		APIResponse: &cohesioned.APIResponse{},
+	}
+}
+
+//ListHandler pages through the quizzes, e.g. those of a video with ?video_id=. The accepted answers are only included for users who can manage quizzes
+func ListHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &cohesioned.APIResponse{}
+		opts := cohesioned.ListOptionsFromRequest(req, ListSpec, resp)
+		if len(resp.ValidationErrors) > 0 {
+			resp.SetErrMsg("Invalid list parameters")
+			r.JSON(w, http.StatusBadRequest, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		list, total, err := repo.List(opts)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred listing quizzes %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		if list == nil {
+			list = []*cohesioned.Quiz{}
+		}
+
+		if !currentUser.Can(cohesioned.PermissionManageQuizzes) {
+			for _, q := range list {
+				q.HideAnswers()
+			}
+		}
+
+		r.JSON(w, http.StatusOK, cohesioned.NewPage(req, opts, list, total))
+	}
+}
+
+//GetHandler returns the quiz and its questions. The accepted answers are only included for users who can manage quizzes
+func GetHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		q, resp, status := findQuizFromPath(req, repo)
+		if q == nil {
+			r.JSON(w, status, resp)
+			return
+		}
+
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		if !currentUser.Can(cohesioned.PermissionManageQuizzes) {
+			q.HideAnswers()
+		}
+
+		resp.Quiz = q
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func AddHandler(r *render.Render, repo Repo, videoRepo video.Repo, taxonomyRepo taxonomy.Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := NewQuizResponse()
+
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		defer req.Body.Close()
+		q := &cohesioned.Quiz{}
+		if err := json.NewDecoder(req.Body).Decode(q); err != nil {
+			resp.SetErrMsg("failed to unmarshall json %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		for _, question := range q.Questions {
+			if question != nil {
+				question.ID = 0
+			}
+		}
+
+		if q.Validate() {
+			if err := validateTarget(q, videoRepo, taxonomyRepo); err != nil {
+				resp.SetErrMsg("Failed to validate quiz: %v", err)
+				fmt.Println(resp.ErrMsg)
+				r.JSON(w, http.StatusInternalServerError, resp)
+				return
+			}
+		}
+
+		if len(q.ValidationErrors) > 0 {
+			resp.Quiz = q
+			resp.SetErrMsg("Hmmm... you seem to be missing some required fields")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		q.Created = time.Now()
+		q.CreatedBy = currentUser.ID
+
+		id, err := repo.Save(q)
+		if err != nil {
+			resp.SetErrMsg("Failed to save quiz: %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		q.ID = id
+		resp.Quiz = q
+		r.JSON(w, http.StatusCreated, resp)
+	}
+}
+
+//UpdateHandler replaces the quiz and its questions. Questions keep their ID, so past attempts still refer to them, while questions without an ID are added
+//and any that are left out are deleted
+func UpdateHandler(r *render.Render, repo Repo, videoRepo video.Repo, taxonomyRepo taxonomy.Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		existing, resp, status := findQuizFromPath(req, repo)
+		if existing == nil {
+			r.JSON(w, status, resp)
+			return
+		}
+
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		defer req.Body.Close()
+		q := &cohesioned.Quiz{}
+		if err := json.NewDecoder(req.Body).Decode(q); err != nil {
+			resp.SetErrMsg("failed to unmarshall json %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		q.ID = existing.ID
+		q.Created = existing.Created
+		q.CreatedBy = existing.CreatedBy
+
+		if q.Validate() {
+			validateQuestionIDs(q, existing)
+			if err := validateTarget(q, videoRepo, taxonomyRepo); err != nil {
+				resp.SetErrMsg("Failed to validate quiz %d: %v", q.ID, err)
+				fmt.Println(resp.ErrMsg)
+				r.JSON(w, http.StatusInternalServerError, resp)
+				return
+			}
+		}
+
+		if len(q.ValidationErrors) > 0 {
+			resp.Quiz = q
+			resp.SetErrMsg("Hmmm... you seem to be missing some required fields")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		q.Updated = time.Now()
+		q.UpdatedBy = currentUser.ID
+
+		if err := repo.Update(q); err != nil {
+			resp.SetErrMsg("Failed to update quiz %d: %v", q.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		resp.Quiz = q
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//DeleteHandler deletes the quiz along with every attempt at it
+func DeleteHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		q, resp, status := findQuizFromPath(req, repo)
+		if q == nil {
+			r.JSON(w, status, resp)
+			return
+		}
+
+		if err := repo.Delete(q.ID); err != nil {
+			resp.SetErrMsg("Failed to delete quiz %d: %v", q.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//AttemptHandler scores the answers one of the current user's students gave to the quiz and records the attempt.
+//The scored attempt says which answers were correct, but not what the accepted answers are
+func AttemptHandler(r *render.Render, repo Repo, studentRepo student.Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		q, quizResp, status := findQuizFromPath(req, repo)
+		if q == nil {
+			r.JSON(w, status, quizResp)
+			return
+		}
+
+		resp := NewAttemptResponse()
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		defer req.Body.Close()
+		attempt := &cohesioned.QuizAttempt{}
+		if err := json.NewDecoder(req.Body).Decode(attempt); err != nil {
+			resp.SetErrMsg("failed to unmarshall json %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		if attempt.StudentID > 0 {
+			s, err := findStudent(studentRepo, currentUser.ID, attempt.StudentID)
+			if err != nil {
+				resp.SetErrMsg("An unexpected error occurred when trying to find student %d: %v", attempt.StudentID, err)
+				fmt.Println(resp.ErrMsg)
+				r.JSON(w, http.StatusInternalServerError, resp)
+				return
+			}
+
+			if s == nil {
+				attempt.AddValidationError("student_id", fmt.Sprintf("student %d is not one of your students", attempt.StudentID))
+			}
+		}
+
+		if !attempt.Mark(q) {
+			resp.Attempt = attempt
+			resp.SetErrMsg("Hmmm... some of your answers can't be scored")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		attempt.ID = 0
+		attempt.UserID = currentUser.ID
+		attempt.Created = time.Now()
+
+		if attempt.ID, err = repo.SaveAttempt(attempt); err != nil {
+			resp.SetErrMsg("Failed to save the attempt at quiz %d: %v", q.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		resp.Attempt = attempt
+		r.JSON(w, http.StatusCreated, resp)
+	}
+}
+
+//AttemptsHandler pages through the quiz attempts of one of the current user's students, newest first
+func AttemptsHandler(r *render.Render, repo Repo, studentRepo student.Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &cohesioned.APIResponse{}
+		opts := cohesioned.ListOptionsFromRequest(req, AttemptListSpec, resp)
+		if len(resp.ValidationErrors) > 0 {
+			resp.SetErrMsg("Invalid list parameters")
+			r.JSON(w, http.StatusBadRequest, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		vars := mux.Vars(req)
+		studentID, err := strconv.ParseInt(vars["student_id"], 10, 64)
+		if err != nil {
+			resp.SetErrMsg("%s is not a valid student id %v", vars["student_id"], err)
+			r.JSON(w, http.StatusBadRequest, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		s, err := findStudent(studentRepo, currentUser.ID, studentID)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to find student %d: %v", studentID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		if s == nil {
+			resp.SetErrMsg("%d is not one of your students", studentID)
+			r.JSON(w, http.StatusNotFound, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		list, total, err := repo.ListAttempts(s.ID, opts)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred listing the quiz attempts of student %d %v", s.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		if list == nil {
+			list = []*cohesioned.QuizAttempt{}
+		}
+
+		r.JSON(w, http.StatusOK, cohesioned.NewPage(req, opts, list, total))
+	}
+}
+
+//ResultsHandler returns how each of the current user's students has done in the quizzes of each subject
+func ResultsHandler(r *render.Render, repo Repo, studentRepo student.Repo, taxonomyRepo taxonomy.Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := NewResultsResponse()
+
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		students, err := studentRepo.FindByUserID(currentUser.ID)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred listing your students %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		subjects := newSubjectCache(taxonomyRepo)
+		resp.List = []*cohesioned.StudentQuizResults{}
+		for _, s := range students {
+			byNode, err := repo.ListResults(s.ID)
+			if err != nil {
+				resp.SetErrMsg("An unexpected error occurred totalling the quiz results of student %d %v", s.ID, err)
+				fmt.Println(resp.ErrMsg)
+				r.JSON(w, http.StatusInternalServerError, resp)
+				return
+			}
+
+			results, err := subjects.rollUp(byNode)
+			if err != nil {
+				resp.SetErrMsg("An unexpected error occurred finding the subjects of student %d's quizzes %v", s.ID, err)
+				fmt.Println(resp.ErrMsg)
+				r.JSON(w, http.StatusInternalServerError, resp)
+				return
+			}
+
+			resp.List = append(resp.List, &cohesioned.StudentQuizResults{Student: s, Subjects: results})
+		}
+
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func findQuizFromPath(req *http.Request, repo Repo) (*cohesioned.Quiz, *QuizResponse, int) {
+	vars := mux.Vars(req)
+	resp := NewQuizResponse()
+
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		resp.SetErrMsg("%s is not a valid id %v", vars["id"], err)
+		return nil, resp, http.StatusBadRequest
+	}
+
+	q, err := repo.Get(id)
+	if err != nil {
+		resp.SetErrMsg("An unexpected error occurred when trying to find quiz %d: %v", id, err)
+		fmt.Println(resp.ErrMsg)
+		return nil, resp, http.StatusInternalServerError
+	}
+
+	if q == nil {
+		resp.SetErrMsg("%d is not a valid quiz id", id)
+		return nil, resp, http.StatusNotFound
+	}
+
+	return q, resp, http.StatusOK
+}
+
+//findStudent returns the parent's student with the given ID, or nil if they don't have one
+func findStudent(studentRepo student.Repo, parentID, studentID int64) (*cohesioned.Student, error) {
+	students, err := studentRepo.FindByUserID(parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range students {
+		if s.ID == studentID {
+			return s, nil
+		}
+	}
+
+	return nil, nil
+}
+
+//validateTarget adds a validation error if the video or taxonomy node the quiz is tied to doesn't exist
+func validateTarget(q *cohesioned.Quiz, videoRepo video.Repo, taxonomyRepo taxonomy.Repo) error {
+	if q.VideoID > 0 {
+		videos, err := videoRepo.FindByIDs([]int64{q.VideoID})
+		if err != nil {
+			return err
+		}
+
+		if len(videos) == 0 {
+			q.AddValidationError("video_id", fmt.Sprintf("%d is not a valid video id", q.VideoID))
+		}
+	}
+
+	if q.TaxonomyID > 0 {
+		t, err := taxonomyRepo.Get(q.TaxonomyID)
+		if err != nil {
+			return err
+		}
+
+		if t == nil {
+			q.AddValidationError("taxonomy_id", fmt.Sprintf("%d is not a valid taxonomy id", q.TaxonomyID))
+		}
+	}
+
+	return nil
+}
+
+//validateQuestionIDs adds a validation error for each question that has an ID that isn't one of the existing quiz's questions
+func validateQuestionIDs(q, existing *cohesioned.Quiz) {
+	ids := make(map[int64]bool)
+	for _, question := range existing.Questions {
+		ids[question.ID] = true
+	}
+
+	for i, question := range q.Questions {
+		if question.ID > 0 && !ids[question.ID] {
+			q.AddValidationError(fmt.Sprintf("questions[%d]", i), fmt.Sprintf("question %d is not in quiz %d", question.ID, existing.ID))
+		}
+	}
+}
@@ -0,0 +1,383 @@
+package quiz_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/quiz"
+	"github.com/gorilla/mux"
+)
+
+func fakeQuiz() *cohesioned.Quiz {
+	return &cohesioned.Quiz{
+		ID:         5,
+		Title:      "Fractions",
+		TaxonomyID: 3,
+		Questions: []*cohesioned.QuizQuestion{
+			{ID: 11, Kind: cohesioned.MultipleChoice, Prompt: "What is 1/2 + 1/4?", Choices: []string{"2/6", "3/4", "1/8"}, Answers: []string{"3/4"}, Points: 2},
+			{ID: 12, Kind: cohesioned.ShortAnswer, Prompt: "What is the bottom number of a fraction called?", Answers: []string{"denominator"}, Points: 1},
+			{ID: 13, Kind: cohesioned.ShortAnswer, Prompt: "What is the top number of a fraction called?", Answers: []string{"numerator"}, Points: 1},
+		},
+	}
+}
+
+func validationFields(errs []*cohesioned.ValidationError) map[string]bool {
+	fields := make(map[string]bool)
+	for _, e := range errs {
+		fields[e.Field] = true
+	}
+
+	return fields
+}
+
+func TestAddHandler(t *testing.T) {
+	repo := new(fakes.FakeQuizRepo)
+	repo.SaveReturns(5, nil)
+
+	taxonomyRepo := new(fakes.FakeTaxonomyRepo)
+	taxonomyRepo.GetReturns(fakes.FakeTaxonomy(), nil)
+
+	body := bytes.NewBufferString(`{"title":" Fractions ","taxonomy_id":1,"questions":[
+		{"id":99,"kind":"multiple_choice","prompt":"What is 1/2 + 1/4?","choices":["2/6"," 3/4 ","1/8"],"answers":["3/4"]},
+		{"kind":"short_answer","prompt":"What is the bottom number of a fraction called?","answers":["denominator"],"points":3}
+	]}`)
+	req := fakes.NewRequestWithContext("POST", "/api/quizzes", body, fakes.FakeContentEditor())
+	rr := httptest.NewRecorder()
+	quiz.AddHandler(fakes.FakeRenderer, repo, new(fakes.FakeVideoRepo), taxonomyRepo).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v - %s", status, http.StatusCreated, rr.Body.String())
+	}
+
+	if len(repo.Saved) != 1 {
+		t.Fatalf("expected the quiz to be saved but got %v", repo.Saved)
+	}
+
+	saved := repo.Saved[0]
+	if saved.Title != "Fractions" || saved.CreatedBy != fakes.FakeContentEditor().ID {
+		t.Errorf("expected the trimmed quiz to be created by the current user but got %v", saved)
+	}
+
+	if saved.Questions[0].ID != 0 || saved.Questions[0].Points != 1 || saved.Questions[0].Choices[1] != "3/4" || saved.Questions[1].Points != 3 {
+		t.Errorf("expected new questions with their points defaulted and choices trimmed but got %v %v", saved.Questions[0], saved.Questions[1])
+	}
+}
+
+func TestAddHandlerRejectsInvalidQuizzes(t *testing.T) {
+	repo := new(fakes.FakeQuizRepo)
+
+	body := bytes.NewBufferString(`{"title":"Fractions","questions":[
+		{"kind":"multiple_choice","prompt":"What is 1/2 + 1/4?","choices":["2/6","1/8"],"answers":["3/4"]},
+		{"kind":"essay","prompt":"Explain fractions","answers":["anything"]}
+	]}`)
+	req := fakes.NewRequestWithContext("POST", "/api/quizzes", body, fakes.FakeContentEditor())
+	rr := httptest.NewRecorder()
+	quiz.AddHandler(fakes.FakeRenderer, repo, new(fakes.FakeVideoRepo), new(fakes.FakeTaxonomyRepo)).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	resp := quiz.NewQuizResponse()
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("failed to unmarshall json response %v", err)
+	}
+
+	fields := validationFields(resp.Quiz.ValidationErrors)
+	for _, field := range []string{"video_id", "questions[0]", "questions[1]"} {
+		if !fields[field] {
+			t.Errorf("expected a validation error for %s but got %v", field, resp.Quiz.ValidationErrors)
+		}
+	}
+
+	if len(repo.Saved) != 0 {
+		t.Errorf("an invalid quiz should not be saved")
+	}
+}
+
+func TestAddHandlerRejectsUnknownVideos(t *testing.T) {
+	repo := new(fakes.FakeQuizRepo)
+
+	body := bytes.NewBufferString(`{"title":"Fractions","video_id":42,"questions":[{"kind":"short_answer","prompt":"1/2 + 1/2?","answers":["1"]}]}`)
+	req := fakes.NewRequestWithContext("POST", "/api/quizzes", body, fakes.FakeContentEditor())
+	rr := httptest.NewRecorder()
+	quiz.AddHandler(fakes.FakeRenderer, repo, new(fakes.FakeVideoRepo), new(fakes.FakeTaxonomyRepo)).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	resp := quiz.NewQuizResponse()
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("failed to unmarshall json response %v", err)
+	}
+
+	if !validationFields(resp.Quiz.ValidationErrors)["video_id"] {
+		t.Errorf("expected a validation error for the video but got %v", resp.Quiz.ValidationErrors)
+	}
+}
+
+func TestUpdateHandlerRejectsQuestionsFromOtherQuizzes(t *testing.T) {
+	repo := new(fakes.FakeQuizRepo)
+	repo.GetReturns(fakeQuiz(), nil)
+
+	taxonomyRepo := new(fakes.FakeTaxonomyRepo)
+	taxonomyRepo.GetReturns(fakes.FakeTaxonomy(), nil)
+
+	body := bytes.NewBufferString(`{"title":"Fractions","taxonomy_id":3,"questions":[
+		{"id":11,"kind":"short_answer","prompt":"What is 1/2 + 1/4?","answers":["3/4"]},
+		{"id":77,"kind":"short_answer","prompt":"What is 1/2 + 1/2?","answers":["1"]}
+	]}`)
+	req := fakes.NewRequestWithContext("PUT", "/api/quizzes/5", body, fakes.FakeContentEditor())
+	req = mux.SetURLVars(req, map[string]string{"id": "5"})
+	rr := httptest.NewRecorder()
+	quiz.UpdateHandler(fakes.FakeRenderer, repo, new(fakes.FakeVideoRepo), taxonomyRepo).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Fatalf("handler returned wrong status code: got %v want %v - %s", status, http.StatusBadRequest, rr.Body.String())
+	}
+
+	resp := quiz.NewQuizResponse()
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("failed to unmarshall json response %v", err)
+	}
+
+	fields := validationFields(resp.Quiz.ValidationErrors)
+	if !fields["questions[1]"] || fields["questions[0]"] {
+		t.Errorf("expected only the question from another quiz to be rejected but got %v", resp.Quiz.ValidationErrors)
+	}
+
+	if len(repo.Updated) != 0 {
+		t.Errorf("an invalid quiz should not be updated")
+	}
+}
+
+func TestGetHandlerHidesAnswersFromParents(t *testing.T) {
+	for _, tc := range []struct {
+		user        *cohesioned.Profile
+		seesAnswers bool
+	}{
+		{fakes.FakeProfile(), false},
+		{fakes.FakeContentEditor(), true},
+	} {
+		repo := new(fakes.FakeQuizRepo)
+		repo.GetReturns(fakeQuiz(), nil)
+
+		req := fakes.NewRequestWithContext("GET", "/api/quizzes/5", nil, tc.user)
+		req = mux.SetURLVars(req, map[string]string{"id": "5"})
+		rr := httptest.NewRecorder()
+		quiz.GetHandler(fakes.FakeRenderer, repo).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		resp := quiz.NewQuizResponse()
+		if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+			t.Fatalf("failed to unmarshall json response %v", err)
+		}
+
+		if seesAnswers := len(resp.Quiz.Questions[0].Answers) > 0; seesAnswers != tc.seesAnswers {
+			t.Errorf("expected %s to see the answers: %v but got %v", tc.user.FullName, tc.seesAnswers, resp.Quiz.Questions[0].Answers)
+		}
+
+		if len(resp.Quiz.Questions[0].Choices) != 3 {
+			t.Errorf("expected the choices to be shown but got %v", resp.Quiz.Questions[0].Choices)
+		}
+	}
+}
+
+func TestGetHandlerReturnsNotFound(t *testing.T) {
+	req := fakes.NewRequestWithContext("GET", "/api/quizzes/5", nil, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "5"})
+	rr := httptest.NewRecorder()
+	quiz.GetHandler(fakes.FakeRenderer, new(fakes.FakeQuizRepo)).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+}
+
+func TestAttemptHandlerScoresTheAnswers(t *testing.T) {
+	repo := new(fakes.FakeQuizRepo)
+	repo.GetReturns(fakeQuiz(), nil)
+	repo.SaveReturns(8, nil)
+
+	studentRepo := new(fakes.FakeStudentRepo)
+	studentRepo.FindByUserIDReturns([]*cohesioned.Student{fakes.FakeStudent()}, nil)
+
+	body := bytes.NewBufferString(`{"student_id":2,"answers":[
+		{"question_id":11,"answer":"3/4"},
+		{"question_id":12,"answer":"  The   Denominator "},
+		{"question_id":13,"answer":" NUMERATOR"}
+	]}`)
+	req := fakes.NewRequestWithContext("POST", "/api/quizzes/5/attempts", body, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "5"})
+	rr := httptest.NewRecorder()
+	quiz.AttemptHandler(fakes.FakeRenderer, repo, studentRepo).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v - %s", status, http.StatusCreated, rr.Body.String())
+	}
+
+	resp := quiz.NewAttemptResponse()
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("failed to unmarshall json response %v", err)
+	}
+
+	attempt := resp.Attempt
+	if attempt.ID != 8 || attempt.QuizID != 5 || attempt.UserID != fakes.FakeProfile().ID || attempt.StudentID != 2 {
+		t.Errorf("expected attempt 8 at quiz 5 by student 2 of the current user but got %v", attempt)
+	}
+
+	if attempt.Score != 3 || attempt.MaxScore != 4 || attempt.Percent != 75 {
+		t.Errorf("expected a score of 3/4 (75%%) but got %d/%d (%v%%)", attempt.Score, attempt.MaxScore, attempt.Percent)
+	}
+
+	if !attempt.Answers[0].Correct || attempt.Answers[1].Correct || !attempt.Answers[2].Correct {
+		t.Errorf("expected only the short answer with extra words to be wrong but got %v %v %v", attempt.Answers[0], attempt.Answers[1], attempt.Answers[2])
+	}
+
+	if len(repo.Attempted) != 1 {
+		t.Errorf("expected the attempt to be saved but got %v", repo.Attempted)
+	}
+}
+
+func TestAttemptHandlerMarksUnansweredQuestionsWrong(t *testing.T) {
+	repo := new(fakes.FakeQuizRepo)
+	repo.GetReturns(fakeQuiz(), nil)
+
+	studentRepo := new(fakes.FakeStudentRepo)
+	studentRepo.FindByUserIDReturns([]*cohesioned.Student{fakes.FakeStudent()}, nil)
+
+	body := bytes.NewBufferString(`{"student_id":2,"answers":[{"question_id":12,"answer":"denominator"}]}`)
+	req := fakes.NewRequestWithContext("POST", "/api/quizzes/5/attempts", body, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "5"})
+	rr := httptest.NewRecorder()
+	quiz.AttemptHandler(fakes.FakeRenderer, repo, studentRepo).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v - %s", status, http.StatusCreated, rr.Body.String())
+	}
+
+	attempt := repo.Attempted[0]
+	if len(attempt.Answers) != 3 || attempt.Answers[0].QuestionID != 11 || attempt.Answers[0].Correct || attempt.Answers[0].Prompt != "What is 1/2 + 1/4?" {
+		t.Errorf("expected every question to be recorded in order with the unanswered ones wrong but got %v", attempt.Answers)
+	}
+
+	if attempt.Score != 1 || attempt.Percent != 25 {
+		t.Errorf("expected a score of 1/4 but got %d/%d", attempt.Score, attempt.MaxScore)
+	}
+}
+
+func TestAttemptHandlerRejectsInvalidAttempts(t *testing.T) {
+	repo := new(fakes.FakeQuizRepo)
+	repo.GetReturns(fakeQuiz(), nil)
+
+	studentRepo := new(fakes.FakeStudentRepo)
+	studentRepo.FindByUserIDReturns([]*cohesioned.Student{fakes.FakeStudent()}, nil)
+
+	body := bytes.NewBufferString(`{"student_id":9,"answers":[{"question_id":12,"answer":"a"},{"question_id":12,"answer":"b"},{"question_id":99,"answer":"c"}]}`)
+	req := fakes.NewRequestWithContext("POST", "/api/quizzes/5/attempts", body, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "5"})
+	rr := httptest.NewRecorder()
+	quiz.AttemptHandler(fakes.FakeRenderer, repo, studentRepo).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	resp := quiz.NewAttemptResponse()
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("failed to unmarshall json response %v", err)
+	}
+
+	fields := validationFields(resp.Attempt.ValidationErrors)
+	if !fields["student_id"] || !fields["answers"] || len(resp.Attempt.ValidationErrors) != 3 {
+		t.Errorf("expected errors for another parent's student, the repeated answer and the unknown question but got %v", resp.Attempt.ValidationErrors)
+	}
+
+	if len(repo.Attempted) != 0 {
+		t.Errorf("an invalid attempt should not be saved")
+	}
+}
+
+func TestAttemptsHandlerOnlyListsTheCurrentUsersStudents(t *testing.T) {
+	repo := new(fakes.FakeQuizRepo)
+	repo.ListAttemptsReturns([]*cohesioned.QuizAttempt{{ID: 8, QuizID: 5, StudentID: 2}}, nil)
+
+	studentRepo := new(fakes.FakeStudentRepo)
+	studentRepo.FindByUserIDReturns([]*cohesioned.Student{fakes.FakeStudent()}, nil)
+
+	for studentID, expected := range map[string]int{"2": http.StatusOK, "9": http.StatusNotFound} {
+		req := fakes.NewRequestWithContext("GET", "/api/profile/students/"+studentID+"/quiz_attempts", nil, fakes.FakeProfile())
+		req = mux.SetURLVars(req, map[string]string{"student_id": studentID})
+		rr := httptest.NewRecorder()
+		quiz.AttemptsHandler(fakes.FakeRenderer, repo, studentRepo).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != expected {
+			t.Errorf("handler returned wrong status code for student %s: got %v want %v", studentID, status, expected)
+		}
+	}
+}
+
+func TestResultsHandlerRollsResultsUpIntoSubjects(t *testing.T) {
+	grade := &cohesioned.Taxonomy{ID: 1, Name: "1st Grade"}
+	subject := &cohesioned.Taxonomy{ID: 2, ParentID: 1, Name: "1st Grade > Math", Parent: grade}
+	node := &cohesioned.Taxonomy{ID: 3, ParentID: 2, Name: "1st Grade > Math > Fractions", Parent: subject}
+
+	taxonomyRepo := new(fakes.FakeTaxonomyRepo)
+	taxonomyRepo.GetReturns(node, nil)
+
+	lastAttempt := time.Now()
+	repo := new(fakes.FakeQuizRepo)
+	repo.ListResultsReturns([]*cohesioned.QuizResult{
+		{SubjectID: 3, Quizzes: 2, Attempts: 3, Score: 5, MaxScore: 8, LastAttempt: lastAttempt.Add(-time.Hour)},
+		{SubjectID: 4, Quizzes: 1, Attempts: 1, Score: 1, MaxScore: 2, LastAttempt: lastAttempt},
+		{SubjectID: 0, Quizzes: 1, Attempts: 1, Score: 0, MaxScore: 1, LastAttempt: lastAttempt},
+	}, nil)
+
+	studentRepo := new(fakes.FakeStudentRepo)
+	studentRepo.FindByUserIDReturns([]*cohesioned.Student{fakes.FakeStudent()}, nil)
+
+	req := fakes.NewRequestWithContext("GET", "/api/profile/quiz_results", nil, fakes.FakeProfile())
+	rr := httptest.NewRecorder()
+	quiz.ResultsHandler(fakes.FakeRenderer, repo, studentRepo, taxonomyRepo).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v - %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	resp := quiz.NewResultsResponse()
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("failed to unmarshall json response %v", err)
+	}
+
+	if len(resp.List) != 1 || resp.List[0].Student.ID != fakes.FakeStudent().ID {
+		t.Fatalf("expected the results of the current user's student but got %v", resp.List)
+	}
+
+	subjects := resp.List[0].Subjects
+	if len(subjects) != 2 {
+		t.Fatalf("expected the results to be rolled up into two subjects but got %v", subjects)
+	}
+
+	math := subjects[0]
+	if math.SubjectID != 2 || math.Subject != "1st Grade > Math" || math.Quizzes != 3 || math.Attempts != 4 || math.Score != 6 || math.MaxScore != 10 || math.Percent != 60 {
+		t.Errorf("unexpected math results %v", math)
+	}
+
+	if !math.LastAttempt.Equal(lastAttempt) {
+		t.Errorf("expected the last attempt to be %v but got %v", lastAttempt, math.LastAttempt)
+	}
+
+	if subjects[1].Subject != quiz.Uncategorized || subjects[1].Attempts != 1 {
+		t.Errorf("expected the quiz that isn't in the taxonomy to be uncategorized but got %v", subjects[1])
+	}
+}
@@ -0,0 +1,38 @@
+package quiz
+
+import (
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+type Repo interface {
+	List(opts *cohesioned.ListOptions) ([]*cohesioned.Quiz, int64, error)
+	Get(id int64) (*cohesioned.Quiz, error)
+	Save(q *cohesioned.Quiz) (int64, error)
+	Update(q *cohesioned.Quiz) error
+	Delete(id int64) error
+	SaveAttempt(a *cohesioned.QuizAttempt) (int64, error)
+	ListAttempts(studentID int64, opts *cohesioned.ListOptions) ([]*cohesioned.QuizAttempt, int64, error)
+	ListResults(studentID int64) ([]*cohesioned.QuizResult, error)
+}
+
+//ListSpec is how the quiz list can be sorted and filtered
+var ListSpec = cohesioned.ListSpec{
+	SortFields:  []string{"id", "title", "created"},
+	DefaultSort: "title",
+	Filters: map[string]cohesioned.FilterType{
+		"title":       cohesioned.FilterString,
+		"video_id":    cohesioned.FilterInt,
+		"taxonomy_id": cohesioned.FilterInt,
+		"created":     cohesioned.FilterDateRange,
+	},
+}
+
+//AttemptListSpec is how a student's quiz attempts can be sorted and filtered
+var AttemptListSpec = cohesioned.ListSpec{
+	SortFields:  []string{"id", "score", "percent", "created"},
+	DefaultSort: "-created",
+	Filters: map[string]cohesioned.FilterType{
+		"quiz_id": cohesioned.FilterInt,
+		"created": cohesioned.FilterDateRange,
+	},
+}
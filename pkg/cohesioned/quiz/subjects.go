@@ -0,0 +1,94 @@
+package quiz
+
+import (
+	"sort"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/taxonomy"
+)
+
+//Uncategorized is the subject of results whose quiz isn't in the taxonomy
+const Uncategorized = "Uncategorized"
+
+//subjectCache finds the subject each taxonomy node is in, remembering them so the taxonomy is only walked once per node
+type subjectCache struct {
+	taxonomyRepo taxonomy.Repo
+	subjects     map[int64]*cohesioned.Taxonomy
+}
+
+func newSubjectCache(taxonomyRepo taxonomy.Repo) *subjectCache {
+	return &subjectCache{
+		taxonomyRepo: taxonomyRepo,
+		subjects:     make(map[int64]*cohesioned.Taxonomy),
+	}
+}
+
+//subjectOf returns the subject the node is in - its ancestor just below the grade at the root of the taxonomy - named with its grade, e.g. "1st Grade > Math".
+//A grade is its own subject. It returns nil if there is no node with that ID
+func (c *subjectCache) subjectOf(nodeID int64) (*cohesioned.Taxonomy, error) {
+	if subject, ok := c.subjects[nodeID]; ok {
+		return subject, nil
+	}
+
+	node, err := c.taxonomyRepo.Get(nodeID)
+	if err != nil || node == nil {
+		return nil, err
+	}
+
+	subject, err := c.taxonomyRepo.ReverseFlatten(node)
+	if err != nil {
+		return nil, err
+	}
+
+	for subject.Parent != nil && subject.Parent.Parent != nil {
+		subject = subject.Parent
+	}
+
+	c.subjects[nodeID] = subject
+	return subject, nil
+}
+
+//rollUp merges results totalled by taxonomy node, as returned by Repo.ListResults, into the subjects the nodes are in, ordered by subject
+func (c *subjectCache) rollUp(byNode []*cohesioned.QuizResult) ([]*cohesioned.QuizResult, error) {
+	bySubject := make(map[int64]*cohesioned.QuizResult)
+	results := []*cohesioned.QuizResult{}
+
+	for _, node := range byNode {
+		subject := &cohesioned.Taxonomy{Name: Uncategorized}
+		if node.SubjectID > 0 {
+			found, err := c.subjectOf(node.SubjectID)
+			if err != nil {
+				return nil, err
+			}
+
+			if found != nil {
+				subject = found
+			}
+		}
+
+		result, ok := bySubject[subject.ID]
+		if !ok {
+			result = &cohesioned.QuizResult{SubjectID: subject.ID, Subject: subject.Name}
+			bySubject[subject.ID] = result
+			results = append(results, result)
+		}
+
+		result.Quizzes += node.Quizzes
+		result.Attempts += node.Attempts
+		result.Score += node.Score
+		result.MaxScore += node.MaxScore
+		if node.LastAttempt.After(result.LastAttempt) {
+			result.LastAttempt = node.LastAttempt
+		}
+	}
+
+	for _, result := range results {
+		result.Percent = cohesioned.Percent(result.Score, result.MaxScore)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Subject < results[j].Subject
+	})
+
+	return results, nil
+}
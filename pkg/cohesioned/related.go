@@ -0,0 +1,8 @@
+package cohesioned
+
+//RelatedVideo is a video to watch after another. Curated videos were linked by an admin, the rest are suggested and ranked by Score
+type RelatedVideo struct {
+	*Video
+	Curated bool  `json:"curated"`
+	Score   int64 `json:"score,omitempty"`
+}
@@ -0,0 +1,28 @@
+package cohesioned
+
+import (
+	"strings"
+	"time"
+)
+
+//Rendition is a transcoded copy of a video's file at a single resolution and bitrate
+type Rendition struct {
+	ID      int64  `json:"id"`
+	VideoID int64  `json:"video_id"`
+	Name    string `json:"name"`
+	Width   int64  `json:"width"`
+	Height  int64  `json:"height"`
+	//Bitrate is the average bitrate in bits per second
+	Bitrate   int64     `json:"bitrate"`
+	ObjectKey string    `json:"object_key"`
+	Size      int64     `json:"size"`
+	SignedURL string    `json:"signed_url,omitempty"`
+	Created   time.Time `json:"created"`
+	//Duration is the length of the rendition in seconds, if the transcoder reports it. It isn't saved with the rendition, but as the video's duration
+	Duration float64 `json:"-"`
+}
+
+//IsHLS returns true if the rendition is an HLS playlist rather than a single file
+func (r *Rendition) IsHLS() bool {
+	return strings.HasSuffix(r.ObjectKey, ".m3u8")
+}
@@ -8,47 +8,111 @@ import (
 	"github.com/cohesion-education/api/pkg/cohesioned/billing"
 	"github.com/cohesion-education/api/pkg/cohesioned/profile"
 	"github.com/cohesion-education/api/pkg/cohesioned/student"
+	"github.com/cohesion-education/api/pkg/cohesioned/video"
 	"github.com/unrolled/render"
 )
 
 func GetUserList(r *render.Render, repo profile.Repo) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		list, err := repo.List()
+		resp := &cohesioned.APIResponse{}
+		opts := cohesioned.ListOptionsFromRequest(req, profile.ListSpec, resp)
+		if len(resp.ValidationErrors) > 0 {
+			resp.SetErrMsg("Invalid list parameters")
+			r.JSON(w, http.StatusBadRequest, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		list, total, err := repo.List(opts)
 		if err != nil {
-			apiResponse := cohesioned.NewAPIErrorResponse("An unexpected error occurred when trying to list users: %v", err)
-			fmt.Println(apiResponse.ErrMsg)
-			r.JSON(w, http.StatusInternalServerError, apiResponse)
+			resp.SetErrMsg("An unexpected error occurred when trying to list users: %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
 			return
 		}
 
-		r.JSON(w, http.StatusOK, list)
+		if list == nil {
+			list = []*cohesioned.Profile{}
+		}
+
+		r.JSON(w, http.StatusOK, cohesioned.NewPage(req, opts, list, total))
 	}
 }
 
 func GetStudentList(r *render.Render, repo student.Repo) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		list, err := repo.List()
+		resp := &cohesioned.APIResponse{}
+		opts := cohesioned.ListOptionsFromRequest(req, student.ListSpec, resp)
+		if len(resp.ValidationErrors) > 0 {
+			resp.SetErrMsg("Invalid list parameters")
+			r.JSON(w, http.StatusBadRequest, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		list, total, err := repo.List(opts)
 		if err != nil {
-			apiResponse := cohesioned.NewAPIErrorResponse("An unexpected error occurred when trying to list students: %v", err)
-			fmt.Println(apiResponse.ErrMsg)
-			r.JSON(w, http.StatusInternalServerError, apiResponse)
+			resp.SetErrMsg("An unexpected error occurred when trying to list students: %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
 			return
 		}
 
-		r.JSON(w, http.StatusOK, list)
+		if list == nil {
+			list = []*cohesioned.Student{}
+		}
+
+		r.JSON(w, http.StatusOK, cohesioned.NewPage(req, opts, list, total))
 	}
 }
 
 func GetPaymentDetailList(r *render.Render, repo billing.Repo) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		list, err := repo.List()
+		resp := &cohesioned.APIResponse{}
+		opts := cohesioned.ListOptionsFromRequest(req, billing.ListSpec, resp)
+		if len(resp.ValidationErrors) > 0 {
+			resp.SetErrMsg("Invalid list parameters")
+			r.JSON(w, http.StatusBadRequest, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		list, total, err := repo.List(opts)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to list payment details: %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		if list == nil {
+			list = []*cohesioned.PaymentDetails{}
+		}
+
+		r.JSON(w, http.StatusOK, cohesioned.NewPage(req, opts, list, total))
+	}
+}
+
+//GetVideoCompletionList lists how many of the profiles and students that started each video went on to finish it
+func GetVideoCompletionList(r *render.Render, repo video.WatchRepo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &cohesioned.APIResponse{}
+		opts := cohesioned.ListOptionsFromRequest(req, video.CompletionListSpec, resp)
+		if len(resp.ValidationErrors) > 0 {
+			resp.SetErrMsg("Invalid list parameters")
+			r.JSON(w, http.StatusBadRequest, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		list, total, err := repo.ListCompletionRates(opts)
 		if err != nil {
-			apiResponse := cohesioned.NewAPIErrorResponse("An unexpected error occurred when trying to list payment details: %v", err)
-			fmt.Println(apiResponse.ErrMsg)
-			r.JSON(w, http.StatusInternalServerError, apiResponse)
+			resp.SetErrMsg("An unexpected error occurred when trying to list video completion rates: %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
 			return
 		}
 
-		r.JSON(w, http.StatusOK, list)
+		if list == nil {
+			list = []*cohesioned.CompletionRate{}
+		}
+
+		r.JSON(w, http.StatusOK, cohesioned.NewPage(req, opts, list, total))
 	}
 }
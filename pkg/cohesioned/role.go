@@ -0,0 +1,99 @@
+package cohesioned
+
+//Role is a named set of Permissions that can be granted to a Profile
+type Role string
+
+//Permission is a single capability that a route can require
+type Permission string
+
+const (
+	RoleAdmin         Role = "admin"
+	RoleContentEditor Role = "content_editor"
+	RoleReviewer      Role = "reviewer"
+	RoleTeacher       Role = "teacher"
+	RoleParent        Role = "parent"
+	//RoleService marks a profile that only authenticates with an APIKey. Its permissions come from the key, not the role
+	RoleService Role = "service"
+
+	PermissionManageTaxonomy  Permission = "taxonomy:manage"
+	PermissionManageVideos    Permission = "videos:manage"
+	PermissionManageStandards Permission = "standards:manage"
+	PermissionReviewVideos    Permission = "videos:review"
+	PermissionViewReports     Permission = "reports:view"
+	PermissionManageRoles     Permission = "roles:manage"
+	PermissionManageAPIKeys   Permission = "apikeys:manage"
+	PermissionImpersonate     Permission = "profiles:impersonate"
+	PermissionManageStorage   Permission = "storage:manage"
+	PermissionManageTeachers  Permission = "teachers:manage"
+	PermissionModerateFAQs    Permission = "faqs:moderate"
+	PermissionManageQuizzes   Permission = "quizzes:manage"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermissionManageTaxonomy,
+		PermissionManageVideos,
+		PermissionManageStandards,
+		PermissionReviewVideos,
+		PermissionViewReports,
+		PermissionManageRoles,
+		PermissionManageAPIKeys,
+		PermissionImpersonate,
+		PermissionManageStorage,
+		PermissionManageTeachers,
+		PermissionModerateFAQs,
+		PermissionManageQuizzes,
+	},
+	RoleContentEditor: {
+		PermissionManageTaxonomy,
+		PermissionManageVideos,
+		PermissionManageStandards,
+		PermissionReviewVideos,
+		PermissionManageTeachers,
+		PermissionModerateFAQs,
+		PermissionManageQuizzes,
+	},
+	RoleReviewer: {
+		PermissionReviewVideos,
+	},
+	RoleTeacher: {},
+	RoleParent:  {},
+	RoleService: {},
+}
+
+//Roles returns every Role known to the system
+func Roles() []Role {
+	return []Role{RoleAdmin, RoleContentEditor, RoleReviewer, RoleTeacher, RoleParent, RoleService}
+}
+
+//Permissions returns every Permission known to the system
+func Permissions() []Permission {
+	return append([]Permission{}, rolePermissions[RoleAdmin]...)
+}
+
+//IsValid returns true if the permission is one of the Permissions known to the system
+func (p Permission) IsValid() bool {
+	return RoleAdmin.Grants(p)
+}
+
+//IsValid returns true if the role is one of the Roles known to the system
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+//Permissions returns the Permissions granted by the role
+func (r Role) Permissions() []Permission {
+	return rolePermissions[r]
+}
+
+//Grants returns true if the role includes the given Permission
+func (r Role) Grants(p Permission) bool {
+	for _, permission := range rolePermissions[r] {
+		if permission == p {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,9 @@
+package cohesioned
+
+//SearchResult is a Video matching a search, with its relevance and the matching parts of its fields
+type SearchResult struct {
+	Video *Video  `json:"video"`
+	Score float64 `json:"score"`
+	//Highlights maps the name of each field that matched to a snippet of it, with the matching terms wrapped in <em> tags
+	Highlights map[string]string `json:"highlights"`
+}
@@ -0,0 +1,57 @@
+package cohesioned
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	//CommonCoreJurisdiction is the jurisdiction of the Common Core State Standards. Every other jurisdiction is a state, written the same way as Profile.State
+	CommonCoreJurisdiction = "CCSS"
+
+	//maxStandardCodeLength matches the longest tag name a video can be tagged with
+	maxStandardCodeLength = 191
+)
+
+//Standard is an entry in the catalog of educational standards that videos are aligned to
+type Standard struct {
+	Validatable
+	ID           int64     `json:"id"`
+	Code         string    `json:"code"`
+	Description  string    `json:"description"`
+	Grade        string    `json:"grade"`
+	Subject      string    `json:"subject"`
+	Jurisdiction string    `json:"jurisdiction"`
+	Created      time.Time `json:"created"`
+	CreatedBy    int64     `json:"created_by"`
+	Updated      time.Time `json:"updated"`
+	UpdatedBy    int64     `json:"updated_by"`
+}
+
+//IsCommonCore returns true if the standard is one of the Common Core State Standards
+func (s *Standard) IsCommonCore() bool {
+	return strings.EqualFold(s.Jurisdiction, CommonCoreJurisdiction)
+}
+
+func (s *Standard) Validate() bool {
+	s.Code = strings.TrimSpace(s.Code)
+	s.Jurisdiction = strings.TrimSpace(s.Jurisdiction)
+
+	if len(s.Code) == 0 {
+		s.AddValidationError("code", "code is required")
+	}
+
+	if len(s.Code) > maxStandardCodeLength {
+		s.AddValidationError("code", "code must be 191 characters or less")
+	}
+
+	if strings.Contains(s.Code, ",") {
+		s.AddValidationError("code", "code can not contain a comma")
+	}
+
+	if len(s.Jurisdiction) == 0 {
+		s.AddValidationError("jurisdiction", "jurisdiction is required - use CCSS for Common Core or the state")
+	}
+
+	return len(s.ValidationErrors) == 0
+}
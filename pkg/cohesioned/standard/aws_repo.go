@@ -0,0 +1,289 @@
+package standard
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/db"
+)
+
+type awsRepo struct {
+	*sql.DB
+}
+
+func NewAwsRepo(db *sql.DB) Repo {
+	return &awsRepo{
+		DB: db,
+	}
+}
+
+const selectQuery = `select
+		id,
+		code,
+		description,
+		grade,
+		subject,
+		jurisdiction,
+		created,
+		created_by,
+		updated,
+		updated_by
+	from standard`
+
+var listQuery = db.ListQuery{
+	Select: `select
+		id,
+		code,
+		description,
+		grade,
+		subject,
+		jurisdiction,
+		created,
+		created_by,
+		updated,
+		updated_by`,
+	From: `from standard`,
+	Columns: map[string]string{
+		"id":           "id",
+		"code":         "code",
+		"grade":        "grade",
+		"subject":      "subject",
+		"jurisdiction": "jurisdiction",
+		"created":      "created",
+	},
+}
+
+func (repo *awsRepo) List(opts *cohesioned.ListOptions) ([]*cohesioned.Standard, int64, error) {
+	var list []*cohesioned.Standard
+	var total int64
+
+	selectQuery, args, countQuery, countArgs, err := listQuery.Build(opts)
+	if err != nil {
+		return list, total, err
+	}
+
+	if err := repo.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		return list, total, fmt.Errorf("Failed to count standards: %v", err)
+	}
+
+	list, err = repo.query(selectQuery, args...)
+	return list, total, err
+}
+
+func (repo *awsRepo) Get(id int64) (*cohesioned.Standard, error) {
+	row := repo.QueryRow(selectQuery+` where id = ?`, id)
+
+	s, err := repo.mapRowToObject(row)
+	if err != nil {
+		return nil, fmt.Errorf("Unexpected error querying for standard by id %d: %v", id, err)
+	}
+
+	return s, nil
+}
+
+//FindByCodes returns the standards, in any jurisdiction, with one of the given codes
+func (repo *awsRepo) FindByCodes(codes []string) ([]*cohesioned.Standard, error) {
+	if len(codes) == 0 {
+		return []*cohesioned.Standard{}, nil
+	}
+
+	args := make([]interface{}, len(codes))
+	for i, code := range codes {
+		args[i] = code
+	}
+
+	return repo.query(fmt.Sprintf(`%s where code in (%s)`, selectQuery, db.Placeholders(len(codes))), args...)
+}
+
+func (repo *awsRepo) query(query string, args ...interface{}) ([]*cohesioned.Standard, error) {
+	var list []*cohesioned.Standard
+
+	rows, err := repo.Query(query, args...)
+	if err != nil {
+		return list, fmt.Errorf("Failed to execute query: %v", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		s, err := repo.mapRowToObject(rows)
+		if err != nil {
+			return list, fmt.Errorf("an unexpected error occurred while processing the result set from the db: %v", err)
+		}
+
+		list = append(list, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return list, fmt.Errorf("rows had an error: %v", err)
+	}
+
+	return list, nil
+}
+
+func (repo *awsRepo) Save(s *cohesioned.Standard) (int64, error) {
+	sql := `insert into standard
+	(
+		code,
+		description,
+		grade,
+		subject,
+		jurisdiction,
+		created,
+		created_by
+	) values (?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := repo.Exec(sql, s.Code, s.Description, s.Grade, s.Subject, s.Jurisdiction, s.Created, s.CreatedBy)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to insert standard %s: %v", s.Code, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get last insert id from result: %v", err)
+	}
+
+	return id, nil
+}
+
+func (repo *awsRepo) Update(s *cohesioned.Standard) error {
+	sql := `update standard set
+		code = ?,
+		description = ?,
+		grade = ?,
+		subject = ?,
+		jurisdiction = ?,
+		updated = ?,
+		updated_by = ?
+	where
+		id = ?`
+
+	result, err := repo.Exec(sql, s.Code, s.Description, s.Grade, s.Subject, s.Jurisdiction, s.Updated, s.UpdatedBy, s.ID)
+	if err != nil {
+		return fmt.Errorf("Failed to update standard %d: %v", s.ID, err)
+	}
+
+	rowsEffected, err := result.RowsAffected()
+	if err != nil || rowsEffected == 0 {
+		return fmt.Errorf("Failed to update standard %d: %v", s.ID, err)
+	}
+
+	return nil
+}
+
+func (repo *awsRepo) Delete(id int64) error {
+	result, err := repo.Exec(`delete from standard where id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("Failed to delete standard with id %d: %v", id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to get number of rows affected from result: %v", err)
+	}
+
+	if rowsAffected != 1 {
+		return fmt.Errorf("Failed to delete standard with id %d (rows affected != 1)", id)
+	}
+
+	return nil
+}
+
+//Import creates or updates each standard, matched on jurisdiction and code, in a single transaction
+func (repo *awsRepo) Import(list []*cohesioned.Standard) (*ImportResult, error) {
+	sql := `insert into standard
+	(
+		code,
+		description,
+		grade,
+		subject,
+		jurisdiction,
+		created,
+		created_by
+	) values (?, ?, ?, ?, ?, ?, ?)
+	on duplicate key update
+		updated_by = if(%[1]s, updated_by, values(created_by)),
+		updated = if(%[1]s, updated, values(created)),
+		description = values(description),
+		grade = values(grade),
+		subject = values(subject)`
+
+	//updated is only touched when the standard changed, so mysql can report which standards were unchanged.
+	//The assignments are evaluated in order, so this has to be checked before description, grade and subject are updated
+	unchanged := "description <=> values(description) and grade <=> values(grade) and subject <=> values(subject)"
+	sql = fmt.Sprintf(sql, unchanged)
+
+	result := &ImportResult{}
+
+	tx, err := repo.Begin()
+	if err != nil {
+		return result, fmt.Errorf("Failed to begin transaction: %v", err)
+	}
+
+	for _, s := range list {
+		r, err := tx.Exec(sql, s.Code, s.Description, s.Grade, s.Subject, s.Jurisdiction, s.Created, s.CreatedBy)
+		if err != nil {
+			tx.Rollback()
+			return &ImportResult{}, fmt.Errorf("Failed to import standard %s (%s): %v", s.Code, s.Jurisdiction, err)
+		}
+
+		//mysql reports 1 row affected for an insert, 2 for an update and 0 when nothing changed
+		rowsAffected, err := r.RowsAffected()
+		if err != nil {
+			tx.Rollback()
+			return &ImportResult{}, fmt.Errorf("Failed to get number of rows affected from result: %v", err)
+		}
+
+		switch rowsAffected {
+		case 1:
+			result.Created++
+		case 2:
+			result.Updated++
+		default:
+			result.Unchanged++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return &ImportResult{}, fmt.Errorf("Failed to commit the import: %v", err)
+	}
+
+	return result, nil
+}
+
+func (repo *awsRepo) mapRowToObject(rs db.RowScanner) (*cohesioned.Standard, error) {
+	s := new(cohesioned.Standard)
+
+	var description, grade, subject sql.NullString
+	var updated db.NullTime
+	var updatedBy sql.NullInt64
+
+	err := rs.Scan(
+		&s.ID,
+		&s.Code,
+		&description,
+		&grade,
+		&subject,
+		&s.Jurisdiction,
+		&s.Created,
+		&s.CreatedBy,
+		&updated,
+		&updatedBy,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	s.Description = description.String
+	s.Grade = grade.String
+	s.Subject = subject.String
+	s.Updated = updated.Time
+	s.UpdatedBy = updatedBy.Int64
+
+	return s, nil
+}
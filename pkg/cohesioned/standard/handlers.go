@@ -0,0 +1,317 @@
+package standard
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/gorilla/mux"
+	"github.com/unrolled/render"
+)
+
+const (
+	//maxImportSize caps the size of an uploaded import file
+	maxImportSize = 10 << 20
+)
+
+type StandardResponse struct {
+	*cohesioned.APIResponse
+	Standard *cohesioned.Standard `json:"standard,omitempty"`
+	Import   *ImportResult        `json:"import,omitempty"`
+}
+
+func NewStandardResponse() *StandardResponse {
+	return &StandardResponse{
+		APIResponse: &cohesioned.APIResponse{},
+	}
+}
+
+func ListHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &cohesioned.APIResponse{}
+		opts := cohesioned.ListOptionsFromRequest(req, ListSpec, resp)
+		if len(resp.ValidationErrors) > 0 {
+			resp.SetErrMsg("Invalid list parameters")
+			r.JSON(w, http.StatusBadRequest, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		list, total, err := repo.List(opts)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred listing standards %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		if list == nil {
+			list = []*cohesioned.Standard{}
+		}
+
+		r.JSON(w, http.StatusOK, cohesioned.NewPage(req, opts, list, total))
+	}
+}
+
+func GetHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		s, resp, status := findStandardFromPath(req, repo)
+		resp.Standard = s
+		r.JSON(w, status, resp)
+	}
+}
+
+func AddHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := NewStandardResponse()
+
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		defer req.Body.Close()
+		s := &cohesioned.Standard{}
+		if err := json.NewDecoder(req.Body).Decode(s); err != nil {
+			resp.SetErrMsg("failed to unmarshall json %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		if status, ok := validate(repo, s, resp); !ok {
+			r.JSON(w, status, resp)
+			return
+		}
+
+		s.Created = time.Now()
+		s.CreatedBy = currentUser.ID
+
+		id, err := repo.Save(s)
+		if err != nil {
+			resp.SetErrMsg("Failed to save standard: %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		s.ID = id
+		resp.Standard = s
+		r.JSON(w, http.StatusCreated, resp)
+	}
+}
+
+func UpdateHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		existing, resp, status := findStandardFromPath(req, repo)
+		if existing == nil {
+			r.JSON(w, status, resp)
+			return
+		}
+
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		defer req.Body.Close()
+		id := existing.ID
+		if err := json.NewDecoder(req.Body).Decode(existing); err != nil {
+			resp.SetErrMsg("failed to unmarshall json %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		existing.ID = id
+		if status, ok := validate(repo, existing, resp); !ok {
+			r.JSON(w, status, resp)
+			return
+		}
+
+		existing.Updated = time.Now()
+		existing.UpdatedBy = currentUser.ID
+
+		if err := repo.Update(existing); err != nil {
+			resp.SetErrMsg("Failed to update standard %d: %v", existing.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		resp.Standard = existing
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func DeleteHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		s, resp, status := findStandardFromPath(req, repo)
+		if s == nil {
+			r.JSON(w, status, resp)
+			return
+		}
+
+		if err := repo.Delete(s.ID); err != nil {
+			resp.SetErrMsg("Failed to delete standard %d: %v", s.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//ImportHandler creates or updates standards from a CSV or JSON body, or from a file uploaded in the "file" field of a multipart form.
+//Standards without a jurisdiction get ?jurisdiction=. Nothing is imported unless every standard is valid
+func ImportHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := NewStandardResponse()
+
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		defer req.Body.Close()
+		list, err := parseImport(w, req)
+		if err != nil {
+			resp.SetErrMsg("Unable to read the standards to import: %v", err)
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		if ValidateImport(list, req.URL.Query().Get("jurisdiction"), resp.APIResponse); len(resp.ValidationErrors) > 0 {
+			resp.SetErrMsg("Nothing was imported because some standards are invalid")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		now := time.Now()
+		for _, s := range list {
+			s.Created = now
+			s.CreatedBy = currentUser.ID
+		}
+
+		result, err := repo.Import(list)
+		if err != nil {
+			resp.SetErrMsg("Failed to import standards: %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		fmt.Printf("user %d imported %d standards: %d created, %d updated, %d unchanged\n", currentUser.ID, len(list), result.Created, result.Updated, result.Unchanged)
+		resp.Import = result
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func parseImport(w http.ResponseWriter, req *http.Request) ([]*cohesioned.Standard, error) {
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, fmt.Errorf("Content-Type must be text/csv, application/json or multipart/form-data")
+	}
+
+	req.Body = http.MaxBytesReader(w, req.Body, maxImportSize)
+	var body io.Reader = req.Body
+
+	if mediaType == "multipart/form-data" {
+		if err := req.ParseMultipartForm(maxImportSize); err != nil {
+			return nil, fmt.Errorf("Failed to read multipart form: %v", err)
+		}
+
+		file, header, err := req.FormFile("file")
+		if err != nil {
+			return nil, fmt.Errorf("the form must have a file field: %v", err)
+		}
+
+		defer file.Close()
+		body = file
+		switch strings.ToLower(filepath.Ext(header.Filename)) {
+		case ".csv":
+			mediaType = "text/csv"
+		case ".json":
+			mediaType = "application/json"
+		default:
+			mediaType = header.Header.Get("Content-Type")
+		}
+	}
+
+	switch mediaType {
+	case "text/csv":
+		return ParseCSV(body)
+	case "application/json":
+		return ParseJSON(body)
+	default:
+		return nil, fmt.Errorf("standards can only be imported from CSV or JSON, not %s", mediaType)
+	}
+}
+
+//validate checks the standard and that no other standard in its jurisdiction has the same code
+func validate(repo Repo, s *cohesioned.Standard, resp *StandardResponse) (int, bool) {
+	if !s.Validate() {
+		resp.Standard = s
+		resp.SetErrMsg("Hmmm... you seem to be missing some required fields")
+		return http.StatusBadRequest, false
+	}
+
+	existing, err := repo.FindByCodes([]string{s.Code})
+	if err != nil {
+		resp.SetErrMsg("Failed to check for existing standards with code %s: %v", s.Code, err)
+		fmt.Println(resp.ErrMsg)
+		return http.StatusInternalServerError, false
+	}
+
+	for _, e := range existing {
+		if e.ID != s.ID && strings.EqualFold(e.Jurisdiction, s.Jurisdiction) {
+			s.AddValidationError("code", fmt.Sprintf("%s already has a standard %s", s.Jurisdiction, s.Code))
+			resp.Standard = s
+			resp.SetErrMsg("Duplicate standard")
+			return http.StatusConflict, false
+		}
+	}
+
+	return http.StatusOK, true
+}
+
+func findStandardFromPath(req *http.Request, repo Repo) (*cohesioned.Standard, *StandardResponse, int) {
+	vars := mux.Vars(req)
+	resp := NewStandardResponse()
+
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		resp.SetErrMsg("%s is not a valid id %v", vars["id"], err)
+		return nil, resp, http.StatusBadRequest
+	}
+
+	s, err := repo.Get(id)
+	if err != nil {
+		resp.SetErrMsg("An unexpected error occurred when trying to find standard %d: %v", id, err)
+		fmt.Println(resp.ErrMsg)
+		return nil, resp, http.StatusInternalServerError
+	}
+
+	if s == nil {
+		resp.SetErrMsg("%d is not a valid standard id", id)
+		return nil, resp, http.StatusNotFound
+	}
+
+	return s, resp, http.StatusOK
+}
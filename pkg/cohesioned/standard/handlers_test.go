@@ -0,0 +1,159 @@
+package standard_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/standard"
+)
+
+const testCSV = `Code,Description,Grade,Subject
+MAFS.1.OA.1.1,"Use addition and subtraction within 20 to solve word problems",1st,Math
+MAFS.1.OA.1.2,Solve word problems that call for addition of three whole numbers,1st,Math
+`
+
+func newImportRequest(t *testing.T, uri, contentType string, body *bytes.Buffer) *http.Request {
+	req, err := http.NewRequest("POST", uri, body)
+	if err != nil {
+		t.Fatalf("Failed to initialize new request %v", err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	ctx := context.WithValue(req.Context(), cohesioned.CurrentUserKey, fakes.FakeAdmin())
+	return req.WithContext(ctx)
+}
+
+func TestImportHandlerWithCSV(t *testing.T) {
+	repo := new(fakes.FakeStandardRepo)
+	repo.ImportReturns(&standard.ImportResult{Created: 1, Updated: 1}, nil)
+
+	req := newImportRequest(t, "/api/standards/import?jurisdiction=FL", "text/csv", bytes.NewBufferString(testCSV))
+	rr := httptest.NewRecorder()
+	standard.ImportHandler(fakes.FakeRenderer, repo).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v - %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	if len(repo.Imported) != 2 {
+		t.Fatalf("expected 2 standards to be imported but got %d", len(repo.Imported))
+	}
+
+	s := repo.Imported[0]
+	if s.Code != "MAFS.1.OA.1.1" || s.Jurisdiction != "FL" || s.Grade != "1st" || s.Subject != "Math" || !strings.HasPrefix(s.Description, "Use addition") {
+		t.Errorf("the first row was not imported correctly: %v", s)
+	}
+
+	resp := standard.NewStandardResponse()
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("failed to unmarshall json response %v", err)
+	}
+
+	if resp.Import == nil || resp.Import.Created != 1 || resp.Import.Updated != 1 {
+		t.Errorf("expected the import result to be returned but got %v", resp.Import)
+	}
+}
+
+func TestImportHandlerWithMultipartJSON(t *testing.T) {
+	repo := new(fakes.FakeStandardRepo)
+	repo.ImportReturns(&standard.ImportResult{Created: 1}, nil)
+
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	file, err := form.CreateFormFile("file", "ccss.json")
+	if err != nil {
+		t.Fatalf("Failed to create form file %v", err)
+	}
+
+	file.Write([]byte(`[{"code":"1.OA.A.1","jurisdiction":"CCSS","grade":"1st","subject":"Math"}]`))
+	form.Close()
+
+	req := newImportRequest(t, "/api/standards/import", form.FormDataContentType(), body)
+	rr := httptest.NewRecorder()
+	standard.ImportHandler(fakes.FakeRenderer, repo).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v - %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	if len(repo.Imported) != 1 || !repo.Imported[0].IsCommonCore() {
+		t.Errorf("expected a common core standard to be imported but got %v", repo.Imported)
+	}
+}
+
+func TestImportHandlerRejectsInvalidStandards(t *testing.T) {
+	repo := new(fakes.FakeStandardRepo)
+
+	csv := testCSV + ",missing code,1st,Math\nMAFS.1.OA.1.1,duplicate,1st,Math\n"
+	req := newImportRequest(t, "/api/standards/import", "text/csv", bytes.NewBufferString(csv))
+	rr := httptest.NewRecorder()
+	standard.ImportHandler(fakes.FakeRenderer, repo).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	if repo.Imported != nil {
+		t.Error("nothing should be imported when a standard is invalid")
+	}
+
+	resp := standard.NewStandardResponse()
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("failed to unmarshall json response %v", err)
+	}
+
+	//every row is missing a jurisdiction, the third row a code and the fourth is a duplicate of the first
+	fields := make(map[string]bool)
+	for _, e := range resp.ValidationErrors {
+		fields[e.Field] = true
+	}
+
+	for _, field := range []string{"standards[0].jurisdiction", "standards[2].code", "standards[3].jurisdiction"} {
+		if !fields[field] {
+			t.Errorf("expected a validation error for %s but got %v", field, fields)
+		}
+	}
+}
+
+func TestAddHandlerRejectsDuplicateCode(t *testing.T) {
+	repo := new(fakes.FakeStandardRepo)
+	repo.FindByCodesReturns([]*cohesioned.Standard{{ID: 3, Code: "MAFS.1.OA.1.1", Jurisdiction: "FL"}}, nil)
+
+	req := newImportRequest(t, "/api/standards", "application/json", bytes.NewBufferString(`{"code":"MAFS.1.OA.1.1","jurisdiction":"fl"}`))
+	rr := httptest.NewRecorder()
+	standard.AddHandler(fakes.FakeRenderer, repo).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusConflict {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusConflict)
+	}
+}
+
+func TestAddHandler(t *testing.T) {
+	repo := new(fakes.FakeStandardRepo)
+	repo.SaveReturns(7, nil)
+
+	req := newImportRequest(t, "/api/standards", "application/json", bytes.NewBufferString(`{"code":"1.OA.A.1","jurisdiction":"CCSS"}`))
+	rr := httptest.NewRecorder()
+	standard.AddHandler(fakes.FakeRenderer, repo).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v - %s", status, http.StatusCreated, rr.Body.String())
+	}
+
+	resp := standard.NewStandardResponse()
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("failed to unmarshall json response %v", err)
+	}
+
+	if resp.Standard.ID != 7 || resp.Standard.CreatedBy != fakes.FakeAdmin().ID {
+		t.Errorf("expected standard 7 created by the current user but got %v", resp.Standard)
+	}
+}
@@ -0,0 +1,113 @@
+package standard
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+//csvColumns are the columns of an import file. Only code is required - jurisdiction can be given for the whole file instead
+var csvColumns = []string{"code", "description", "grade", "subject", "jurisdiction"}
+
+//ParseCSV reads standards from a CSV file whose first row names its columns
+func ParseCSV(r io.Reader) ([]*cohesioned.Standard, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the header row: %v", err)
+	}
+
+	index := make(map[string]int)
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	if _, ok := index["code"]; !ok {
+		return nil, fmt.Errorf("the header row must have a code column. Supported columns are %v", csvColumns)
+	}
+
+	value := func(record []string, column string) string {
+		i, ok := index[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+
+		return strings.TrimSpace(record[i])
+	}
+
+	var list []*cohesioned.Standard
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read row %d: %v", len(list)+2, err)
+		}
+
+		list = append(list, &cohesioned.Standard{
+			Code:         value(record, "code"),
+			Description:  value(record, "description"),
+			Grade:        value(record, "grade"),
+			Subject:      value(record, "subject"),
+			Jurisdiction: value(record, "jurisdiction"),
+		})
+	}
+
+	return list, nil
+}
+
+//ParseJSON reads standards from a JSON array
+func ParseJSON(r io.Reader) ([]*cohesioned.Standard, error) {
+	var list []*cohesioned.Standard
+	if err := json.NewDecoder(r).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to unmarshall json %v", err)
+	}
+
+	return list, nil
+}
+
+//ValidateImport validates each standard, defaulting its jurisdiction when it has none, and rejects standards that appear more than once.
+//Errors are added to resp with the field prefixed by the standard's position, e.g. standards[3].code
+func ValidateImport(list []*cohesioned.Standard, defaultJurisdiction string, resp *cohesioned.APIResponse) {
+	if len(list) == 0 {
+		resp.AddValidationError("standards", "there are no standards to import")
+		return
+	}
+
+	seen := make(map[string]int)
+	for i, s := range list {
+		if s == nil {
+			resp.AddValidationError(fmt.Sprintf("standards[%d]", i), "standard can not be null")
+			continue
+		}
+
+		if len(strings.TrimSpace(s.Jurisdiction)) == 0 {
+			s.Jurisdiction = defaultJurisdiction
+		}
+
+		if !s.Validate() {
+			for _, e := range s.ValidationErrors {
+				resp.AddValidationError(fmt.Sprintf("standards[%d].%s", i, e.Field), e.Err)
+			}
+
+			continue
+		}
+
+		key := strings.ToUpper(s.Jurisdiction + " " + s.Code)
+		if first, ok := seen[key]; ok {
+			resp.AddValidationError(fmt.Sprintf("standards[%d].code", i), fmt.Sprintf("%s is already in this import at standards[%d]", s.Code, first))
+			continue
+		}
+
+		seen[key] = i
+	}
+}
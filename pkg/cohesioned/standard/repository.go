@@ -0,0 +1,33 @@
+package standard
+
+import (
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+//ImportResult counts what an import did to the catalog
+type ImportResult struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+}
+
+type Repo interface {
+	List(opts *cohesioned.ListOptions) ([]*cohesioned.Standard, int64, error)
+	Get(id int64) (*cohesioned.Standard, error)
+	FindByCodes(codes []string) ([]*cohesioned.Standard, error)
+	Save(s *cohesioned.Standard) (int64, error)
+	Update(s *cohesioned.Standard) error
+	Delete(id int64) error
+	Import(list []*cohesioned.Standard) (*ImportResult, error)
+}
+
+//ListSpec is how the standards catalog can be sorted and filtered
+var ListSpec = cohesioned.ListSpec{
+	SortFields:  []string{"id", "code", "grade", "subject", "jurisdiction", "created"},
+	DefaultSort: "code",
+	Filters: map[string]cohesioned.FilterType{
+		"jurisdiction": cohesioned.FilterString,
+		"grade":        cohesioned.FilterString,
+		"subject":      cohesioned.FilterString,
+	},
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned/config"
+)
+
+//ErrNotFound is returned by Head when there is no object with the given key
+var ErrNotFound = errors.New("object not found")
+
+//ObjectInfo describes an object kept in a BlobStore
+type ObjectInfo struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	ContentType  string    `json:"content_type,omitempty"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+//BlobStore keeps files, such as uploaded videos, in named buckets
+type BlobStore interface {
+	//Put writes body to the object at key, replacing any object already there
+	Put(bucket, key string, body io.Reader, contentType string) error
+	//Get opens the object at key for reading, or returns ErrNotFound
+	Get(bucket, key string) (io.ReadCloser, error)
+	//Delete removes the object at key. Deleting an object that doesn't exist is not an error
+	Delete(bucket, key string) error
+	//Head describes the object at key, or returns ErrNotFound
+	Head(bucket, key string) (*ObjectInfo, error)
+	//SignedGetURL is a url anyone can download the object from until ttl has passed
+	SignedGetURL(bucket, key string, ttl time.Duration) (string, error)
+	//SignedPutURL is a url anyone can upload the object to with a PUT request until ttl has passed
+	SignedPutURL(bucket, key, contentType string, ttl time.Duration) (string, error)
+	//List describes the objects whose keys start with prefix, in key order
+	List(bucket, prefix string) ([]*ObjectInfo, error)
+	//CreateMultipartUpload starts uploading the object at key in parts, returning the ID of the upload
+	CreateMultipartUpload(bucket, key, contentType string) (string, error)
+	//SignedPartURL is a url anyone can upload a part of a multipart upload to with a PUT request until ttl has passed. The ETag header of the response identifies the part when the upload is completed
+	SignedPartURL(bucket, key, uploadID string, partNumber int64, ttl time.Duration) (string, error)
+	//CompleteMultipartUpload joins the parts, in the order given, into the object at key
+	CompleteMultipartUpload(bucket, key, uploadID string, parts []*CompletedPart) error
+	//AbortMultipartUpload discards the parts uploaded so far
+	AbortMultipartUpload(bucket, key, uploadID string) error
+}
+
+//CompletedPart is a part of a multipart upload, identified by the ETag returned when it was uploaded
+type CompletedPart struct {
+	PartNumber int64  `json:"part_number"`
+	ETag       string `json:"etag"`
+}
+
+//New returns the BlobStore configured by cfg
+func New(cfg config.AwsConfig) BlobStore {
+	sc := cfg.GetStorageConfig()
+	if sc.IsLocal() {
+		return NewLocalStore(sc.LocalDir, sc.LocalBaseURL, sc.LocalSecret)
+	}
+
+	return NewS3Store(cfg)
+}
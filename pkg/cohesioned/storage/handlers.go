@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/gorilla/mux"
+	"github.com/unrolled/render"
+)
+
+//bodyTooLarge is the error http.MaxBytesReader returns once more than its limit is read
+const bodyTooLarge = "http: request body too large"
+
+//LocalFileHandler serves GET, HEAD and PUT requests for the objects of a LocalStore at LocalStorePath/{bucket}/{key}. Parts of multipart uploads are PUT to the same path with upload_id and part_number params. Requests are authorized by the url's signature rather than a token, so they can come straight from a browser or video player.
+//PUT bodies larger than maxSize are rejected. A maxSize <= 0 allows any size
+func LocalFileHandler(r *render.Render, store *LocalStore, maxSize int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &cohesioned.APIResponse{}
+
+		vars := mux.Vars(req)
+		bucket, key := vars["bucket"], vars["key"]
+
+		//HEAD requests are allowed with a url signed for GET, the same as S3
+		method := req.Method
+		if method == http.MethodHead {
+			method = http.MethodGet
+		}
+
+		if err := store.Verify(method, bucket, key, req.URL.Query()); err != nil {
+			resp.SetErr(err)
+			r.JSON(w, http.StatusForbidden, resp)
+			return
+		}
+
+		if req.Method == http.MethodPut && maxSize > 0 {
+			if req.ContentLength > maxSize {
+				resp.SetErrMsg("%s/%s is larger than the maximum upload size of %d bytes", bucket, key, maxSize)
+				r.JSON(w, http.StatusRequestEntityTooLarge, resp)
+				return
+			}
+
+			req.Body = http.MaxBytesReader(w, req.Body, maxSize)
+		}
+
+		if uploadID := req.URL.Query().Get("upload_id"); req.Method == http.MethodPut && len(uploadID) > 0 {
+			partNumber, _ := strconv.ParseInt(req.URL.Query().Get("part_number"), 10, 64)
+			etag, err := store.PutPart(bucket, key, uploadID, partNumber, req.Body)
+			if err == ErrUploadNotFound || err == ErrInvalidPart {
+				resp.SetErr(err)
+				r.JSON(w, http.StatusBadRequest, resp)
+				return
+			}
+
+			if err != nil && strings.Contains(err.Error(), bodyTooLarge) {
+				resp.SetErrMsg("Part %d of %s/%s is larger than the maximum upload size of %d bytes", partNumber, bucket, key, maxSize)
+				r.JSON(w, http.StatusRequestEntityTooLarge, resp)
+				return
+			}
+
+			if err != nil {
+				resp.SetErrMsg("Failed to store part %d of %s/%s: %v", partNumber, bucket, key, err)
+				fmt.Println(resp.ErrMsg)
+				r.JSON(w, http.StatusInternalServerError, resp)
+				return
+			}
+
+			w.Header().Set("ETag", etag)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		if req.Method == http.MethodPut {
+			err := store.Put(bucket, key, req.Body, req.Header.Get("Content-Type"))
+			if err != nil && strings.Contains(err.Error(), bodyTooLarge) {
+				resp.SetErrMsg("%s/%s is larger than the maximum upload size of %d bytes", bucket, key, maxSize)
+				r.JSON(w, http.StatusRequestEntityTooLarge, resp)
+				return
+			}
+
+			if err != nil {
+				resp.SetErrMsg("Failed to store %s/%s: %v", bucket, key, err)
+				fmt.Println(resp.ErrMsg)
+				r.JSON(w, http.StatusInternalServerError, resp)
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		f, info, err := store.Open(bucket, key)
+		if err == ErrNotFound {
+			resp.SetErrMsg("%s/%s was not found", bucket, key)
+			r.JSON(w, http.StatusNotFound, resp)
+			return
+		}
+
+		if err != nil {
+			resp.SetErrMsg("Failed to read %s/%s: %v", bucket, key, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		defer f.Close()
+
+		//ServeContent handles HEAD and Range requests, so players can seek
+		w.Header().Set("Content-Type", info.ContentType)
+		http.ServeContent(w, req, info.Key, info.LastModified, f)
+	}
+}
@@ -0,0 +1,118 @@
+package storage_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned/storage"
+	"github.com/gorilla/mux"
+)
+
+func TestLocalFileHandler(t *testing.T) {
+	store, cleanup := newLocalStore(t)
+	defer cleanup()
+
+	router := mux.NewRouter()
+	router.Methods("GET", "HEAD", "PUT").Path(storage.LocalStorePath + "/{bucket}/{key:.+}").Handler(storage.LocalFileHandler(fakes.FakeRenderer, store, 0))
+
+	putURL, _ := store.SignedPutURL("videos", "transcoded/1-video.mp4", "video/mp4", time.Minute)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("PUT", putURL, strings.NewReader("video bytes")))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("PUT returned wrong status code: got %v want %v: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	getURL, _ := store.SignedGetURL("videos", "transcoded/1-video.mp4", time.Minute)
+	rr = httptest.NewRecorder()
+	req := httptest.NewRequest("GET", getURL, nil)
+	req.Header.Set("Range", "bytes=6-")
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusPartialContent {
+		t.Fatalf("GET returned wrong status code: got %v want %v", rr.Code, http.StatusPartialContent)
+	}
+
+	if body := rr.Body.String(); body != "bytes" {
+		t.Errorf("expected the requested range but got %s", body)
+	}
+
+	if contentType := rr.Header().Get("Content-Type"); contentType != "video/mp4" {
+		t.Errorf("expected content type video/mp4 but got %s", contentType)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("PUT", getURL, strings.NewReader("overwritten")))
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("PUT with a GET url returned wrong status code: got %v want %v", rr.Code, http.StatusForbidden)
+	}
+
+	missingURL, _ := store.SignedGetURL("videos", "missing.mp4", time.Minute)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", missingURL, nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("GET of a missing object returned wrong status code: got %v want %v", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestLocalFileHandlerUploadsParts(t *testing.T) {
+	store, cleanup := newLocalStore(t)
+	defer cleanup()
+
+	router := mux.NewRouter()
+	router.Methods("GET", "HEAD", "PUT").Path(storage.LocalStorePath + "/{bucket}/{key:.+}").Handler(storage.LocalFileHandler(fakes.FakeRenderer, store, 0))
+
+	uploadID, _ := store.CreateMultipartUpload("videos", "1-video.mp4", "video/mp4")
+	partURL, _ := store.SignedPartURL("videos", "1-video.mp4", uploadID, 1, time.Minute)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("PUT", partURL, strings.NewReader("video bytes")))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("PUT returned wrong status code: got %v want %v: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	etag := rr.Header().Get("ETag")
+	if len(etag) == 0 {
+		t.Fatal("expected the part's ETag in the response")
+	}
+
+	if err := store.CompleteMultipartUpload("videos", "1-video.mp4", uploadID, []*storage.CompletedPart{{PartNumber: 1, ETag: etag}}); err != nil {
+		t.Errorf("Unexpected error completing upload with the returned ETag: %v", err)
+	}
+}
+
+func TestLocalFileHandlerRejectsBodiesOverTheMaximumUploadSize(t *testing.T) {
+	store, cleanup := newLocalStore(t)
+	defer cleanup()
+
+	router := mux.NewRouter()
+	router.Methods("GET", "HEAD", "PUT").Path(storage.LocalStorePath + "/{bucket}/{key:.+}").Handler(storage.LocalFileHandler(fakes.FakeRenderer, store, 8))
+
+	putURL, _ := store.SignedPutURL("videos", "1-video.mp4", "video/mp4", time.Minute)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("PUT", putURL, strings.NewReader("too many video bytes")))
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("PUT returned wrong status code: got %v want %v", rr.Code, http.StatusRequestEntityTooLarge)
+	}
+
+	//without a content length the body is only cut off as it is read
+	req := httptest.NewRequest("PUT", putURL, strings.NewReader("too many video bytes"))
+	req.ContentLength = -1
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("chunked PUT returned wrong status code: got %v want %v", rr.Code, http.StatusRequestEntityTooLarge)
+	}
+
+	if _, err := store.Head("videos", "1-video.mp4"); err != storage.ErrNotFound {
+		t.Errorf("expected nothing to be stored but got %v", err)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("PUT", putURL, strings.NewReader("video")))
+	if rr.Code != http.StatusOK {
+		t.Errorf("PUT within the maximum returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,428 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//LocalStorePath is where the API serves the files of a LocalStore. The bucket and key follow
+const LocalStorePath = "/api/storage"
+
+const (
+	//tempFilePrefix marks files that are still being written by Put
+	tempFilePrefix = ".upload-"
+	//multipartDir holds the parts of multipart uploads, one sub directory per upload
+	multipartDir = ".multipart"
+)
+
+var (
+	//ErrInvalidSignature is returned by Verify when a url wasn't signed by the store, or has expired
+	ErrInvalidSignature = errors.New("invalid or expired signature")
+	//ErrInvalidKey is returned for buckets or keys that would escape the store's directory
+	ErrInvalidKey = errors.New("invalid bucket or key")
+	//ErrUploadNotFound is returned for multipart uploads that were never started for the bucket and key, or have already been completed or aborted
+	ErrUploadNotFound = errors.New("multipart upload not found")
+	//ErrInvalidPart is returned when completing a multipart upload with parts that are missing, out of order or don't match their ETag
+	ErrInvalidPart = errors.New("invalid multipart upload part")
+)
+
+//LocalStore keeps objects in a directory on the local filesystem, one sub directory per bucket. Its signed urls point back at the API, which serves them with LocalFileHandler
+type LocalStore struct {
+	dir     string
+	baseURL string
+	secret  []byte
+}
+
+//NewLocalStore keeps objects under dir and signs urls relative to baseURL, the address of this API, with secret
+func NewLocalStore(dir, baseURL string, secret []byte) *LocalStore {
+	return &LocalStore{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		secret:  secret,
+	}
+}
+
+//filePath is where the object at key is kept. Keys are always separated with / and can't contain .. segments
+func (s *LocalStore) filePath(bucket, key string) (string, error) {
+	if !validBucket(bucket) {
+		return "", ErrInvalidKey
+	}
+
+	for _, segment := range strings.Split(key, "/") {
+		if !validName(segment) {
+			return "", ErrInvalidKey
+		}
+	}
+
+	return filepath.Join(s.dir, bucket, filepath.FromSlash(key)), nil
+}
+
+//validBucket rejects names starting with . so buckets can't clash with multipartDir
+func validBucket(bucket string) bool {
+	return validName(bucket) && !strings.Contains(bucket, "/") && !strings.HasPrefix(bucket, ".")
+}
+
+func validName(name string) bool {
+	return len(name) > 0 && name != "." && name != ".." && !strings.ContainsAny(name, "\\\x00") && !strings.HasPrefix(name, tempFilePrefix)
+}
+
+func (s *LocalStore) Put(bucket, key string, body io.Reader, contentType string) error {
+	p, err := s.filePath(bucket, key)
+	if err != nil {
+		return err
+	}
+
+	if _, err := writeFile(p, body); err != nil {
+		return fmt.Errorf("Failed to write %s/%s: %v", bucket, key, err)
+	}
+
+	return nil
+}
+
+//writeFile writes body to a temp file first and then renames it to p, so a failed write never leaves a partial file behind. It returns the md5 of what was written
+func writeFile(p string, body io.Reader) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return "", err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(p), tempFilePrefix)
+	if err != nil {
+		return "", err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	hash := md5.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), body); err != nil {
+		tmp.Close()
+		return "", err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (s *LocalStore) Get(bucket, key string) (io.ReadCloser, error) {
+	f, _, err := s.Open(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (s *LocalStore) Delete(bucket, key string) error {
+	p, err := s.filePath(bucket, key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Failed to delete %s/%s: %v", bucket, key, err)
+	}
+
+	return nil
+}
+
+func (s *LocalStore) Head(bucket, key string) (*ObjectInfo, error) {
+	p, err := s.filePath(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(p)
+	if os.IsNotExist(err) || (err == nil && info.IsDir()) {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to head %s/%s: %v", bucket, key, err)
+	}
+
+	return &ObjectInfo{
+		Key:          key,
+		Size:         info.Size(),
+		ContentType:  contentTypeOf(key),
+		LastModified: info.ModTime(),
+	}, nil
+}
+
+//contentTypeOf guesses the content type from the key's extension, as the local filesystem has nowhere to keep the type given to Put
+func contentTypeOf(key string) string {
+	if contentType := mime.TypeByExtension(path.Ext(key)); len(contentType) > 0 {
+		return contentType
+	}
+
+	return "application/octet-stream"
+}
+
+func (s *LocalStore) SignedGetURL(bucket, key string, ttl time.Duration) (string, error) {
+	return s.signedURL(http.MethodGet, bucket, key, url.Values{}, ttl)
+}
+
+func (s *LocalStore) SignedPutURL(bucket, key, contentType string, ttl time.Duration) (string, error) {
+	return s.signedURL(http.MethodPut, bucket, key, url.Values{}, ttl)
+}
+
+func (s *LocalStore) SignedPartURL(bucket, key, uploadID string, partNumber int64, ttl time.Duration) (string, error) {
+	query := url.Values{}
+	query.Set("upload_id", uploadID)
+	query.Set("part_number", strconv.FormatInt(partNumber, 10))
+
+	return s.signedURL(http.MethodPut, bucket, key, query, ttl)
+}
+
+func (s *LocalStore) signedURL(method, bucket, key string, query url.Values, ttl time.Duration) (string, error) {
+	if _, err := s.filePath(bucket, key); err != nil {
+		return "", err
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(method, bucket, key, query))
+
+	return fmt.Sprintf("%s%s/%s/%s?%s", s.baseURL, LocalStorePath, url.PathEscape(bucket), strings.Join(segments, "/"), query.Encode()), nil
+}
+
+//sign covers the method, bucket and key along with the expiry and multipart upload params of the query
+func (s *LocalStore) sign(method, bucket, key string, query url.Values) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%s\n%s\n%s", method, bucket, key, query.Get("expires"), query.Get("upload_id"), query.Get("part_number"))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+//Verify checks the expires and signature query params of a signed url for method on bucket and key
+func (s *LocalStore) Verify(method, bucket, key string, query url.Values) error {
+	expiresAt, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return ErrInvalidSignature
+	}
+
+	signature, err := hex.DecodeString(query.Get("signature"))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	expected, _ := hex.DecodeString(s.sign(method, bucket, key, query))
+	if !hmac.Equal(signature, expected) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+//Open opens the object at key for reading, or returns ErrNotFound
+func (s *LocalStore) Open(bucket, key string) (*os.File, *ObjectInfo, error) {
+	info, err := s.Head(bucket, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p, _ := s.filePath(bucket, key)
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to open %s/%s: %v", bucket, key, err)
+	}
+
+	return f, info, nil
+}
+
+func (s *LocalStore) List(bucket, prefix string) ([]*ObjectInfo, error) {
+	var list []*ObjectInfo
+
+	if !validBucket(bucket) {
+		return list, ErrInvalidKey
+	}
+
+	root := filepath.Join(s.dir, bucket)
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == root {
+				return filepath.SkipDir
+			}
+
+			return err
+		}
+
+		if info.IsDir() || strings.HasPrefix(info.Name(), tempFilePrefix) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		list = append(list, &ObjectInfo{
+			Key:          key,
+			Size:         info.Size(),
+			ContentType:  contentTypeOf(key),
+			LastModified: info.ModTime(),
+		})
+
+		return nil
+	})
+
+	if err != nil {
+		return list, fmt.Errorf("Failed to list %s/%s: %v", bucket, prefix, err)
+	}
+
+	//the walk is in file path order, which differs from key order when keys contain characters that sort before /
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Key < list[j].Key
+	})
+
+	return list, nil
+}
+
+func (s *LocalStore) CreateMultipartUpload(bucket, key, contentType string) (string, error) {
+	if _, err := s.filePath(bucket, key); err != nil {
+		return "", err
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("Failed to generate upload id: %v", err)
+	}
+
+	uploadID := hex.EncodeToString(id)
+	dir := filepath.Join(s.dir, multipartDir, uploadID)
+	if _, err := writeFile(filepath.Join(dir, "target"), strings.NewReader(bucket+"\n"+key)); err != nil {
+		return "", fmt.Errorf("Failed to create multipart upload of %s/%s: %v", bucket, key, err)
+	}
+
+	return uploadID, nil
+}
+
+//uploadDir is where the parts of the upload are kept, as long as the upload was started for bucket and key
+func (s *LocalStore) uploadDir(bucket, key, uploadID string) (string, error) {
+	if _, err := hex.DecodeString(uploadID); err != nil || len(uploadID) == 0 {
+		return "", ErrUploadNotFound
+	}
+
+	dir := filepath.Join(s.dir, multipartDir, uploadID)
+	target, err := ioutil.ReadFile(filepath.Join(dir, "target"))
+	if os.IsNotExist(err) || (err == nil && string(target) != bucket+"\n"+key) {
+		return "", ErrUploadNotFound
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to read multipart upload %s: %v", uploadID, err)
+	}
+
+	return dir, nil
+}
+
+func partFile(dir string, partNumber int64) string {
+	return filepath.Join(dir, strconv.FormatInt(partNumber, 10))
+}
+
+//PutPart writes body as a part of the upload, returning its ETag
+func (s *LocalStore) PutPart(bucket, key, uploadID string, partNumber int64, body io.Reader) (string, error) {
+	if partNumber < 1 {
+		return "", ErrInvalidPart
+	}
+
+	dir, err := s.uploadDir(bucket, key, uploadID)
+	if err != nil {
+		return "", err
+	}
+
+	sum, err := writeFile(partFile(dir, partNumber), body)
+	if err != nil {
+		return "", fmt.Errorf("Failed to write part %d of %s/%s: %v", partNumber, bucket, key, err)
+	}
+
+	etag := strconv.Quote(sum)
+	if _, err := writeFile(partFile(dir, partNumber)+".etag", strings.NewReader(etag)); err != nil {
+		return "", fmt.Errorf("Failed to write part %d of %s/%s: %v", partNumber, bucket, key, err)
+	}
+
+	return etag, nil
+}
+
+func (s *LocalStore) CompleteMultipartUpload(bucket, key, uploadID string, parts []*CompletedPart) error {
+	p, err := s.filePath(bucket, key)
+	if err != nil {
+		return err
+	}
+
+	dir, err := s.uploadDir(bucket, key, uploadID)
+	if err != nil {
+		return err
+	}
+
+	if len(parts) == 0 {
+		return ErrInvalidPart
+	}
+
+	var readers []io.Reader
+	for i, part := range parts {
+		if i > 0 && part.PartNumber <= parts[i-1].PartNumber {
+			return ErrInvalidPart
+		}
+
+		etag, err := ioutil.ReadFile(partFile(dir, part.PartNumber) + ".etag")
+		if err != nil || strings.Trim(string(etag), `"`) != strings.Trim(part.ETag, `"`) {
+			return ErrInvalidPart
+		}
+
+		f, err := os.Open(partFile(dir, part.PartNumber))
+		if err != nil {
+			return fmt.Errorf("Failed to read part %d of %s/%s: %v", part.PartNumber, bucket, key, err)
+		}
+
+		defer f.Close()
+		readers = append(readers, f)
+	}
+
+	if _, err := writeFile(p, io.MultiReader(readers...)); err != nil {
+		return fmt.Errorf("Failed to write %s/%s: %v", bucket, key, err)
+	}
+
+	return os.RemoveAll(dir)
+}
+
+func (s *LocalStore) AbortMultipartUpload(bucket, key, uploadID string) error {
+	dir, err := s.uploadDir(bucket, key, uploadID)
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(dir)
+}
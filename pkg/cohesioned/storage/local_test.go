@@ -0,0 +1,173 @@
+package storage_test
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned/storage"
+)
+
+func newLocalStore(t *testing.T) (*storage.LocalStore, func()) {
+	dir, err := ioutil.TempDir("", "local-store")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+
+	store := storage.NewLocalStore(dir, "http://localhost:3001/", []byte("test-secret"))
+	return store, func() { os.RemoveAll(dir) }
+}
+
+func TestLocalStore(t *testing.T) {
+	store, cleanup := newLocalStore(t)
+	defer cleanup()
+
+	if err := store.Put("videos", "1-video.mp4", strings.NewReader("video"), "video/mp4"); err != nil {
+		t.Fatalf("Unexpected error putting object: %v", err)
+	}
+
+	if err := store.Put("videos", "transcoded/480p-1-video.mp4", strings.NewReader("rendition"), "video/mp4"); err != nil {
+		t.Fatalf("Unexpected error putting object: %v", err)
+	}
+
+	info, err := store.Head("videos", "1-video.mp4")
+	if err != nil {
+		t.Fatalf("Unexpected error heading object: %v", err)
+	}
+
+	if info.Size != 5 || info.ContentType != "video/mp4" {
+		t.Errorf("unexpected object info %v", info)
+	}
+
+	list, err := store.List("videos", "transcoded/")
+	if err != nil {
+		t.Fatalf("Unexpected error listing objects: %v", err)
+	}
+
+	if len(list) != 1 || list[0].Key != "transcoded/480p-1-video.mp4" {
+		t.Errorf("expected only the transcoded object to be listed but got %v", list)
+	}
+
+	if err := store.Delete("videos", "1-video.mp4"); err != nil {
+		t.Fatalf("Unexpected error deleting object: %v", err)
+	}
+
+	if _, err := store.Head("videos", "1-video.mp4"); err != storage.ErrNotFound {
+		t.Errorf("expected ErrNotFound after delete but got %v", err)
+	}
+
+	if err := store.Delete("videos", "1-video.mp4"); err != nil {
+		t.Errorf("deleting a missing object should not be an error: %v", err)
+	}
+}
+
+func TestLocalStoreRejectsKeysOutsideItsDirectory(t *testing.T) {
+	store, cleanup := newLocalStore(t)
+	defer cleanup()
+
+	for _, key := range []string{"../secret", "a/../../secret", "/etc/passwd", ""} {
+		if err := store.Put("videos", key, strings.NewReader("x"), ""); err != storage.ErrInvalidKey {
+			t.Errorf("expected %q to be rejected but got %v", key, err)
+		}
+	}
+
+	if _, err := store.List("..", ""); err != storage.ErrInvalidKey {
+		t.Errorf("expected bucket .. to be rejected but got %v", err)
+	}
+}
+
+func TestLocalStoreSignedURLs(t *testing.T) {
+	store, cleanup := newLocalStore(t)
+	defer cleanup()
+
+	signedURL, err := store.SignedGetURL("videos", "transcoded/480p 1.mp4", time.Minute)
+	if err != nil {
+		t.Fatalf("Unexpected error signing url: %v", err)
+	}
+
+	u, err := url.Parse(signedURL)
+	if err != nil {
+		t.Fatalf("Failed to parse signed url %s: %v", signedURL, err)
+	}
+
+	if expected := storage.LocalStorePath + "/videos/transcoded/480p 1.mp4"; u.Path != expected {
+		t.Errorf("expected path %s but got %s", expected, u.Path)
+	}
+
+	if err := store.Verify("GET", "videos", "transcoded/480p 1.mp4", u.Query()); err != nil {
+		t.Errorf("expected the signed url to verify: %v", err)
+	}
+
+	if err := store.Verify("PUT", "videos", "transcoded/480p 1.mp4", u.Query()); err != storage.ErrInvalidSignature {
+		t.Errorf("a url signed for GET should not allow PUT: %v", err)
+	}
+
+	if err := store.Verify("GET", "videos", "1-video.mp4", u.Query()); err != storage.ErrInvalidSignature {
+		t.Errorf("a url signed for one key should not allow another: %v", err)
+	}
+
+	expired, _ := store.SignedGetURL("videos", "1-video.mp4", -time.Minute)
+	u, _ = url.Parse(expired)
+	if err := store.Verify("GET", "videos", "1-video.mp4", u.Query()); err != storage.ErrInvalidSignature {
+		t.Errorf("an expired url should not verify: %v", err)
+	}
+}
+
+func TestLocalStoreMultipartUpload(t *testing.T) {
+	store, cleanup := newLocalStore(t)
+	defer cleanup()
+
+	uploadID, err := store.CreateMultipartUpload("videos", "1-video.mp4", "video/mp4")
+	if err != nil {
+		t.Fatalf("Unexpected error creating upload: %v", err)
+	}
+
+	var parts []*storage.CompletedPart
+	for i, chunk := range []string{"first ", "second"} {
+		etag, err := store.PutPart("videos", "1-video.mp4", uploadID, int64(i+1), strings.NewReader(chunk))
+		if err != nil {
+			t.Fatalf("Unexpected error putting part %d: %v", i+1, err)
+		}
+
+		parts = append(parts, &storage.CompletedPart{PartNumber: int64(i + 1), ETag: etag})
+	}
+
+	if _, err := store.PutPart("videos", "2-video.mp4", uploadID, 1, strings.NewReader("x")); err != storage.ErrUploadNotFound {
+		t.Errorf("expected parts for another key to be rejected but got %v", err)
+	}
+
+	badETag := []*storage.CompletedPart{{PartNumber: 1, ETag: "nope"}, parts[1]}
+	if err := store.CompleteMultipartUpload("videos", "1-video.mp4", uploadID, badETag); err != storage.ErrInvalidPart {
+		t.Errorf("expected a part with the wrong etag to be rejected but got %v", err)
+	}
+
+	outOfOrder := []*storage.CompletedPart{parts[1], parts[0]}
+	if err := store.CompleteMultipartUpload("videos", "1-video.mp4", uploadID, outOfOrder); err != storage.ErrInvalidPart {
+		t.Errorf("expected parts out of order to be rejected but got %v", err)
+	}
+
+	if err := store.CompleteMultipartUpload("videos", "1-video.mp4", uploadID, parts); err != nil {
+		t.Fatalf("Unexpected error completing upload: %v", err)
+	}
+
+	f, _, err := store.Open("videos", "1-video.mp4")
+	if err != nil {
+		t.Fatalf("Unexpected error opening the completed object: %v", err)
+	}
+
+	defer f.Close()
+	if data, _ := ioutil.ReadAll(f); string(data) != "first second" {
+		t.Errorf("expected the parts to be joined in order but got %s", string(data))
+	}
+
+	if err := store.AbortMultipartUpload("videos", "1-video.mp4", uploadID); err != storage.ErrUploadNotFound {
+		t.Errorf("expected a completed upload to be gone but got %v", err)
+	}
+
+	if list, _ := store.List("videos", ""); len(list) != 1 {
+		t.Errorf("expected only the completed object to be listed but got %v", list)
+	}
+}
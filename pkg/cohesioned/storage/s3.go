@@ -0,0 +1,313 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/cohesion-education/api/pkg/cohesioned/config"
+)
+
+type s3Store struct {
+	cfg config.AwsConfig
+}
+
+//NewS3Store keeps objects in the S3 buckets of the account configured by cfg
+func NewS3Store(cfg config.AwsConfig) BlobStore {
+	return &s3Store{cfg: cfg}
+}
+
+func (s *s3Store) client() (*s3.S3, error) {
+	sess, err := s.cfg.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("Error creating session %v", err)
+	}
+
+	return s3.New(sess), nil
+}
+
+func (s *s3Store) Put(bucket, key string, body io.Reader, contentType string) error {
+	svc, err := s.client()
+	if err != nil {
+		return err
+	}
+
+	params := &s3manager.UploadInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   body,
+	}
+
+	if len(contentType) > 0 {
+		params.ContentType = aws.String(contentType)
+	}
+
+	uploader := s3manager.NewUploaderWithClient(svc)
+
+	if _, err := uploader.Upload(params, func(u *s3manager.Uploader) {
+		u.PartSize = 10 * 1024 * 1024 // 10MB part size
+		u.LeavePartsOnError = false
+	}); err != nil {
+		return fmt.Errorf("Failed to upload file to s3: %v", err)
+	}
+
+	return nil
+}
+
+func (s *s3Store) Get(bucket, key string) (io.ReadCloser, error) {
+	svc, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := svc.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("Failed to get %s/%s: %v", bucket, key, err)
+	}
+
+	return resp.Body, nil
+}
+
+func (s *s3Store) Delete(bucket, key string) error {
+	svc, err := s.client()
+	if err != nil {
+		return err
+	}
+
+	deleteInput := &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+
+	if _, err := svc.DeleteObject(deleteInput); err != nil {
+		return fmt.Errorf("Failed to delete %s/%s: %v", bucket, key, err)
+	}
+
+	return nil
+}
+
+func (s *s3Store) Head(bucket, key string) (*ObjectInfo, error) {
+	svc, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := svc.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NotFound" {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("Failed to head %s/%s: %v", bucket, key, err)
+	}
+
+	return &ObjectInfo{
+		Key:          key,
+		Size:         aws.Int64Value(resp.ContentLength),
+		ContentType:  aws.StringValue(resp.ContentType),
+		LastModified: aws.TimeValue(resp.LastModified),
+	}, nil
+}
+
+func (s *s3Store) SignedGetURL(bucket, key string, ttl time.Duration) (string, error) {
+	svc, err := s.client()
+	if err != nil {
+		return "", err
+	}
+
+	req, _ := svc.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+
+	signedURL, err := req.Presign(ttl)
+	if err != nil {
+		return "", fmt.Errorf("Failed to sign request: %v", err)
+	}
+
+	return signedURL, nil
+}
+
+func (s *s3Store) SignedPutURL(bucket, key, contentType string, ttl time.Duration) (string, error) {
+	svc, err := s.client()
+	if err != nil {
+		return "", err
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+
+	if len(contentType) > 0 {
+		input.ContentType = aws.String(contentType)
+	}
+
+	req, _ := svc.PutObjectRequest(input)
+	signedURL, err := req.Presign(ttl)
+	if err != nil {
+		return "", fmt.Errorf("Failed to sign request: %v", err)
+	}
+
+	return signedURL, nil
+}
+
+func (s *s3Store) List(bucket, prefix string) ([]*ObjectInfo, error) {
+	var list []*ObjectInfo
+
+	svc, err := s.client()
+	if err != nil {
+		return list, err
+	}
+
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	}
+
+	err = svc.ListObjectsV2Pages(input, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			list = append(list, &ObjectInfo{
+				Key:          aws.StringValue(obj.Key),
+				Size:         aws.Int64Value(obj.Size),
+				LastModified: aws.TimeValue(obj.LastModified),
+			})
+		}
+
+		return true
+	})
+
+	if err != nil {
+		return list, fmt.Errorf("Failed to list %s/%s: %v", bucket, prefix, err)
+	}
+
+	return list, nil
+}
+
+func (s *s3Store) CreateMultipartUpload(bucket, key, contentType string) (string, error) {
+	svc, err := s.client()
+	if err != nil {
+		return "", err
+	}
+
+	input := &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+
+	if len(contentType) > 0 {
+		input.ContentType = aws.String(contentType)
+	}
+
+	resp, err := svc.CreateMultipartUpload(input)
+	if err != nil {
+		return "", fmt.Errorf("Failed to create multipart upload of %s/%s: %v", bucket, key, err)
+	}
+
+	return aws.StringValue(resp.UploadId), nil
+}
+
+func (s *s3Store) SignedPartURL(bucket, key, uploadID string, partNumber int64, ttl time.Duration) (string, error) {
+	svc, err := s.client()
+	if err != nil {
+		return "", err
+	}
+
+	req, _ := svc.UploadPartRequest(&s3.UploadPartInput{
+		Bucket:     aws.String(bucket),
+		Key:        aws.String(key),
+		UploadId:   aws.String(uploadID),
+		PartNumber: aws.Int64(partNumber),
+	})
+
+	signedURL, err := req.Presign(ttl)
+	if err != nil {
+		return "", fmt.Errorf("Failed to sign request: %v", err)
+	}
+
+	return signedURL, nil
+}
+
+func (s *s3Store) CompleteMultipartUpload(bucket, key, uploadID string, parts []*CompletedPart) error {
+	svc, err := s.client()
+	if err != nil {
+		return err
+	}
+
+	upload := &s3.CompletedMultipartUpload{}
+	for _, part := range parts {
+		upload.Parts = append(upload.Parts, &s3.CompletedPart{
+			ETag:       aws.String(part.ETag),
+			PartNumber: aws.Int64(part.PartNumber),
+		})
+	}
+
+	if _, err := svc.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: upload,
+	}); err != nil {
+		if mapped := multipartErr(err); mapped != nil {
+			return mapped
+		}
+
+		return fmt.Errorf("Failed to complete multipart upload of %s/%s: %v", bucket, key, err)
+	}
+
+	return nil
+}
+
+func (s *s3Store) AbortMultipartUpload(bucket, key, uploadID string) error {
+	svc, err := s.client()
+	if err != nil {
+		return err
+	}
+
+	if _, err := svc.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	}); err != nil {
+		if mapped := multipartErr(err); mapped != nil {
+			return mapped
+		}
+
+		return fmt.Errorf("Failed to abort multipart upload of %s/%s: %v", bucket, key, err)
+	}
+
+	return nil
+}
+
+//multipartErr returns the sentinel error matching the S3 error code of a failed multipart upload request, or nil if there isn't one
+func multipartErr(err error) error {
+	aerr, ok := err.(awserr.Error)
+	if !ok {
+		return nil
+	}
+
+	switch aerr.Code() {
+	case "NoSuchUpload":
+		return ErrUploadNotFound
+	case "InvalidPart", "InvalidPartOrder":
+		return ErrInvalidPart
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+)
+
+type fakeAwsErr struct {
+	code string
+}
+
+func (e fakeAwsErr) Error() string   { return e.code }
+func (e fakeAwsErr) Code() string    { return e.code }
+func (e fakeAwsErr) Message() string { return e.code }
+func (e fakeAwsErr) OrigErr() error  { return nil }
+
+func TestMultipartErr(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected error
+	}{
+		{fakeAwsErr{"NoSuchUpload"}, ErrUploadNotFound},
+		{fakeAwsErr{"InvalidPart"}, ErrInvalidPart},
+		{fakeAwsErr{"InvalidPartOrder"}, ErrInvalidPart},
+		{fakeAwsErr{"AccessDenied"}, nil},
+		{errors.New("connection reset"), nil},
+	}
+
+	for _, test := range tests {
+		if err := multipartErr(test.err); err != test.expected {
+			t.Errorf("multipartErr(%v) = %v, expected %v", test.err, err, test.expected)
+		}
+	}
+}
@@ -92,12 +92,9 @@ func (repo *awsRepo) Update(s *cohesioned.Student) error {
 	return nil
 }
 
-func (repo *awsRepo) List() ([]*cohesioned.Student, error) {
-	var list []*cohesioned.Student
-
-	query := `
-	select
-	  id,
+var listQuery = db.ListQuery{
+	Select: `select
+		id,
 		name,
 		grade,
 		school,
@@ -105,30 +102,51 @@ func (repo *awsRepo) List() ([]*cohesioned.Student, error) {
 		created,
 		created_by,
 		updated,
-		updated_by
-	from
-		student`
+		updated_by`,
+	From: `from student`,
+	Columns: map[string]string{
+		"id":      "id",
+		"name":    "name",
+		"grade":   "grade",
+		"school":  "school",
+		"user_id": "user_id",
+		"created": "created",
+	},
+}
+
+func (repo *awsRepo) List(opts *cohesioned.ListOptions) ([]*cohesioned.Student, int64, error) {
+	var list []*cohesioned.Student
+	var total int64
 
-	rows, err := repo.Query(query)
+	query, args, countQuery, countArgs, err := listQuery.Build(opts)
 	if err != nil {
-		return list, fmt.Errorf("Failed to list students: %v", err)
+		return list, total, err
+	}
+
+	if err := repo.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		return list, total, fmt.Errorf("Failed to count students: %v", err)
+	}
+
+	rows, err := repo.Query(query, args...)
+	if err != nil {
+		return list, total, fmt.Errorf("Failed to list students: %v", err)
 	}
 
 	defer rows.Close()
 	for rows.Next() {
 		student, err := repo.mapRowToObject(rows)
 		if err != nil {
-			return list, fmt.Errorf("an unexpected error occurred while processing the result set from the db: %v", err)
+			return list, total, fmt.Errorf("an unexpected error occurred while processing the result set from the db: %v", err)
 		}
 
 		list = append(list, student)
 	}
 
 	if err := rows.Err(); err != nil {
-		return list, fmt.Errorf("db rows returned unexpected error: %v", err)
+		return list, total, fmt.Errorf("db rows returned unexpected error: %v", err)
 	}
 
-	return list, nil
+	return list, total, nil
 }
 
 func (repo *awsRepo) FindByUserID(parentID int64) ([]*cohesioned.Student, error) {
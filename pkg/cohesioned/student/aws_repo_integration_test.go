@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
 	"github.com/cohesion-education/api/pkg/cohesioned/config"
 	"github.com/cohesion-education/api/pkg/cohesioned/student"
 	"github.com/cohesion-education/api/testutils"
@@ -47,7 +48,10 @@ func TestMain(m *testing.M) {
 
 func TestRepoList(t *testing.T) {
 	parentID := int64(1)
-	list, err := repo.List(parentID)
+	opts := cohesioned.NewListOptions(student.ListSpec)
+	opts.Filters = append(opts.Filters, cohesioned.Filter{Field: "user_id", Op: "=", Value: parentID})
+
+	list, total, err := repo.List(opts)
 	if err != nil {
 		t.Errorf("Failed to list student: %v", err)
 	}
@@ -56,6 +60,10 @@ func TestRepoList(t *testing.T) {
 		t.Errorf("student list is empty")
 	}
 
+	if total != int64(len(list)) {
+		t.Errorf("expected total %d to match the %d students listed", total, len(list))
+	}
+
 	for _, student := range list {
 		if len(student.Name) == 0 {
 			t.Error("student name is empty")
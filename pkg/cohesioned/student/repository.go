@@ -6,8 +6,20 @@ import (
 
 type Repo interface {
 	FindByUserID(parentID int64) ([]*cohesioned.Student, error)
-	List() ([]*cohesioned.Student, error)
+	List(opts *cohesioned.ListOptions) ([]*cohesioned.Student, int64, error)
 	Save(s *cohesioned.Student) (int64, error)
 	Update(s *cohesioned.Student) error
 	Delete(id int64) error
 }
+
+//ListSpec is how the student list can be sorted and filtered. user_id filters by parent
+var ListSpec = cohesioned.ListSpec{
+	SortFields:  []string{"id", "name", "grade", "school", "created"},
+	DefaultSort: "-created",
+	Filters: map[string]cohesioned.FilterType{
+		"user_id": cohesioned.FilterInt,
+		"grade":   cohesioned.FilterString,
+		"school":  cohesioned.FilterString,
+		"created": cohesioned.FilterDateRange,
+	},
+}
@@ -0,0 +1,61 @@
+package cohesioned
+
+import "strings"
+
+//TagKind is what a video tag describes
+type TagKind string
+
+const (
+	TagKeyTerm            TagKind = "key_term"
+	TagStateStandard      TagKind = "state_standard"
+	TagCommonCoreStandard TagKind = "common_core_standard"
+)
+
+//StandardTagKinds are the kinds of tag that are educational standards
+var StandardTagKinds = []TagKind{TagStateStandard, TagCommonCoreStandard}
+
+//Tag is a key term or standard along with the number of videos tagged with it
+type Tag struct {
+	ID         int64   `json:"id"`
+	Kind       TagKind `json:"kind"`
+	Name       string  `json:"name"`
+	VideoCount int64   `json:"video_count"`
+}
+
+//Tags returns the video's key terms and standards by kind, trimmed and without blanks or duplicates
+func (v *Video) Tags() map[TagKind][]string {
+	return map[TagKind][]string{
+		TagKeyTerm:            cleanTagNames(v.KeyTerms),
+		TagStateStandard:      cleanTagNames(v.StateStandards),
+		TagCommonCoreStandard: cleanTagNames(v.CommonCoreStandards),
+	}
+}
+
+//AddTag appends name to the video's list of the given kind
+func (v *Video) AddTag(kind TagKind, name string) {
+	switch kind {
+	case TagKeyTerm:
+		v.KeyTerms = append(v.KeyTerms, name)
+	case TagStateStandard:
+		v.StateStandards = append(v.StateStandards, name)
+	case TagCommonCoreStandard:
+		v.CommonCoreStandards = append(v.CommonCoreStandards, name)
+	}
+}
+
+func cleanTagNames(names []string) []string {
+	var cleaned []string
+	seen := make(map[string]bool)
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if len(name) == 0 || seen[name] {
+			continue
+		}
+
+		seen[name] = true
+		cleaned = append(cleaned, name)
+	}
+
+	return cleaned
+}
@@ -0,0 +1,39 @@
+package cohesioned
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+//Teacher is an instructor who presents videos. Unlike Video.CreatedBy, who uploaded the video, a teacher doesn't need a Profile
+type Teacher struct {
+	Validatable
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Bio         string    `json:"bio"`
+	AvatarURL   string    `json:"avatar_url"`
+	Credentials string    `json:"credentials"`
+	Created     time.Time `json:"created"`
+	CreatedBy   int64     `json:"created_by"`
+	Updated     time.Time `json:"updated"`
+	UpdatedBy   int64     `json:"updated_by"`
+}
+
+func (t *Teacher) Validate() bool {
+	t.Name = strings.TrimSpace(t.Name)
+	t.AvatarURL = strings.TrimSpace(t.AvatarURL)
+
+	if len(t.Name) == 0 {
+		t.AddValidationError("name", "name is required")
+	}
+
+	if len(t.AvatarURL) > 0 {
+		u, err := url.Parse(t.AvatarURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+			t.AddValidationError("avatar_url", "avatar_url must be an http or https url")
+		}
+	}
+
+	return len(t.ValidationErrors) == 0
+}
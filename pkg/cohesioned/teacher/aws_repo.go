@@ -0,0 +1,220 @@
+package teacher
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/db"
+)
+
+type awsRepo struct {
+	*sql.DB
+}
+
+func NewAwsRepo(db *sql.DB) Repo {
+	return &awsRepo{
+		DB: db,
+	}
+}
+
+const selectQuery = `select
+		id,
+		name,
+		bio,
+		avatar_url,
+		credentials,
+		created,
+		created_by,
+		updated,
+		updated_by
+	from teacher`
+
+var listQuery = db.ListQuery{
+	Select: `select
+		id,
+		name,
+		bio,
+		avatar_url,
+		credentials,
+		created,
+		created_by,
+		updated,
+		updated_by`,
+	From: `from teacher`,
+	Columns: map[string]string{
+		"id":      "id",
+		"name":    "name",
+		"created": "created",
+	},
+}
+
+func (repo *awsRepo) List(opts *cohesioned.ListOptions) ([]*cohesioned.Teacher, int64, error) {
+	var list []*cohesioned.Teacher
+	var total int64
+
+	selectQuery, args, countQuery, countArgs, err := listQuery.Build(opts)
+	if err != nil {
+		return list, total, err
+	}
+
+	if err := repo.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		return list, total, fmt.Errorf("Failed to count teachers: %v", err)
+	}
+
+	list, err = repo.query(selectQuery, args...)
+	return list, total, err
+}
+
+func (repo *awsRepo) Get(id int64) (*cohesioned.Teacher, error) {
+	row := repo.QueryRow(selectQuery+` where id = ?`, id)
+
+	t, err := repo.mapRowToObject(row)
+	if err != nil {
+		return nil, fmt.Errorf("Unexpected error querying for teacher by id %d: %v", id, err)
+	}
+
+	return t, nil
+}
+
+//FindByIDs returns the teachers with one of the given IDs. IDs without a teacher are skipped
+func (repo *awsRepo) FindByIDs(ids []int64) ([]*cohesioned.Teacher, error) {
+	if len(ids) == 0 {
+		return []*cohesioned.Teacher{}, nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	return repo.query(fmt.Sprintf(`%s where id in (%s)`, selectQuery, db.Placeholders(len(ids))), args...)
+}
+
+func (repo *awsRepo) query(query string, args ...interface{}) ([]*cohesioned.Teacher, error) {
+	var list []*cohesioned.Teacher
+
+	rows, err := repo.Query(query, args...)
+	if err != nil {
+		return list, fmt.Errorf("Failed to execute query: %v", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		t, err := repo.mapRowToObject(rows)
+		if err != nil {
+			return list, fmt.Errorf("an unexpected error occurred while processing the result set from the db: %v", err)
+		}
+
+		list = append(list, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return list, fmt.Errorf("rows had an error: %v", err)
+	}
+
+	return list, nil
+}
+
+func (repo *awsRepo) Save(t *cohesioned.Teacher) (int64, error) {
+	sql := `insert into teacher
+	(
+		name,
+		bio,
+		avatar_url,
+		credentials,
+		created,
+		created_by
+	) values (?, ?, ?, ?, ?, ?)`
+
+	result, err := repo.Exec(sql, t.Name, t.Bio, t.AvatarURL, t.Credentials, t.Created, t.CreatedBy)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to insert teacher %s: %v", t.Name, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get last insert id from result: %v", err)
+	}
+
+	return id, nil
+}
+
+func (repo *awsRepo) Update(t *cohesioned.Teacher) error {
+	sql := `update teacher set
+		name = ?,
+		bio = ?,
+		avatar_url = ?,
+		credentials = ?,
+		updated = ?,
+		updated_by = ?
+	where
+		id = ?`
+
+	result, err := repo.Exec(sql, t.Name, t.Bio, t.AvatarURL, t.Credentials, t.Updated, t.UpdatedBy, t.ID)
+	if err != nil {
+		return fmt.Errorf("Failed to update teacher %d: %v", t.ID, err)
+	}
+
+	rowsEffected, err := result.RowsAffected()
+	if err != nil || rowsEffected == 0 {
+		return fmt.Errorf("Failed to update teacher %d: %v", t.ID, err)
+	}
+
+	return nil
+}
+
+//Delete deletes the teacher, unlinking them from their videos
+func (repo *awsRepo) Delete(id int64) error {
+	result, err := repo.Exec(`delete from teacher where id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("Failed to delete teacher with id %d: %v", id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to get number of rows affected from result: %v", err)
+	}
+
+	if rowsAffected != 1 {
+		return fmt.Errorf("Failed to delete teacher with id %d (rows affected != 1)", id)
+	}
+
+	return nil
+}
+
+func (repo *awsRepo) mapRowToObject(rs db.RowScanner) (*cohesioned.Teacher, error) {
+	t := new(cohesioned.Teacher)
+
+	var bio, avatarURL, credentials sql.NullString
+	var updated db.NullTime
+	var updatedBy sql.NullInt64
+
+	err := rs.Scan(
+		&t.ID,
+		&t.Name,
+		&bio,
+		&avatarURL,
+		&credentials,
+		&t.Created,
+		&t.CreatedBy,
+		&updated,
+		&updatedBy,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	t.Bio = bio.String
+	t.AvatarURL = avatarURL.String
+	t.Credentials = credentials.String
+	t.Updated = updated.Time
+	t.UpdatedBy = updatedBy.Int64
+
+	return t, nil
+}
@@ -0,0 +1,197 @@
+package teacher
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/gorilla/mux"
+	"github.com/unrolled/render"
+)
+
+type TeacherResponse struct {
+	*cohesioned.APIResponse
+	Teacher *cohesioned.Teacher `json:"teacher,omitempty"`
+}
+
+func NewTeacherResponse() *TeacherResponse {
+	return &TeacherResponse{
+		APIResponse: &cohesioned.APIResponse{},
+	}
+}
+
+func ListHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &cohesioned.APIResponse{}
+		opts := cohesioned.ListOptionsFromRequest(req, ListSpec, resp)
+		if len(resp.ValidationErrors) > 0 {
+			resp.SetErrMsg("Invalid list parameters")
+			r.JSON(w, http.StatusBadRequest, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		list, total, err := repo.List(opts)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred listing teachers %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		if list == nil {
+			list = []*cohesioned.Teacher{}
+		}
+
+		r.JSON(w, http.StatusOK, cohesioned.NewPage(req, opts, list, total))
+	}
+}
+
+//GetHandler returns the teacher's profile. Their lessons are listed by /api/videos/by_teacher/{teacher_id}
+func GetHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		t, resp, status := findTeacherFromPath(req, repo)
+		resp.Teacher = t
+		r.JSON(w, status, resp)
+	}
+}
+
+func AddHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := NewTeacherResponse()
+
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		defer req.Body.Close()
+		t := &cohesioned.Teacher{}
+		if err := json.NewDecoder(req.Body).Decode(t); err != nil {
+			resp.SetErrMsg("failed to unmarshall json %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		if !t.Validate() {
+			resp.Teacher = t
+			resp.SetErrMsg("Hmmm... you seem to be missing some required fields")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		t.Created = time.Now()
+		t.CreatedBy = currentUser.ID
+
+		id, err := repo.Save(t)
+		if err != nil {
+			resp.SetErrMsg("Failed to save teacher: %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		t.ID = id
+		resp.Teacher = t
+		r.JSON(w, http.StatusCreated, resp)
+	}
+}
+
+func UpdateHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		existing, resp, status := findTeacherFromPath(req, repo)
+		if existing == nil {
+			r.JSON(w, status, resp)
+			return
+		}
+
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		defer req.Body.Close()
+		id := existing.ID
+		if err := json.NewDecoder(req.Body).Decode(existing); err != nil {
+			resp.SetErrMsg("failed to unmarshall json %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		existing.ID = id
+		if !existing.Validate() {
+			resp.Teacher = existing
+			resp.SetErrMsg("Hmmm... you seem to be missing some required fields")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		existing.Updated = time.Now()
+		existing.UpdatedBy = currentUser.ID
+
+		if err := repo.Update(existing); err != nil {
+			resp.SetErrMsg("Failed to update teacher %d: %v", existing.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		resp.Teacher = existing
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//DeleteHandler deletes the teacher. Their videos are kept but no longer credit them
+func DeleteHandler(r *render.Render, repo Repo) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		t, resp, status := findTeacherFromPath(req, repo)
+		if t == nil {
+			r.JSON(w, status, resp)
+			return
+		}
+
+		if err := repo.Delete(t.ID); err != nil {
+			resp.SetErrMsg("Failed to delete teacher %d: %v", t.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func findTeacherFromPath(req *http.Request, repo Repo) (*cohesioned.Teacher, *TeacherResponse, int) {
+	vars := mux.Vars(req)
+	resp := NewTeacherResponse()
+
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		resp.SetErrMsg("%s is not a valid id %v", vars["id"], err)
+		return nil, resp, http.StatusBadRequest
+	}
+
+	t, err := repo.Get(id)
+	if err != nil {
+		resp.SetErrMsg("An unexpected error occurred when trying to find teacher %d: %v", id, err)
+		fmt.Println(resp.ErrMsg)
+		return nil, resp, http.StatusInternalServerError
+	}
+
+	if t == nil {
+		resp.SetErrMsg("%d is not a valid teacher id", id)
+		return nil, resp, http.StatusNotFound
+	}
+
+	return t, resp, http.StatusOK
+}
@@ -0,0 +1,120 @@
+package teacher_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/teacher"
+	"github.com/gorilla/mux"
+)
+
+func TestAddHandler(t *testing.T) {
+	repo := new(fakes.FakeTeacherRepo)
+	repo.SaveReturns(4, nil)
+
+	body := bytes.NewBufferString(`{"name":"Ms. Frizzle","bio":"Science teacher","avatar_url":"https://example.com/frizzle.png","credentials":"M.Ed."}`)
+	req := fakes.NewRequestWithContext("POST", "/api/teachers", body, fakes.FakeContentEditor())
+	rr := httptest.NewRecorder()
+	teacher.AddHandler(fakes.FakeRenderer, repo).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v - %s", status, http.StatusCreated, rr.Body.String())
+	}
+
+	resp := teacher.NewTeacherResponse()
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("failed to unmarshall json response %v", err)
+	}
+
+	if resp.Teacher.ID != 4 || resp.Teacher.CreatedBy != fakes.FakeContentEditor().ID || resp.Teacher.Credentials != "M.Ed." {
+		t.Errorf("expected teacher 4 created by the current user but got %v", resp.Teacher)
+	}
+}
+
+func TestAddHandlerRejectsInvalidTeachers(t *testing.T) {
+	repo := new(fakes.FakeTeacherRepo)
+
+	body := bytes.NewBufferString(`{"bio":"Science teacher","avatar_url":"javascript:alert(1)"}`)
+	req := fakes.NewRequestWithContext("POST", "/api/teachers", body, fakes.FakeContentEditor())
+	rr := httptest.NewRecorder()
+	teacher.AddHandler(fakes.FakeRenderer, repo).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	resp := teacher.NewTeacherResponse()
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("failed to unmarshall json response %v", err)
+	}
+
+	fields := make(map[string]bool)
+	for _, e := range resp.Teacher.ValidationErrors {
+		fields[e.Field] = true
+	}
+
+	if !fields["name"] || !fields["avatar_url"] {
+		t.Errorf("expected validation errors for the name and avatar url but got %v", resp.Teacher.ValidationErrors)
+	}
+}
+
+func TestGetHandlerReturnsNotFound(t *testing.T) {
+	repo := new(fakes.FakeTeacherRepo)
+	repo.GetReturns(nil, nil)
+
+	req := fakes.NewRequestWithContext("GET", "/api/teachers/9", nil, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "9"})
+	rr := httptest.NewRecorder()
+	teacher.GetHandler(fakes.FakeRenderer, repo).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+}
+
+func TestUpdateHandlerKeepsTheID(t *testing.T) {
+	repo := new(fakes.FakeTeacherRepo)
+	repo.GetReturns(&cohesioned.Teacher{ID: 3, Name: "Ms. Frizzle"}, nil)
+
+	body := bytes.NewBufferString(`{"id":8,"name":"Valerie Frizzle"}`)
+	req := fakes.NewRequestWithContext("PUT", "/api/teachers/3", body, fakes.FakeContentEditor())
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	rr := httptest.NewRecorder()
+	teacher.UpdateHandler(fakes.FakeRenderer, repo).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v - %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	resp := teacher.NewTeacherResponse()
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("failed to unmarshall json response %v", err)
+	}
+
+	if resp.Teacher.ID != 3 || resp.Teacher.Name != "Valerie Frizzle" || resp.Teacher.UpdatedBy != fakes.FakeContentEditor().ID {
+		t.Errorf("expected teacher 3 to be renamed by the current user but got %v", resp.Teacher)
+	}
+}
+
+func TestDeleteHandler(t *testing.T) {
+	repo := new(fakes.FakeTeacherRepo)
+	repo.GetReturns(&cohesioned.Teacher{ID: 3, Name: "Ms. Frizzle"}, nil)
+
+	req := fakes.NewRequestWithContext("DELETE", "/api/teachers/3", nil, fakes.FakeAdmin())
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	rr := httptest.NewRecorder()
+	teacher.DeleteHandler(fakes.FakeRenderer, repo).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	if len(repo.Deleted) != 1 || repo.Deleted[0] != 3 {
+		t.Errorf("expected teacher 3 to be deleted but got %v", repo.Deleted)
+	}
+}
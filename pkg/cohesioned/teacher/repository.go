@@ -0,0 +1,24 @@
+package teacher
+
+import (
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+type Repo interface {
+	List(opts *cohesioned.ListOptions) ([]*cohesioned.Teacher, int64, error)
+	Get(id int64) (*cohesioned.Teacher, error)
+	FindByIDs(ids []int64) ([]*cohesioned.Teacher, error)
+	Save(t *cohesioned.Teacher) (int64, error)
+	Update(t *cohesioned.Teacher) error
+	Delete(id int64) error
+}
+
+//ListSpec is how the teacher list can be sorted and filtered
+var ListSpec = cohesioned.ListSpec{
+	SortFields:  []string{"id", "name", "created"},
+	DefaultSort: "name",
+	Filters: map[string]cohesioned.FilterType{
+		"name":    cohesioned.FilterString,
+		"created": cohesioned.FilterDateRange,
+	},
+}
@@ -0,0 +1,48 @@
+package cohesioned
+
+import "time"
+
+//ThumbnailKind is where a Thumbnail came from
+type ThumbnailKind string
+
+const (
+	//ThumbnailGenerated thumbnails are captured from the video by the transcoder
+	ThumbnailGenerated ThumbnailKind = "generated"
+	//ThumbnailCustom thumbnails are poster images uploaded by an admin
+	ThumbnailCustom ThumbnailKind = "custom"
+)
+
+//Thumbnail is a still image of a video, stored in the video's bucket
+type Thumbnail struct {
+	ID          int64         `json:"id"`
+	VideoID     int64         `json:"video_id"`
+	Kind        ThumbnailKind `json:"kind"`
+	ObjectKey   string        `json:"object_key"`
+	ContentType string        `json:"content_type"`
+	Width       int64         `json:"width"`
+	Height      int64         `json:"height"`
+	SignedURL   string        `json:"signed_url,omitempty"`
+	Created     time.Time     `json:"created"`
+}
+
+//Poster picks the thumbnail to show for a video from all of its thumbnails - a custom poster if there is one, otherwise a generated thumbnail.
+//The first frames of a video are often blank, so the third generated thumbnail is preferred. Generated thumbnails are expected in the order they were captured
+func Poster(thumbnails []*Thumbnail) *Thumbnail {
+	var generated []*Thumbnail
+	for _, t := range thumbnails {
+		if t.Kind == ThumbnailCustom {
+			return t
+		}
+
+		generated = append(generated, t)
+	}
+
+	switch {
+	case len(generated) == 0:
+		return nil
+	case len(generated) < 3:
+		return generated[len(generated)-1]
+	default:
+		return generated[2]
+	}
+}
@@ -0,0 +1,33 @@
+package cohesioned
+
+import "time"
+
+//TranscodingStatus is where a TranscodingJob is in its lifecycle
+type TranscodingStatus string
+
+const (
+	TranscodingPending  TranscodingStatus = "pending"
+	TranscodingRunning  TranscodingStatus = "running"
+	TranscodingComplete TranscodingStatus = "complete"
+	TranscodingFailed   TranscodingStatus = "failed"
+)
+
+//IsDone returns true once the status can no longer change
+func (s TranscodingStatus) IsDone() bool {
+	return s == TranscodingComplete || s == TranscodingFailed
+}
+
+//TranscodingJob tracks the transcoding of a video's file into a single preset
+type TranscodingJob struct {
+	ID      int64  `json:"id"`
+	VideoID int64  `json:"video_id"`
+	Preset  string `json:"preset"`
+	//ExternalID is the transcoder's id for the job
+	ExternalID string            `json:"external_id"`
+	Status     TranscodingStatus `json:"status"`
+	//OutputKey is the object the rendition is written to, in the video's bucket
+	OutputKey string    `json:"output_key"`
+	Error     string    `json:"error,omitempty"`
+	Created   time.Time `json:"created"`
+	Updated   time.Time `json:"updated"`
+}
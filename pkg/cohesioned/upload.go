@@ -0,0 +1,27 @@
+package cohesioned
+
+import "time"
+
+//VideoUpload tracks a resumable upload of a video's file through the API
+type VideoUpload struct {
+	VideoID int64 `json:"video_id"`
+	//Size is the size of the whole file in bytes
+	Size int64 `json:"size"`
+	//Received is the number of bytes received so far, and so the offset the next chunk starts at
+	Received    int64     `json:"received"`
+	FileType    string    `json:"file_type,omitempty"`
+	Created     time.Time `json:"created"`
+	CreatedByID int64     `json:"created_by_id"`
+	Updated     time.Time `json:"updated"`
+}
+
+//IsComplete returns true once every byte of the file has been received
+func (u *VideoUpload) IsComplete() bool {
+	return u.Received >= u.Size
+}
+
+//UploadChunk is a range of bytes received for a VideoUpload. Last is inclusive, as in a Content-Range header
+type UploadChunk struct {
+	First int64 `json:"first"`
+	Last  int64 `json:"last"`
+}
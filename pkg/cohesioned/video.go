@@ -1,32 +1,46 @@
 package cohesioned
 
 import (
+	"fmt"
+	"strings"
 	"time"
 )
 
 type Video struct {
 	Validatable
-	ID                  int64     `json:"id"`
-	Created             time.Time `json:"created"`
-	Updated             time.Time `json:"updated"`
-	CreatedByID         int64     `json:"created_by_id"`
-	CreatedBy           *Profile  `json:"created_by"`
-	UpdatedByID         int64     `json:"updated_by_id"`
-	UpdatedBy           *Profile  `json:"updated_by"`
-	Title               string    `json:"title"`
-	TaxonomyID          int64     `json:"taxonomy_id"`
-	Taxonomy            *Taxonomy `json:"taxonomy"`
-	KeyTerms            []string  `json:"key_terms,omitempty"`
-	StateStandards      []string  `json:"state_standards,omitempty"`
-	CommonCoreStandards []string  `json:"common_core_standards,omitempty"`
-	FileName            string    `json:"file_name"`
-	FileType            string    `json:"file_type"`
-	FileSize            int64     `json:"file_size"`
-	StorageBucket       string    `json:"bucket"`
-	StorageObjectName   string    `json:"object_name"`
-	SignedURL           string    `json:"signed_url,omitempty"`
-	ThumbnailURL        string    `json:thumbnail_url`
-	//TODO - Teacher, Related Videos, FAQs
+	ID                  int64              `json:"id"`
+	Created             time.Time          `json:"created"`
+	Updated             time.Time          `json:"updated"`
+	CreatedByID         int64              `json:"created_by_id"`
+	CreatedBy           *Profile           `json:"created_by"`
+	UpdatedByID         int64              `json:"updated_by_id"`
+	UpdatedBy           *Profile           `json:"updated_by"`
+	Title               string             `json:"title"`
+	TaxonomyID          int64              `json:"taxonomy_id"`
+	Taxonomy            *Taxonomy          `json:"taxonomy"`
+	KeyTerms            []string           `json:"key_terms,omitempty"`
+	KeyTermTimestamps   map[string]float64 `json:"key_term_timestamps,omitempty"` //seconds into the video where a key term is explained, by key term
+	StateStandards      []string           `json:"state_standards,omitempty"`
+	CommonCoreStandards []string           `json:"common_core_standards,omitempty"`
+	FileName            string             `json:"file_name"`
+	FileType            string             `json:"file_type"`
+	FileSize            int64              `json:"file_size"`
+	StorageBucket       string             `json:"bucket"`
+	StorageObjectName   string             `json:"object_name"`
+	Checksum            string             `json:"checksum,omitempty"` //hex encoded sha-256 of the file
+	Duration            float64            `json:"duration,omitempty"` //length of the file in seconds, 0 if it isn't known
+	SignedURL           string             `json:"signed_url,omitempty"`
+	Renditions          []*Rendition       `json:"renditions,omitempty"`
+	ThumbnailURL        string             `json:"thumbnail_url"`
+	ThumbnailKey        string             `json:"-"` //object key of the poster thumbnail, signed into ThumbnailURL
+	Thumbnails          []*Thumbnail       `json:"thumbnails,omitempty"`
+	Captions            []*CaptionTrack    `json:"captions,omitempty"`
+	Chapters            []*Chapter         `json:"chapters,omitempty"`
+	Transcript          string             `json:"-"` //text of the caption tracks, only loaded for search results
+	Progress            *WatchProgress     `json:"progress,omitempty"`
+	TeacherIDs          []int64            `json:"teacher_ids,omitempty"`
+	Teachers            []*Teacher         `json:"teachers,omitempty"`
+	FAQs                []*FAQ             `json:"faqs,omitempty"`
 }
 
 //NewVideo creates a Video with the Auditable fields initialized
@@ -55,5 +69,27 @@ func (v *Video) Validate() bool {
 		v.AddValidationError("taxonomy_id", "taxonomy_id is required")
 	}
 
+	v.validateKeyTermTimestamps()
+
 	return len(v.ValidationErrors) == 0
 }
+
+//validateKeyTermTimestamps checks that each key term timestamp is for one of the video's key terms and falls within the video.
+//Videos transcoded before durations were recorded have no duration until one is set or the file is replaced, so their timestamps can't be checked against the end of the video
+func (v *Video) validateKeyTermTimestamps() {
+	keyTerms := make(map[string]bool)
+	for _, term := range v.KeyTerms {
+		keyTerms[strings.TrimSpace(term)] = true
+	}
+
+	for term, seconds := range v.KeyTermTimestamps {
+		switch {
+		case !keyTerms[term]:
+			v.AddValidationError("key_term_timestamps", fmt.Sprintf("%s is not one of the video's key terms", term))
+		case seconds < 0:
+			v.AddValidationError("key_term_timestamps", fmt.Sprintf("the timestamp of %s can't be negative", term))
+		case v.Duration > 0 && seconds > v.Duration:
+			v.AddValidationError("key_term_timestamps", fmt.Sprintf("the timestamp of %s is after the end of the video at %.3f seconds", term, v.Duration))
+		}
+	}
+}
@@ -6,48 +6,155 @@ import (
 	"io"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/elastictranscoder"
-	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/cohesion-education/api/pkg/cohesioned"
 	"github.com/cohesion-education/api/pkg/cohesioned/config"
+	"github.com/cohesion-education/api/pkg/cohesioned/notification"
+	"github.com/cohesion-education/api/pkg/cohesioned/standard"
+	"github.com/cohesion-education/api/pkg/cohesioned/storage"
 	"github.com/cohesion-education/api/pkg/cohesioned/taxonomy"
+	"github.com/cohesion-education/api/pkg/cohesioned/teacher"
 )
 
 type AdminService interface {
-	List() ([]*cohesioned.Video, error)
+	List(opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error)
 	FindByTaxonomyID(taxonomyID int64) ([]*cohesioned.Video, error)
 	FindByGrade(gradeName string) (map[string][]*cohesioned.Video, error)
 	FindBySubject(gradeName, subjectName string) (map[string][]*cohesioned.Video, error)
+	FindByKeyTerm(term string, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error)
+	FindByStandard(standard string, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error)
+	FindByJurisdiction(jurisdiction string, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error)
+	FindByTeacher(teacherID int64, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error)
+	ListKeyTerms() ([]*cohesioned.Tag, error)
+	ListStandards() ([]*cohesioned.Tag, error)
+	Search(query SearchQuery, opts *cohesioned.ListOptions) ([]*cohesioned.SearchResult, int64, error)
 	Get(id int64) (*cohesioned.Video, error)
 	GetWithSignedURL(id int64) (*cohesioned.Video, error)
 	Delete(id int64) error
 	Save(ctx context.Context, video *cohesioned.Video) error
 	Update(ctx context.Context, video *cohesioned.Video) error
 	SetFile(ctx context.Context, fileReader io.Reader, video *cohesioned.Video) error
+	SetThumbnail(video *cohesioned.Video, body io.Reader) (*cohesioned.Thumbnail, error)
+	DeleteThumbnail(video *cohesioned.Video) error
+	SetCaptions(video *cohesioned.Video, language, label string, body io.Reader) (*cohesioned.CaptionTrack, error)
+	DeleteCaptions(video *cohesioned.Video, language string) error
+	ListChapters(videoID int64) ([]*cohesioned.Chapter, error)
+	SaveChapter(video *cohesioned.Video, chapter *cohesioned.Chapter) error
+	UpdateChapter(video *cohesioned.Video, chapter *cohesioned.Chapter) error
+	DeleteChapter(video *cohesioned.Video, chapterID int64) error
+	ListFAQs(videoID int64) ([]*cohesioned.FAQ, error)
+	ListQuestions(opts *cohesioned.ListOptions) ([]*cohesioned.FAQ, int64, error)
+	AskQuestion(video *cohesioned.Video, faq *cohesioned.FAQ, askedBy int64) error
+	SaveFAQ(video *cohesioned.Video, faq *cohesioned.FAQ, userID int64) error
+	UpdateFAQ(video *cohesioned.Video, faq *cohesioned.FAQ, userID int64) error
+	DeleteFAQ(video *cohesioned.Video, faqID int64) error
+	RecordWatchEvent(video *cohesioned.Video, event *cohesioned.WatchEvent) (*cohesioned.WatchProgress, error)
+	WatchProgress(videoID, userID, studentID int64) (*cohesioned.WatchProgress, error)
+	RelatedVideos(video *cohesioned.Video, limit int) ([]*cohesioned.RelatedVideo, error)
+	SetRelated(video *cohesioned.Video, relatedIDs []int64) error
+	PresignUpload(video *cohesioned.Video, contentType string) (*UploadTarget, error)
+	StartMultipartUpload(video *cohesioned.Video, contentType string, fileSize int64) (*UploadTarget, error)
+	CompleteUpload(ctx context.Context, video *cohesioned.Video, upload *CompletedUpload) error
+	AbortUpload(video *cohesioned.Video, objectName, uploadID string) error
+	UploadProgress(videoID int64) (*cohesioned.VideoUpload, error)
+	WriteChunk(ctx context.Context, video *cohesioned.Video, chunk *cohesioned.UploadChunk, total int64, fileType string, body io.Reader) (*cohesioned.VideoUpload, error)
+	CancelUpload(videoID int64) error
+	ValidateStandards(video, previous *cohesioned.Video) error
+	ValidateTeachers(video *cohesioned.Video) error
+	TranscodingJobs(videoID int64) ([]*cohesioned.TranscodingJob, error)
+	RefreshTranscodingJobs() error
+	HLSRenditions(videoID int64) ([]*cohesioned.Rendition, error)
+	VariantPlaylist(video *cohesioned.Video, renditionID int64) ([]byte, error)
+	ReconcileStorage(minAge time.Duration, remove bool) (*StorageReport, error)
+	BackfillThumbnails() (*ThumbnailBackfill, error)
 }
 
 type adminService struct {
-	videoRepo    Repo
-	taxonomyRepo taxonomy.Repo
-	cfg          config.AwsConfig
+	videoRepo        Repo
+	chapterRepo      ChapterRepo
+	faqRepo          FAQRepo
+	watchRepo        WatchRepo
+	relatedRepo      RelatedRepo
+	taxonomyRepo     taxonomy.Repo
+	standardRepo     standard.Repo
+	teacherRepo      teacher.Repo
+	notificationRepo notification.Repo
+	store            storage.BlobStore
+	//transcoder is nil when transcoding is turned off
+	transcoder Transcoder
+	cfg        config.AwsConfig
 }
 
-func NewService(videoRepo Repo, taxonomyRepo taxonomy.Repo, cfg config.AwsConfig) AdminService {
+func NewService(videoRepo Repo, chapterRepo ChapterRepo, faqRepo FAQRepo, watchRepo WatchRepo, relatedRepo RelatedRepo, taxonomyRepo taxonomy.Repo, standardRepo standard.Repo, teacherRepo teacher.Repo, notificationRepo notification.Repo, store storage.BlobStore, transcoder Transcoder, cfg config.AwsConfig) AdminService {
 	return &adminService{
-		videoRepo:    videoRepo,
-		taxonomyRepo: taxonomyRepo,
-		cfg:          cfg,
+		videoRepo:        videoRepo,
+		chapterRepo:      chapterRepo,
+		faqRepo:          faqRepo,
+		watchRepo:        watchRepo,
+		relatedRepo:      relatedRepo,
+		taxonomyRepo:     taxonomyRepo,
+		standardRepo:     standardRepo,
+		teacherRepo:      teacherRepo,
+		notificationRepo: notificationRepo,
+		store:            store,
+		transcoder:       transcoder,
+		cfg:              cfg,
 	}
 }
 
-func (s *adminService) List() ([]*cohesioned.Video, error) {
-	return s.videoRepo.List()
+func (s *adminService) List(opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error) {
+	return s.signPage(s.videoRepo.List(opts))
 }
 
 func (s *adminService) FindByTaxonomyID(taxonomyID int64) ([]*cohesioned.Video, error) {
-	return s.videoRepo.FindByTaxonomyID(taxonomyID)
+	list, err := s.videoRepo.FindByTaxonomyID(taxonomyID)
+	s.signThumbnails(list...)
+	return list, err
+}
+
+func (s *adminService) FindByKeyTerm(term string, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error) {
+	return s.signPage(s.videoRepo.FindByTag([]cohesioned.TagKind{cohesioned.TagKeyTerm}, term, opts))
+}
+
+//FindByStandard finds videos tagged with the standard as either a state or a common core standard
+func (s *adminService) FindByStandard(standard string, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error) {
+	return s.signPage(s.videoRepo.FindByTag(cohesioned.StandardTagKinds, standard, opts))
+}
+
+//FindByJurisdiction finds videos tagged with a state standard from the given jurisdiction's catalog
+func (s *adminService) FindByJurisdiction(jurisdiction string, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error) {
+	return s.signPage(s.videoRepo.FindByJurisdiction(jurisdiction, opts))
+}
+
+//signPage signs the thumbnails of a page of videos returned by the repo
+func (s *adminService) signPage(list []*cohesioned.Video, total int64, err error) ([]*cohesioned.Video, int64, error) {
+	s.signThumbnails(list...)
+	return list, total, err
+}
+
+//ValidateStandards adds a validation error to the video for each of its standards that isn't in the standards catalog.
+//previous is the video before it was updated, or nil for a new video. Standards it already had aren't checked again, so videos tagged before the catalog existed can still be saved
+func (s *adminService) ValidateStandards(video, previous *cohesioned.Video) error {
+	tags := addedStandards(video, previous)
+	codes := append(tags[cohesioned.TagStateStandard], tags[cohesioned.TagCommonCoreStandard]...)
+	if len(codes) == 0 {
+		return nil
+	}
+
+	catalog, err := s.standardRepo.FindByCodes(codes)
+	if err != nil {
+		return fmt.Errorf("Failed to look up standards %v: %v", codes, err)
+	}
+
+	checkStandards(video, previous, catalog)
+	return nil
+}
+
+func (s *adminService) ListKeyTerms() ([]*cohesioned.Tag, error) {
+	return s.videoRepo.ListTags(cohesioned.TagKeyTerm)
+}
+
+func (s *adminService) ListStandards() ([]*cohesioned.Tag, error) {
+	return s.videoRepo.ListTags(cohesioned.StandardTagKinds...)
 }
 
 func (s *adminService) FindByGrade(gradeName string) (map[string][]*cohesioned.Video, error) {
@@ -71,7 +178,7 @@ func (s *adminService) FindByGrade(gradeName string) (map[string][]*cohesioned.V
 		}
 
 		for _, f := range flattened {
-			videos, err := s.videoRepo.FindByTaxonomyID(f.ID)
+			videos, err := s.FindByTaxonomyID(f.ID)
 			if err != nil {
 				fmt.Printf("Failed to find videos by taxonomy ID %d: %v\n", f.ID, err)
 				continue
@@ -123,7 +230,7 @@ func (s *adminService) FindBySubject(gradeName, subjectName string) (map[string]
 		}
 
 		for _, f := range flattened {
-			videos, err := s.videoRepo.FindByTaxonomyID(f.ID)
+			videos, err := s.FindByTaxonomyID(f.ID)
 			if err != nil {
 				fmt.Printf("Failed to find videos by taxonomy ID %d: %v\n", f.ID, err)
 				continue
@@ -136,52 +243,305 @@ func (s *adminService) FindBySubject(gradeName, subjectName string) (map[string]
 	return videosByFlattenedTaxonomy, nil
 }
 
+func (s *adminService) Search(query SearchQuery, opts *cohesioned.ListOptions) ([]*cohesioned.SearchResult, int64, error) {
+	terms := SearchTerms(query.Text)
+
+	taxonomyIDs, err := s.searchTaxonomyIDs(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results, total, err := s.videoRepo.Search(terms, taxonomyIDs, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]int64, len(results))
+	for i, result := range results {
+		ids[i] = result.Video.ID
+	}
+
+	transcripts, err := s.videoRepo.ListTranscripts(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, result := range results {
+		result.Video.Transcript = transcripts[result.Video.ID]
+		result.Highlights = Highlights(result.Video, terms)
+		s.signThumbnails(result.Video)
+	}
+
+	return results, total, nil
+}
+
+//searchTaxonomyIDs returns the IDs of the taxonomy subtree a search is narrowed to, or nil if the search isn't narrowed
+func (s *adminService) searchTaxonomyIDs(query SearchQuery) ([]int64, error) {
+	rootID := query.TaxonomyID
+
+	if rootID == 0 && len(query.Grade) > 0 {
+		grades, err := s.taxonomyRepo.List()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to list grades: %v", err)
+		}
+
+		var grade *cohesioned.Taxonomy
+		for _, g := range grades {
+			if g.Name == query.Grade {
+				grade = g
+				break
+			}
+		}
+
+		if grade == nil {
+			return nil, ErrUnknownGrade
+		}
+
+		rootID = grade.ID
+		if len(query.Subject) > 0 {
+			subjects, err := s.taxonomyRepo.ListChildren(grade.ID)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to get subjects (children) for grade with ID %d: %v", grade.ID, err)
+			}
+
+			rootID = 0
+			for _, subject := range subjects {
+				if subject.Name == query.Subject {
+					rootID = subject.ID
+					break
+				}
+			}
+
+			if rootID == 0 {
+				return nil, ErrUnknownSubject
+			}
+		}
+	}
+
+	if rootID == 0 {
+		return nil, nil
+	}
+
+	children, err := s.taxonomyRepo.ListChildrenRecursive(rootID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get the children of taxonomy %d: %v", rootID, err)
+	}
+
+	ids := []int64{rootID}
+	var collect func(list []*cohesioned.Taxonomy)
+	collect = func(list []*cohesioned.Taxonomy) {
+		for _, t := range list {
+			ids = append(ids, t.ID)
+			collect(t.Children)
+		}
+	}
+
+	collect(children)
+	return ids, nil
+}
+
 func (s *adminService) Get(id int64) (*cohesioned.Video, error) {
 	video, err := s.videoRepo.Get(id)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get video by ID: %v", err)
 	}
 
+	if video == nil {
+		return nil, nil
+	}
+
 	video.Taxonomy, err = s.taxonomyRepo.ReverseFlatten(video.Taxonomy)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get full taxonomy for video: %v", err)
 	}
 
+	s.signThumbnails(video)
 	return video, nil
 }
 
 func (s *adminService) GetWithSignedURL(id int64) (*cohesioned.Video, error) {
 	video, err := s.Get(id)
-	if err != nil {
-		return nil, err
+	if err != nil || video == nil {
+		return video, err
 	}
 
 	if len(video.StorageBucket) != 0 && len(video.StorageObjectName) != 0 {
-		transcodedVideoKey := fmt.Sprintf("transcoded/480p-16x9-%s", video.StorageObjectName)
+		if video.Renditions, err = s.renditions(video); err != nil {
+			return nil, err
+		}
 
-		signedURL, err := s.cfg.GetSignedURL(video.StorageBucket, transcodedVideoKey)
+		signedURL, err := s.signedURL(video.StorageBucket, s.playbackKey(video))
 		video.SignedURL = signedURL
 		if err != nil {
 			return nil, fmt.Errorf("Failed to generate signed url %v", err)
 		}
 	}
 
+	if video.Thumbnails, err = s.videoRepo.ListThumbnails(video.ID); err != nil {
+		return nil, err
+	}
+
+	for _, t := range video.Thumbnails {
+		if t.SignedURL, err = s.signedURL(s.cfg.GetVideoBucket(), t.ObjectKey); err != nil {
+			return nil, fmt.Errorf("Failed to generate signed url for thumbnail %s: %v", t.ObjectKey, err)
+		}
+	}
+
+	if video.Captions, err = s.signCaptions(video); err != nil {
+		return nil, err
+	}
+
+	if video.Chapters, err = s.chapterRepo.ListChapters(video.ID); err != nil {
+		return nil, err
+	}
+
+	if video.FAQs, err = s.ListFAQs(video.ID); err != nil {
+		return nil, err
+	}
+
 	return video, nil
 }
 
+//renditions lists the video's renditions, lowest bitrate first, each with a signed url.
+func (s *adminService) renditions(video *cohesioned.Video) ([]*cohesioned.Rendition, error) {
+	renditions, err := s.videoRepo.ListRenditions(video.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range renditions {
+		if r.SignedURL, err = s.signedURL(video.StorageBucket, r.ObjectKey); err != nil {
+			return nil, fmt.Errorf("Failed to generate signed url for %s rendition: %v", r.Name, err)
+		}
+	}
+
+	return renditions, nil
+}
+
+func (s *adminService) signedURL(bucket, key string) (string, error) {
+	return s.store.SignedGetURL(bucket, key, s.cfg.GetStorageConfig().SignedURLTTL)
+}
+
+//playbackKey is the object to play the video from - the rendition of the most preferred preset, or the original file if there are none
+func (s *adminService) playbackKey(video *cohesioned.Video) string {
+	byName := make(map[string]*cohesioned.Rendition)
+	for _, r := range video.Renditions {
+		byName[r.Name] = r
+	}
+
+	for _, preset := range s.cfg.GetTranscodingConfig().Presets {
+		if r, ok := byName[preset.Name]; ok {
+			return r.ObjectKey
+		}
+	}
+
+	return video.StorageObjectName
+}
+
+//TranscodingJobs lists the video's transcoding jobs, newest first. Their status is kept up to date by RefreshTranscodingJobs
+func (s *adminService) TranscodingJobs(videoID int64) ([]*cohesioned.TranscodingJob, error) {
+	return s.videoRepo.ListTranscodingJobs(videoID)
+}
+
+//RefreshTranscodingJobs checks the status of every transcoding job that hasn't finished with the transcoder. The rendition of a job that has completed is saved
+func (s *adminService) RefreshTranscodingJobs() error {
+	if s.transcoder == nil {
+		return nil
+	}
+
+	jobs, err := s.videoRepo.ListUnfinishedTranscodingJobs()
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		status := job.Status
+		rendition, err := s.transcoder.Refresh(job)
+		if err != nil {
+			fmt.Printf("Failed to refresh transcoding job %d: %v\n", job.ID, err)
+			continue
+		}
+
+		if job.Status == status {
+			continue
+		}
+
+		job.Updated = time.Now()
+		if err := s.videoRepo.UpdateTranscodingJob(job); err != nil {
+			return err
+		}
+
+		if err := s.saveRendition(rendition); err != nil {
+			return err
+		}
+
+		if err := s.recordThumbnailsOf(job); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *adminService) saveRendition(r *cohesioned.Rendition) error {
+	if r == nil {
+		return nil
+	}
+
+	r.Created = time.Now()
+	id, err := s.videoRepo.SaveRendition(r)
+	if err != nil {
+		return err
+	}
+
+	r.ID = id
+	if r.Duration > 0 {
+		return s.videoRepo.SetDuration(r.VideoID, r.Duration)
+	}
+
+	return nil
+}
+
+//Delete deletes the video, then its file, renditions, thumbnails and captions
 func (s *adminService) Delete(id int64) error {
 	video, err := s.Get(id)
 	if err != nil {
 		return fmt.Errorf("Failed to find video with id %d: %v", id, err)
 	}
 
-	if len(video.StorageBucket) != 0 && len(video.StorageObjectName) != 0 {
-		if err := s.deleteFile(video.StorageBucket, video.StorageObjectName); err != nil {
-			return fmt.Errorf("Failed to delete the video file: %v", err)
-		}
+	if video == nil {
+		return fmt.Errorf("No video with ID %d", id)
+	}
+
+	renditions, err := s.videoRepo.ListRenditions(id)
+	if err != nil {
+		return err
+	}
+
+	custom, err := s.thumbnails(id, cohesioned.ThumbnailCustom)
+	if err != nil {
+		return err
 	}
 
-	return s.videoRepo.Delete(id)
+	captions, err := s.videoRepo.ListCaptions(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.videoRepo.Delete(id); err != nil {
+		return err
+	}
+
+	s.discardFile(video.StorageBucket, video.StorageObjectName, renditions)
+	for _, t := range custom {
+		s.discardObject(s.cfg.GetVideoBucket(), t.ObjectKey)
+	}
+
+	for _, c := range captions {
+		s.discardObject(s.cfg.GetVideoBucket(), c.ObjectKey)
+	}
+
+	return nil
 }
 
 //Save saves the given video, looking for the current user in the given context argument. Sets the resulting ID from the save operation on the video instance
@@ -206,107 +566,203 @@ func (s *adminService) Update(ctx context.Context, video *cohesioned.Video) erro
 	return s.videoRepo.Update(video)
 }
 
+//SetFile checks the file's type, size and checksum as it is staged under a new key, and swaps it in for the video's current file once the video is saved.
+//A file that fails the checks is discarded and ErrInvalidFile is returned with the reasons added to the video's ValidationErrors
 func (s *adminService) SetFile(ctx context.Context, fileReader io.Reader, video *cohesioned.Video) error {
-	//TODO - wrap in transaction
-	//TODO - delete existing file
-	video.StorageBucket = s.cfg.GetVideoBucket()
-	video.StorageObjectName = fmt.Sprintf("%d-%s", video.ID, video.FileName)
+	fileType, body, err := sniff(fileReader)
+	if err != nil {
+		return fmt.Errorf("Failed to read file: %v", err)
+	}
+
+	if err := s.checkType(video, fileType); err != nil {
+		return err
+	}
+
+	check := newFileCheck(body, s.cfg.GetUploadConfig().MaxSize)
+	bucket, key := s.cfg.GetVideoBucket(), objectName(video)
+	if err := s.store.Put(bucket, key, check, fileType); err != nil {
+		if check.tooLarge {
+			return s.checkSize(video, check.size)
+		}
 
-	if err := s.writeFileToStorage(fileReader, video.StorageBucket, video.StorageObjectName); err != nil {
 		return fmt.Errorf("Failed to write file to storage: %v", err)
 	}
 
-	// if err := s.submitTranscodingJobs(video); err != nil {
-	// 	return fmt.Errorf("Failed to submit transcoding jobs: %v", err)
-	// }
+	checksum := check.Checksum()
+	if err := s.checkDuplicate(video, checksum); err != nil {
+		s.discardObject(bucket, key)
+		return err
+	}
+
+	prevBucket, prevKey := video.StorageBucket, video.StorageObjectName
+	video.StorageBucket = bucket
+	video.StorageObjectName = key
+	video.FileType = fileType
+	video.FileSize = check.size
+	video.Checksum = checksum
+
+	return s.swapFile(ctx, video, prevBucket, prevKey)
+}
+
+//swapFile saves the video once its new file is in storage, then discards the file it replaced and starts post-processing the new one.
+//If the video can't be saved the new file is deleted instead and the video is left pointing at its previous file
+func (s *adminService) swapFile(ctx context.Context, video *cohesioned.Video, prevBucket, prevKey string) error {
+	prevDuration := video.Duration
+	if prevBucket != video.StorageBucket || prevKey != video.StorageObjectName {
+		//the new file's duration isn't known until it is transcoded
+		video.Duration = 0
+	}
 
 	if err := s.Update(ctx, video); err != nil {
+		s.discardObject(video.StorageBucket, video.StorageObjectName)
+		video.StorageBucket = prevBucket
+		video.StorageObjectName = prevKey
+		video.Duration = prevDuration
+
+		if err == ErrDuplicateChecksum {
+			//another upload of the same file was saved after checkDuplicate ran
+			if err := s.checkDuplicate(video, video.Checksum); err != nil {
+				return err
+			}
+
+			return invalidFile(video, "checksum", "the file has already been uploaded for another video")
+		}
+
 		return fmt.Errorf("Failed to update video record: %v", err)
 	}
 
-	return nil
-}
+	if len(prevKey) > 0 && (prevBucket != video.StorageBucket || prevKey != video.StorageObjectName) {
+		renditions, err := s.videoRepo.ListRenditions(video.ID)
+		if err != nil {
+			return err
+		}
 
-func (s *adminService) deleteFile(bucketName, objectName string) error {
-	sess, err := s.cfg.NewSession()
-	if err != nil {
-		return fmt.Errorf("Error creating session %v", err)
-	}
+		if err := s.videoRepo.DeleteRenditions(video.ID); err != nil {
+			return err
+		}
 
-	svc := s3.New(sess)
+		if err := s.videoRepo.DeleteThumbnails(video.ID, cohesioned.ThumbnailGenerated); err != nil {
+			return err
+		}
 
-	deleteInput := &s3.DeleteObjectInput{
-		Bucket: aws.String(bucketName),
-		Key:    aws.String(objectName),
+		if err := s.supersedeTranscodingJobs(video.ID); err != nil {
+			return err
+		}
+
+		s.discardFile(prevBucket, prevKey, renditions)
 	}
 
-	if _, err := svc.DeleteObject(deleteInput); err != nil {
-		return fmt.Errorf("Failed to delete %s/%s: %v", bucketName, objectName, err)
+	if err := s.transcode(video); err != nil {
+		return fmt.Errorf("Failed to submit transcoding jobs: %v", err)
 	}
 
 	return nil
-
 }
 
-func (s *adminService) writeFileToStorage(fileReader io.Reader, bucketName, objectName string) error {
-	sess, err := s.cfg.NewSession()
+//supersedeTranscodingJobs marks the video's unfinished transcoding jobs as failed, so renditions of a file that has been replaced aren't saved when they finish
+func (s *adminService) supersedeTranscodingJobs(videoID int64) error {
+	jobs, err := s.videoRepo.ListTranscodingJobs(videoID)
 	if err != nil {
-		return fmt.Errorf("Error creating session %v", err)
+		return err
 	}
 
-	svc := s3.New(sess)
+	for _, job := range jobs {
+		if job.Status.IsDone() {
+			continue
+		}
 
-	// Upload input parameters
-	params := &s3manager.UploadInput{
-		Bucket: aws.String(bucketName),
-		Key:    aws.String(objectName),
-		Body:   fileReader,
+		job.Status = cohesioned.TranscodingFailed
+		job.Error = "the file was replaced before transcoding finished"
+		job.Updated = time.Now()
+		if err := s.videoRepo.UpdateTranscodingJob(job); err != nil {
+			return err
+		}
 	}
 
-	uploader := s3manager.NewUploaderWithClient(svc)
+	return nil
+}
 
-	// Perform an upload.
-	if _, err := uploader.Upload(params, func(u *s3manager.Uploader) {
-		u.PartSize = 10 * 1024 * 1024 // 10MB part size
-		u.LeavePartsOnError = false
-	}); err != nil {
-		return fmt.Errorf("Failed to upload file to s3: %v", err)
+//discardFile deletes a video file that is no longer in use along with the given renditions of it and its thumbnails. Failures are only logged - anything left behind is found by ReconcileStorage
+func (s *adminService) discardFile(bucket, key string, renditions []*cohesioned.Rendition) {
+	if len(bucket) == 0 || len(key) == 0 {
+		return
 	}
 
-	return nil
-}
+	keys := []string{key}
+	for _, r := range renditions {
+		//the stub transcoder's renditions are the original file
+		if r.ObjectKey != key {
+			keys = append(keys, r.ObjectKey)
+		}
+	}
 
-func (s *adminService) submitTranscodingJobs(v *cohesioned.Video) error {
-	sess, err := s.cfg.NewSession()
-	if err != nil {
-		return fmt.Errorf("Error creating session %v", err)
-	}
-
-	svc := elastictranscoder.New(sess)
-
-	//TODO - grab this config from s.cfg.TranscodingParams
-	pipelineID := "1507471006678-grs7px"
-	outputFolder := "outputs/"
-	outputKeyPrefix := "480p-16x9"
-	outputPresetID := "1351620000001-000020"
-
-	input := &elastictranscoder.CreateJobInput{}
-	input.SetPipelineId(pipelineID)
-	input.SetOutputKeyPrefix(outputFolder)
-	input.SetInput(&elastictranscoder.JobInput{
-		Key: aws.String(v.StorageObjectName),
-	})
-	input.SetOutput(&elastictranscoder.CreateJobOutput{
-		Key:              aws.String(fmt.Sprintf("%s-%s", outputKeyPrefix, v.StorageObjectName)),
-		PresetId:         aws.String(outputPresetID),
-		ThumbnailPattern: aws.String(fmt.Sprintf("%s-{resolution}-{count}", v.StorageObjectName)),
-	})
-
-	resp, err := svc.CreateJob(input)
+	tc := s.cfg.GetTranscodingConfig()
+	thumbnails, err := s.store.List(bucket, tc.ThumbnailPrefix(key))
 	if err != nil {
-		return fmt.Errorf("Failed to create job: %v", err)
+		fmt.Printf("Failed to list the thumbnails of %s: %v\n", key, err)
+	}
+
+	for _, obj := range thumbnails {
+		keys = append(keys, obj.Key)
+	}
+
+	for _, k := range keys {
+		if err := s.store.Delete(bucket, k); err != nil {
+			fmt.Printf("Failed to delete %s from %s: %v\n", k, bucket, err)
+		}
+	}
+}
+
+//transcode submits a job for each configured preset. A job the transcoder rejects is recorded as failed rather than failing the upload
+func (s *adminService) transcode(v *cohesioned.Video) error {
+	if s.transcoder == nil {
+		return nil
+	}
+
+	tc := s.cfg.GetTranscodingConfig()
+	for _, preset := range tc.AllPresets() {
+		job, err := s.transcoder.Submit(v, preset)
+		if err != nil {
+			fmt.Printf("Failed to submit %s transcoding job for video %d: %v\n", preset.Name, v.ID, err)
+			job = &cohesioned.TranscodingJob{
+				VideoID:   v.ID,
+				Preset:    preset.Name,
+				Status:    cohesioned.TranscodingFailed,
+				OutputKey: tc.OutputKey(preset, v.StorageObjectName),
+				Error:     err.Error(),
+			}
+		}
+
+		job.Created = time.Now()
+		if job.ID, err = s.videoRepo.SaveTranscodingJob(job); err != nil {
+			return err
+		}
+
+		//some transcoders finish as soon as the job is submitted
+		if job.Status != cohesioned.TranscodingComplete {
+			continue
+		}
+
+		rendition, err := s.transcoder.Refresh(job)
+		if err != nil {
+			fmt.Printf("Failed to read %s rendition for video %d: %v\n", preset.Name, v.ID, err)
+			continue
+		}
+
+		if rendition != nil && rendition.ObjectKey == v.StorageObjectName && rendition.Size == 0 {
+			rendition.Size = v.FileSize
+		}
+
+		if err := s.saveRendition(rendition); err != nil {
+			return err
+		}
+
+		if len(tc.Presets) > 0 && preset == tc.Presets[0] {
+			if _, err := s.recordThumbnails(v); err != nil {
+				return err
+			}
+		}
 	}
 
-	fmt.Printf("create job response: %v\n", resp)
 	return nil
 }
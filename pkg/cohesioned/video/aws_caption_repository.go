@@ -0,0 +1,136 @@
+package video
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/db"
+)
+
+//SaveCaptions inserts the caption track, replacing the track the video already has in the same language
+func (repo *awsRepo) SaveCaptions(c *cohesioned.CaptionTrack) (int64, error) {
+	insertSql := `insert into video_caption
+	(
+		video_id,
+		language,
+		label,
+		object_key,
+		transcript,
+		created,
+		updated
+	) values (?, ?, ?, ?, ?, ?, ?)
+	on duplicate key update
+		id = last_insert_id(id),
+		label = values(label),
+		object_key = values(object_key),
+		transcript = values(transcript),
+		updated = values(updated)`
+
+	result, err := repo.Exec(insertSql, c.VideoID, c.Language, c.Label, c.ObjectKey, c.Transcript, c.Created, c.Updated)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to save %s captions for video %d: %v", c.Language, c.VideoID, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get last insert id from result: %v", err)
+	}
+
+	return id, nil
+}
+
+//ListCaptions lists the video's caption tracks by language. Transcripts aren't loaded
+func (repo *awsRepo) ListCaptions(videoID int64) ([]*cohesioned.CaptionTrack, error) {
+	var list []*cohesioned.CaptionTrack
+
+	selectQuery := `select
+		id,
+		video_id,
+		language,
+		label,
+		object_key,
+		created,
+		updated
+	from video_caption
+	where video_id = ?
+	order by language`
+
+	rows, err := repo.Query(selectQuery, videoID)
+	if err != nil {
+		return list, fmt.Errorf("Failed to list the captions of video %d: %v", videoID, err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		c := &cohesioned.CaptionTrack{}
+		var updated db.NullTime
+
+		if err := rows.Scan(&c.ID, &c.VideoID, &c.Language, &c.Label, &c.ObjectKey, &c.Created, &updated); err != nil {
+			return list, fmt.Errorf("failed to map row to caption track: %v", err)
+		}
+
+		c.Updated = updated.Time
+		list = append(list, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return list, fmt.Errorf("caption rows had an error: %v", err)
+	}
+
+	return list, nil
+}
+
+//DeleteCaptions deletes the video's caption track in the given language
+func (repo *awsRepo) DeleteCaptions(videoID int64, language string) error {
+	if _, err := repo.Exec(`delete from video_caption where video_id = ? and language = ?`, videoID, language); err != nil {
+		return fmt.Errorf("Failed to delete the %s captions of video %d: %v", language, videoID, err)
+	}
+
+	return nil
+}
+
+//ListTranscripts returns the transcripts of each of the videos' caption tracks, joined into one text per video
+func (repo *awsRepo) ListTranscripts(videoIDs []int64) (map[int64]string, error) {
+	transcripts := make(map[int64]string)
+	if len(videoIDs) == 0 {
+		return transcripts, nil
+	}
+
+	args := make([]interface{}, len(videoIDs))
+	for i, id := range videoIDs {
+		args[i] = id
+	}
+
+	selectQuery := fmt.Sprintf(`select video_id, transcript
+	from video_caption
+	where video_id in (%s) and transcript is not null
+	order by video_id, language`, db.Placeholders(len(videoIDs)))
+
+	rows, err := repo.Query(selectQuery, args...)
+	if err != nil {
+		return transcripts, fmt.Errorf("Failed to list transcripts: %v", err)
+	}
+
+	defer rows.Close()
+	byVideo := make(map[int64][]string)
+	for rows.Next() {
+		var videoID int64
+		var transcript string
+		if err := rows.Scan(&videoID, &transcript); err != nil {
+			return transcripts, fmt.Errorf("failed to map row to transcript: %v", err)
+		}
+
+		byVideo[videoID] = append(byVideo[videoID], transcript)
+	}
+
+	if err := rows.Err(); err != nil {
+		return transcripts, fmt.Errorf("transcript rows had an error: %v", err)
+	}
+
+	for id, texts := range byVideo {
+		transcripts[id] = strings.Join(texts, " ")
+	}
+
+	return transcripts, nil
+}
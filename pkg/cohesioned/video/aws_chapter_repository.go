@@ -0,0 +1,109 @@
+package video
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/db"
+)
+
+type awsChapterRepo struct {
+	*sql.DB
+}
+
+func NewAwsChapterRepo(db *sql.DB) ChapterRepo {
+	return &awsChapterRepo{
+		DB: db,
+	}
+}
+
+//ListChapters lists the video's chapters in the order they start
+func (repo *awsChapterRepo) ListChapters(videoID int64) ([]*cohesioned.Chapter, error) {
+	var list []*cohesioned.Chapter
+
+	selectQuery := `select
+		id,
+		video_id,
+		title,
+		start_seconds,
+		end_seconds,
+		created,
+		updated
+	from video_chapter
+	where video_id = ?
+	order by start_seconds, id`
+
+	rows, err := repo.Query(selectQuery, videoID)
+	if err != nil {
+		return list, fmt.Errorf("Failed to list the chapters of video %d: %v", videoID, err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		c := &cohesioned.Chapter{}
+		var updated db.NullTime
+
+		if err := rows.Scan(&c.ID, &c.VideoID, &c.Title, &c.Start, &c.End, &c.Created, &updated); err != nil {
+			return list, fmt.Errorf("failed to map row to chapter: %v", err)
+		}
+
+		c.Updated = updated.Time
+		list = append(list, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return list, fmt.Errorf("chapter rows had an error: %v", err)
+	}
+
+	return list, nil
+}
+
+func (repo *awsChapterRepo) SaveChapter(c *cohesioned.Chapter) (int64, error) {
+	insertSql := `insert into video_chapter
+	(
+		video_id,
+		title,
+		start_seconds,
+		end_seconds,
+		created
+	) values (?, ?, ?, ?, ?)`
+
+	result, err := repo.Exec(insertSql, c.VideoID, c.Title, c.Start, c.End, c.Created)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to save chapter %s of video %d: %v", c.Title, c.VideoID, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get last insert id from result: %v", err)
+	}
+
+	return id, nil
+}
+
+func (repo *awsChapterRepo) UpdateChapter(c *cohesioned.Chapter) error {
+	updateSql := `update video_chapter set
+		title = ?,
+		start_seconds = ?,
+		end_seconds = ?,
+		updated = ?
+	where
+		id = ?
+	and
+		video_id = ?`
+
+	if _, err := repo.Exec(updateSql, c.Title, c.Start, c.End, c.Updated, c.ID, c.VideoID); err != nil {
+		return fmt.Errorf("Failed to update chapter %d of video %d: %v", c.ID, c.VideoID, err)
+	}
+
+	return nil
+}
+
+func (repo *awsChapterRepo) DeleteChapter(videoID, id int64) error {
+	if _, err := repo.Exec(`delete from video_chapter where id = ? and video_id = ?`, id, videoID); err != nil {
+		return fmt.Errorf("Failed to delete chapter %d of video %d: %v", id, videoID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,242 @@
+package video
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/db"
+)
+
+type awsFAQRepo struct {
+	*sql.DB
+}
+
+func NewAwsFAQRepo(db *sql.DB) FAQRepo {
+	return &awsFAQRepo{
+		DB: db,
+	}
+}
+
+const faqColumns = `
+		id,
+		video_id,
+		question,
+		answer,
+		status,
+		position,
+		asked_by,
+		answered,
+		answered_by,
+		created,
+		created_by,
+		updated,
+		updated_by`
+
+var faqQuery = db.ListQuery{
+	Select: `select` + faqColumns,
+	From:   `from video_faq`,
+	Columns: map[string]string{
+		"id":       "id",
+		"video_id": "video_id",
+		"status":   "status",
+		"asked_by": "asked_by",
+		"created":  "created",
+		"answered": "answered",
+	},
+}
+
+//ListFAQs lists the video's FAQs that are in one of the given statuses, in the order they are shown
+func (repo *awsFAQRepo) ListFAQs(videoID int64, statuses ...cohesioned.FAQStatus) ([]*cohesioned.FAQ, error) {
+	if len(statuses) == 0 {
+		return nil, nil
+	}
+
+	args := []interface{}{videoID}
+	for _, status := range statuses {
+		args = append(args, string(status))
+	}
+
+	selectQuery := fmt.Sprintf(`select %s
+	from video_faq
+	where video_id = ? and status in (%s)
+	order by position, id`, faqColumns, db.Placeholders(len(statuses)))
+
+	list, err := repo.queryFAQs(selectQuery, args...)
+	if err != nil {
+		return list, fmt.Errorf("Failed to list the FAQs of video %d: %v", videoID, err)
+	}
+
+	return list, nil
+}
+
+//GetFAQ finds one of the video's FAQs, or returns nil if the video has no FAQ with that ID
+func (repo *awsFAQRepo) GetFAQ(videoID, id int64) (*cohesioned.FAQ, error) {
+	list, err := repo.queryFAQs(fmt.Sprintf(`select %s from video_faq where id = ? and video_id = ?`, faqColumns), id, videoID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get FAQ %d of video %d: %v", id, videoID, err)
+	}
+
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	return list[0], nil
+}
+
+//ListQuestions pages through the FAQs of every video, e.g. the questions parents asked that are waiting for an answer
+func (repo *awsFAQRepo) ListQuestions(opts *cohesioned.ListOptions) ([]*cohesioned.FAQ, int64, error) {
+	var list []*cohesioned.FAQ
+	var total int64
+
+	selectQuery, args, countQuery, countArgs, err := faqQuery.Build(opts)
+	if err != nil {
+		return list, total, err
+	}
+
+	if err := repo.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		return list, total, fmt.Errorf("Failed to count FAQs: %v", err)
+	}
+
+	if list, err = repo.queryFAQs(selectQuery, args...); err != nil {
+		return list, total, fmt.Errorf("Failed to list FAQs: %v", err)
+	}
+
+	return list, total, nil
+}
+
+func (repo *awsFAQRepo) SaveFAQ(f *cohesioned.FAQ) (int64, error) {
+	insertSql := `insert into video_faq
+	(
+		video_id,
+		question,
+		answer,
+		status,
+		position,
+		asked_by,
+		answered,
+		answered_by,
+		created,
+		created_by
+	) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := repo.Exec(
+		insertSql,
+		f.VideoID,
+		f.Question,
+		sql.NullString{String: f.Answer, Valid: len(f.Answer) > 0},
+		string(f.Status),
+		f.Position,
+		sql.NullInt64{Int64: f.AskedBy, Valid: f.AskedBy > 0},
+		db.NullTime{Time: f.Answered, Valid: !f.Answered.IsZero()},
+		sql.NullInt64{Int64: f.AnsweredBy, Valid: f.AnsweredBy > 0},
+		f.Created,
+		f.CreatedBy,
+	)
+
+	if err != nil {
+		return 0, fmt.Errorf("Failed to save FAQ of video %d: %v", f.VideoID, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get last insert id from result: %v", err)
+	}
+
+	return id, nil
+}
+
+func (repo *awsFAQRepo) UpdateFAQ(f *cohesioned.FAQ) error {
+	updateSql := `update video_faq set
+		question = ?,
+		answer = ?,
+		status = ?,
+		position = ?,
+		answered = ?,
+		answered_by = ?,
+		updated = ?,
+		updated_by = ?
+	where
+		id = ?
+	and
+		video_id = ?`
+
+	_, err := repo.Exec(
+		updateSql,
+		f.Question,
+		sql.NullString{String: f.Answer, Valid: len(f.Answer) > 0},
+		string(f.Status),
+		f.Position,
+		db.NullTime{Time: f.Answered, Valid: !f.Answered.IsZero()},
+		sql.NullInt64{Int64: f.AnsweredBy, Valid: f.AnsweredBy > 0},
+		f.Updated,
+		f.UpdatedBy,
+		f.ID,
+		f.VideoID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("Failed to update FAQ %d of video %d: %v", f.ID, f.VideoID, err)
+	}
+
+	return nil
+}
+
+func (repo *awsFAQRepo) DeleteFAQ(videoID, id int64) error {
+	if _, err := repo.Exec(`delete from video_faq where id = ? and video_id = ?`, id, videoID); err != nil {
+		return fmt.Errorf("Failed to delete FAQ %d of video %d: %v", id, videoID, err)
+	}
+
+	return nil
+}
+
+func (repo *awsFAQRepo) queryFAQs(query string, args ...interface{}) ([]*cohesioned.FAQ, error) {
+	var list []*cohesioned.FAQ
+
+	rows, err := repo.Query(query, args...)
+	if err != nil {
+		return list, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		f := &cohesioned.FAQ{}
+		var answer sql.NullString
+		var askedBy, answeredBy, updatedBy sql.NullInt64
+		var answered, updated db.NullTime
+
+		err := rows.Scan(
+			&f.ID,
+			&f.VideoID,
+			&f.Question,
+			&answer,
+			&f.Status,
+			&f.Position,
+			&askedBy,
+			&answered,
+			&answeredBy,
+			&f.Created,
+			&f.CreatedBy,
+			&updated,
+			&updatedBy,
+		)
+
+		if err != nil {
+			return list, fmt.Errorf("failed to map row to FAQ: %v", err)
+		}
+
+		f.Answer = answer.String
+		f.AskedBy = askedBy.Int64
+		f.Answered = answered.Time
+		f.AnsweredBy = answeredBy.Int64
+		f.Updated = updated.Time
+		f.UpdatedBy = updatedBy.Int64
+		list = append(list, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return list, fmt.Errorf("FAQ rows had an error: %v", err)
+	}
+
+	return list, nil
+}
@@ -0,0 +1,125 @@
+package video
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+type awsRelatedRepo struct {
+	*sql.DB
+}
+
+func NewAwsRelatedRepo(db *sql.DB) RelatedRepo {
+	return &awsRelatedRepo{
+		DB: db,
+	}
+}
+
+//ListRelatedIDs lists the IDs of the videos an admin linked to the video, in the order they were given
+func (repo *awsRelatedRepo) ListRelatedIDs(videoID int64) ([]int64, error) {
+	var ids []int64
+
+	rows, err := repo.Query(`select related_id from video_related where video_id = ? order by position`, videoID)
+	if err != nil {
+		return ids, fmt.Errorf("Failed to list the related videos of video %d: %v", videoID, err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return ids, fmt.Errorf("failed to map row to related video id: %v", err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return ids, fmt.Errorf("related video rows had an error: %v", err)
+	}
+
+	return ids, nil
+}
+
+//SetRelated replaces the videos an admin linked to the video
+func (repo *awsRelatedRepo) SetRelated(videoID int64, relatedIDs []int64) error {
+	tx, err := repo.Begin()
+	if err != nil {
+		return fmt.Errorf("Failed to begin transaction: %v", err)
+	}
+
+	if _, err := tx.Exec(`delete from video_related where video_id = ?`, videoID); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("Failed to clear the related videos of video %d: %v", videoID, err)
+	}
+
+	for position, relatedID := range relatedIDs {
+		insertSql := `insert into video_related (video_id, related_id, position, created) values (?, ?, ?, ?)`
+		if _, err := tx.Exec(insertSql, videoID, relatedID, position, time.Now()); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Failed to relate video %d to video %d: %v", relatedID, videoID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("Failed to commit the related videos of video %d: %v", videoID, err)
+	}
+
+	return nil
+}
+
+//SuggestRelated scores every other video that isn't already linked to the video and returns the best limit of them, highest score first and then newest first.
+//A video scores 3 for each key term or standard it shares with the video, plus 2 if it is in the same taxonomy or 1 if its taxonomy is a sibling.
+//Videos that score 0 aren't suggested. Only the ID of each suggested Video is set
+func (repo *awsRelatedRepo) SuggestRelated(videoID int64, limit int) ([]*cohesioned.RelatedVideo, error) {
+	var list []*cohesioned.RelatedVideo
+
+	selectQuery := `select
+		c.id,
+		3 * (
+			select count(*) from video_tag_map a, video_tag_map b
+			where a.video_id = v.id and b.video_id = c.id and a.tag_id = b.tag_id
+		) + case
+			when c.taxonomy_id = v.taxonomy_id then 2
+			when ct.parent_id = vt.parent_id then 1
+			else 0
+		end as score
+	from video v, taxonomy vt, video c, taxonomy ct
+	where
+		v.id = ?
+	and
+		vt.id = v.taxonomy_id
+	and
+		ct.id = c.taxonomy_id
+	and
+		c.id <> v.id
+	and
+		c.id not in (select related_id from video_related where video_id = v.id)
+	having score > 0
+	order by score desc, c.created desc, c.id desc
+	limit ?`
+
+	rows, err := repo.Query(selectQuery, videoID, limit)
+	if err != nil {
+		return list, fmt.Errorf("Failed to suggest videos related to video %d: %v", videoID, err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		r := &cohesioned.RelatedVideo{Video: &cohesioned.Video{}}
+		if err := rows.Scan(&r.ID, &r.Score); err != nil {
+			return list, fmt.Errorf("failed to map row to related video: %v", err)
+		}
+
+		list = append(list, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return list, fmt.Errorf("related video rows had an error: %v", err)
+	}
+
+	return list, nil
+}
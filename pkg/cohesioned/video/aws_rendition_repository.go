@@ -0,0 +1,110 @@
+package video
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+//SaveRendition inserts the rendition, replacing any the video already has with the same name
+func (repo *awsRepo) SaveRendition(r *cohesioned.Rendition) (int64, error) {
+	insertSql := `insert into video_rendition
+	(
+		video_id,
+		name,
+		width,
+		height,
+		bitrate,
+		object_key,
+		size,
+		created
+	) values (?, ?, ?, ?, ?, ?, ?, ?)
+	on duplicate key update
+		id = last_insert_id(id),
+		width = values(width),
+		height = values(height),
+		bitrate = values(bitrate),
+		object_key = values(object_key),
+		size = values(size),
+		created = values(created)`
+
+	result, err := repo.Exec(insertSql, r.VideoID, r.Name, r.Width, r.Height, r.Bitrate, r.ObjectKey, r.Size, r.Created)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to save %s rendition for video %d: %v", r.Name, r.VideoID, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get last insert id from result: %v", err)
+	}
+
+	return id, nil
+}
+
+//ListRenditions lists the video's renditions, lowest bitrate first
+func (repo *awsRepo) ListRenditions(videoID int64) ([]*cohesioned.Rendition, error) {
+	var list []*cohesioned.Rendition
+
+	selectQuery := `select
+		id,
+		video_id,
+		name,
+		width,
+		height,
+		bitrate,
+		object_key,
+		size,
+		created
+	from video_rendition
+	where video_id = ?
+	order by bitrate, id`
+
+	rows, err := repo.Query(selectQuery, videoID)
+	if err != nil {
+		return list, fmt.Errorf("Failed to list renditions for video %d: %v", videoID, err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		r := &cohesioned.Rendition{}
+		var width, height, bitrate, size sql.NullInt64
+
+		err := rows.Scan(
+			&r.ID,
+			&r.VideoID,
+			&r.Name,
+			&width,
+			&height,
+			&bitrate,
+			&r.ObjectKey,
+			&size,
+			&r.Created,
+		)
+
+		if err != nil {
+			return list, fmt.Errorf("failed to map row to rendition: %v", err)
+		}
+
+		r.Width = width.Int64
+		r.Height = height.Int64
+		r.Bitrate = bitrate.Int64
+		r.Size = size.Int64
+		list = append(list, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return list, fmt.Errorf("rendition rows had an error: %v", err)
+	}
+
+	return list, nil
+}
+
+//DeleteRenditions deletes every rendition of the video
+func (repo *awsRepo) DeleteRenditions(videoID int64) error {
+	if _, err := repo.Exec(`delete from video_rendition where video_id = ?`, videoID); err != nil {
+		return fmt.Errorf("Failed to delete renditions of video %d: %v", videoID, err)
+	}
+
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
 	"github.com/cohesion-education/api/pkg/cohesioned/config"
 	"github.com/cohesion-education/api/pkg/cohesioned/video"
 	"github.com/cohesion-education/api/testutils"
@@ -57,11 +58,15 @@ func TestGet(t *testing.T) {
 }
 
 func TestList(t *testing.T) {
-	list, err := repo.List()
+	list, total, err := repo.List(cohesioned.NewListOptions(video.ListSpec))
 	if err != nil {
 		t.Errorf("Failed to List videos: %v", err)
 	}
 
+	if total != int64(len(list)) {
+		t.Errorf("expected total %d to match the %d videos listed", total, len(list))
+	}
+
 	if len(list) == 0 {
 		t.Error("repo returned empty list")
 	}
@@ -8,10 +8,11 @@ import (
 	"github.com/cohesion-education/api/pkg/cohesioned"
 	"github.com/cohesion-education/api/pkg/cohesioned/config"
 	"github.com/cohesion-education/api/pkg/cohesioned/db"
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 )
 
-const videoThumbnailURLTemplate string = "https://s3.amazonaws.com/%s/transcoded/thumbnails/%s-192x108-00003.png"
+//mysqlDuplicateEntry is the MySQL error number for an insert or update that violates a unique index
+const mysqlDuplicateEntry = 1062
 
 type awsRepo struct {
 	*sql.DB
@@ -25,11 +26,6 @@ func NewAwsRepo(db *sql.DB, awsConfig config.AwsConfig) Repo {
 	}
 }
 
-//temporary func based on pattern for thumbnails created by transcoding service
-func (repo *awsRepo) getThumbnailURL(video *cohesioned.Video) string {
-	return fmt.Sprintf(videoThumbnailURLTemplate, repo.awsConfig.GetVideoBucket(), video.StorageObjectName)
-}
-
 func (repo *awsRepo) Get(id int64) (*cohesioned.Video, error) {
 	selectQuery := `select
 		v.id,
@@ -40,9 +36,8 @@ func (repo *awsRepo) Get(id int64) (*cohesioned.Video, error) {
 		v.file_size,
 		v.bucket,
 		v.object_key,
-		v.key_terms,
-		v.state_standards,
-		v.common_core_standards,
+		v.checksum,
+		v.duration,
 		v.created,
 		v.created_by,
 		v.updated,
@@ -62,15 +57,24 @@ func (repo *awsRepo) Get(id int64) (*cohesioned.Video, error) {
 	row := repo.QueryRow(selectQuery, id)
 	video, err := repo.mapRowToObject(row)
 	if err != nil {
-		switch {
-		case err == sql.ErrNoRows:
-			return nil, fmt.Errorf("No video with ID %d", id)
-		default:
-			return nil, fmt.Errorf("Unexpected error querying for video by id %d: %v", id, err)
+		if err == sql.ErrNoRows {
+			return nil, nil
 		}
+
+		return nil, fmt.Errorf("Unexpected error querying for video by id %d: %v", id, err)
+	}
+
+	if err := repo.loadTags(video); err != nil {
+		return nil, err
+	}
+
+	if err := repo.loadPosters(video); err != nil {
+		return nil, err
 	}
 
-	video.ThumbnailURL = repo.getThumbnailURL(video)
+	if err := repo.loadTeachers(video); err != nil {
+		return nil, err
+	}
 
 	return video, nil
 }
@@ -95,6 +99,30 @@ func (repo *awsRepo) Delete(id int64) error {
 	return nil
 }
 
+//SetDuration records the length of the video's file in seconds
+func (repo *awsRepo) SetDuration(videoID int64, seconds float64) error {
+	if _, err := repo.Exec(`update video set duration = ? where id = ?`, seconds, videoID); err != nil {
+		return fmt.Errorf("Failed to set the duration of video %d: %v", videoID, err)
+	}
+
+	return nil
+}
+
+//FindByChecksum returns the video whose file has the given checksum, or nil if there isn't one
+func (repo *awsRepo) FindByChecksum(checksum string) (*cohesioned.Video, error) {
+	var id int64
+	err := repo.QueryRow(`select id from video where checksum = ?`, checksum).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Unexpected error querying for video by checksum %s: %v", checksum, err)
+	}
+
+	return repo.Get(id)
+}
+
 func (repo *awsRepo) FindByTaxonomyID(taxonomyID int64) ([]*cohesioned.Video, error) {
 	var list []*cohesioned.Video
 
@@ -107,9 +135,8 @@ func (repo *awsRepo) FindByTaxonomyID(taxonomyID int64) ([]*cohesioned.Video, er
 		v.file_size,
 		v.bucket,
 		v.object_key,
-		v.key_terms,
-		v.state_standards,
-		v.common_core_standards,
+		v.checksum,
+		v.duration,
 		v.created,
 		v.created_by,
 		v.updated,
@@ -138,7 +165,6 @@ func (repo *awsRepo) FindByTaxonomyID(taxonomyID int64) ([]*cohesioned.Video, er
 			return list, fmt.Errorf("an unexpected error occurred while processing the result set from the db: %v", err)
 		}
 
-		video.ThumbnailURL = repo.getThumbnailURL(video)
 		list = append(list, video)
 	}
 
@@ -146,13 +172,23 @@ func (repo *awsRepo) FindByTaxonomyID(taxonomyID int64) ([]*cohesioned.Video, er
 		return list, fmt.Errorf("rows had an error: %v", err)
 	}
 
+	if err := repo.loadTags(list...); err != nil {
+		return list, err
+	}
+
+	if err := repo.loadPosters(list...); err != nil {
+		return list, err
+	}
+
+	if err := repo.loadTeachers(list...); err != nil {
+		return list, err
+	}
+
 	return list, nil
 }
 
-func (repo *awsRepo) List() ([]*cohesioned.Video, error) {
-	var list []*cohesioned.Video
-
-	selectQuery := `select
+var listQuery = db.ListQuery{
+	Select: `select
 		v.id,
 		v.title,
 		v.taxonomy_id,
@@ -161,46 +197,247 @@ func (repo *awsRepo) List() ([]*cohesioned.Video, error) {
 		v.file_size,
 		v.bucket,
 		v.object_key,
-		v.key_terms,
-		v.state_standards,
-		v.common_core_standards,
+		v.checksum,
+		v.duration,
 		v.created,
 		v.created_by,
 		v.updated,
 		v.updated_by,
 		u.full_name,
 		t.name,
-		t.parent_id
-		from
-			video v, user u, taxonomy t
-		where
-			v.taxonomy_id = t.id
-		and
-			v.created_by = u.id`
+		t.parent_id`,
+	From:  `from video v, user u, taxonomy t`,
+	Where: []string{"v.taxonomy_id = t.id", "v.created_by = u.id"},
+	Columns: map[string]string{
+		"id":          "v.id",
+		"title":       "v.title",
+		"created":     "v.created",
+		"updated":     "v.updated",
+		"taxonomy_id": "v.taxonomy_id",
+		"created_by":  "v.created_by",
+	},
+}
+
+func (repo *awsRepo) List(opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error) {
+	return repo.list(listQuery, opts)
+}
+
+//FindByTag lists the videos tagged with name as any of the given kinds
+func (repo *awsRepo) FindByTag(kinds []cohesioned.TagKind, name string, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error) {
+	q := listQuery
+	q.Where = append(append([]string{}, listQuery.Where...), fmt.Sprintf(`exists (
+		select 1 from video_tag_map m, video_tag g
+		where m.video_id = v.id and m.tag_id = g.id and g.name = ? and g.kind in (%s)
+	)`, db.Placeholders(len(kinds))))
+
+	q.Args = []interface{}{name}
+	for _, kind := range kinds {
+		q.Args = append(q.Args, string(kind))
+	}
+
+	return repo.list(q, opts)
+}
+
+//FindByJurisdiction lists the videos tagged with a state standard that the standards catalog has for the jurisdiction
+func (repo *awsRepo) FindByJurisdiction(jurisdiction string, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error) {
+	q := listQuery
+	q.Where = append(append([]string{}, listQuery.Where...), `exists (
+		select 1 from video_tag_map m, video_tag g, standard s
+		where m.video_id = v.id and m.tag_id = g.id and g.kind = ? and s.code = g.name and s.jurisdiction = ?
+	)`)
+
+	q.Args = []interface{}{string(cohesioned.TagStateStandard), jurisdiction}
+	return repo.list(q, opts)
+}
+
+func (repo *awsRepo) list(q db.ListQuery, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error) {
+	var list []*cohesioned.Video
+	var total int64
 
-	rows, err := repo.Query(selectQuery)
+	selectQuery, args, countQuery, countArgs, err := q.Build(opts)
 	if err != nil {
-		return list, fmt.Errorf("Failed to execute query: %v", err)
+		return list, total, err
+	}
+
+	if err := repo.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		return list, total, fmt.Errorf("Failed to count videos: %v", err)
+	}
+
+	rows, err := repo.Query(selectQuery, args...)
+	if err != nil {
+		return list, total, fmt.Errorf("Failed to execute query: %v", err)
 	}
 
 	defer rows.Close()
 	for rows.Next() {
 		video, err := repo.mapRowToObject(rows)
 		if err != nil {
-			return list, fmt.Errorf("an unexpected error occurred while processing the result set from the db: %v", err)
+			return list, total, fmt.Errorf("an unexpected error occurred while processing the result set from the db: %v", err)
 		}
 
-		video.ThumbnailURL = repo.getThumbnailURL(video)
 		list = append(list, video)
 	}
 
 	if err := rows.Err(); err != nil {
-		return list, fmt.Errorf("rows had an error: %v", err)
+		return list, total, fmt.Errorf("rows had an error: %v", err)
+	}
+
+	if err := repo.loadTags(list...); err != nil {
+		return list, total, err
+	}
+
+	if err := repo.loadPosters(list...); err != nil {
+		return list, total, err
+	}
+
+	if err := repo.loadTeachers(list...); err != nil {
+		return list, total, err
+	}
+
+	return list, total, nil
+}
+
+//ListTags lists every tag of the given kinds with the number of videos tagged with it, most used first
+func (repo *awsRepo) ListTags(kinds ...cohesioned.TagKind) ([]*cohesioned.Tag, error) {
+	var list []*cohesioned.Tag
+	if len(kinds) == 0 {
+		return list, nil
+	}
+
+	selectQuery := fmt.Sprintf(`select
+		t.id,
+		t.kind,
+		t.name,
+		count(m.video_id)
+	from
+		video_tag t left join video_tag_map m on m.tag_id = t.id
+	where
+		t.kind in (%s)
+	group by t.id, t.kind, t.name
+	order by count(m.video_id) desc, t.name`, db.Placeholders(len(kinds)))
+
+	args := make([]interface{}, len(kinds))
+	for i, kind := range kinds {
+		args[i] = string(kind)
+	}
+
+	rows, err := repo.Query(selectQuery, args...)
+	if err != nil {
+		return list, fmt.Errorf("Failed to list tags: %v", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		tag := &cohesioned.Tag{}
+		if err := rows.Scan(&tag.ID, &tag.Kind, &tag.Name, &tag.VideoCount); err != nil {
+			return list, fmt.Errorf("failed to map row to tag: %v", err)
+		}
+
+		list = append(list, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return list, fmt.Errorf("tag rows had an error: %v", err)
 	}
 
 	return list, nil
 }
 
+//searchColumns must match the columns of the video_search_idx fulltext index
+const searchColumns = "v.title, v.key_terms, v.state_standards, v.common_core_standards"
+
+//Search ranks videos matching any of the terms in their fields or caption transcripts, boosting matches in the title. When taxonomyIDs is not empty only videos in those taxonomies are matched
+func (repo *awsRepo) Search(terms []string, taxonomyIDs []int64, opts *cohesioned.ListOptions) ([]*cohesioned.SearchResult, int64, error) {
+	var list []*cohesioned.SearchResult
+	var total int64
+
+	if len(terms) == 0 {
+		return list, total, nil
+	}
+
+	natural := strings.Join(terms, " ")
+	boolean := booleanQuery(terms)
+
+	where := fmt.Sprintf(`(match(%s) against (? in boolean mode)
+		or v.id in (select c.video_id from video_caption c where match(c.transcript) against (? in boolean mode)))`, searchColumns)
+	whereArgs := []interface{}{boolean, boolean}
+
+	if len(taxonomyIDs) > 0 {
+		where = fmt.Sprintf("%s and v.taxonomy_id in (%s)", where, db.Placeholders(len(taxonomyIDs)))
+		for _, id := range taxonomyIDs {
+			whereArgs = append(whereArgs, id)
+		}
+	}
+
+	countQuery := fmt.Sprintf(`select count(*)
+	%s
+	where
+		v.taxonomy_id = t.id
+	and
+		v.created_by = u.id
+	and
+		%s`, listQuery.From, where)
+	if err := repo.QueryRow(countQuery, whereArgs...).Scan(&total); err != nil {
+		return list, total, fmt.Errorf("Failed to count search results: %v", err)
+	}
+
+	selectQuery := fmt.Sprintf(`%s,
+		(match(v.title) against (? in natural language mode) * 2 + match(%s) against (? in natural language mode)
+			+ coalesce((select max(match(c.transcript) against (? in natural language mode)) from video_caption c where c.video_id = v.id), 0)) as score
+	%s
+	where
+		v.taxonomy_id = t.id
+	and
+		v.created_by = u.id
+	and
+		%s
+	order by score desc, v.id desc
+	limit ? offset ?`, listQuery.Select, searchColumns, listQuery.From, where)
+
+	args := append([]interface{}{natural, natural, natural}, whereArgs...)
+	args = append(args, opts.Limit, opts.Offset)
+
+	rows, err := repo.Query(selectQuery, args...)
+	if err != nil {
+		return list, total, fmt.Errorf("Failed to execute search query: %v", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		result := &cohesioned.SearchResult{}
+		video, err := repo.mapRowToObject(rows, &result.Score)
+		if err != nil {
+			return list, total, fmt.Errorf("an unexpected error occurred while processing the search result set from the db: %v", err)
+		}
+
+		result.Video = video
+		list = append(list, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return list, total, fmt.Errorf("search rows had an error: %v", err)
+	}
+
+	videos := make([]*cohesioned.Video, len(list))
+	for i, result := range list {
+		videos[i] = result.Video
+	}
+
+	if err := repo.loadTags(videos...); err != nil {
+		return list, total, err
+	}
+
+	if err := repo.loadPosters(videos...); err != nil {
+		return list, total, err
+	}
+
+	if err := repo.loadTeachers(videos...); err != nil {
+		return list, total, err
+	}
+
+	return list, total, nil
+}
+
 func (repo *awsRepo) Save(v *cohesioned.Video) (int64, error) {
 	insertSql := `insert into video
 	(
@@ -211,6 +448,8 @@ func (repo *awsRepo) Save(v *cohesioned.Video) (int64, error) {
 		file_size,
 		bucket,
 		object_key,
+		checksum,
+		duration,
 		key_terms,
 		state_standards,
 		common_core_standards,
@@ -219,15 +458,18 @@ func (repo *awsRepo) Save(v *cohesioned.Video) (int64, error) {
 	)
 	values
 	(
-		?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 	)`
 
-	stmt, err := repo.Prepare(insertSql)
+	tags := v.Tags()
+
+	tx, err := repo.Begin()
 	if err != nil {
-		return 0, fmt.Errorf("Failed to prepare statement %s: %v", insertSql, err)
+		return 0, fmt.Errorf("Failed to begin transaction: %v", err)
 	}
 
-	result, err := stmt.Exec(
+	result, err := tx.Exec(
+		insertSql,
 		v.Title,
 		v.TaxonomyID,
 		v.FileName,
@@ -235,25 +477,49 @@ func (repo *awsRepo) Save(v *cohesioned.Video) (int64, error) {
 		v.FileSize,
 		v.StorageBucket,
 		v.StorageObjectName,
-		strings.Join(v.KeyTerms, ","),
-		strings.Join(v.StateStandards, ","),
-		strings.Join(v.CommonCoreStandards, ","),
+		sql.NullString{String: v.Checksum, Valid: len(v.Checksum) > 0},
+		sql.NullFloat64{Float64: v.Duration, Valid: v.Duration > 0},
+		searchText(tags[cohesioned.TagKeyTerm]),
+		searchText(tags[cohesioned.TagStateStandard]),
+		searchText(tags[cohesioned.TagCommonCoreStandard]),
 		v.Created,
 		v.CreatedByID,
 	)
 
 	if err != nil {
+		tx.Rollback()
 		return 0, fmt.Errorf("Failed to insert video: %v", err)
 	}
 
 	id, err := result.LastInsertId()
 	if err != nil {
+		tx.Rollback()
 		return 0, fmt.Errorf("Failed to get last insert id from result: %v", err)
 	}
 
+	if err := setTags(tx, id, tags, v.KeyTermTimestamps); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err := setTeachers(tx, id, v.TeacherIDs); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("Failed to commit video: %v", err)
+	}
+
 	return id, nil
 }
 
+//isDuplicateChecksum returns true if err is MySQL rejecting a checksum that another video already has
+func isDuplicateChecksum(err error) bool {
+	mysqlErr, ok := err.(*mysql.MySQLError)
+	return ok && mysqlErr.Number == mysqlDuplicateEntry && strings.Contains(mysqlErr.Message, "video_checksum_UNIQUE")
+}
+
 func (repo *awsRepo) Update(v *cohesioned.Video) error {
 	updateSql := `update video set
 		title = ?,
@@ -263,6 +529,8 @@ func (repo *awsRepo) Update(v *cohesioned.Video) error {
 		file_size = ?,
 		bucket = ?,
 		object_key = ?,
+		checksum = ?,
+		duration = ?,
 		key_terms = ?,
 		state_standards = ?,
 		common_core_standards = ?,
@@ -271,12 +539,15 @@ func (repo *awsRepo) Update(v *cohesioned.Video) error {
 	where
 		id = ?`
 
-	stmt, err := repo.Prepare(updateSql)
+	tags := v.Tags()
+
+	tx, err := repo.Begin()
 	if err != nil {
-		return fmt.Errorf("Failed to prepare update statement %s: %v", updateSql, err)
+		return fmt.Errorf("Failed to begin transaction: %v", err)
 	}
 
-	result, err := stmt.Exec(
+	result, err := tx.Exec(
+		updateSql,
 		v.Title,
 		v.TaxonomyID,
 		v.FileName,
@@ -284,33 +555,158 @@ func (repo *awsRepo) Update(v *cohesioned.Video) error {
 		v.FileSize,
 		v.StorageBucket,
 		v.StorageObjectName,
-		strings.Join(v.KeyTerms, ","),
-		strings.Join(v.StateStandards, ","),
-		strings.Join(v.CommonCoreStandards, ","),
+		sql.NullString{String: v.Checksum, Valid: len(v.Checksum) > 0},
+		sql.NullFloat64{Float64: v.Duration, Valid: v.Duration > 0},
+		searchText(tags[cohesioned.TagKeyTerm]),
+		searchText(tags[cohesioned.TagStateStandard]),
+		searchText(tags[cohesioned.TagCommonCoreStandard]),
 		v.Updated,
 		v.UpdatedByID,
 		v.ID,
 	)
 
 	if err != nil {
+		tx.Rollback()
+		if isDuplicateChecksum(err) {
+			return ErrDuplicateChecksum
+		}
+
 		return fmt.Errorf("Failed to update video: %v", err)
 	}
 
 	rowsEffected, err := result.RowsAffected()
 	if err != nil || rowsEffected == 0 {
+		tx.Rollback()
 		return fmt.Errorf("Failed to update video: %v", err)
 	}
 
+	if err := setTags(tx, v.ID, tags, v.KeyTermTimestamps); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := setTeachers(tx, v.ID, v.TeacherIDs); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("Failed to commit video %d: %v", v.ID, err)
+	}
+
+	return nil
+}
+
+//searchText is how a list of tags is stored in the video table. Those columns only feed the fulltext index - video_tag is the source of truth
+func searchText(names []string) string {
+	return strings.Join(names, "\n")
+}
+
+//setTags replaces the video's tags, creating any tags that don't exist yet. Key terms are saved with their timestamp, if they have one
+func setTags(tx *sql.Tx, videoID int64, tags map[cohesioned.TagKind][]string, timestamps map[string]float64) error {
+	if _, err := tx.Exec(`delete from video_tag_map where video_id = ?`, videoID); err != nil {
+		return fmt.Errorf("Failed to clear the tags of video %d: %v", videoID, err)
+	}
+
+	for kind, names := range tags {
+		for position, name := range names {
+			//last_insert_id(id) makes LastInsertId return the existing tag's id when the tag already exists
+			result, err := tx.Exec(`insert into video_tag (kind, name) values (?, ?) on duplicate key update id = last_insert_id(id)`, string(kind), name)
+			if err != nil {
+				return fmt.Errorf("Failed to save %s %s: %v", kind, name, err)
+			}
+
+			tagID, err := result.LastInsertId()
+			if err != nil {
+				return fmt.Errorf("Failed to get the id of %s %s: %v", kind, name, err)
+			}
+
+			var seconds sql.NullFloat64
+			if kind == cohesioned.TagKeyTerm {
+				seconds.Float64, seconds.Valid = timestamps[name]
+			}
+
+			if _, err := tx.Exec(`insert into video_tag_map (video_id, tag_id, position, seconds) values (?, ?, ?, ?)`, videoID, tagID, position, seconds); err != nil {
+				return fmt.Errorf("Failed to tag video %d with %s %s: %v", videoID, kind, name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+//loadTags sets the key terms, their timestamps and the standards of each of the videos
+func (repo *awsRepo) loadTags(videos ...*cohesioned.Video) error {
+	if len(videos) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*cohesioned.Video)
+	args := make([]interface{}, len(videos))
+	for i, video := range videos {
+		byID[video.ID] = video
+		args[i] = video.ID
+	}
+
+	selectQuery := fmt.Sprintf(`select
+		m.video_id,
+		t.kind,
+		t.name,
+		m.seconds
+	from
+		video_tag_map m, video_tag t
+	where
+		m.tag_id = t.id
+	and
+		m.video_id in (%s)
+	order by m.video_id, m.position`, db.Placeholders(len(videos)))
+
+	rows, err := repo.Query(selectQuery, args...)
+	if err != nil {
+		return fmt.Errorf("Failed to query video tags: %v", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var videoID int64
+		var kind cohesioned.TagKind
+		var name string
+		var seconds sql.NullFloat64
+		if err := rows.Scan(&videoID, &kind, &name, &seconds); err != nil {
+			return fmt.Errorf("failed to map row to video tag: %v", err)
+		}
+
+		video, ok := byID[videoID]
+		if !ok {
+			continue
+		}
+
+		video.AddTag(kind, name)
+		if kind == cohesioned.TagKeyTerm && seconds.Valid {
+			if video.KeyTermTimestamps == nil {
+				video.KeyTermTimestamps = make(map[string]float64)
+			}
+
+			video.KeyTermTimestamps[name] = seconds.Float64
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("video tag rows had an error: %v", err)
+	}
+
 	return nil
 }
 
-func (repo *awsRepo) mapRowToObject(rs db.RowScanner) (*cohesioned.Video, error) {
+//mapRowToObject scans the standard video columns, followed by any extra columns the query selected into extra
+func (repo *awsRepo) mapRowToObject(rs db.RowScanner, extra ...interface{}) (*cohesioned.Video, error) {
 	video := &cohesioned.Video{}
 	var updated db.NullTime
 	var fileSize, updatedBy, taxonomyParentID sql.NullInt64
-	var createdByFullName, taxonomyName, fileType, keyTerms, stateStandards, commonCoreStandards sql.NullString
+	var createdByFullName, taxonomyName, fileType, checksum sql.NullString
+	var duration sql.NullFloat64
 
-	err := rs.Scan(
+	dest := []interface{}{
 		&video.ID,
 		&video.Title,
 		&video.TaxonomyID,
@@ -319,9 +715,8 @@ func (repo *awsRepo) mapRowToObject(rs db.RowScanner) (*cohesioned.Video, error)
 		&fileSize,
 		&video.StorageBucket,
 		&video.StorageObjectName,
-		&keyTerms,
-		&stateStandards,
-		&commonCoreStandards,
+		&checksum,
+		&duration,
 		&video.Created,
 		&video.CreatedByID,
 		&updated,
@@ -329,30 +724,41 @@ func (repo *awsRepo) mapRowToObject(rs db.RowScanner) (*cohesioned.Video, error)
 		&createdByFullName,
 		&taxonomyName,
 		&taxonomyParentID,
-	)
+	}
+
+	if err := rs.Scan(append(dest, extra...)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
 
-	if err != nil {
 		return video, fmt.Errorf("failed to map row to video: %v", err)
 	}
 
 	video.FileType = fileType.String
 	video.FileSize = fileSize.Int64
+	video.Checksum = checksum.String
+	video.Duration = duration.Float64
 	video.CreatedBy = &cohesioned.Profile{ID: video.CreatedByID, FullName: createdByFullName.String}
 	video.Taxonomy = &cohesioned.Taxonomy{ID: video.TaxonomyID, Name: taxonomyName.String, ParentID: taxonomyParentID.Int64}
 	video.Updated = updated.Time
 	video.UpdatedByID = updatedBy.Int64
 
-	if len(keyTerms.String) > 0 {
-		video.KeyTerms = strings.Split(keyTerms.String, ",")
-	}
+	return video, nil
+}
 
-	if len(stateStandards.String) > 0 {
-		video.StateStandards = strings.Split(stateStandards.String, ",")
+//FindByIDs returns the videos with the given IDs, in no particular order. IDs without a video are skipped
+func (repo *awsRepo) FindByIDs(ids []int64) ([]*cohesioned.Video, error) {
+	if len(ids) == 0 {
+		return nil, nil
 	}
 
-	if len(commonCoreStandards.String) > 0 {
-		video.CommonCoreStandards = strings.Split(commonCoreStandards.String, ",")
+	q := listQuery
+	q.Where = append(append([]string{}, listQuery.Where...), fmt.Sprintf("v.id in (%s)", db.Placeholders(len(ids))))
+	for _, id := range ids {
+		q.Args = append(q.Args, id)
 	}
 
-	return video, nil
+	opts := &cohesioned.ListOptions{Limit: len(ids)}
+	list, _, err := repo.list(q, opts)
+	return list, err
 }
@@ -0,0 +1,69 @@
+package video
+
+import (
+	"fmt"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+//ListObjectKeys lists every object key in the video bucket that is still in use - video files, renditions, thumbnails, caption tracks, and the outputs of transcoding jobs that haven't finished yet
+func (repo *awsRepo) ListObjectKeys() ([]string, error) {
+	var keys []string
+
+	selectQuery := `select object_key from video where object_key != ''
+	union
+	select object_key from video_rendition
+	union
+	select object_key from video_thumbnail
+	union
+	select object_key from video_caption
+	union
+	select output_key from transcoding_job where status in (?, ?)`
+
+	rows, err := repo.Query(selectQuery, string(cohesioned.TranscodingPending), string(cohesioned.TranscodingRunning))
+	if err != nil {
+		return keys, fmt.Errorf("Failed to list object keys: %v", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return keys, fmt.Errorf("failed to map row to object key: %v", err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return keys, fmt.Errorf("object key rows had an error: %v", err)
+	}
+
+	return keys, nil
+}
+
+//ListUploadVideoIDs lists the IDs of videos with a resumable upload in progress
+func (repo *awsRepo) ListUploadVideoIDs() ([]int64, error) {
+	var ids []int64
+
+	rows, err := repo.Query(`select video_id from video_upload`)
+	if err != nil {
+		return ids, fmt.Errorf("Failed to list resumable uploads: %v", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return ids, fmt.Errorf("failed to map row to video id: %v", err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return ids, fmt.Errorf("upload rows had an error: %v", err)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,104 @@
+package video
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/db"
+	"github.com/go-sql-driver/mysql"
+)
+
+//mysqlNoReferencedRow is the MySQL error number for an insert whose foreign key doesn't match a row
+const mysqlNoReferencedRow = 1452
+
+//FindByTeacher lists the videos the teacher presents
+func (repo *awsRepo) FindByTeacher(teacherID int64, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error) {
+	q := listQuery
+	q.Where = append(append([]string{}, listQuery.Where...), `exists (
+		select 1 from video_teacher vt where vt.video_id = v.id and vt.teacher_id = ?
+	)`)
+
+	q.Args = []interface{}{teacherID}
+	return repo.list(q, opts)
+}
+
+//setTeachers replaces the teachers of the video, keeping them in the order they were given
+func setTeachers(tx *sql.Tx, videoID int64, teacherIDs []int64) error {
+	if _, err := tx.Exec(`delete from video_teacher where video_id = ?`, videoID); err != nil {
+		return fmt.Errorf("Failed to clear the teachers of video %d: %v", videoID, err)
+	}
+
+	for position, teacherID := range teacherIDs {
+		_, err := tx.Exec(`insert into video_teacher (video_id, teacher_id, position) values (?, ?, ?)`, videoID, teacherID, position)
+		if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == mysqlNoReferencedRow {
+			//the teacher was deleted after ValidateTeachers checked them
+			return ErrUnknownTeacher
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed to add teacher %d to video %d: %v", teacherID, videoID, err)
+		}
+	}
+
+	return nil
+}
+
+//loadTeachers sets the teachers of each of the videos
+func (repo *awsRepo) loadTeachers(videos ...*cohesioned.Video) error {
+	if len(videos) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*cohesioned.Video)
+	args := make([]interface{}, len(videos))
+	for i, video := range videos {
+		byID[video.ID] = video
+		args[i] = video.ID
+	}
+
+	selectQuery := fmt.Sprintf(`select
+		vt.video_id,
+		t.id,
+		t.name,
+		t.avatar_url,
+		t.credentials
+	from
+		video_teacher vt, teacher t
+	where
+		vt.teacher_id = t.id
+	and
+		vt.video_id in (%s)
+	order by vt.video_id, vt.position`, db.Placeholders(len(videos)))
+
+	rows, err := repo.Query(selectQuery, args...)
+	if err != nil {
+		return fmt.Errorf("Failed to query video teachers: %v", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var videoID int64
+		var avatarURL, credentials sql.NullString
+		t := &cohesioned.Teacher{}
+		if err := rows.Scan(&videoID, &t.ID, &t.Name, &avatarURL, &credentials); err != nil {
+			return fmt.Errorf("failed to map row to video teacher: %v", err)
+		}
+
+		video, ok := byID[videoID]
+		if !ok {
+			continue
+		}
+
+		t.AvatarURL = avatarURL.String
+		t.Credentials = credentials.String
+		video.Teachers = append(video.Teachers, t)
+		video.TeacherIDs = append(video.TeacherIDs, t.ID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("video teacher rows had an error: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package video
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/db"
+)
+
+//SaveThumbnail inserts the thumbnail, replacing any the video already has with the same object key
+func (repo *awsRepo) SaveThumbnail(t *cohesioned.Thumbnail) (int64, error) {
+	insertSql := `insert into video_thumbnail
+	(
+		video_id,
+		kind,
+		object_key,
+		content_type,
+		width,
+		height,
+		created
+	) values (?, ?, ?, ?, ?, ?, ?)
+	on duplicate key update
+		id = last_insert_id(id),
+		kind = values(kind),
+		content_type = values(content_type),
+		width = values(width),
+		height = values(height),
+		created = values(created)`
+
+	result, err := repo.Exec(insertSql, t.VideoID, string(t.Kind), t.ObjectKey, t.ContentType, t.Width, t.Height, t.Created)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to save thumbnail %s for video %d: %v", t.ObjectKey, t.VideoID, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get last insert id from result: %v", err)
+	}
+
+	return id, nil
+}
+
+//ListThumbnails lists the video's thumbnails, custom posters first and then generated thumbnails in the order they were captured
+func (repo *awsRepo) ListThumbnails(videoID int64) ([]*cohesioned.Thumbnail, error) {
+	thumbnails, err := repo.listThumbnails(videoID)
+	return thumbnails[videoID], err
+}
+
+//DeleteThumbnail deletes a single thumbnail
+func (repo *awsRepo) DeleteThumbnail(id int64) error {
+	if _, err := repo.Exec(`delete from video_thumbnail where id = ?`, id); err != nil {
+		return fmt.Errorf("Failed to delete thumbnail %d: %v", id, err)
+	}
+
+	return nil
+}
+
+//DeleteThumbnails deletes the video's thumbnails of the given kind
+func (repo *awsRepo) DeleteThumbnails(videoID int64, kind cohesioned.ThumbnailKind) error {
+	if _, err := repo.Exec(`delete from video_thumbnail where video_id = ? and kind = ?`, videoID, string(kind)); err != nil {
+		return fmt.Errorf("Failed to delete %s thumbnails of video %d: %v", kind, videoID, err)
+	}
+
+	return nil
+}
+
+//ListWithoutThumbnails lists the id and object key of each video with a file but no thumbnails
+func (repo *awsRepo) ListWithoutThumbnails() ([]*cohesioned.Video, error) {
+	var list []*cohesioned.Video
+
+	selectQuery := `select v.id, v.object_key
+	from video v
+	where v.object_key != ''
+	and not exists (select 1 from video_thumbnail t where t.video_id = v.id)
+	order by v.id`
+
+	rows, err := repo.Query(selectQuery)
+	if err != nil {
+		return list, fmt.Errorf("Failed to list videos without thumbnails: %v", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		video := &cohesioned.Video{}
+		if err := rows.Scan(&video.ID, &video.StorageObjectName); err != nil {
+			return list, fmt.Errorf("failed to map row to video: %v", err)
+		}
+
+		list = append(list, video)
+	}
+
+	if err := rows.Err(); err != nil {
+		return list, fmt.Errorf("video rows had an error: %v", err)
+	}
+
+	return list, nil
+}
+
+//loadPosters sets the ThumbnailKey of each of the videos to its poster thumbnail
+func (repo *awsRepo) loadPosters(videos ...*cohesioned.Video) error {
+	if len(videos) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(videos))
+	for i, video := range videos {
+		ids[i] = video.ID
+	}
+
+	thumbnails, err := repo.listThumbnails(ids...)
+	if err != nil {
+		return err
+	}
+
+	for _, video := range videos {
+		if poster := cohesioned.Poster(thumbnails[video.ID]); poster != nil {
+			video.ThumbnailKey = poster.ObjectKey
+		}
+	}
+
+	return nil
+}
+
+//listThumbnails lists the thumbnails of each of the videos, by video id
+func (repo *awsRepo) listThumbnails(videoIDs ...int64) (map[int64][]*cohesioned.Thumbnail, error) {
+	byVideo := make(map[int64][]*cohesioned.Thumbnail)
+
+	args := make([]interface{}, len(videoIDs))
+	for i, id := range videoIDs {
+		args[i] = id
+	}
+
+	selectQuery := fmt.Sprintf(`select
+		id,
+		video_id,
+		kind,
+		object_key,
+		content_type,
+		width,
+		height,
+		created
+	from video_thumbnail
+	where video_id in (%s)
+	order by video_id, kind = ?, object_key`, db.Placeholders(len(videoIDs)))
+
+	rows, err := repo.Query(selectQuery, append(args, string(cohesioned.ThumbnailGenerated))...)
+	if err != nil {
+		return byVideo, fmt.Errorf("Failed to list video thumbnails: %v", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		t := &cohesioned.Thumbnail{}
+		var contentType sql.NullString
+		var width, height sql.NullInt64
+
+		err := rows.Scan(
+			&t.ID,
+			&t.VideoID,
+			&t.Kind,
+			&t.ObjectKey,
+			&contentType,
+			&width,
+			&height,
+			&t.Created,
+		)
+
+		if err != nil {
+			return byVideo, fmt.Errorf("failed to map row to thumbnail: %v", err)
+		}
+
+		t.ContentType = contentType.String
+		t.Width = width.Int64
+		t.Height = height.Int64
+		byVideo[t.VideoID] = append(byVideo[t.VideoID], t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return byVideo, fmt.Errorf("thumbnail rows had an error: %v", err)
+	}
+
+	return byVideo, nil
+}
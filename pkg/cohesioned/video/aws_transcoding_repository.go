@@ -0,0 +1,125 @@
+package video
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/db"
+)
+
+func (repo *awsRepo) SaveTranscodingJob(job *cohesioned.TranscodingJob) (int64, error) {
+	insertSql := `insert into transcoding_job
+	(
+		video_id,
+		preset,
+		external_id,
+		status,
+		output_key,
+		error,
+		created
+	) values (?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := repo.Exec(insertSql, job.VideoID, job.Preset, job.ExternalID, string(job.Status), job.OutputKey, job.Error, job.Created)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to insert transcoding job for video %d: %v", job.VideoID, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get last insert id from result: %v", err)
+	}
+
+	return id, nil
+}
+
+func (repo *awsRepo) UpdateTranscodingJob(job *cohesioned.TranscodingJob) error {
+	updateSql := `update transcoding_job set status = ?, error = ?, updated = ? where id = ?`
+
+	if _, err := repo.Exec(updateSql, string(job.Status), job.Error, job.Updated, job.ID); err != nil {
+		return fmt.Errorf("Failed to update transcoding job %d: %v", job.ID, err)
+	}
+
+	return nil
+}
+
+//ListTranscodingJobs lists the video's transcoding jobs, newest first
+func (repo *awsRepo) ListTranscodingJobs(videoID int64) ([]*cohesioned.TranscodingJob, error) {
+	list, err := repo.queryTranscodingJobs(`where video_id = ? order by created desc, id desc`, videoID)
+	if err != nil {
+		return list, fmt.Errorf("Failed to list transcoding jobs for video %d: %v", videoID, err)
+	}
+
+	return list, nil
+}
+
+//ListUnfinishedTranscodingJobs lists the transcoding jobs of every video that haven't completed or failed, oldest first
+func (repo *awsRepo) ListUnfinishedTranscodingJobs() ([]*cohesioned.TranscodingJob, error) {
+	list, err := repo.queryTranscodingJobs(
+		`where status not in (?, ?) order by created, id`,
+		string(cohesioned.TranscodingComplete),
+		string(cohesioned.TranscodingFailed),
+	)
+
+	if err != nil {
+		return list, fmt.Errorf("Failed to list unfinished transcoding jobs: %v", err)
+	}
+
+	return list, nil
+}
+
+//queryTranscodingJobs lists the transcoding jobs selected by the where and order by clauses in criteria
+func (repo *awsRepo) queryTranscodingJobs(criteria string, args ...interface{}) ([]*cohesioned.TranscodingJob, error) {
+	var list []*cohesioned.TranscodingJob
+
+	selectQuery := `select
+		id,
+		video_id,
+		preset,
+		external_id,
+		status,
+		output_key,
+		error,
+		created,
+		updated
+	from transcoding_job ` + criteria
+
+	rows, err := repo.Query(selectQuery, args...)
+	if err != nil {
+		return list, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		job := &cohesioned.TranscodingJob{}
+		var externalID, jobErr sql.NullString
+		var updated db.NullTime
+
+		err := rows.Scan(
+			&job.ID,
+			&job.VideoID,
+			&job.Preset,
+			&externalID,
+			&job.Status,
+			&job.OutputKey,
+			&jobErr,
+			&job.Created,
+			&updated,
+		)
+
+		if err != nil {
+			return list, fmt.Errorf("failed to map row to transcoding job: %v", err)
+		}
+
+		job.ExternalID = externalID.String
+		job.Error = jobErr.String
+		job.Updated = updated.Time
+		list = append(list, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return list, fmt.Errorf("transcoding job rows had an error: %v", err)
+	}
+
+	return list, nil
+}
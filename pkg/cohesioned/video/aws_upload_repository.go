@@ -0,0 +1,146 @@
+package video
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/db"
+)
+
+//GetUpload returns the video's resumable upload, or nil if there isn't one in progress
+func (repo *awsRepo) GetUpload(videoID int64) (*cohesioned.VideoUpload, error) {
+	selectQuery := `select
+		video_id,
+		size,
+		received,
+		file_type,
+		created,
+		created_by,
+		updated
+	from video_upload
+	where video_id = ?`
+
+	u := &cohesioned.VideoUpload{}
+	var fileType sql.NullString
+	var updated db.NullTime
+
+	err := repo.QueryRow(selectQuery, videoID).Scan(
+		&u.VideoID,
+		&u.Size,
+		&u.Received,
+		&fileType,
+		&u.Created,
+		&u.CreatedByID,
+		&updated,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get upload of video %d: %v", videoID, err)
+	}
+
+	u.FileType = fileType.String
+	u.Updated = updated.Time
+	return u, nil
+}
+
+//SaveUpload starts the upload. It returns false if the video already has one
+func (repo *awsRepo) SaveUpload(u *cohesioned.VideoUpload) (bool, error) {
+	insertSql := `insert into video_upload
+	(
+		video_id,
+		size,
+		received,
+		file_type,
+		created,
+		created_by
+	) values (?, ?, ?, ?, ?, ?)
+	on duplicate key update video_id = video_id`
+
+	result, err := repo.Exec(insertSql, u.VideoID, u.Size, u.Received, u.FileType, u.Created, u.CreatedByID)
+	if err != nil {
+		return false, fmt.Errorf("Failed to insert upload of video %d: %v", u.VideoID, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("Failed to get rows affected from result: %v", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+//AddUploadChunk records the chunk as received, as long as it starts where the upload left off. It returns false if another chunk got there first
+func (repo *awsRepo) AddUploadChunk(videoID int64, chunk *cohesioned.UploadChunk) (bool, error) {
+	tx, err := repo.Begin()
+	if err != nil {
+		return false, fmt.Errorf("Failed to begin transaction: %v", err)
+	}
+
+	result, err := tx.Exec(
+		`update video_upload set received = ?, updated = now() where video_id = ? and received = ?`,
+		chunk.Last+1,
+		videoID,
+		chunk.First,
+	)
+
+	if err != nil {
+		tx.Rollback()
+		return false, fmt.Errorf("Failed to update upload of video %d: %v", videoID, err)
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err != nil || rowsAffected == 0 {
+		tx.Rollback()
+		return false, err
+	}
+
+	if _, err := tx.Exec(`insert into video_upload_chunk (video_id, first_byte, last_byte) values (?, ?, ?)`, videoID, chunk.First, chunk.Last); err != nil {
+		tx.Rollback()
+		return false, fmt.Errorf("Failed to insert upload chunk of video %d: %v", videoID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("Failed to commit upload chunk of video %d: %v", videoID, err)
+	}
+
+	return true, nil
+}
+
+//ListUploadChunks lists the chunks received for the video's upload in order
+func (repo *awsRepo) ListUploadChunks(videoID int64) ([]*cohesioned.UploadChunk, error) {
+	var list []*cohesioned.UploadChunk
+
+	rows, err := repo.Query(`select first_byte, last_byte from video_upload_chunk where video_id = ? order by first_byte`, videoID)
+	if err != nil {
+		return list, fmt.Errorf("Failed to list upload chunks of video %d: %v", videoID, err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		chunk := &cohesioned.UploadChunk{}
+		if err := rows.Scan(&chunk.First, &chunk.Last); err != nil {
+			return list, fmt.Errorf("failed to map row to upload chunk: %v", err)
+		}
+
+		list = append(list, chunk)
+	}
+
+	if err := rows.Err(); err != nil {
+		return list, fmt.Errorf("upload chunk rows had an error: %v", err)
+	}
+
+	return list, nil
+}
+
+//DeleteUpload deletes the video's upload along with its chunks
+func (repo *awsRepo) DeleteUpload(videoID int64) error {
+	if _, err := repo.Exec(`delete from video_upload where video_id = ?`, videoID); err != nil {
+		return fmt.Errorf("Failed to delete upload of video %d: %v", videoID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package video
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/db"
+)
+
+type awsWatchRepo struct {
+	*sql.DB
+}
+
+func NewAwsWatchRepo(db *sql.DB) WatchRepo {
+	return &awsWatchRepo{
+		DB: db,
+	}
+}
+
+//IsParentOf returns true if the student belongs to the profile
+func (repo *awsWatchRepo) IsParentOf(userID, studentID int64) (bool, error) {
+	var count int64
+	if err := repo.QueryRow(`select count(*) from student where id = ? and user_id = ?`, studentID, userID).Scan(&count); err != nil {
+		return false, fmt.Errorf("Failed to look up student %d of user %d: %v", studentID, userID, err)
+	}
+
+	return count > 0, nil
+}
+
+func (repo *awsWatchRepo) SaveWatchEvent(e *cohesioned.WatchEvent) (int64, error) {
+	insertSql := `insert into video_watch_event
+	(
+		video_id,
+		user_id,
+		student_id,
+		event,
+		position,
+		created
+	) values (?, ?, ?, ?, ?, ?)`
+
+	studentID := sql.NullInt64{Int64: e.StudentID, Valid: e.StudentID > 0}
+	result, err := repo.Exec(insertSql, e.VideoID, e.UserID, studentID, string(e.Event), e.Position, e.Created)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to save %s event of video %d: %v", e.Event, e.VideoID, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get last insert id from result: %v", err)
+	}
+
+	return id, nil
+}
+
+//SaveWatchProgress inserts or replaces where the viewer is up to. Once a viewer has completed the video it stays completed
+func (repo *awsWatchRepo) SaveWatchProgress(p *cohesioned.WatchProgress) error {
+	upsertSql := `insert into video_watch_progress
+	(
+		video_id,
+		user_id,
+		student_id,
+		student_key,
+		position,
+		completed,
+		created,
+		updated
+	) values (?, ?, ?, ?, ?, ?, ?, ?)
+	on duplicate key update
+		position = values(position),
+		completed = completed or values(completed),
+		updated = values(updated)`
+
+	studentID := sql.NullInt64{Int64: p.StudentID, Valid: p.StudentID > 0}
+	if _, err := repo.Exec(upsertSql, p.VideoID, p.UserID, studentID, p.StudentID, p.Position, p.Completed, p.Created, p.Updated); err != nil {
+		return fmt.Errorf("Failed to save the progress of user %d through video %d: %v", p.UserID, p.VideoID, err)
+	}
+
+	return nil
+}
+
+//GetWatchProgress returns where the viewer is up to in the video, or nil if they haven't started it
+func (repo *awsWatchRepo) GetWatchProgress(videoID, userID, studentID int64) (*cohesioned.WatchProgress, error) {
+	selectQuery := `select
+		video_id,
+		user_id,
+		student_key,
+		position,
+		completed,
+		created,
+		updated
+	from video_watch_progress
+	where video_id = ? and user_id = ? and student_key = ?`
+
+	p := &cohesioned.WatchProgress{}
+	var updated db.NullTime
+
+	err := repo.QueryRow(selectQuery, videoID, userID, studentID).Scan(&p.VideoID, &p.UserID, &p.StudentID, &p.Position, &p.Completed, &p.Created, &updated)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get the progress of user %d through video %d: %v", userID, videoID, err)
+	}
+
+	p.Updated = updated.Time
+	return p, nil
+}
+
+var completionQuery = db.ListQuery{
+	Select: `select
+		v.id,
+		v.title,
+		coalesce(c.viewers, 0),
+		coalesce(c.completions, 0)`,
+	From: `from video v left join (
+		select video_id, count(*) viewers, sum(completed) completions
+		from video_watch_progress
+		group by video_id
+	) c on c.video_id = v.id`,
+	Columns: map[string]string{
+		"id":              "v.id",
+		"video_id":        "v.id",
+		"title":           "v.title",
+		"taxonomy_id":     "v.taxonomy_id",
+		"viewers":         "coalesce(c.viewers, 0)",
+		"completions":     "coalesce(c.completions, 0)",
+		"completion_rate": "coalesce(c.completions / c.viewers, 0)",
+	},
+}
+
+//ListCompletionRates lists how many viewers started and finished each video
+func (repo *awsWatchRepo) ListCompletionRates(opts *cohesioned.ListOptions) ([]*cohesioned.CompletionRate, int64, error) {
+	var list []*cohesioned.CompletionRate
+	var total int64
+
+	selectQuery, args, countQuery, countArgs, err := completionQuery.Build(opts)
+	if err != nil {
+		return list, total, err
+	}
+
+	if err := repo.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		return list, total, fmt.Errorf("Failed to count videos: %v", err)
+	}
+
+	rows, err := repo.Query(selectQuery, args...)
+	if err != nil {
+		return list, total, fmt.Errorf("Failed to list completion rates: %v", err)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		c := &cohesioned.CompletionRate{}
+		if err := rows.Scan(&c.VideoID, &c.Title, &c.Viewers, &c.Completions); err != nil {
+			return list, total, fmt.Errorf("failed to map row to completion rate: %v", err)
+		}
+
+		if c.Viewers > 0 {
+			c.Rate = float64(c.Completions) / float64(c.Viewers)
+		}
+
+		list = append(list, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return list, total, fmt.Errorf("completion rate rows had an error: %v", err)
+	}
+
+	return list, total, nil
+}
@@ -0,0 +1,342 @@
+package video
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+//MaxCaptionsSize is the largest caption file in bytes that can be uploaded
+const MaxCaptionsSize = 2 << 20
+
+//CaptionsContentType is the content type caption tracks are stored and served as
+const CaptionsContentType = "text/vtt"
+
+//ErrNoCaptions is returned when a video has no caption track in the requested language
+var ErrNoCaptions = errors.New("the video has no captions in that language")
+
+var (
+	//captionsLanguage matches a BCP 47 language tag such as en or es-MX
+	captionsLanguage = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{1,8})*$`)
+	//cueTiming matches the timing line of a cue, with any WebVTT cue settings after it. SRT files separate the milliseconds with a comma
+	cueTiming = regexp.MustCompile(`^((?:\d+:)?\d{2}:\d{2}[.,]\d{3})[ \t]+-->[ \t]+((?:\d+:)?\d{2}:\d{2}[.,]\d{3})(.*)$`)
+	//cueMarkup matches the tags that can style the text of a cue, e.g. <i> or <v Speaker>
+	cueMarkup  = regexp.MustCompile(`<[^>]*>`)
+	lineBreaks = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+)
+
+//cue is a single caption - its text and when it is shown
+type cue struct {
+	ID    string
+	Start time.Duration
+	End   time.Duration
+	//Settings are the WebVTT cue settings, such as position. They are dropped from SRT files
+	Settings string
+	Lines    []string
+}
+
+//ParseCaptions reads a WebVTT or SRT caption file, returning it as WebVTT along with the plain text of its cues
+func ParseCaptions(data []byte) (string, string, error) {
+	if !utf8.Valid(data) {
+		return "", "", errors.New("captions must be UTF-8 encoded")
+	}
+
+	text := strings.TrimPrefix(lineBreaks.Replace(string(data)), "\ufeff")
+	blocks := splitBlocks(text)
+	if len(blocks) == 0 {
+		return "", "", errors.New("the file is empty")
+	}
+
+	vtt := isWebVTT(blocks[0][0])
+	if vtt {
+		blocks = blocks[1:]
+	}
+
+	var cues []*cue
+	for _, block := range blocks {
+		//comments, styles and regions are only allowed in WebVTT files
+		if vtt && (strings.HasPrefix(block[0], "NOTE") || block[0] == "STYLE" || block[0] == "REGION") {
+			continue
+		}
+
+		c, err := parseCue(block, vtt)
+		if err != nil {
+			return "", "", fmt.Errorf("cue %d: %v", len(cues)+1, err)
+		}
+
+		cues = append(cues, c)
+	}
+
+	if len(cues) == 0 {
+		return "", "", errors.New("the file has no cues")
+	}
+
+	transcript := make([]string, 0, len(cues))
+	for _, c := range cues {
+		if t := cueText(c); len(t) > 0 {
+			transcript = append(transcript, t)
+		}
+	}
+
+	if vtt {
+		return text, strings.Join(transcript, " "), nil
+	}
+
+	return toWebVTT(cues), strings.Join(transcript, " "), nil
+}
+
+//isWebVTT returns true if line is the header that starts every WebVTT file
+func isWebVTT(line string) bool {
+	return line == "WEBVTT" || strings.HasPrefix(line, "WEBVTT ") || strings.HasPrefix(line, "WEBVTT\t")
+}
+
+//splitBlocks splits text into the lines of each of its blank line separated blocks
+func splitBlocks(text string) [][]string {
+	var blocks [][]string
+	var block []string
+	for _, line := range strings.Split(text, "\n") {
+		if len(strings.TrimSpace(line)) == 0 {
+			if len(block) > 0 {
+				blocks = append(blocks, block)
+				block = nil
+			}
+
+			continue
+		}
+
+		block = append(block, strings.TrimRight(line, " \t"))
+	}
+
+	if len(block) > 0 {
+		blocks = append(blocks, block)
+	}
+
+	return blocks
+}
+
+//parseCue parses a block of lines - an optional identifier, the timing line and then the text
+func parseCue(block []string, vtt bool) (*cue, error) {
+	c := &cue{}
+	timing := cueTiming.FindStringSubmatch(block[0])
+	if timing == nil && len(block) > 1 {
+		c.ID = block[0]
+		timing = cueTiming.FindStringSubmatch(block[1])
+		block = block[1:]
+	}
+
+	if timing == nil {
+		return nil, fmt.Errorf("%q is not a cue timing such as 00:00:01.000 --> 00:00:04.000", block[0])
+	}
+
+	var err error
+	if c.Start, err = parseCueTime(timing[1]); err != nil {
+		return nil, err
+	}
+
+	if c.End, err = parseCueTime(timing[2]); err != nil {
+		return nil, err
+	}
+
+	if c.End < c.Start {
+		return nil, fmt.Errorf("it ends at %s, before it starts at %s", timing[2], timing[1])
+	}
+
+	if vtt {
+		c.Settings = strings.TrimSpace(timing[3])
+	}
+
+	c.Lines = block[1:]
+	return c, nil
+}
+
+//parseCueTime parses a [hh:]mm:ss.ttt or SRT hh:mm:ss,ttt timestamp
+func parseCueTime(value string) (time.Duration, error) {
+	parts := strings.Split(strings.Replace(value, ",", ".", 1), ":")
+	if len(parts) == 2 {
+		parts = append([]string{"0"}, parts...)
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("%s is not a valid timestamp", value)
+	}
+
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil || minutes > 59 {
+		return 0, fmt.Errorf("%s is not a valid timestamp", value)
+	}
+
+	seconds, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil || seconds >= 60 {
+		return 0, fmt.Errorf("%s is not a valid timestamp", value)
+	}
+
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds*float64(time.Second)+0.5), nil
+}
+
+//formatCueTime formats d as a WebVTT hh:mm:ss.ttt timestamp
+func formatCueTime(d time.Duration) string {
+	ms := int64(d / time.Millisecond)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+//cueText is the plain text of the cue, without markup or line breaks
+func cueText(c *cue) string {
+	text := html.UnescapeString(cueMarkup.ReplaceAllString(strings.Join(c.Lines, " "), ""))
+	return strings.Join(strings.Fields(text), " ")
+}
+
+//toWebVTT writes the cues as a WebVTT file
+func toWebVTT(cues []*cue) string {
+	var b bytes.Buffer
+	b.WriteString("WEBVTT\n")
+	for _, c := range cues {
+		b.WriteString("\n")
+		if len(c.ID) > 0 {
+			b.WriteString(c.ID + "\n")
+		}
+
+		b.WriteString(formatCueTime(c.Start) + " --> " + formatCueTime(c.End))
+		if len(c.Settings) > 0 {
+			b.WriteString(" " + c.Settings)
+		}
+
+		b.WriteString("\n")
+		for _, line := range c.Lines {
+			//--> can't appear in the text of a WebVTT cue
+			b.WriteString(strings.Replace(line, "-->", "->", -1) + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+//captionsKey is where a caption track is stored. Each upload gets a new key so a cached track is never served for a replaced one
+func captionsKey(video *cohesioned.Video, language string) string {
+	return fmt.Sprintf("captions/%d-%s-%s.vtt", video.ID, language, strconv.FormatInt(time.Now().UnixNano(), 36))
+}
+
+//SetCaptions stores body, a WebVTT or SRT file, as the video's caption track in the given language, replacing any track already uploaded in that language.
+//The file is rejected with ErrInvalidFile if it can't be parsed or is larger than MaxCaptionsSize. Label defaults to the language
+func (s *adminService) SetCaptions(video *cohesioned.Video, language, label string, body io.Reader) (*cohesioned.CaptionTrack, error) {
+	language = strings.TrimSpace(language)
+	if !captionsLanguage.MatchString(language) {
+		return nil, invalidFile(video, "language", "%s is not a language tag such as en or es-MX", language)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(body, MaxCaptionsSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the captions: %v", err)
+	}
+
+	if len(data) > MaxCaptionsSize {
+		return nil, invalidFile(video, "captions", "the file is larger than the maximum caption file size of %d bytes", MaxCaptionsSize)
+	}
+
+	vtt, transcript, err := ParseCaptions(data)
+	if err != nil {
+		return nil, invalidFile(video, "captions", "the file is not a valid WebVTT or SRT file: %v", err)
+	}
+
+	previous, err := s.captions(video.ID, language)
+	if err != nil {
+		return nil, err
+	}
+
+	label = strings.TrimSpace(label)
+	if len(label) == 0 {
+		label = language
+	}
+
+	bucket := s.cfg.GetVideoBucket()
+	track := &cohesioned.CaptionTrack{
+		VideoID:    video.ID,
+		Language:   language,
+		Label:      label,
+		ObjectKey:  captionsKey(video, language),
+		Transcript: transcript,
+		Created:    time.Now(),
+		Updated:    time.Now(),
+	}
+
+	if err := s.store.Put(bucket, track.ObjectKey, strings.NewReader(vtt), CaptionsContentType); err != nil {
+		return nil, fmt.Errorf("Failed to store the captions: %v", err)
+	}
+
+	if track.ID, err = s.videoRepo.SaveCaptions(track); err != nil {
+		s.discardObject(bucket, track.ObjectKey)
+		return nil, err
+	}
+
+	if previous != nil {
+		track.Created = previous.Created
+		s.discardObject(bucket, previous.ObjectKey)
+	}
+
+	if track.SignedURL, err = s.signedURL(bucket, track.ObjectKey); err != nil {
+		return nil, fmt.Errorf("Failed to generate signed url for the %s captions: %v", language, err)
+	}
+
+	return track, nil
+}
+
+//DeleteCaptions deletes the video's caption track in the given language, or returns ErrNoCaptions if it has none
+func (s *adminService) DeleteCaptions(video *cohesioned.Video, language string) error {
+	track, err := s.captions(video.ID, language)
+	if err != nil {
+		return err
+	}
+
+	if track == nil {
+		return ErrNoCaptions
+	}
+
+	if err := s.videoRepo.DeleteCaptions(video.ID, track.Language); err != nil {
+		return err
+	}
+
+	s.discardObject(s.cfg.GetVideoBucket(), track.ObjectKey)
+	return nil
+}
+
+//captions finds the video's caption track in the given language, or nil if it has none
+func (s *adminService) captions(videoID int64, language string) (*cohesioned.CaptionTrack, error) {
+	tracks, err := s.videoRepo.ListCaptions(videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, track := range tracks {
+		if strings.EqualFold(track.Language, language) {
+			return track, nil
+		}
+	}
+
+	return nil, nil
+}
+
+//signCaptions lists the video's caption tracks, each with a signed url
+func (s *adminService) signCaptions(video *cohesioned.Video) ([]*cohesioned.CaptionTrack, error) {
+	tracks, err := s.videoRepo.ListCaptions(video.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, track := range tracks {
+		if track.SignedURL, err = s.signedURL(s.cfg.GetVideoBucket(), track.ObjectKey); err != nil {
+			return nil, fmt.Errorf("Failed to generate signed url for the %s captions: %v", track.Language, err)
+		}
+	}
+
+	return tracks, nil
+}
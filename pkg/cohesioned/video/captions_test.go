@@ -0,0 +1,110 @@
+package video_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/video"
+)
+
+const srtCaptions = "1\r\n00:00:01,000 --> 00:00:04,500\r\nToday we'll add <i>fractions</i>\r\n\r\n2\r\n00:00:05,000 --> 00:01:02,250\r\nwith the same denominator &amp; more.\r\n"
+
+func TestParseCaptionsConvertsSRT(t *testing.T) {
+	vtt, transcript, err := video.ParseCaptions([]byte(srtCaptions))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing captions: %v", err)
+	}
+
+	expected := "WEBVTT\n\n1\n00:00:01.000 --> 00:00:04.500\nToday we'll add <i>fractions</i>\n\n2\n00:00:05.000 --> 00:01:02.250\nwith the same denominator &amp; more.\n"
+	if vtt != expected {
+		t.Errorf("expected WebVTT\n%s\nbut got\n%s", expected, vtt)
+	}
+
+	if expected := "Today we'll add fractions with the same denominator & more."; transcript != expected {
+		t.Errorf("expected transcript %q but got %q", expected, transcript)
+	}
+}
+
+func TestParseCaptionsKeepsWebVTT(t *testing.T) {
+	captions := "WEBVTT - lesson 1\n\nNOTE written by hand\n\n00:01.000 --> 00:04.000 align:start\n<v Teacher>Hello class\n"
+	vtt, transcript, err := video.ParseCaptions([]byte(captions))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing captions: %v", err)
+	}
+
+	if vtt != captions {
+		t.Errorf("expected the WebVTT file to be kept as it is but got\n%s", vtt)
+	}
+
+	if transcript != "Hello class" {
+		t.Errorf("expected transcript %q but got %q", "Hello class", transcript)
+	}
+}
+
+func TestParseCaptionsRejectsInvalidFiles(t *testing.T) {
+	files := map[string]string{
+		"empty":             "\n\n",
+		"no cues":           "WEBVTT\n",
+		"missing timing":    "1\nHello class\n",
+		"invalid timestamp": "1\n00:00:01,000 --> 00:61:00,000\nHello class\n",
+		"ends before start": "1\n00:00:04,000 --> 00:00:01,000\nHello class\n",
+		"not utf-8":         "1\n00:00:01,000 --> 00:00:04,000\n\xff\xfe\n",
+	}
+
+	for name, file := range files {
+		if _, _, err := video.ParseCaptions([]byte(file)); err == nil {
+			t.Errorf("expected an error parsing a file that is %s", name)
+		}
+	}
+}
+
+func TestSetCaptionsReplacesTheTrackInTheSameLanguage(t *testing.T) {
+	repo := new(fakes.FakeVideoRepo)
+	repo.SaveCaptions(&cohesioned.CaptionTrack{Language: "en", Label: "English", ObjectKey: "captions/1-en-old.vtt"})
+
+	store := new(fakes.FakeBlobStore)
+	store.SignedURLReturns("http://signed-captions", nil)
+
+	track, err := newStorageService(repo, store).SetCaptions(fakes.FakeVideo(), "en", "", strings.NewReader(srtCaptions))
+	if err != nil {
+		t.Fatalf("Unexpected error setting captions: %v", err)
+	}
+
+	if track.Language != "en" || track.Label != "en" || track.SignedURL != "http://signed-captions" || !strings.HasPrefix(track.Transcript, "Today") {
+		t.Errorf("unexpected caption track %v", track)
+	}
+
+	if !strings.HasPrefix(track.ObjectKey, "captions/1-en-") || !strings.HasPrefix(string(store.Stored[track.ObjectKey]), "WEBVTT\n") {
+		t.Errorf("expected the captions to be stored as WebVTT at %s but got %v", track.ObjectKey, store.Stored)
+	}
+
+	if len(repo.Captions) != 1 || repo.Captions["en"] != track {
+		t.Errorf("expected the new track to replace the old one but got %v", repo.Captions)
+	}
+
+	if len(store.Deleted) != 1 || store.Deleted[0] != "captions/1-en-old.vtt" {
+		t.Errorf("expected the previous track to be deleted but got %v", store.Deleted)
+	}
+}
+
+func TestSetCaptionsRejectsInvalidLanguages(t *testing.T) {
+	store := new(fakes.FakeBlobStore)
+	v := fakes.FakeVideo()
+
+	if _, err := newStorageService(new(fakes.FakeVideoRepo), store).SetCaptions(v, "english/us", "", strings.NewReader(srtCaptions)); err != video.ErrInvalidFile {
+		t.Fatalf("expected ErrInvalidFile but got %v", err)
+	}
+
+	if !hasValidationError(v, "language") || len(store.Stored) != 0 {
+		t.Errorf("expected a validation error for the language and nothing stored but got %v %v", v.ValidationErrors, store.Stored)
+	}
+}
+
+func TestDeleteCaptionsWithoutTrack(t *testing.T) {
+	svc := newStorageService(new(fakes.FakeVideoRepo), new(fakes.FakeBlobStore))
+	if err := svc.DeleteCaptions(fakes.FakeVideo(), "fr"); err != video.ErrNoCaptions {
+		t.Errorf("expected ErrNoCaptions but got %v", err)
+	}
+}
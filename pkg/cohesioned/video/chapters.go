@@ -0,0 +1,97 @@
+package video
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+//ErrInvalidChapter is returned when a chapter can't be saved. The reasons are added to the chapter's ValidationErrors
+var ErrInvalidChapter = errors.New("the chapter is not valid")
+
+//ErrNoChapter is returned when a video has no chapter with the requested ID
+var ErrNoChapter = errors.New("the video has no chapter with that id")
+
+//ListChapters lists the video's chapters in the order they start
+func (s *adminService) ListChapters(videoID int64) ([]*cohesioned.Chapter, error) {
+	return s.chapterRepo.ListChapters(videoID)
+}
+
+//SaveChapter adds a chapter to the video, or returns ErrInvalidChapter if it isn't within the video or overlaps another chapter
+func (s *adminService) SaveChapter(video *cohesioned.Video, chapter *cohesioned.Chapter) error {
+	chapter.ID = 0
+	chapter.VideoID = video.ID
+	if err := s.validateChapter(video, chapter); err != nil {
+		return err
+	}
+
+	chapter.Created = time.Now()
+	id, err := s.chapterRepo.SaveChapter(chapter)
+	if err != nil {
+		return err
+	}
+
+	chapter.ID = id
+	return nil
+}
+
+//UpdateChapter replaces the title and times of one of the video's chapters, returning ErrNoChapter if the video has no chapter with the chapter's ID
+func (s *adminService) UpdateChapter(video *cohesioned.Video, chapter *cohesioned.Chapter) error {
+	existing, err := s.chapter(video.ID, chapter.ID)
+	if err != nil {
+		return err
+	}
+
+	chapter.VideoID = video.ID
+	chapter.Created = existing.Created
+	if err := s.validateChapter(video, chapter); err != nil {
+		return err
+	}
+
+	chapter.Updated = time.Now()
+	return s.chapterRepo.UpdateChapter(chapter)
+}
+
+//DeleteChapter deletes one of the video's chapters, returning ErrNoChapter if the video has no chapter with that ID
+func (s *adminService) DeleteChapter(video *cohesioned.Video, chapterID int64) error {
+	if _, err := s.chapter(video.ID, chapterID); err != nil {
+		return err
+	}
+
+	return s.chapterRepo.DeleteChapter(video.ID, chapterID)
+}
+
+//chapter finds one of the video's chapters, or returns ErrNoChapter
+func (s *adminService) chapter(videoID, chapterID int64) (*cohesioned.Chapter, error) {
+	chapters, err := s.chapterRepo.ListChapters(videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range chapters {
+		if c.ID == chapterID {
+			return c, nil
+		}
+	}
+
+	return nil, ErrNoChapter
+}
+
+//validateChapter checks the chapter on its own, then against the video's duration and other chapters
+func (s *adminService) validateChapter(video *cohesioned.Video, chapter *cohesioned.Chapter) error {
+	if !chapter.Validate() {
+		return ErrInvalidChapter
+	}
+
+	others, err := s.chapterRepo.ListChapters(video.ID)
+	if err != nil {
+		return err
+	}
+
+	if !chapter.ValidateAgainst(video, others) {
+		return ErrInvalidChapter
+	}
+
+	return nil
+}
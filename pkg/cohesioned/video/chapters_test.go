@@ -0,0 +1,191 @@
+package video_test
+
+import (
+	"testing"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/storage"
+	"github.com/cohesion-education/api/pkg/cohesioned/video"
+)
+
+func hasChapterError(c *cohesioned.Chapter, field string) bool {
+	for _, e := range c.ValidationErrors {
+		if e.Field == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newChapterService(videoRepo video.Repo, chapterRepo video.ChapterRepo, store storage.BlobStore) video.AdminService {
+	cfg := new(fakes.FakeAwsConfig)
+	cfg.GetVideoBucketReturns("videos")
+
+	return video.NewService(videoRepo, chapterRepo, new(fakes.FakeFAQRepo), new(fakes.FakeWatchRepo), new(fakes.FakeRelatedRepo), new(fakes.FakeTaxonomyRepo), new(fakes.FakeStandardRepo), new(fakes.FakeTeacherRepo), new(fakes.FakeNotificationRepo), store, nil, cfg)
+}
+
+func TestSaveChapter(t *testing.T) {
+	repo := new(fakes.FakeChapterRepo)
+	v := fakes.FakeVideo()
+	v.Duration = 120
+
+	chapter := &cohesioned.Chapter{ID: 9, VideoID: 2, Title: " Introduction ", Start: 0, End: 30}
+	if err := newChapterService(new(fakes.FakeVideoRepo), repo, new(fakes.FakeBlobStore)).SaveChapter(v, chapter); err != nil {
+		t.Fatalf("Unexpected error saving chapter: %v", err)
+	}
+
+	if chapter.ID != 1 || chapter.VideoID != v.ID || chapter.Title != "Introduction" || chapter.Created.IsZero() {
+		t.Errorf("unexpected chapter %v", chapter)
+	}
+
+	if len(repo.Chapters) != 1 {
+		t.Errorf("expected the chapter to be saved but got %v", repo.Chapters)
+	}
+}
+
+func TestSaveChapterRejectsInvalidChapters(t *testing.T) {
+	v := fakes.FakeVideo()
+	v.Duration = 120
+
+	chapters := map[string]*cohesioned.Chapter{
+		"title": {Start: 0, End: 30},
+		"start": {Title: "Negative", Start: -1, End: 30},
+		"end":   {Title: "Backwards", Start: 30, End: 10},
+	}
+
+	for field, chapter := range chapters {
+		repo := new(fakes.FakeChapterRepo)
+		if err := newChapterService(new(fakes.FakeVideoRepo), repo, new(fakes.FakeBlobStore)).SaveChapter(v, chapter); err != video.ErrInvalidChapter {
+			t.Errorf("expected ErrInvalidChapter for an invalid %s but got %v", field, err)
+		}
+
+		if !hasChapterError(chapter, field) {
+			t.Errorf("expected a validation error for the %s but got %v", field, chapter.ValidationErrors)
+		}
+
+		if len(repo.Chapters) != 0 {
+			t.Errorf("the invalid chapter should not be saved but got %v", repo.Chapters)
+		}
+	}
+}
+
+func TestSaveChapterRejectsChaptersAfterTheEndOfTheVideo(t *testing.T) {
+	v := fakes.FakeVideo()
+	v.Duration = 120
+
+	chapter := &cohesioned.Chapter{Title: "Outro", Start: 100, End: 130}
+	if err := newChapterService(new(fakes.FakeVideoRepo), new(fakes.FakeChapterRepo), new(fakes.FakeBlobStore)).SaveChapter(v, chapter); err != video.ErrInvalidChapter {
+		t.Fatalf("expected ErrInvalidChapter but got %v", err)
+	}
+
+	if !hasChapterError(chapter, "end") {
+		t.Errorf("expected a validation error for the end but got %v", chapter.ValidationErrors)
+	}
+}
+
+func TestSaveChapterRejectsOverlappingChapters(t *testing.T) {
+	repo := new(fakes.FakeChapterRepo)
+	repo.Chapters = []*cohesioned.Chapter{{ID: 1, VideoID: 1, Title: "Introduction", Start: 0, End: 30}}
+
+	chapter := &cohesioned.Chapter{Title: "Fractions", Start: 20, End: 60}
+	if err := newChapterService(new(fakes.FakeVideoRepo), repo, new(fakes.FakeBlobStore)).SaveChapter(fakes.FakeVideo(), chapter); err != video.ErrInvalidChapter {
+		t.Fatalf("expected ErrInvalidChapter but got %v", err)
+	}
+
+	if !hasChapterError(chapter, "start") {
+		t.Errorf("expected a validation error for the overlap but got %v", chapter.ValidationErrors)
+	}
+
+	adjacent := &cohesioned.Chapter{Title: "Fractions", Start: 30, End: 60}
+	if err := newChapterService(new(fakes.FakeVideoRepo), repo, new(fakes.FakeBlobStore)).SaveChapter(fakes.FakeVideo(), adjacent); err != nil {
+		t.Errorf("a chapter starting where another ends should be saved but got %v", err)
+	}
+}
+
+func TestUpdateChapter(t *testing.T) {
+	repo := new(fakes.FakeChapterRepo)
+	repo.Chapters = []*cohesioned.Chapter{
+		{ID: 1, VideoID: 1, Title: "Introduction", Start: 0, End: 30},
+		{ID: 2, VideoID: 1, Title: "Fractions", Start: 30, End: 60},
+	}
+
+	svc := newChapterService(new(fakes.FakeVideoRepo), repo, new(fakes.FakeBlobStore))
+	if err := svc.UpdateChapter(fakes.FakeVideo(), &cohesioned.Chapter{ID: 1, Title: "Welcome", Start: 0, End: 25}); err != nil {
+		t.Fatalf("Unexpected error updating chapter: %v", err)
+	}
+
+	if repo.Chapters[0].Title != "Welcome" || repo.Chapters[0].End != 25 || repo.Chapters[0].Updated.IsZero() {
+		t.Errorf("expected the chapter to be updated but got %v", repo.Chapters[0])
+	}
+
+	if err := svc.UpdateChapter(fakes.FakeVideo(), &cohesioned.Chapter{ID: 3, Title: "Missing", Start: 60, End: 90}); err != video.ErrNoChapter {
+		t.Errorf("expected ErrNoChapter but got %v", err)
+	}
+}
+
+func TestDeleteChapter(t *testing.T) {
+	repo := new(fakes.FakeChapterRepo)
+	repo.Chapters = []*cohesioned.Chapter{{ID: 1, VideoID: 1, Title: "Introduction", Start: 0, End: 30}}
+
+	svc := newChapterService(new(fakes.FakeVideoRepo), repo, new(fakes.FakeBlobStore))
+	if err := svc.DeleteChapter(fakes.FakeVideo(), 2); err != video.ErrNoChapter {
+		t.Errorf("expected ErrNoChapter but got %v", err)
+	}
+
+	if err := svc.DeleteChapter(fakes.FakeVideo(), 1); err != nil {
+		t.Fatalf("Unexpected error deleting chapter: %v", err)
+	}
+
+	if len(repo.Chapters) != 0 {
+		t.Errorf("expected the chapter to be deleted but got %v", repo.Chapters)
+	}
+}
+
+func TestValidateKeyTermTimestamps(t *testing.T) {
+	v := fakes.FakeVideo()
+	v.Duration = 120
+	v.KeyTerms = []string{"numerator", "denominator"}
+	v.KeyTermTimestamps = map[string]float64{"numerator": 42.5}
+
+	v.Validate()
+	if hasValidationError(v, "key_term_timestamps") {
+		t.Errorf("expected the timestamp to be valid but got %v", v.ValidationErrors)
+	}
+
+	for term, seconds := range map[string]float64{"quotient": 10, "denominator": -1, "numerator": 121} {
+		v := fakes.FakeVideo()
+		v.Duration = 120
+		v.KeyTerms = []string{"numerator", "denominator"}
+		v.KeyTermTimestamps = map[string]float64{term: seconds}
+
+		v.Validate()
+		if !hasValidationError(v, "key_term_timestamps") {
+			t.Errorf("expected a validation error for %s at %v seconds but got %v", term, seconds, v.ValidationErrors)
+		}
+	}
+}
+
+func TestGetWithSignedURLIncludesChapters(t *testing.T) {
+	v := fakes.FakeVideo()
+	v.StorageBucket = "videos"
+	v.StorageObjectName = "1-abc-test.mp4"
+
+	repo := new(fakes.FakeVideoRepo)
+	repo.GetReturns(v, nil)
+	chapters := new(fakes.FakeChapterRepo)
+	chapters.Chapters = []*cohesioned.Chapter{{ID: 1, VideoID: 1, Title: "Introduction", Start: 0, End: 30}}
+
+	store := new(fakes.FakeBlobStore)
+	store.SignedURLReturns("http://signed", nil)
+
+	result, err := newChapterService(repo, chapters, store).GetWithSignedURL(v.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error getting video: %v", err)
+	}
+
+	if len(result.Chapters) != 1 || result.Chapters[0].Title != "Introduction" {
+		t.Errorf("expected the video's chapters but got %v", result.Chapters)
+	}
+}
@@ -0,0 +1,176 @@
+package video
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+//ErrInvalidFAQ is returned when an FAQ or question can't be saved. The reasons are added to the FAQ's ValidationErrors
+var ErrInvalidFAQ = errors.New("the FAQ is not valid")
+
+//ErrNoFAQ is returned when a video has no FAQ with the requested ID
+var ErrNoFAQ = errors.New("the video has no FAQ with that id")
+
+//ListFAQs lists the video's published FAQs in the order they are shown. The parent who asked each question isn't included
+func (s *adminService) ListFAQs(videoID int64) ([]*cohesioned.FAQ, error) {
+	faqs, err := s.faqRepo.ListFAQs(videoID, cohesioned.FAQPublished)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range faqs {
+		f.AskedBy = 0
+		f.CreatedBy = 0
+	}
+
+	return faqs, nil
+}
+
+//ListQuestions pages through the FAQs of every video, including the questions waiting to be answered
+func (s *adminService) ListQuestions(opts *cohesioned.ListOptions) ([]*cohesioned.FAQ, int64, error) {
+	return s.faqRepo.ListQuestions(opts)
+}
+
+//AskQuestion saves a question a parent asked about the video. It waits in moderation until an admin answers it with UpdateFAQ
+func (s *adminService) AskQuestion(video *cohesioned.Video, faq *cohesioned.FAQ, askedBy int64) error {
+	faq.ID = 0
+	faq.VideoID = video.ID
+	faq.Answer = ""
+	faq.Status = cohesioned.FAQPending
+	faq.Position = 0
+	faq.AskedBy = askedBy
+	faq.Answered = time.Time{}
+	faq.AnsweredBy = 0
+	if !faq.Validate() {
+		return ErrInvalidFAQ
+	}
+
+	faq.Created = time.Now()
+	faq.CreatedBy = askedBy
+	id, err := s.faqRepo.SaveFAQ(faq)
+	if err != nil {
+		return err
+	}
+
+	faq.ID = id
+	return nil
+}
+
+//SaveFAQ adds an FAQ an admin wrote to the video. It is published unless another status is given
+func (s *adminService) SaveFAQ(video *cohesioned.Video, faq *cohesioned.FAQ, userID int64) error {
+	faq.ID = 0
+	faq.VideoID = video.ID
+	faq.AskedBy = 0
+	if len(faq.Status) == 0 {
+		faq.Status = cohesioned.FAQPublished
+	}
+
+	if !validateFAQ(faq) {
+		return ErrInvalidFAQ
+	}
+
+	faq.Created = time.Now()
+	faq.CreatedBy = userID
+	faq.Answered = time.Time{}
+	faq.AnsweredBy = 0
+	if faq.Status.IsAnswered() {
+		faq.Answered = faq.Created
+		faq.AnsweredBy = userID
+	}
+
+	id, err := s.faqRepo.SaveFAQ(faq)
+	if err != nil {
+		return err
+	}
+
+	faq.ID = id
+	return nil
+}
+
+//UpdateFAQ replaces the question, answer, status and position of one of the video's FAQs, returning ErrNoFAQ if the video has no FAQ with the FAQ's ID.
+//The parent who asked is notified the first time their question is answered
+func (s *adminService) UpdateFAQ(video *cohesioned.Video, faq *cohesioned.FAQ, userID int64) error {
+	existing, err := s.faqRepo.GetFAQ(video.ID, faq.ID)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		return ErrNoFAQ
+	}
+
+	faq.VideoID = video.ID
+	faq.AskedBy = existing.AskedBy
+	faq.Created = existing.Created
+	faq.CreatedBy = existing.CreatedBy
+	faq.Answered = existing.Answered
+	faq.AnsweredBy = existing.AnsweredBy
+	if len(faq.Status) == 0 {
+		faq.Status = existing.Status
+	}
+
+	if !validateFAQ(faq) {
+		return ErrInvalidFAQ
+	}
+
+	faq.Updated = time.Now()
+	faq.UpdatedBy = userID
+	answered := faq.Status.IsAnswered() && (!existing.Status.IsAnswered() || faq.Answer != existing.Answer)
+	if answered {
+		faq.Answered = faq.Updated
+		faq.AnsweredBy = userID
+	}
+
+	if err := s.faqRepo.UpdateFAQ(faq); err != nil {
+		return err
+	}
+
+	if faq.AskedBy > 0 && faq.Status.IsAnswered() && !existing.Status.IsAnswered() {
+		s.notifyAnswered(video, faq)
+	}
+
+	return nil
+}
+
+//DeleteFAQ deletes one of the video's FAQs, returning ErrNoFAQ if the video has no FAQ with that ID
+func (s *adminService) DeleteFAQ(video *cohesioned.Video, faqID int64) error {
+	existing, err := s.faqRepo.GetFAQ(video.ID, faqID)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		return ErrNoFAQ
+	}
+
+	return s.faqRepo.DeleteFAQ(video.ID, faqID)
+}
+
+//validateFAQ checks an FAQ an admin wrote or moderated. Only a question a parent asked can be answered without being published
+func validateFAQ(faq *cohesioned.FAQ) bool {
+	faq.Validate()
+	if faq.Status == cohesioned.FAQAnswered && faq.AskedBy == 0 {
+		faq.AddValidationError("status", "only a question a parent asked can be answered without publishing it")
+	}
+
+	return len(faq.ValidationErrors) == 0
+}
+
+//notifyAnswered tells the parent who asked the question that it has been answered. Failures are only logged - the answer is saved either way
+func (s *adminService) notifyAnswered(video *cohesioned.Video, faq *cohesioned.FAQ) {
+	n := &cohesioned.Notification{
+		UserID:  faq.AskedBy,
+		Kind:    cohesioned.NotificationQuestionAnswered,
+		Message: fmt.Sprintf("Your question about %s has been answered", video.Title),
+		VideoID: video.ID,
+		FAQID:   faq.ID,
+		Created: time.Now(),
+	}
+
+	if _, err := s.notificationRepo.Save(n); err != nil {
+		fmt.Printf("Failed to notify user %d that FAQ %d was answered: %v\n", faq.AskedBy, faq.ID, err)
+	}
+}
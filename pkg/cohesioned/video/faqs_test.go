@@ -0,0 +1,165 @@
+package video_test
+
+import (
+	"testing"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/video"
+)
+
+func newFAQService(videoRepo video.Repo, faqRepo video.FAQRepo, notifications *fakes.FakeNotificationRepo) video.AdminService {
+	return video.NewService(videoRepo, new(fakes.FakeChapterRepo), faqRepo, new(fakes.FakeWatchRepo), new(fakes.FakeRelatedRepo), new(fakes.FakeTaxonomyRepo), new(fakes.FakeStandardRepo), new(fakes.FakeTeacherRepo), notifications, new(fakes.FakeBlobStore), nil, new(fakes.FakeAwsConfig))
+}
+
+func TestAskQuestionIsPendingUntilAnswered(t *testing.T) {
+	repo := new(fakes.FakeFAQRepo)
+	svc := newFAQService(new(fakes.FakeVideoRepo), repo, new(fakes.FakeNotificationRepo))
+
+	faq := &cohesioned.FAQ{Question: "  Does this cover carrying?  ", Answer: "yes", Status: cohesioned.FAQPublished}
+	if err := svc.AskQuestion(fakes.FakeVideo(), faq, fakes.FakeProfile().ID); err != nil {
+		t.Fatalf("Unexpected error asking question: %v", err)
+	}
+
+	if faq.ID == 0 || faq.Status != cohesioned.FAQPending || len(faq.Answer) != 0 || faq.AskedBy != fakes.FakeProfile().ID {
+		t.Errorf("expected a pending question without an answer but got %v", faq)
+	}
+
+	if faq.Question != "Does this cover carrying?" {
+		t.Errorf("expected the question to be trimmed but got %q", faq.Question)
+	}
+}
+
+func TestAskQuestionRequiresAQuestion(t *testing.T) {
+	svc := newFAQService(new(fakes.FakeVideoRepo), new(fakes.FakeFAQRepo), new(fakes.FakeNotificationRepo))
+
+	faq := &cohesioned.FAQ{}
+	if err := svc.AskQuestion(fakes.FakeVideo(), faq, fakes.FakeProfile().ID); err != video.ErrInvalidFAQ {
+		t.Fatalf("expected ErrInvalidFAQ but got %v", err)
+	}
+
+	if len(faq.ValidationErrors) != 1 || faq.ValidationErrors[0].Field != "question" {
+		t.Errorf("expected a validation error for the question but got %v", faq.ValidationErrors)
+	}
+}
+
+func TestSaveFAQPublishesByDefault(t *testing.T) {
+	repo := new(fakes.FakeFAQRepo)
+	svc := newFAQService(new(fakes.FakeVideoRepo), repo, new(fakes.FakeNotificationRepo))
+
+	faq := &cohesioned.FAQ{Question: "How long is the lesson?", Answer: "About ten minutes"}
+	if err := svc.SaveFAQ(fakes.FakeVideo(), faq, fakes.FakeAdmin().ID); err != nil {
+		t.Fatalf("Unexpected error saving FAQ: %v", err)
+	}
+
+	if faq.Status != cohesioned.FAQPublished || faq.AnsweredBy != fakes.FakeAdmin().ID || faq.Answered.IsZero() {
+		t.Errorf("expected a published FAQ answered by the admin but got %v", faq)
+	}
+}
+
+func TestSaveFAQRequiresAnAnswerToPublish(t *testing.T) {
+	svc := newFAQService(new(fakes.FakeVideoRepo), new(fakes.FakeFAQRepo), new(fakes.FakeNotificationRepo))
+
+	for _, status := range []cohesioned.FAQStatus{cohesioned.FAQPublished, cohesioned.FAQAnswered} {
+		faq := &cohesioned.FAQ{Question: "How long is the lesson?", Status: status}
+		if err := svc.SaveFAQ(fakes.FakeVideo(), faq, fakes.FakeAdmin().ID); err != video.ErrInvalidFAQ {
+			t.Errorf("expected ErrInvalidFAQ for a %s FAQ without an answer but got %v", status, err)
+		}
+	}
+}
+
+func TestUpdateFAQNotifiesTheAskerWhenAnswered(t *testing.T) {
+	repo := new(fakes.FakeFAQRepo)
+	notifications := new(fakes.FakeNotificationRepo)
+	svc := newFAQService(new(fakes.FakeVideoRepo), repo, notifications)
+
+	v := fakes.FakeVideo()
+	question := &cohesioned.FAQ{Question: "Does this cover carrying?"}
+	if err := svc.AskQuestion(v, question, fakes.FakeProfile().ID); err != nil {
+		t.Fatalf("Unexpected error asking question: %v", err)
+	}
+
+	answer := &cohesioned.FAQ{ID: question.ID, Question: question.Question, Answer: "Yes, from 4:10", Status: cohesioned.FAQPublished}
+	if err := svc.UpdateFAQ(v, answer, fakes.FakeAdmin().ID); err != nil {
+		t.Fatalf("Unexpected error answering question: %v", err)
+	}
+
+	if answer.AskedBy != fakes.FakeProfile().ID || answer.AnsweredBy != fakes.FakeAdmin().ID {
+		t.Errorf("expected the asker to be kept and the answer to be recorded but got %v", answer)
+	}
+
+	if len(notifications.Saved) != 1 {
+		t.Fatalf("expected the asker to be notified once but got %v", notifications.Saved)
+	}
+
+	n := notifications.Saved[0]
+	if n.UserID != fakes.FakeProfile().ID || n.Kind != cohesioned.NotificationQuestionAnswered || n.FAQID != question.ID || n.VideoID != v.ID {
+		t.Errorf("unexpected notification %v", n)
+	}
+
+	//editing the answer doesn't notify them again
+	answer.Answer = "Yes, from 4:15"
+	if err := svc.UpdateFAQ(v, answer, fakes.FakeAdmin().ID); err != nil {
+		t.Fatalf("Unexpected error editing answer: %v", err)
+	}
+
+	if len(notifications.Saved) != 1 {
+		t.Errorf("expected only the first answer to notify the asker but got %v", notifications.Saved)
+	}
+}
+
+func TestUpdateFAQOnlyAnswersParentsQuestionsPrivately(t *testing.T) {
+	repo := new(fakes.FakeFAQRepo)
+	svc := newFAQService(new(fakes.FakeVideoRepo), repo, new(fakes.FakeNotificationRepo))
+
+	v := fakes.FakeVideo()
+	faq := &cohesioned.FAQ{Question: "How long is the lesson?", Answer: "About ten minutes"}
+	if err := svc.SaveFAQ(v, faq, fakes.FakeAdmin().ID); err != nil {
+		t.Fatalf("Unexpected error saving FAQ: %v", err)
+	}
+
+	update := &cohesioned.FAQ{ID: faq.ID, Question: faq.Question, Answer: faq.Answer, Status: cohesioned.FAQAnswered}
+	if err := svc.UpdateFAQ(v, update, fakes.FakeAdmin().ID); err != video.ErrInvalidFAQ {
+		t.Fatalf("expected ErrInvalidFAQ but got %v", err)
+	}
+}
+
+func TestUpdateFAQReturnsErrNoFAQ(t *testing.T) {
+	svc := newFAQService(new(fakes.FakeVideoRepo), new(fakes.FakeFAQRepo), new(fakes.FakeNotificationRepo))
+
+	faq := &cohesioned.FAQ{ID: 9, Question: "How long is the lesson?"}
+	if err := svc.UpdateFAQ(fakes.FakeVideo(), faq, fakes.FakeAdmin().ID); err != video.ErrNoFAQ {
+		t.Errorf("expected ErrNoFAQ but got %v", err)
+	}
+
+	if err := svc.DeleteFAQ(fakes.FakeVideo(), 9); err != video.ErrNoFAQ {
+		t.Errorf("expected ErrNoFAQ but got %v", err)
+	}
+}
+
+func TestGetWithSignedURLIncludesPublishedFAQs(t *testing.T) {
+	v := fakes.FakeVideo()
+
+	repo := new(fakes.FakeVideoRepo)
+	repo.GetReturns(v, nil)
+	faqs := new(fakes.FakeFAQRepo)
+	faqs.FAQs = []*cohesioned.FAQ{
+		{ID: 1, VideoID: v.ID, Question: "How long is the lesson?", Answer: "About ten minutes", Status: cohesioned.FAQPublished, CreatedBy: fakes.FakeAdmin().ID},
+		{ID: 2, VideoID: v.ID, Question: "Does this cover carrying?", Answer: "Yes", Status: cohesioned.FAQPublished, AskedBy: fakes.FakeProfile().ID, CreatedBy: fakes.FakeProfile().ID},
+		{ID: 3, VideoID: v.ID, Question: "Is there a worksheet?", Status: cohesioned.FAQPending, AskedBy: fakes.FakeProfile().ID},
+		{ID: 4, VideoID: v.ID, Question: "Can I see the answer?", Answer: "Only you can", Status: cohesioned.FAQAnswered, AskedBy: fakes.FakeProfile().ID},
+	}
+
+	result, err := newFAQService(repo, faqs, new(fakes.FakeNotificationRepo)).GetWithSignedURL(v.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error getting video: %v", err)
+	}
+
+	if len(result.FAQs) != 2 || result.FAQs[0].ID != 1 || result.FAQs[1].ID != 2 {
+		t.Fatalf("expected only the published FAQs but got %v", result.FAQs)
+	}
+
+	if result.FAQs[1].AskedBy != 0 || result.FAQs[1].CreatedBy != 0 {
+		t.Errorf("the parent who asked should not be shown with a published FAQ but got %v", result.FAQs[1])
+	}
+}
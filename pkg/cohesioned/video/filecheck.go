@@ -0,0 +1,202 @@
+package video
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+//sniffLen is how much of the start of a file is read to detect its format
+const sniffLen = 512
+
+//ErrInvalidFile is returned when an uploaded file is rejected. The reasons are added to the video's ValidationErrors
+var ErrInvalidFile = errors.New("the file is not valid")
+
+var errFileTooLarge = errors.New("the file is larger than the maximum upload size")
+
+//quicktimeAtoms are the top level atoms a QuickTime file without an ftyp atom can start with
+var quicktimeAtoms = []string{"moov", "mdat", "wide", "free", "skip"}
+
+//videoBrands are the ftyp brands of mp4 files that hold video. Other ISO base media files, such as HEIC and AVIF images or M4A audio, use the same ftyp atom
+var videoBrands = []string{
+	"isom", "iso2", "iso4", "iso5", "iso6", "mp41", "mp42", "avc1", "dash", "mmp4", "MSNV",
+	"M4V ", "M4VH", "M4VP", "f4v ", "3gp4", "3gp5", "3gp6", "3g2a",
+}
+
+//nonVideoBrands are the major brands of ISO base media files that aren't video, even when they list a video brand as compatible
+var nonVideoBrands = []string{
+	"heic", "heix", "hevc", "hevx", "heim", "heis", "mif1", "msf1", "avif", "avis",
+	"M4A ", "M4B ", "M4P ", "F4A ", "F4B ",
+}
+
+//SniffContentType detects the format of a file from its first bytes. Video containers are checked before falling back to http.DetectContentType
+func SniffContentType(head []byte) string {
+	if len(head) >= 12 && string(head[4:8]) == "ftyp" {
+		return ftypContentType(head)
+	}
+
+	if len(head) >= 8 {
+		for _, atom := range quicktimeAtoms {
+			if string(head[4:8]) == atom {
+				return "video/quicktime"
+			}
+		}
+	}
+
+	if bytes.HasPrefix(head, []byte{0x1A, 0x45, 0xDF, 0xA3}) {
+		if bytes.Contains(head, []byte("webm")) {
+			return "video/webm"
+		}
+
+		return "video/x-matroska"
+	}
+
+	if len(head) >= 12 && string(head[0:4]) == "RIFF" && string(head[8:12]) == "AVI " {
+		return "video/x-msvideo"
+	}
+
+	//mpeg transport streams are 188 byte packets that each start with a 0x47 sync byte
+	if len(head) > 188 && head[0] == 0x47 && head[188] == 0x47 {
+		return "video/mp2t"
+	}
+
+	return strings.TrimSpace(strings.SplitN(http.DetectContentType(head), ";", 2)[0])
+}
+
+//ftypContentType detects the format of a file that starts with an ftyp atom from its major and compatible brands.
+//Files that don't carry a video brand are application/octet-stream, as they are an image or audio format that no video type covers
+func ftypContentType(head []byte) string {
+	major := string(head[8:12])
+	if major == "qt  " {
+		return "video/quicktime"
+	}
+
+	if hasBrand(videoBrands, major) {
+		return "video/mp4"
+	}
+
+	if hasBrand(nonVideoBrands, major) {
+		return "application/octet-stream"
+	}
+
+	//the compatible brands follow the major brand and its minor version, up to the end of the atom
+	end := int(binary.BigEndian.Uint32(head[0:4]))
+	if end > len(head) {
+		end = len(head)
+	}
+
+	for i := 16; i+4 <= end; i += 4 {
+		if hasBrand(videoBrands, string(head[i:i+4])) {
+			return "video/mp4"
+		}
+	}
+
+	return "application/octet-stream"
+}
+
+func hasBrand(brands []string, brand string) bool {
+	for _, b := range brands {
+		if b == brand {
+			return true
+		}
+	}
+
+	return false
+}
+
+//sniff detects the format of the file read from r. The returned reader reads the whole file, including the bytes that were sniffed
+func sniff(r io.Reader) (string, io.Reader, error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", nil, err
+	}
+
+	head = head[:n]
+	return SniffContentType(head), io.MultiReader(bytes.NewReader(head), r), nil
+}
+
+//fileCheck measures and hashes a file as it is read, failing with errFileTooLarge once more than max bytes are read
+type fileCheck struct {
+	r        io.Reader
+	max      int64
+	size     int64
+	hash     hash.Hash
+	tooLarge bool
+}
+
+func newFileCheck(r io.Reader, max int64) *fileCheck {
+	return &fileCheck{r: r, max: max, hash: sha256.New()}
+}
+
+func (c *fileCheck) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.size += int64(n)
+	c.hash.Write(p[:n])
+
+	if c.max > 0 && c.size > c.max {
+		c.tooLarge = true
+		return n, errFileTooLarge
+	}
+
+	return n, err
+}
+
+//Checksum is the hex encoded sha-256 of what has been read so far
+func (c *fileCheck) Checksum() string {
+	return hex.EncodeToString(c.hash.Sum(nil))
+}
+
+//invalidFile adds a validation error to the video and returns ErrInvalidFile
+func invalidFile(video *cohesioned.Video, field, format string, a ...interface{}) error {
+	video.AddValidationError(field, fmt.Sprintf(format, a...))
+	return ErrInvalidFile
+}
+
+//checkType rejects files of a type that can't be uploaded
+func (s *adminService) checkType(video *cohesioned.Video, fileType string) error {
+	if !s.cfg.GetUploadConfig().Allows(fileType) {
+		return invalidFile(video, "file_type", "%s files can't be uploaded", fileType)
+	}
+
+	return nil
+}
+
+//checkSize rejects files larger than the maximum upload size
+func (s *adminService) checkSize(video *cohesioned.Video, size int64) error {
+	if max := s.cfg.GetUploadConfig().MaxSize; max > 0 && size > max {
+		return invalidFile(video, "file_size", "the file is larger than the maximum upload size of %d bytes", max)
+	}
+
+	return nil
+}
+
+//checkDuplicate rejects a file that has already been uploaded for another video
+func (s *adminService) checkDuplicate(video *cohesioned.Video, checksum string) error {
+	existing, err := s.videoRepo.FindByChecksum(checksum)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil && existing.ID != video.ID {
+		return invalidFile(video, "checksum", "the file has already been uploaded for video %d", existing.ID)
+	}
+
+	return nil
+}
+
+//discardObject deletes an uploaded object that won't be used. Failures are only logged - anything left behind is found by ReconcileStorage
+func (s *adminService) discardObject(bucket, key string) {
+	if err := s.store.Delete(bucket, key); err != nil {
+		fmt.Printf("Failed to delete %s from %s: %v\n", key, bucket, err)
+	}
+}
@@ -0,0 +1,198 @@
+package video_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/config"
+	"github.com/cohesion-education/api/pkg/cohesioned/storage"
+	"github.com/cohesion-education/api/pkg/cohesioned/video"
+)
+
+func newValidatingService(repo video.Repo, store storage.BlobStore) video.AdminService {
+	cfg := new(fakes.FakeAwsConfig)
+	cfg.GetVideoBucketReturns("videos")
+	cfg.GetUploadConfigReturns(&config.UploadConfig{AllowedTypes: []string{"video/mp4", "video/webm"}, MaxSize: 64})
+
+	return video.NewService(repo, new(fakes.FakeChapterRepo), new(fakes.FakeFAQRepo), new(fakes.FakeWatchRepo), new(fakes.FakeRelatedRepo), new(fakes.FakeTaxonomyRepo), new(fakes.FakeStandardRepo), new(fakes.FakeTeacherRepo), new(fakes.FakeNotificationRepo), store, nil, cfg)
+}
+
+func hasValidationError(v *cohesioned.Video, field string) bool {
+	for _, e := range v.ValidationErrors {
+		if e.Field == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestSniffContentType(t *testing.T) {
+	files := map[string]string{
+		"\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00":                 "video/mp4",
+		"\x00\x00\x00\x14ftypqt  \x00\x00\x00\x00":                 "video/quicktime",
+		"\x00\x00\x00\x18ftypXAVC\x00\x00\x00\x00mp42iso2":         "video/mp4",
+		"\x00\x00\x00\x18ftypheic\x00\x00\x00\x00mif1heic":         "application/octet-stream",
+		"\x00\x00\x00\x1cftypavif\x00\x00\x00\x00avifmif1miaf":     "application/octet-stream",
+		"\x00\x00\x00\x1cftypM4A \x00\x00\x00\x00M4A mp42isom":     "application/octet-stream",
+		"\x00\x00\x00\x08wide\x00\x00\x00\x00mdat":                 "video/quicktime",
+		"\x1a\x45\xdf\xa3\x9f\x42\x86\x81\x01\x42\x82\x84webm":     "video/webm",
+		"\x1a\x45\xdf\xa3\x9f\x42\x86\x81\x01\x42\x82\x88matroska": "video/x-matroska",
+		"RIFF\x00\x00\x00\x00AVI LIST":                             "video/x-msvideo",
+		"hello world":                                              "text/plain",
+		"<html><body></body></html>":                               "text/html",
+	}
+
+	for file, expected := range files {
+		if contentType := video.SniffContentType([]byte(file)); contentType != expected {
+			t.Errorf("expected %q to be sniffed as %s but got %s", file, expected, contentType)
+		}
+	}
+}
+
+func TestSetFileRejectsTypesThatArentAllowed(t *testing.T) {
+	store := new(fakes.FakeBlobStore)
+	svc := newValidatingService(new(fakes.FakeVideoRepo), store)
+	v := fakes.FakeVideo()
+
+	ctx := context.WithValue(context.Background(), cohesioned.CurrentUserKey, fakes.FakeProfile())
+	if err := svc.SetFile(ctx, strings.NewReader("<html><body>not a video</body></html>"), v); err != video.ErrInvalidFile {
+		t.Fatalf("expected ErrInvalidFile but got %v", err)
+	}
+
+	if !hasValidationError(v, "file_type") {
+		t.Errorf("expected a validation error for the file type but got %v", v.ValidationErrors)
+	}
+
+	if len(store.Stored) != 0 {
+		t.Errorf("nothing should be stored for a rejected file but got %v", store.Stored)
+	}
+}
+
+func TestSetFileRejectsFilesThatAreTooLarge(t *testing.T) {
+	store := new(fakes.FakeBlobStore)
+	svc := newValidatingService(new(fakes.FakeVideoRepo), store)
+	v := fakes.FakeVideo()
+
+	file := append(append([]byte{}, mp4Header...), bytes.Repeat([]byte{0}, 64)...)
+	ctx := context.WithValue(context.Background(), cohesioned.CurrentUserKey, fakes.FakeProfile())
+	if err := svc.SetFile(ctx, bytes.NewReader(file), v); err != video.ErrInvalidFile {
+		t.Fatalf("expected ErrInvalidFile but got %v", err)
+	}
+
+	if !hasValidationError(v, "file_size") {
+		t.Errorf("expected a validation error for the file size but got %v", v.ValidationErrors)
+	}
+}
+
+func TestSetFileRecordsTheChecksum(t *testing.T) {
+	store := new(fakes.FakeBlobStore)
+	svc := newValidatingService(new(fakes.FakeVideoRepo), store)
+	v := fakes.FakeVideo()
+	v.FileType = "application/octet-stream"
+
+	ctx := context.WithValue(context.Background(), cohesioned.CurrentUserKey, fakes.FakeProfile())
+	if err := svc.SetFile(ctx, bytes.NewReader(mp4Header), v); err != nil {
+		t.Fatalf("Unexpected error setting file: %v", err)
+	}
+
+	if v.FileType != "video/mp4" || v.FileSize != int64(len(mp4Header)) || len(v.Checksum) != 64 {
+		t.Errorf("expected the sniffed type, size and checksum to be recorded but got %s %d %s", v.FileType, v.FileSize, v.Checksum)
+	}
+}
+
+func TestSetFileRejectsDuplicates(t *testing.T) {
+	other := fakes.FakeVideo()
+	other.ID = 2
+
+	repo := new(fakes.FakeVideoRepo)
+	repo.FindByChecksumReturns(other, nil)
+
+	store := new(fakes.FakeBlobStore)
+	svc := newValidatingService(repo, store)
+	v := fakes.FakeVideo()
+	v.StorageObjectName = "1-current-test.mp4"
+
+	ctx := context.WithValue(context.Background(), cohesioned.CurrentUserKey, fakes.FakeProfile())
+	if err := svc.SetFile(ctx, bytes.NewReader(mp4Header), v); err != video.ErrInvalidFile {
+		t.Fatalf("expected ErrInvalidFile but got %v", err)
+	}
+
+	if !hasValidationError(v, "checksum") {
+		t.Errorf("expected a validation error for the checksum but got %v", v.ValidationErrors)
+	}
+
+	if v.StorageObjectName != "1-current-test.mp4" || len(v.Checksum) != 0 {
+		t.Errorf("the rejected file should not be recorded on the video: %v", v)
+	}
+
+	if len(store.Deleted) != 1 || store.Deleted[0] == "1-current-test.mp4" {
+		t.Errorf("expected the staged duplicate to be deleted but got %v", store.Deleted)
+	}
+}
+
+func TestSetFileRejectsDuplicatesSavedByAnotherUpload(t *testing.T) {
+	repo := new(fakes.FakeVideoRepo)
+	repo.UpdateErr = video.ErrDuplicateChecksum
+
+	store := new(fakes.FakeBlobStore)
+	svc := newValidatingService(repo, store)
+	v := fakes.FakeVideo()
+	v.StorageObjectName = "1-current-test.mp4"
+
+	ctx := context.WithValue(context.Background(), cohesioned.CurrentUserKey, fakes.FakeProfile())
+	if err := svc.SetFile(ctx, bytes.NewReader(mp4Header), v); err != video.ErrInvalidFile {
+		t.Fatalf("expected ErrInvalidFile but got %v", err)
+	}
+
+	if !hasValidationError(v, "checksum") {
+		t.Errorf("expected a validation error for the checksum but got %v", v.ValidationErrors)
+	}
+
+	if v.StorageObjectName != "1-current-test.mp4" {
+		t.Errorf("the rejected file should not be recorded on the video: %v", v)
+	}
+
+	if len(store.Deleted) != 1 || store.Deleted[0] == "1-current-test.mp4" {
+		t.Errorf("expected the staged duplicate to be deleted but got %v", store.Deleted)
+	}
+}
+
+func TestCompleteUploadDeletesInvalidFiles(t *testing.T) {
+	store := new(fakes.FakeBlobStore)
+	store.HeadReturns(&storage.ObjectInfo{Key: "1-abc-test.mp4", Size: 11, ContentType: "video/mp4"}, nil)
+	store.Put("videos", "1-abc-test.mp4", strings.NewReader("hello world"), "video/mp4")
+
+	svc := newValidatingService(new(fakes.FakeVideoRepo), store)
+	v := fakes.FakeVideo()
+
+	if err := svc.CompleteUpload(context.Background(), v, &video.CompletedUpload{ObjectName: "1-abc-test.mp4"}); err != video.ErrInvalidFile {
+		t.Fatalf("expected ErrInvalidFile but got %v", err)
+	}
+
+	if !hasValidationError(v, "file_type") {
+		t.Errorf("expected a validation error for the file type but got %v", v.ValidationErrors)
+	}
+
+	if len(store.Deleted) != 1 || store.Deleted[0] != "1-abc-test.mp4" {
+		t.Errorf("expected the invalid upload to be deleted but got %v", store.Deleted)
+	}
+}
+
+func TestWriteChunkRejectsUploadsThatAreTooLarge(t *testing.T) {
+	svc := newValidatingService(new(fakes.FakeVideoRepo), new(fakes.FakeBlobStore))
+	v := fakes.FakeVideo()
+
+	ctx := context.WithValue(context.Background(), cohesioned.CurrentUserKey, fakes.FakeProfile())
+	if _, err := svc.WriteChunk(ctx, v, &cohesioned.UploadChunk{First: 0, Last: 9}, 100, "video/mp4", strings.NewReader("0123456789")); err != video.ErrInvalidFile {
+		t.Fatalf("expected ErrInvalidFile but got %v", err)
+	}
+
+	if progress, _ := svc.UploadProgress(v.ID); progress != nil {
+		t.Errorf("expected no upload to be started but got %v", progress)
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/storage"
 	"github.com/gorilla/mux"
 	"github.com/unrolled/render"
 )
@@ -29,17 +32,91 @@ func NewAPIResponse(v *cohesioned.Video) *VideoResponse {
 
 func ListHandler(r *render.Render, svc AdminService) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		resp := NewAPIResponse(nil)
-		videos, err := svc.List()
-		resp.List = videos
+		resp := &cohesioned.APIResponse{}
+		opts := cohesioned.ListOptionsFromRequest(req, ListSpec, resp)
+		if len(resp.ValidationErrors) > 0 {
+			resp.SetErrMsg("Invalid list parameters")
+			r.JSON(w, http.StatusBadRequest, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		videos, total, err := svc.List(opts)
 		if err != nil {
 			resp.SetErrMsg("Failed to list videos %v", err)
 			fmt.Println(resp.ErrMsg)
-			r.JSON(w, http.StatusInternalServerError, resp)
+			r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
 			return
 		}
 
-		r.JSON(w, http.StatusOK, resp)
+		if videos == nil {
+			videos = []*cohesioned.Video{}
+		}
+
+		r.JSON(w, http.StatusOK, cohesioned.NewPage(req, opts, videos, total))
+	}
+}
+
+//SearchHandler searches video titles, key terms and standards for ?q=, optionally within the taxonomy subtree given by ?taxonomy_id= or ?grade= and ?subject=
+func SearchHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &cohesioned.APIResponse{}
+		opts := cohesioned.ListOptionsFromRequest(req, SearchSpec, resp)
+
+		params := req.URL.Query()
+		query := SearchQuery{
+			Text:    strings.TrimSpace(params.Get("q")),
+			Grade:   params.Get("grade"),
+			Subject: params.Get("subject"),
+		}
+
+		if len(SearchTerms(query.Text)) == 0 {
+			resp.AddValidationError("q", fmt.Sprintf("q must contain at least one word of %d or more characters", MinTermLength))
+		}
+
+		if taxonomyID := params.Get("taxonomy_id"); len(taxonomyID) > 0 {
+			id, err := strconv.ParseInt(taxonomyID, 10, 64)
+			if err != nil {
+				resp.AddValidationError("taxonomy_id", fmt.Sprintf("%s is not a valid taxonomy ID", taxonomyID))
+			}
+
+			query.TaxonomyID = id
+		}
+
+		if len(query.Subject) > 0 && len(query.Grade) == 0 {
+			resp.AddValidationError("subject", "subject can only be used together with grade")
+		}
+
+		if len(resp.ValidationErrors) > 0 {
+			resp.SetErrMsg("Invalid search")
+			r.JSON(w, http.StatusBadRequest, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		results, total, err := svc.Search(query, opts)
+		if err == ErrUnknownGrade || err == ErrUnknownSubject {
+			field, value := "grade", query.Grade
+			if err == ErrUnknownSubject {
+				field, value = "subject", query.Subject
+			}
+
+			resp.AddValidationError(field, fmt.Sprintf("%s is not a %s in the taxonomy", value, field))
+			resp.SetErrMsg("Invalid search")
+			r.JSON(w, http.StatusBadRequest, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		if err != nil {
+			resp.SetErrMsg("Failed to search videos for %s: %v", query.Text, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		if results == nil {
+			results = []*cohesioned.SearchResult{}
+		}
+
+		r.JSON(w, http.StatusOK, cohesioned.NewPage(req, opts, results, total))
 	}
 }
 
@@ -111,6 +188,152 @@ func FindByTaxonomyHandler(r *render.Render, svc AdminService) http.HandlerFunc
 	}
 }
 
+//TagResponse lists key terms or standards with the number of videos tagged with each
+type TagResponse struct {
+	*cohesioned.APIResponse
+	List []*cohesioned.Tag `json:"list"`
+}
+
+//FindByKeyTermHandler pages through the videos tagged with the {term} key term
+func FindByKeyTermHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return findByTagHandler(r, "term", "key term", svc.FindByKeyTerm)
+}
+
+//FindByStandardHandler pages through the videos tagged with the {standard} state or common core standard
+func FindByStandardHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return findByTagHandler(r, "standard", "standard", svc.FindByStandard)
+}
+
+//FindByTeacherHandler pages through the videos presented by the {teacher_id} teacher
+func FindByTeacherHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &cohesioned.APIResponse{}
+		opts := cohesioned.ListOptionsFromRequest(req, ListSpec, resp)
+
+		teacherIDParam := mux.Vars(req)["teacher_id"]
+		teacherID, err := strconv.ParseInt(teacherIDParam, 10, 64)
+		if err != nil {
+			resp.AddValidationError("teacher_id", fmt.Sprintf("%s is not a valid teacher ID", teacherIDParam))
+		}
+
+		if len(resp.ValidationErrors) > 0 {
+			resp.SetErrMsg("Invalid list parameters")
+			r.JSON(w, http.StatusBadRequest, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		videos, total, err := svc.FindByTeacher(teacherID, opts)
+		if err != nil {
+			resp.SetErrMsg("Failed to list videos by teacher %d: %v", teacherID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		if videos == nil {
+			videos = []*cohesioned.Video{}
+		}
+
+		r.JSON(w, http.StatusOK, cohesioned.NewPage(req, opts, videos, total))
+	}
+}
+
+func findByTagHandler(r *render.Render, param, description string, find func(string, *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &cohesioned.APIResponse{}
+		opts := cohesioned.ListOptionsFromRequest(req, ListSpec, resp)
+		if len(resp.ValidationErrors) > 0 {
+			resp.SetErrMsg("Invalid list parameters")
+			r.JSON(w, http.StatusBadRequest, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		name := mux.Vars(req)[param]
+		videos, total, err := find(name, opts)
+		if err != nil {
+			resp.SetErrMsg("Failed to list videos by %s %s: %v", description, name, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		if videos == nil {
+			videos = []*cohesioned.Video{}
+		}
+
+		r.JSON(w, http.StatusOK, cohesioned.NewPage(req, opts, videos, total))
+	}
+}
+
+//ForMyStateHandler pages through the videos aligned to the standards of the current user's state
+func ForMyStateHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &cohesioned.APIResponse{}
+		opts := cohesioned.ListOptionsFromRequest(req, ListSpec, resp)
+
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		if len(currentUser.State) == 0 {
+			resp.AddValidationError("state", "set the state on your profile to see the videos aligned to its standards")
+		}
+
+		if len(resp.ValidationErrors) > 0 {
+			resp.SetErrMsg("Invalid list parameters")
+			r.JSON(w, http.StatusBadRequest, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		videos, total, err := svc.FindByJurisdiction(currentUser.State, opts)
+		if err != nil {
+			resp.SetErrMsg("Failed to list videos aligned to the standards of %s: %v", currentUser.State, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		if videos == nil {
+			videos = []*cohesioned.Video{}
+		}
+
+		r.JSON(w, http.StatusOK, cohesioned.NewPage(req, opts, videos, total))
+	}
+}
+
+func KeyTermsHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return tagsHandler(r, "key terms", svc.ListKeyTerms)
+}
+
+func StandardsHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return tagsHandler(r, "standards", svc.ListStandards)
+}
+
+func tagsHandler(r *render.Render, description string, list func() ([]*cohesioned.Tag, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &TagResponse{APIResponse: &cohesioned.APIResponse{}}
+
+		tags, err := list()
+		if err != nil {
+			resp.SetErrMsg("Failed to list %s: %v", description, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		resp.List = tags
+		if resp.List == nil {
+			resp.List = []*cohesioned.Tag{}
+		}
+
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
 func AddHandler(r *render.Render, svc AdminService) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		resp := NewAPIResponse(nil)
@@ -126,7 +349,22 @@ func AddHandler(r *render.Render, svc AdminService) http.HandlerFunc {
 			return
 		}
 
-		if video.Validate() != true {
+		video.Validate()
+		if err := svc.ValidateStandards(video, nil); err != nil {
+			resp.SetErrMsg("Failed to validate standards %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		if err := svc.ValidateTeachers(video); err != nil {
+			resp.SetErrMsg("Failed to validate teachers %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		if len(video.ValidationErrors) > 0 {
 			resp.Video = video
 			resp.SetErrMsg("Hmmm... you seem to be missing some required fields")
 			r.JSON(w, http.StatusBadRequest, resp)
@@ -134,7 +372,13 @@ func AddHandler(r *render.Render, svc AdminService) http.HandlerFunc {
 		}
 
 		ctx := req.Context()
-		if err := svc.Save(ctx, video); err != nil {
+		err := svc.Save(ctx, video)
+		if err == ErrUnknownTeacher {
+			writeUnknownTeacher(r, w, resp, video)
+			return
+		}
+
+		if err != nil {
 			resp.SetErrMsg("Failed to save video %v", err)
 			fmt.Println(resp.ErrMsg)
 			r.JSON(w, http.StatusInternalServerError, resp)
@@ -178,7 +422,13 @@ func UploadHandler(r *render.Render, svc AdminService) http.HandlerFunc {
 		}
 
 		ctx := req.Context()
-		if err := svc.SetFile(ctx, req.Body, video); err != nil {
+		err = svc.SetFile(ctx, req.Body, video)
+		if err == ErrInvalidFile {
+			writeInvalidFile(r, w, resp.APIResponse, video)
+			return
+		}
+
+		if err != nil {
 			resp.SetErrMsg("An unknown error occurred when saving the video file: %v", err)
 			fmt.Println(resp.ErrMsg)
 			r.JSON(w, http.StatusInternalServerError, resp)
@@ -209,6 +459,21 @@ func UpdateHandler(r *render.Render, svc AdminService) http.HandlerFunc {
 			return
 		}
 
+		if existing == nil {
+			resp.SetErrMsg("%d is not a valid video id", videoID)
+			r.JSON(w, http.StatusNotFound, resp)
+			return
+		}
+
+		//decoding reuses the slices, so the standards the video had are copied first
+		previous := &cohesioned.Video{
+			StateStandards:      append([]string{}, existing.StateStandards...),
+			CommonCoreStandards: append([]string{}, existing.CommonCoreStandards...),
+		}
+
+		//decoding merges into maps, so the timestamps are replaced by the payload's rather than added to
+		existing.KeyTermTimestamps = nil
+
 		defer req.Body.Close()
 		decoder := json.NewDecoder(req.Body)
 		if err := decoder.Decode(&existing); err != nil {
@@ -218,7 +483,22 @@ func UpdateHandler(r *render.Render, svc AdminService) http.HandlerFunc {
 			return
 		}
 
-		if existing.Validate() != true {
+		existing.Validate()
+		if err := svc.ValidateStandards(existing, previous); err != nil {
+			resp.SetErrMsg("Failed to validate standards %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		if err := svc.ValidateTeachers(existing); err != nil {
+			resp.SetErrMsg("Failed to validate teachers %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		if len(existing.ValidationErrors) > 0 {
 			resp.Video = existing
 			resp.SetErrMsg("Hmmm... you seem to be missing some required fields")
 			r.JSON(w, http.StatusBadRequest, resp)
@@ -226,7 +506,13 @@ func UpdateHandler(r *render.Render, svc AdminService) http.HandlerFunc {
 		}
 
 		ctx := req.Context()
-		if err := svc.Update(ctx, existing); err != nil {
+		err = svc.Update(ctx, existing)
+		if err == ErrUnknownTeacher {
+			writeUnknownTeacher(r, w, resp, existing)
+			return
+		}
+
+		if err != nil {
 			resp.SetErrMsg("Failed to update video %v", err)
 			fmt.Println(resp.ErrMsg)
 			r.JSON(w, http.StatusInternalServerError, resp)
@@ -257,6 +543,31 @@ func GetByIDHandler(r *render.Render, svc AdminService) http.HandlerFunc {
 			return
 		}
 
+		if video == nil {
+			resp.SetErrMsg("%d is not a valid video id", videoID)
+			r.JSON(w, http.StatusNotFound, resp)
+			return
+		}
+
+		//the optional student_id param resumes where one of the current user's students is up to instead
+		var studentID int64
+		if param := req.URL.Query().Get("student_id"); len(param) > 0 {
+			if studentID, err = strconv.ParseInt(param, 10, 64); err != nil {
+				resp.SetErrMsg("%s is not a valid student id %v", param, err)
+				r.JSON(w, http.StatusBadRequest, resp)
+				return
+			}
+		}
+
+		if currentUser, ok := cohesioned.FromRequest(req); ok {
+			if video.Progress, err = svc.WatchProgress(video.ID, currentUser.ID, studentID); err != nil {
+				resp.SetErrMsg("Failed to get the progress of user %d through video %d %v", currentUser.ID, videoID, err)
+				fmt.Println(resp.ErrMsg)
+				r.JSON(w, http.StatusInternalServerError, resp)
+				return
+			}
+		}
+
 		resp.Video = video
 
 		r.JSON(w, http.StatusOK, resp)
@@ -284,3 +595,1197 @@ func DeleteHandler(r *render.Render, svc AdminService) http.HandlerFunc {
 		r.JSON(w, http.StatusOK, resp)
 	}
 }
+
+type TranscodingResponse struct {
+	*cohesioned.APIResponse
+	List []*cohesioned.TranscodingJob `json:"jobs"`
+}
+
+//TranscodingStatusHandler lists the transcoding jobs of the video, newest first
+func TranscodingStatusHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &TranscodingResponse{APIResponse: &cohesioned.APIResponse{}}
+
+		vars := mux.Vars(req)
+		videoID, err := strconv.ParseInt(vars["id"], 10, 64)
+		if err != nil {
+			resp.SetErrMsg("%s is not a valid id %v", vars["id"], err)
+			r.JSON(w, http.StatusNotFound, resp)
+			return
+		}
+
+		jobs, err := svc.TranscodingJobs(videoID)
+		if err != nil {
+			resp.SetErrMsg("Failed to get the transcoding jobs of video %d: %v", videoID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		resp.List = jobs
+		if resp.List == nil {
+			resp.List = []*cohesioned.TranscodingJob{}
+		}
+
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//MasterPlaylistHandler serves an HLS master playlist of the video's HLS renditions
+func MasterPlaylistHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := NewAPIResponse(nil)
+
+		vars := mux.Vars(req)
+		videoID, err := strconv.ParseInt(vars["id"], 10, 64)
+		if err != nil {
+			resp.SetErrMsg("%s is not a valid id %v", vars["id"], err)
+			r.JSON(w, http.StatusNotFound, resp)
+			return
+		}
+
+		renditions, err := svc.HLSRenditions(videoID)
+		if err != nil {
+			resp.SetErrMsg("Failed to list the renditions of video %d %v", videoID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		if len(renditions) == 0 {
+			resp.SetErrMsg("Video %d has no renditions to stream", videoID)
+			r.JSON(w, http.StatusNotFound, resp)
+			return
+		}
+
+		w.Header().Set("Content-Type", HLSContentType)
+		w.Header().Set("Cache-Control", "private, no-store")
+		r.Data(w, http.StatusOK, MasterPlaylist(renditions))
+	}
+}
+
+//VariantPlaylistHandler serves the playlist of one of the video's HLS renditions, with a signed url for each segment
+func VariantPlaylistHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := NewAPIResponse(nil)
+		video := findVideo(r, w, req, svc, resp.APIResponse)
+		if video == nil {
+			return
+		}
+
+		renditionID, _ := strconv.ParseInt(mux.Vars(req)["rendition_id"], 10, 64)
+		playlist, err := svc.VariantPlaylist(video, renditionID)
+		if err == ErrNoHLSRendition {
+			resp.SetErrMsg("Video %d has no HLS rendition %d", video.ID, renditionID)
+			r.JSON(w, http.StatusNotFound, resp)
+			return
+		}
+
+		if err != nil {
+			resp.SetErrMsg("Failed to read the playlist of rendition %d of video %d: %v", renditionID, video.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		w.Header().Set("Content-Type", HLSContentType)
+		w.Header().Set("Cache-Control", "private, no-store")
+		r.Data(w, http.StatusOK, playlist)
+	}
+}
+
+//writeUnknownTeacher responds to a video that couldn't be saved because one of its teachers was deleted after it was validated
+func writeUnknownTeacher(r *render.Render, w http.ResponseWriter, resp *VideoResponse, video *cohesioned.Video) {
+	video.AddValidationError("teacher_ids", "one of the teachers was deleted while the video was being saved")
+	resp.Video = video
+	resp.SetErrMsg("Hmmm... you seem to be missing some required fields")
+	r.JSON(w, http.StatusBadRequest, resp)
+}
+
+//writeInvalidFile responds to a file rejected with ErrInvalidFile with the reasons it was rejected
+func writeInvalidFile(r *render.Render, w http.ResponseWriter, resp *cohesioned.APIResponse, video *cohesioned.Video) {
+	resp.ValidationErrors = append(resp.ValidationErrors, video.ValidationErrors...)
+	resp.SetErrMsg("Invalid file")
+	r.JSON(w, http.StatusBadRequest, resp)
+}
+
+type UploadResponse struct {
+	*cohesioned.APIResponse
+	*UploadTarget
+}
+
+//UploadRequest describes the file about to be uploaded directly to storage. FileType defaults to the video's file_type
+type UploadRequest struct {
+	FileType string `json:"file_type"`
+	FileSize int64  `json:"file_size"`
+}
+
+//findVideo gets the video in the id path param, writing an error response and returning nil if it can't
+func findVideo(r *render.Render, w http.ResponseWriter, req *http.Request, svc AdminService, resp *cohesioned.APIResponse) *cohesioned.Video {
+	idParam := mux.Vars(req)["id"]
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		resp.SetErrMsg("%s is not a valid video ID; %v", idParam, err)
+		r.JSON(w, http.StatusBadRequest, resp)
+		return nil
+	}
+
+	video, err := svc.Get(id)
+	if err != nil {
+		resp.SetErrMsg("Unable to retrieve video by id %v - %v", idParam, err)
+		fmt.Println(resp.ErrMsg)
+		r.JSON(w, http.StatusInternalServerError, resp)
+		return nil
+	}
+
+	if video == nil {
+		resp.SetErrMsg("%v is not a valid video id", idParam)
+		r.JSON(w, http.StatusNotFound, resp)
+		return nil
+	}
+
+	return video
+}
+
+//decodeUploadRequest reads the optional UploadRequest body, writing an error response and returning nil if it is invalid
+func decodeUploadRequest(r *render.Render, w http.ResponseWriter, req *http.Request, resp *cohesioned.APIResponse) *UploadRequest {
+	upload := &UploadRequest{}
+	if req.ContentLength == 0 {
+		return upload
+	}
+
+	defer req.Body.Close()
+	if err := json.NewDecoder(req.Body).Decode(upload); err != nil {
+		resp.SetErrMsg("Unable to process the upload payload. Error: %v", err)
+		r.JSON(w, http.StatusBadRequest, resp)
+		return nil
+	}
+
+	if upload.FileSize < 0 {
+		resp.AddValidationError("file_size", "file_size can't be negative")
+		resp.SetErrMsg("Invalid upload")
+		r.JSON(w, http.StatusBadRequest, resp)
+		return nil
+	}
+
+	return upload
+}
+
+//PresignedUploadHandler returns a signed url the video's file can be PUT to directly, instead of sending it through UploadHandler. POST to CompleteUploadHandler once the upload has finished
+func PresignedUploadHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &UploadResponse{APIResponse: &cohesioned.APIResponse{}}
+
+		video := findVideo(r, w, req, svc, resp.APIResponse)
+		if video == nil {
+			return
+		}
+
+		upload := decodeUploadRequest(r, w, req, resp.APIResponse)
+		if upload == nil {
+			return
+		}
+
+		if len(upload.FileType) == 0 {
+			upload.FileType = video.FileType
+		}
+
+		target, err := svc.PresignUpload(video, upload.FileType)
+		if err != nil {
+			resp.SetErrMsg("Failed to create an upload url for video %d: %v", video.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		resp.UploadTarget = target
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//MultipartUploadHandler starts a multipart upload of the video's file straight to storage, returning a signed url for each part of a file of file_size
+func MultipartUploadHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &UploadResponse{APIResponse: &cohesioned.APIResponse{}}
+
+		video := findVideo(r, w, req, svc, resp.APIResponse)
+		if video == nil {
+			return
+		}
+
+		upload := decodeUploadRequest(r, w, req, resp.APIResponse)
+		if upload == nil {
+			return
+		}
+
+		if upload.FileSize == 0 {
+			resp.AddValidationError("file_size", "file_size is required")
+			resp.SetErrMsg("Invalid upload")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		if len(upload.FileType) == 0 {
+			upload.FileType = video.FileType
+		}
+
+		target, err := svc.StartMultipartUpload(video, upload.FileType, upload.FileSize)
+		if err != nil {
+			resp.SetErrMsg("Failed to start a multipart upload for video %d: %v", video.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		resp.UploadTarget = target
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//CompleteUploadHandler is called once the video's file has been uploaded to a url from PresignedUploadHandler or MultipartUploadHandler.
+//It checks the file is in storage, records its size and type on the video and starts transcoding it
+func CompleteUploadHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := NewAPIResponse(nil)
+
+		video := findVideo(r, w, req, svc, resp.APIResponse)
+		if video == nil {
+			return
+		}
+
+		upload := &CompletedUpload{}
+		if req.ContentLength != 0 {
+			defer req.Body.Close()
+			if err := json.NewDecoder(req.Body).Decode(upload); err != nil {
+				resp.SetErrMsg("Unable to process the upload payload. Error: %v", err)
+				r.JSON(w, http.StatusBadRequest, resp)
+				return
+			}
+		}
+
+		if len(upload.ObjectName) == 0 {
+			resp.AddValidationError("object_name", "the object name of the upload target is required")
+			resp.SetErrMsg("Invalid upload")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		if len(upload.UploadID) > 0 && len(upload.Parts) == 0 {
+			resp.AddValidationError("parts", "parts are required to complete a multipart upload")
+			resp.SetErrMsg("Invalid upload")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		err := svc.CompleteUpload(req.Context(), video, upload)
+		switch err {
+		case nil:
+		case ErrInvalidFile:
+			writeInvalidFile(r, w, resp.APIResponse, video)
+			return
+		case ErrInvalidObjectName:
+			resp.AddValidationError("object_name", err.Error())
+			resp.SetErrMsg("Invalid upload")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		case ErrFileNotUploaded:
+			resp.AddValidationError("file", err.Error())
+			resp.SetErrMsg("Invalid upload")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		case storage.ErrUploadNotFound, storage.ErrInvalidPart:
+			resp.AddValidationError("parts", err.Error())
+			resp.SetErrMsg("Invalid upload")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		default:
+			resp.SetErrMsg("Failed to complete the upload of video %d: %v", video.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		resp.Video = video
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//AbortUploadHandler discards the parts uploaded so far of a multipart upload. The upload target's object_name is required as a query param
+func AbortUploadHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &cohesioned.APIResponse{}
+
+		video := findVideo(r, w, req, svc, resp)
+		if video == nil {
+			return
+		}
+
+		uploadID := mux.Vars(req)["upload_id"]
+		objectName := req.URL.Query().Get("object_name")
+		if len(objectName) == 0 {
+			resp.AddValidationError("object_name", "the object name of the upload target is required")
+			resp.SetErrMsg("Invalid upload")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		err := svc.AbortUpload(video, objectName, uploadID)
+		if err == ErrInvalidObjectName {
+			resp.AddValidationError("object_name", err.Error())
+			resp.SetErrMsg("Invalid upload")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		if err == storage.ErrUploadNotFound {
+			resp.SetErr(err)
+			r.JSON(w, http.StatusNotFound, resp)
+			return
+		}
+
+		if err != nil {
+			resp.SetErrMsg("Failed to abort upload %s of video %d: %v", uploadID, video.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+type UploadProgressResponse struct {
+	*cohesioned.APIResponse
+	Upload *cohesioned.VideoUpload `json:"upload,omitempty"`
+	Video  *cohesioned.Video       `json:"video,omitempty"`
+}
+
+//writeUploadProgress sets the Range header to the bytes received so far, as in Google's resumable upload protocol
+func writeUploadProgress(w http.ResponseWriter, upload *cohesioned.VideoUpload) {
+	if upload != nil && upload.Received > 0 {
+		w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", upload.Received-1))
+	}
+}
+
+//ResumableUploadHandler receives the video's file in chunks PUT with a Content-Range header, e.g. bytes 0-1048575/5242880.
+//Chunks must be sent in order. After a disconnect, ask UploadProgressHandler where to resume from. Once the last chunk is received the file is assembled and saved with SetFile
+func ResumableUploadHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		defer req.Body.Close()
+
+		resp := &UploadProgressResponse{APIResponse: &cohesioned.APIResponse{}}
+
+		video := findVideo(r, w, req, svc, resp.APIResponse)
+		if video == nil {
+			return
+		}
+
+		chunk, total, err := ParseContentRange(req.Header.Get("Content-Range"))
+		if err != nil {
+			resp.AddValidationError("Content-Range", err.Error())
+			resp.SetErrMsg("Invalid chunk")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		upload, err := svc.WriteChunk(req.Context(), video, chunk, total, req.Header.Get("Content-Type"), req.Body)
+		resp.Upload = upload
+		writeUploadProgress(w, upload)
+
+		switch err {
+		case nil:
+		case ErrUploadOffsetMismatch:
+			resp.SetErr(err)
+			r.JSON(w, http.StatusConflict, resp)
+			return
+		case ErrUploadSizeChanged, ErrShortChunk:
+			resp.AddValidationError("Content-Range", err.Error())
+			resp.SetErrMsg("Invalid chunk")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		case ErrInvalidFile:
+			//the upload is discarded, so there is nothing to resume
+			w.Header().Del("Range")
+			resp.Upload = nil
+			writeInvalidFile(r, w, resp.APIResponse, video)
+			return
+		default:
+			resp.SetErrMsg("Failed to save chunk %d-%d of video %d: %v", chunk.First, chunk.Last, video.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		if upload.IsComplete() {
+			resp.Video = video
+		}
+
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//UploadProgressHandler reports how much of the video's resumable upload has been received
+func UploadProgressHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &UploadProgressResponse{APIResponse: &cohesioned.APIResponse{}}
+
+		vars := mux.Vars(req)
+		videoID, err := strconv.ParseInt(vars["id"], 10, 64)
+		if err != nil {
+			resp.SetErrMsg("%s is not a valid id %v", vars["id"], err)
+			r.JSON(w, http.StatusNotFound, resp)
+			return
+		}
+
+		upload, err := svc.UploadProgress(videoID)
+		if err != nil {
+			resp.SetErrMsg("Failed to get the upload of video %d: %v", videoID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		if upload == nil {
+			resp.SetErrMsg("Video %d has no upload in progress", videoID)
+			r.JSON(w, http.StatusNotFound, resp)
+			return
+		}
+
+		resp.Upload = upload
+		writeUploadProgress(w, upload)
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//CancelUploadHandler discards the video's resumable upload so it can be started again from the beginning
+func CancelUploadHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &cohesioned.APIResponse{}
+
+		vars := mux.Vars(req)
+		videoID, err := strconv.ParseInt(vars["id"], 10, 64)
+		if err != nil {
+			resp.SetErrMsg("%s is not a valid id %v", vars["id"], err)
+			r.JSON(w, http.StatusNotFound, resp)
+			return
+		}
+
+		if err := svc.CancelUpload(videoID); err != nil {
+			resp.SetErrMsg("Failed to cancel the upload of video %d: %v", videoID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+type StorageReportResponse struct {
+	*cohesioned.APIResponse
+	*StorageReport
+}
+
+//OrphansHandler reports the objects in the video bucket that no video refers to. Objects younger than the optional min_age param, e.g. 48h, are skipped
+func OrphansHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return reconcileStorageHandler(r, svc, false)
+}
+
+//DeleteOrphansHandler deletes the objects in the video bucket that no video refers to, accepting the same min_age param as OrphansHandler as long as it is at least MinOrphanDeleteAge
+func DeleteOrphansHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return reconcileStorageHandler(r, svc, true)
+}
+
+func reconcileStorageHandler(r *render.Render, svc AdminService, remove bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &StorageReportResponse{APIResponse: &cohesioned.APIResponse{}}
+
+		minAge := DefaultOrphanMinAge
+		if param := req.URL.Query().Get("min_age"); len(param) > 0 {
+			var err error
+			if minAge, err = time.ParseDuration(param); err != nil || minAge < 0 {
+				resp.AddValidationError("min_age", fmt.Sprintf("%s is not a valid duration - use e.g. 48h", param))
+				resp.SetErrMsg("Invalid min_age")
+				r.JSON(w, http.StatusBadRequest, resp)
+				return
+			}
+		}
+
+		if remove && minAge < MinOrphanDeleteAge {
+			resp.AddValidationError("min_age", fmt.Sprintf("orphans younger than %v may be uploads in progress and can't be deleted", MinOrphanDeleteAge))
+			resp.SetErrMsg("Invalid min_age")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		report, err := svc.ReconcileStorage(minAge, remove)
+		if err != nil {
+			resp.SetErrMsg("Failed to reconcile video storage: %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		resp.StorageReport = report
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+type ThumbnailBackfillResponse struct {
+	*cohesioned.APIResponse
+	*ThumbnailBackfill
+}
+
+//BackfillThumbnailsHandler records the thumbnails in the video bucket for every video that has none
+func BackfillThumbnailsHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &ThumbnailBackfillResponse{APIResponse: &cohesioned.APIResponse{}}
+
+		backfill, err := svc.BackfillThumbnails()
+		if err != nil {
+			resp.SetErrMsg("Failed to backfill thumbnails: %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		resp.ThumbnailBackfill = backfill
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+type ThumbnailResponse struct {
+	*cohesioned.APIResponse
+	*cohesioned.Thumbnail
+}
+
+//ThumbnailUploadHandler sets the request body, a JPEG, PNG or GIF image, as the video's poster. It is shown instead of the thumbnails generated from the video
+func ThumbnailUploadHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		defer req.Body.Close()
+
+		resp := &ThumbnailResponse{APIResponse: &cohesioned.APIResponse{}}
+		video := findVideo(r, w, req, svc, resp.APIResponse)
+		if video == nil {
+			return
+		}
+
+		thumbnail, err := svc.SetThumbnail(video, req.Body)
+		if err == ErrInvalidFile {
+			writeInvalidFile(r, w, resp.APIResponse, video)
+			return
+		}
+
+		if err != nil {
+			resp.SetErrMsg("Failed to save the thumbnail of video %d: %v", video.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		resp.Thumbnail = thumbnail
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//DeleteThumbnailHandler deletes the video's poster, going back to a generated thumbnail
+func DeleteThumbnailHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &cohesioned.APIResponse{}
+		video := findVideo(r, w, req, svc, resp)
+		if video == nil {
+			return
+		}
+
+		if err := svc.DeleteThumbnail(video); err != nil {
+			resp.SetErrMsg("Failed to delete the thumbnail of video %d: %v", video.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//PlaceholderThumbnailHandler serves the image at PlaceholderThumbnailPath
+func PlaceholderThumbnailHandler(r *render.Render) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+		r.Data(w, http.StatusOK, []byte(placeholderThumbnail))
+	}
+}
+
+type CaptionsResponse struct {
+	*cohesioned.APIResponse
+	*cohesioned.CaptionTrack
+}
+
+//CaptionsUploadHandler sets the request body, a WebVTT or SRT file, as the video's caption track in the language in the path. SRT files are converted to WebVTT. The optional label query param names the track in players
+func CaptionsUploadHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		defer req.Body.Close()
+
+		resp := &CaptionsResponse{APIResponse: &cohesioned.APIResponse{}}
+		video := findVideo(r, w, req, svc, resp.APIResponse)
+		if video == nil {
+			return
+		}
+
+		language := mux.Vars(req)["language"]
+		track, err := svc.SetCaptions(video, language, req.URL.Query().Get("label"), req.Body)
+		if err == ErrInvalidFile {
+			writeInvalidFile(r, w, resp.APIResponse, video)
+			return
+		}
+
+		if err != nil {
+			resp.SetErrMsg("Failed to save the %s captions of video %d: %v", language, video.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		resp.CaptionTrack = track
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//DeleteCaptionsHandler deletes the video's caption track in the language in the path
+func DeleteCaptionsHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &cohesioned.APIResponse{}
+		video := findVideo(r, w, req, svc, resp)
+		if video == nil {
+			return
+		}
+
+		language := mux.Vars(req)["language"]
+		err := svc.DeleteCaptions(video, language)
+		if err == ErrNoCaptions {
+			resp.SetErrMsg("Video %d has no %s captions", video.ID, language)
+			r.JSON(w, http.StatusNotFound, resp)
+			return
+		}
+
+		if err != nil {
+			resp.SetErrMsg("Failed to delete the %s captions of video %d: %v", language, video.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+type ChapterResponse struct {
+	*cohesioned.APIResponse
+	*cohesioned.Chapter
+	List []*cohesioned.Chapter `json:"list,omitempty"`
+}
+
+//ChaptersHandler lists the video's chapters in the order they start
+func ChaptersHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &ChapterResponse{APIResponse: &cohesioned.APIResponse{}}
+		video := findVideo(r, w, req, svc, resp.APIResponse)
+		if video == nil {
+			return
+		}
+
+		chapters, err := svc.ListChapters(video.ID)
+		if err != nil {
+			resp.SetErrMsg("Failed to list the chapters of video %d: %v", video.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		resp.List = chapters
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//AddChapterHandler adds the chapter in the request body to the video. It must end within the video and can't overlap the video's other chapters
+func AddChapterHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		defer req.Body.Close()
+
+		resp := &ChapterResponse{APIResponse: &cohesioned.APIResponse{}}
+		video := findVideo(r, w, req, svc, resp.APIResponse)
+		if video == nil {
+			return
+		}
+
+		chapter := decodeChapter(r, w, req, resp.APIResponse)
+		if chapter == nil {
+			return
+		}
+
+		if err := svc.SaveChapter(video, chapter); err != nil {
+			writeChapterErr(r, w, resp.APIResponse, video, chapter, err)
+			return
+		}
+
+		resp.Chapter = chapter
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//UpdateChapterHandler replaces the title, start and end of the chapter in the path with those in the request body
+func UpdateChapterHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		defer req.Body.Close()
+
+		resp := &ChapterResponse{APIResponse: &cohesioned.APIResponse{}}
+		video := findVideo(r, w, req, svc, resp.APIResponse)
+		if video == nil {
+			return
+		}
+
+		chapter := decodeChapter(r, w, req, resp.APIResponse)
+		if chapter == nil {
+			return
+		}
+
+		chapter.ID, _ = strconv.ParseInt(mux.Vars(req)["chapter_id"], 10, 64)
+		if err := svc.UpdateChapter(video, chapter); err != nil {
+			writeChapterErr(r, w, resp.APIResponse, video, chapter, err)
+			return
+		}
+
+		resp.Chapter = chapter
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//DeleteChapterHandler deletes the chapter in the path from the video
+func DeleteChapterHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &cohesioned.APIResponse{}
+		video := findVideo(r, w, req, svc, resp)
+		if video == nil {
+			return
+		}
+
+		chapter := &cohesioned.Chapter{}
+		chapter.ID, _ = strconv.ParseInt(mux.Vars(req)["chapter_id"], 10, 64)
+		if err := svc.DeleteChapter(video, chapter.ID); err != nil {
+			writeChapterErr(r, w, resp, video, chapter, err)
+			return
+		}
+
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//decodeChapter reads the chapter in the request body, writing an error response and returning nil if it can't
+func decodeChapter(r *render.Render, w http.ResponseWriter, req *http.Request, resp *cohesioned.APIResponse) *cohesioned.Chapter {
+	chapter := &cohesioned.Chapter{}
+	if err := json.NewDecoder(req.Body).Decode(chapter); err != nil {
+		resp.SetErrMsg("Unable to process the chapter payload. Error: %v", err)
+		r.JSON(w, http.StatusBadRequest, resp)
+		return nil
+	}
+
+	return chapter
+}
+
+//writeChapterErr responds to an error saving or deleting one of the video's chapters
+func writeChapterErr(r *render.Render, w http.ResponseWriter, resp *cohesioned.APIResponse, video *cohesioned.Video, chapter *cohesioned.Chapter, err error) {
+	switch err {
+	case ErrInvalidChapter:
+		resp.ValidationErrors = append(resp.ValidationErrors, chapter.ValidationErrors...)
+		resp.SetErrMsg("Invalid chapter")
+		r.JSON(w, http.StatusBadRequest, resp)
+	case ErrNoChapter:
+		resp.SetErrMsg("Video %d has no chapter %d", video.ID, chapter.ID)
+		r.JSON(w, http.StatusNotFound, resp)
+	default:
+		resp.SetErrMsg("Failed to save chapter %d of video %d: %v", chapter.ID, video.ID, err)
+		fmt.Println(resp.ErrMsg)
+		r.JSON(w, http.StatusInternalServerError, resp)
+	}
+}
+
+type FAQResponse struct {
+	*cohesioned.APIResponse
+	*cohesioned.FAQ
+	List []*cohesioned.FAQ `json:"list,omitempty"`
+}
+
+//FAQsHandler lists the video's published FAQs
+func FAQsHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &FAQResponse{APIResponse: &cohesioned.APIResponse{}}
+		video := findVideo(r, w, req, svc, resp.APIResponse)
+		if video == nil {
+			return
+		}
+
+		faqs, err := svc.ListFAQs(video.ID)
+		if err != nil {
+			resp.SetErrMsg("Failed to list the FAQs of video %d: %v", video.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		resp.List = faqs
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//AskQuestionHandler saves the question in the request body, asked by the current user about the video. It is pending until an admin answers it
+func AskQuestionHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		defer req.Body.Close()
+
+		resp := &FAQResponse{APIResponse: &cohesioned.APIResponse{}}
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		video := findVideo(r, w, req, svc, resp.APIResponse)
+		if video == nil {
+			return
+		}
+
+		faq := decodeFAQ(r, w, req, resp.APIResponse)
+		if faq == nil {
+			return
+		}
+
+		if err := svc.AskQuestion(video, faq, currentUser.ID); err != nil {
+			writeFAQErr(r, w, resp.APIResponse, video, faq, err)
+			return
+		}
+
+		resp.FAQ = faq
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//AddFAQHandler adds the FAQ in the request body to the video. It is published unless the body has another status
+func AddFAQHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		defer req.Body.Close()
+
+		resp := &FAQResponse{APIResponse: &cohesioned.APIResponse{}}
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		video := findVideo(r, w, req, svc, resp.APIResponse)
+		if video == nil {
+			return
+		}
+
+		faq := decodeFAQ(r, w, req, resp.APIResponse)
+		if faq == nil {
+			return
+		}
+
+		if err := svc.SaveFAQ(video, faq, currentUser.ID); err != nil {
+			writeFAQErr(r, w, resp.APIResponse, video, faq, err)
+			return
+		}
+
+		resp.FAQ = faq
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//UpdateFAQHandler answers, publishes, rejects or edits the FAQ in the path. The parent who asked is notified when their question is first answered
+func UpdateFAQHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		defer req.Body.Close()
+
+		resp := &FAQResponse{APIResponse: &cohesioned.APIResponse{}}
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		video := findVideo(r, w, req, svc, resp.APIResponse)
+		if video == nil {
+			return
+		}
+
+		faq := decodeFAQ(r, w, req, resp.APIResponse)
+		if faq == nil {
+			return
+		}
+
+		faq.ID, _ = strconv.ParseInt(mux.Vars(req)["faq_id"], 10, 64)
+		if err := svc.UpdateFAQ(video, faq, currentUser.ID); err != nil {
+			writeFAQErr(r, w, resp.APIResponse, video, faq, err)
+			return
+		}
+
+		resp.FAQ = faq
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//DeleteFAQHandler deletes the FAQ in the path from the video
+func DeleteFAQHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &cohesioned.APIResponse{}
+		video := findVideo(r, w, req, svc, resp)
+		if video == nil {
+			return
+		}
+
+		faq := &cohesioned.FAQ{}
+		faq.ID, _ = strconv.ParseInt(mux.Vars(req)["faq_id"], 10, 64)
+		if err := svc.DeleteFAQ(video, faq.ID); err != nil {
+			writeFAQErr(r, w, resp, video, faq, err)
+			return
+		}
+
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//QuestionsHandler pages through the FAQs of every video for moderation, e.g. ?status=pending for the questions waiting for an answer
+func QuestionsHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &cohesioned.APIResponse{}
+		opts := cohesioned.ListOptionsFromRequest(req, QuestionListSpec, resp)
+		if len(resp.ValidationErrors) > 0 {
+			resp.SetErrMsg("Invalid list parameters")
+			r.JSON(w, http.StatusBadRequest, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		writeQuestions(r, w, req, svc, opts, resp)
+	}
+}
+
+//MyQuestionsHandler pages through the questions the current user asked, with their answers once they are answered
+func MyQuestionsHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &cohesioned.APIResponse{}
+		opts := cohesioned.ListOptionsFromRequest(req, QuestionListSpec, resp)
+		if len(resp.ValidationErrors) > 0 {
+			resp.SetErrMsg("Invalid list parameters")
+			r.JSON(w, http.StatusBadRequest, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
+			return
+		}
+
+		opts.Filters = append(opts.Filters, cohesioned.Filter{Field: "asked_by", Op: "=", Value: currentUser.ID})
+		writeQuestions(r, w, req, svc, opts, resp)
+	}
+}
+
+func writeQuestions(r *render.Render, w http.ResponseWriter, req *http.Request, svc AdminService, opts *cohesioned.ListOptions, resp *cohesioned.APIResponse) {
+	faqs, total, err := svc.ListQuestions(opts)
+	if err != nil {
+		resp.SetErrMsg("Failed to list questions: %v", err)
+		fmt.Println(resp.ErrMsg)
+		r.JSON(w, http.StatusInternalServerError, cohesioned.NewErrorPage(opts, resp))
+		return
+	}
+
+	if faqs == nil {
+		faqs = []*cohesioned.FAQ{}
+	}
+
+	r.JSON(w, http.StatusOK, cohesioned.NewPage(req, opts, faqs, total))
+}
+
+//decodeFAQ reads the FAQ in the request body, writing an error response and returning nil if it can't
+func decodeFAQ(r *render.Render, w http.ResponseWriter, req *http.Request, resp *cohesioned.APIResponse) *cohesioned.FAQ {
+	faq := &cohesioned.FAQ{}
+	if err := json.NewDecoder(req.Body).Decode(faq); err != nil {
+		resp.SetErrMsg("Unable to process the FAQ payload. Error: %v", err)
+		r.JSON(w, http.StatusBadRequest, resp)
+		return nil
+	}
+
+	return faq
+}
+
+//writeFAQErr responds to an error saving or deleting one of the video's FAQs
+func writeFAQErr(r *render.Render, w http.ResponseWriter, resp *cohesioned.APIResponse, video *cohesioned.Video, faq *cohesioned.FAQ, err error) {
+	switch err {
+	case ErrInvalidFAQ:
+		resp.ValidationErrors = append(resp.ValidationErrors, faq.ValidationErrors...)
+		resp.SetErrMsg("Invalid FAQ")
+		r.JSON(w, http.StatusBadRequest, resp)
+	case ErrNoFAQ:
+		resp.SetErrMsg("Video %d has no FAQ %d", video.ID, faq.ID)
+		r.JSON(w, http.StatusNotFound, resp)
+	default:
+		resp.SetErrMsg("Failed to save FAQ %d of video %d: %v", faq.ID, video.ID, err)
+		fmt.Println(resp.ErrMsg)
+		r.JSON(w, http.StatusInternalServerError, resp)
+	}
+}
+
+type WatchProgressResponse struct {
+	*cohesioned.APIResponse
+	*cohesioned.WatchProgress
+}
+
+//WatchEventHandler records a play, pause, seek, heartbeat or complete event sent by the current user's player, and responds with where they are up to in the video.
+//Set student_id in the body when the user is watching for one of their students
+func WatchEventHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		defer req.Body.Close()
+
+		resp := &WatchProgressResponse{APIResponse: &cohesioned.APIResponse{}}
+		currentUser, err := cohesioned.GetCurrentUser(req)
+		if err != nil {
+			resp.SetErrMsg("An unexpected error occurred when trying to get the current user %v", err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		video := findVideo(r, w, req, svc, resp.APIResponse)
+		if video == nil {
+			return
+		}
+
+		event := &cohesioned.WatchEvent{}
+		if err := json.NewDecoder(req.Body).Decode(event); err != nil {
+			resp.SetErrMsg("Unable to process the watch event payload. Error: %v", err)
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		event.UserID = currentUser.ID
+		progress, err := svc.RecordWatchEvent(video, event)
+		if err == ErrInvalidWatchEvent {
+			resp.ValidationErrors = append(resp.ValidationErrors, event.ValidationErrors...)
+			resp.SetErrMsg("Invalid watch event")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		if err != nil {
+			resp.SetErrMsg("Failed to record the %s event of video %d: %v", event.Event, video.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		resp.WatchProgress = progress
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+type RelatedResponse struct {
+	*cohesioned.APIResponse
+	List []*cohesioned.RelatedVideo `json:"list"`
+}
+
+//RelatedRequest lists the videos to link to a video, in the order they should be shown
+type RelatedRequest struct {
+	RelatedIDs []int64 `json:"related_ids"`
+}
+
+//RelatedHandler lists videos to watch after the video in the path - the ones linked by an admin, then suggestions. The optional limit param defaults to DefaultRelatedLimit
+func RelatedHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := &RelatedResponse{APIResponse: &cohesioned.APIResponse{}}
+		limit := DefaultRelatedLimit
+		if param := req.URL.Query().Get("limit"); len(param) > 0 {
+			var err error
+			if limit, err = strconv.Atoi(param); err != nil || limit < 1 || limit > MaxRelatedLimit {
+				resp.AddValidationError("limit", fmt.Sprintf("limit must be a number from 1 to %d", MaxRelatedLimit))
+				resp.SetErrMsg("Invalid limit %s", param)
+				r.JSON(w, http.StatusBadRequest, resp)
+				return
+			}
+		}
+
+		video := findVideo(r, w, req, svc, resp.APIResponse)
+		if video == nil {
+			return
+		}
+
+		related, err := svc.RelatedVideos(video, limit)
+		if err != nil {
+			resp.SetErrMsg("Failed to list the videos related to video %d: %v", video.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		resp.List = related
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
+
+//SetRelatedHandler links the videos in the request body to the video in the path, replacing any linked before, and responds with the videos now related to it
+func SetRelatedHandler(r *render.Render, svc AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		defer req.Body.Close()
+
+		resp := &RelatedResponse{APIResponse: &cohesioned.APIResponse{}}
+		video := findVideo(r, w, req, svc, resp.APIResponse)
+		if video == nil {
+			return
+		}
+
+		body := &RelatedRequest{}
+		if err := json.NewDecoder(req.Body).Decode(body); err != nil {
+			resp.SetErrMsg("Unable to process the related videos payload. Error: %v", err)
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		err := svc.SetRelated(video, body.RelatedIDs)
+		if err == ErrInvalidRelated {
+			resp.ValidationErrors = append(resp.ValidationErrors, video.ValidationErrors...)
+			resp.SetErrMsg("Invalid related videos")
+			r.JSON(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		if err != nil {
+			resp.SetErrMsg("Failed to save the videos related to video %d: %v", video.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		limit := DefaultRelatedLimit
+		if len(body.RelatedIDs) > limit {
+			limit = len(body.RelatedIDs)
+		}
+
+		if resp.List, err = svc.RelatedVideos(video, limit); err != nil {
+			resp.SetErrMsg("Failed to list the videos related to video %d: %v", video.ID, err)
+			fmt.Println(resp.ErrMsg)
+			r.JSON(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		r.JSON(w, http.StatusOK, resp)
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/cohesion-education/api/fakes"
 	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/storage"
 	"github.com/cohesion-education/api/pkg/cohesioned/video"
 	"github.com/gorilla/mux"
 )
@@ -35,8 +36,244 @@ func TestListHandler(t *testing.T) {
 		t.Errorf("handler returned wrong status code: got %v want %v", status, expectedStatus)
 	}
 
-	fakeResp := &video.VideoResponse{
-		List: videos,
+	fakeResp := &cohesioned.Page{
+		APIResponse: &cohesioned.APIResponse{},
+		Items:       videos,
+		Total:       1,
+		Limit:       cohesioned.DefaultPageLimit,
+	}
+
+	expectedBody := fakes.RenderJSON(fakeResp)
+	if bytes.Compare(expectedBody, rr.Body.Bytes()) != 0 {
+		t.Errorf("The expected json was not generated.\n\nExpected: %s\n\nActual: %s", string(expectedBody), rr.Body.String())
+	}
+}
+
+func TestListHandlerLinksToNextPage(t *testing.T) {
+	videos := []*cohesioned.Video{fakes.FakeVideo(), fakes.FakeVideo(), fakes.FakeVideo()}
+
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.ListReturns(videos, nil)
+
+	handler := video.ListHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("GET", "/api/videos?limit=2&sort=title&taxonomy_id=1", nil, fakes.FakeProfile())
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	page := &cohesioned.Page{}
+	if err := json.NewDecoder(rr.Body).Decode(page); err != nil {
+		t.Fatalf("failed to unmarshall json response %v", err)
+	}
+
+	if page.Total != 3 {
+		t.Errorf("expected total of 3 but got %d", page.Total)
+	}
+
+	if expected := "/api/videos?limit=2&offset=2&sort=title&taxonomy_id=1"; page.Next != expected {
+		t.Errorf("expected next link %s but got %s", expected, page.Next)
+	}
+
+	if len(page.Prev) != 0 {
+		t.Errorf("expected no previous link on the first page but got %s", page.Prev)
+	}
+}
+
+func TestListHandlerWithInvalidSort(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	handler := video.ListHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("GET", "/api/videos?sort=file_size", nil, fakes.FakeProfile())
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestSearchHandler(t *testing.T) {
+	testVideo := fakes.FakeVideo()
+	results := []*cohesioned.SearchResult{
+		{Video: testVideo, Score: 1.5, Highlights: map[string]string{"title": "<em>Fractions</em>"}},
+	}
+
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.SearchReturns(results, nil)
+
+	handler := video.SearchHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("GET", "/api/videos/search?q=fractions&taxonomy_id=4", nil, fakes.FakeProfile())
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		fmt.Printf("response %s\n", rr.Body.String())
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	if fakeAdminService.SearchedWith.Text != "fractions" || fakeAdminService.SearchedWith.TaxonomyID != 4 {
+		t.Errorf("the search query was not passed to the service: %v", fakeAdminService.SearchedWith)
+	}
+
+	fakeResp := &cohesioned.Page{
+		APIResponse: &cohesioned.APIResponse{},
+		Items:       results,
+		Total:       1,
+		Limit:       cohesioned.DefaultPageLimit,
+	}
+
+	expectedBody := fakes.RenderJSON(fakeResp)
+	if bytes.Compare(expectedBody, rr.Body.Bytes()) != 0 {
+		t.Errorf("The expected json was not generated.\n\nExpected: %s\n\nActual: %s", string(expectedBody), rr.Body.String())
+	}
+}
+
+func TestSearchHandlerWithoutQuery(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	handler := video.SearchHandler(fakes.FakeRenderer, fakeAdminService)
+
+	for _, uri := range []string{"/api/videos/search", "/api/videos/search?q=a", "/api/videos/search?q=ab", "/api/videos/search?q=math&subject=Math"} {
+		rr := httptest.NewRecorder()
+		req := fakes.NewRequestWithContext("GET", uri, nil, fakes.FakeProfile())
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusBadRequest {
+			t.Errorf("handler returned wrong status code for %s: got %v want %v", uri, status, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestSearchHandlerWithUnknownGrade(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.SearchReturns(nil, video.ErrUnknownGrade)
+	handler := video.SearchHandler(fakes.FakeRenderer, fakeAdminService)
+
+	rr := httptest.NewRecorder()
+	req := fakes.NewRequestWithContext("GET", "/api/videos/search?q=fractions&grade=13th", nil, fakes.FakeProfile())
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	resp := &cohesioned.APIResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("Failed to unmarshall response json to APIResponse: %v", err)
+	}
+
+	if len(resp.ValidationErrors) != 1 || resp.ValidationErrors[0].Field != "grade" {
+		t.Errorf("expected a validation error for the grade but got %v", resp.ValidationErrors)
+	}
+}
+
+func TestFindByStandardHandler(t *testing.T) {
+	videos := []*cohesioned.Video{fakes.FakeVideo()}
+
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.ListReturns(videos, nil)
+
+	handler := video.FindByStandardHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("GET", "/api/videos/by_standard/MAFS.1.OA.1.1", nil, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"standard": "MAFS.1.OA.1.1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	if fakeAdminService.FoundByTag != "MAFS.1.OA.1.1" {
+		t.Errorf("expected videos to be found by MAFS.1.OA.1.1 but were found by %s", fakeAdminService.FoundByTag)
+	}
+
+	fakeResp := &cohesioned.Page{
+		APIResponse: &cohesioned.APIResponse{},
+		Items:       videos,
+		Total:       1,
+		Limit:       cohesioned.DefaultPageLimit,
+	}
+
+	expectedBody := fakes.RenderJSON(fakeResp)
+	if bytes.Compare(expectedBody, rr.Body.Bytes()) != 0 {
+		t.Errorf("The expected json was not generated.\n\nExpected: %s\n\nActual: %s", string(expectedBody), rr.Body.String())
+	}
+}
+
+func TestFindByTeacherHandler(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.ListReturns([]*cohesioned.Video{fakes.FakeVideo()}, nil)
+
+	handler := video.FindByTeacherHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("GET", "/api/videos/by_teacher/3", nil, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"teacher_id": "3"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	if fakeAdminService.FoundByTeacher != 3 {
+		t.Errorf("expected videos to be found by teacher 3 but were found by %d", fakeAdminService.FoundByTeacher)
+	}
+}
+
+func TestForMyStateHandler(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.ListReturns([]*cohesioned.Video{fakes.FakeVideo()}, nil)
+	handler := video.ForMyStateHandler(fakes.FakeRenderer, fakeAdminService)
+
+	parent := fakes.FakeProfile()
+	parent.State = ""
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, fakes.NewRequestWithContext("GET", "/api/videos/for_my_state", nil, parent))
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code without a state: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	parent.State = "FL"
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, fakes.NewRequestWithContext("GET", "/api/videos/for_my_state", nil, parent))
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	if fakeAdminService.FoundByTag != "FL" {
+		t.Errorf("expected videos to be found for FL but were found for %s", fakeAdminService.FoundByTag)
+	}
+}
+
+func TestKeyTermsHandler(t *testing.T) {
+	tags := []*cohesioned.Tag{
+		{ID: 1, Kind: cohesioned.TagKeyTerm, Name: "numerator", VideoCount: 3},
+		{ID: 2, Kind: cohesioned.TagKeyTerm, Name: "denominator", VideoCount: 0},
+	}
+
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.ListTagsReturns(tags, nil)
+
+	handler := video.KeyTermsHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("GET", "/api/videos/key_terms", nil, fakes.FakeProfile())
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	fakeResp := &video.TagResponse{
+		APIResponse: &cohesioned.APIResponse{},
+		List:        tags,
 	}
 
 	expectedBody := fakes.RenderJSON(fakeResp)
@@ -124,6 +361,39 @@ func TestAddHandler(t *testing.T) {
 	}
 }
 
+func TestAddHandlerWhenATeacherIsDeletedWhileSaving(t *testing.T) {
+	profile := fakes.FakeProfile()
+	testVideo := fakes.FakeVideo()
+
+	testJSON, err := json.Marshal(testVideo)
+	if err != nil {
+		t.Fatalf("Failed to marshall video json: %v", err)
+	}
+
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.SaveReturns(video.ErrUnknownTeacher)
+
+	handler := video.AddHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("POST", "/api/video", bytes.NewReader(testJSON), profile)
+	handler.ServeHTTP(rr, req)
+
+	expectedStatus := http.StatusBadRequest
+	if status := rr.Code; status != expectedStatus {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, expectedStatus)
+	}
+
+	resp := &video.VideoResponse{}
+	if err := json.Unmarshal(rr.Body.Bytes(), resp); err != nil {
+		t.Fatalf("Failed to unmarshal response %s: %v", rr.Body.String(), err)
+	}
+
+	if resp.APIResponse == nil || resp.ErrMsg == "" {
+		t.Errorf("Expected an error message but got %s", rr.Body.String())
+	}
+}
+
 func TestUploadHandler(t *testing.T) {
 	fakeUser := fakes.FakeProfile()
 	testVideo := fakes.FakeVideo()
@@ -168,6 +438,35 @@ func TestUploadHandler(t *testing.T) {
 	}
 }
 
+func TestUploadHandlerWithInvalidFile(t *testing.T) {
+	testVideo := fakes.FakeVideo()
+	testVideo.AddValidationError("file_type", "text/plain files can't be uploaded")
+
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(testVideo, nil)
+	fakeAdminService.SetFileReturns(video.ErrInvalidFile)
+
+	handler := video.UploadHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewFileUploadRequestWithContext("POST", "/api/video/upload/1", "../../../testdata/file-upload.txt", fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	resp := &cohesioned.APIResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("Failed to unmarshall response json to APIResponse: %v", err)
+	}
+
+	if len(resp.ValidationErrors) != 1 || resp.ValidationErrors[0].Field != "file_type" {
+		t.Errorf("expected a validation error for the file type but got %v", resp.ValidationErrors)
+	}
+}
+
 func TestUpdateHandler(t *testing.T) {
 	fakeUser := fakes.FakeProfile()
 	existingVideo := fakes.FakeVideo()
@@ -218,3 +517,896 @@ func TestUpdateHandler(t *testing.T) {
 		t.Errorf("Video UpdatedBy was not set correctly; expected: %d - actual: %d", fakeUser.ID, fakeResp.Video.UpdatedByID)
 	}
 }
+
+func TestUpdateHandlerReplacesKeyTermTimestamps(t *testing.T) {
+	existingVideo := fakes.FakeVideo()
+	existingVideo.KeyTerms = []string{"numerator", "denominator"}
+	existingVideo.KeyTermTimestamps = map[string]float64{"numerator": 12, "denominator": 30}
+
+	body := `{"title": "Fractions", "taxonomy_id": 1, "key_terms": ["numerator"], "key_term_timestamps": {"numerator": 15}}`
+
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(existingVideo, nil)
+
+	handler := video.UpdateHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("PUT", fmt.Sprintf("/api/video/%d", existingVideo.ID), bytes.NewReader([]byte(body)), fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": fmt.Sprintf("%d", existingVideo.ID)})
+	handler(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v - %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	if len(existingVideo.KeyTermTimestamps) != 1 || existingVideo.KeyTermTimestamps["numerator"] != 15 {
+		t.Errorf("expected the key term timestamps to be replaced but got %v", existingVideo.KeyTermTimestamps)
+	}
+}
+
+func TestTranscodingStatusHandler(t *testing.T) {
+	jobs := []*cohesioned.TranscodingJob{
+		{ID: 2, VideoID: 1, Preset: "480p-16x9", Status: cohesioned.TranscodingRunning, OutputKey: "transcoded/480p-16x9-1-video.mp4"},
+	}
+
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.TranscodingJobsReturns(jobs, nil)
+
+	handler := video.TranscodingStatusHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("GET", "/api/video/1/transcoding", nil, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	fakeResp := &video.TranscodingResponse{
+		APIResponse: &cohesioned.APIResponse{},
+		List:        jobs,
+	}
+
+	expectedBody := fakes.RenderJSON(fakeResp)
+	if bytes.Compare(expectedBody, rr.Body.Bytes()) != 0 {
+		t.Errorf("The expected json was not generated.\n\nExpected: %s\n\nActual: %s", string(expectedBody), rr.Body.String())
+	}
+}
+
+func TestMasterPlaylistHandler(t *testing.T) {
+	testVideo := fakes.FakeVideo()
+	renditions := []*cohesioned.Rendition{
+		{ID: 3, Name: "hls-1m", Width: 854, Height: 480, Bitrate: 1000000, ObjectKey: "transcoded/hls-1m-1-video.mp4.m3u8"},
+	}
+
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.HLSRenditionsReturns(renditions, nil)
+
+	handler := video.MasterPlaylistHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("GET", fmt.Sprintf("/api/video/%d/master.m3u8", testVideo.ID), nil, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": fmt.Sprintf("%d", testVideo.ID)})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	if contentType := rr.Header().Get("Content-Type"); contentType != video.HLSContentType {
+		t.Errorf("expected content type %s but got %s", video.HLSContentType, contentType)
+	}
+
+	if expected := video.MasterPlaylist(renditions); bytes.Compare(expected, rr.Body.Bytes()) != 0 {
+		t.Errorf("The expected playlist was not generated.\n\nExpected: %s\n\nActual: %s", string(expected), rr.Body.String())
+	}
+}
+
+func TestMasterPlaylistHandlerWithoutRenditions(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.HLSRenditionsReturns(nil, nil)
+
+	handler := video.MasterPlaylistHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("GET", "/api/video/1/master.m3u8", nil, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+}
+
+func TestVariantPlaylistHandler(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+	fakeAdminService.VariantPlaylistReturns([]byte("#EXTM3U\nhttps://example.com/segment00000.ts\n"), nil)
+
+	handler := video.VariantPlaylistHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("GET", "/api/video/1/hls/3.m3u8", nil, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1", "rendition_id": "3"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	if contentType := rr.Header().Get("Content-Type"); contentType != video.HLSContentType {
+		t.Errorf("expected content type %s but got %s", video.HLSContentType, contentType)
+	}
+
+	fakeAdminService.VariantPlaylistReturns(nil, video.ErrNoHLSRendition)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code for a missing rendition: got %v want %v", status, http.StatusNotFound)
+	}
+}
+
+func TestPresignedUploadHandler(t *testing.T) {
+	testVideo := fakes.FakeVideo()
+	target := &video.UploadTarget{ObjectName: "1-test.mp4", Method: "PUT", URL: "http://fake-upload-url"}
+
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(testVideo, nil)
+	fakeAdminService.UploadTargetReturns(target, nil)
+
+	handler := video.PresignedUploadHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("POST", "/api/video/upload/1/presigned", bytes.NewBufferString(`{"file_type":"video/mp4"}`), fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	expectedBody := fakes.RenderJSON(&video.UploadResponse{APIResponse: &cohesioned.APIResponse{}, UploadTarget: target})
+	if bytes.Compare(expectedBody, rr.Body.Bytes()) != 0 {
+		t.Errorf("The expected json was not generated.\n\nExpected: %s\n\nActual: %s", string(expectedBody), rr.Body.String())
+	}
+}
+
+func TestMultipartUploadHandlerRequiresFileSize(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+
+	handler := video.MultipartUploadHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("POST", "/api/video/upload/1/multipart", bytes.NewBufferString(`{"file_type":"video/mp4"}`), fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestCompleteUploadHandler(t *testing.T) {
+	testVideo := fakes.FakeVideo()
+
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(testVideo, nil)
+
+	handler := video.CompleteUploadHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	body := `{"object_name":"1-abc-test.mp4","upload_id":"abc","parts":[{"part_number":1,"etag":"\"etag-1\""}]}`
+	req := fakes.NewRequestWithContext("POST", "/api/video/upload/1/complete", bytes.NewBufferString(body), fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	completed := fakeAdminService.Completed
+	if completed == nil || completed.ObjectName != "1-abc-test.mp4" || completed.UploadID != "abc" || len(completed.Parts) != 1 || completed.Parts[0].ETag != `"etag-1"` {
+		t.Errorf("the upload was not completed with the given parts: %v", completed)
+	}
+}
+
+func TestCompleteUploadHandlerBeforeTheFileIsUploaded(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+	fakeAdminService.CompleteUploadReturns(video.ErrFileNotUploaded)
+
+	handler := video.CompleteUploadHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("POST", "/api/video/upload/1/complete", bytes.NewBufferString(`{"object_name":"1-abc-test.mp4"}`), fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	resp := &cohesioned.APIResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("Failed to unmarshall response json to APIResponse: %v", err)
+	}
+
+	if len(resp.ValidationErrors) != 1 || resp.ValidationErrors[0].Field != "file" {
+		t.Errorf("expected a validation error for the file but got %v", resp.ValidationErrors)
+	}
+}
+
+func TestCompleteUploadHandlerRequiresObjectName(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+
+	handler := video.CompleteUploadHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("POST", "/api/video/upload/1/complete", nil, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	if fakeAdminService.Completed != nil {
+		t.Errorf("the upload should not have been completed without an object name")
+	}
+}
+
+func TestAbortUploadHandlerWithAnotherVideosObject(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+	fakeAdminService.AbortUploadReturns(video.ErrInvalidObjectName)
+
+	handler := video.AbortUploadHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("DELETE", "/api/video/upload/1/multipart/abc?object_name=2-abc-test.mp4", nil, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1", "upload_id": "abc"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	resp := &cohesioned.APIResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("Failed to unmarshall response json to APIResponse: %v", err)
+	}
+
+	if len(resp.ValidationErrors) != 1 || resp.ValidationErrors[0].Field != "object_name" {
+		t.Errorf("expected a validation error for the object name but got %v", resp.ValidationErrors)
+	}
+}
+
+func TestResumableUploadHandlerRequiresContentRange(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+
+	handler := video.ResumableUploadHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("PUT", "/api/video/upload/1", bytes.NewBufferString("chunk"), fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestResumableUploadHandlerWithChunkOutOfOrder(t *testing.T) {
+	upload := &cohesioned.VideoUpload{VideoID: 1, Size: 12, Received: 6}
+
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+	fakeAdminService.UploadProgressReturns(upload, video.ErrUploadOffsetMismatch)
+
+	handler := video.ResumableUploadHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("PUT", "/api/video/upload/1", bytes.NewBufferString("rld!"), fakes.FakeProfile())
+	req.Header.Set("Content-Range", "bytes 8-11/12")
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusConflict {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusConflict)
+	}
+
+	if progress := rr.Header().Get("Range"); progress != "bytes=0-5" {
+		t.Errorf("expected the Range header to report the bytes received but got %s", progress)
+	}
+}
+
+func TestUploadProgressHandlerWithoutUpload(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.UploadProgressReturns(nil, nil)
+
+	handler := video.UploadProgressHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("GET", "/api/video/upload/1", nil, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+}
+
+func TestOrphansHandler(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.ReconcileStorageReturns(&video.StorageReport{
+		Bucket:  "videos",
+		Scanned: 3,
+		Orphans: []*storage.ObjectInfo{{Key: "1-old.mp4"}},
+	}, nil)
+
+	handler := video.OrphansHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("GET", "/api/admin/storage/orphans?min_age=48h", nil, fakes.FakeProfile())
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	if fakeAdminService.ReconciledMinAge != 48*time.Hour || fakeAdminService.Removed {
+		t.Errorf("expected a report of objects older than 48h but got min age %v, remove %v", fakeAdminService.ReconciledMinAge, fakeAdminService.Removed)
+	}
+
+	resp := &video.StorageReportResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("Failed to unmarshall response json to StorageReportResponse: %v", err)
+	}
+
+	if resp.StorageReport == nil || resp.Scanned != 3 || len(resp.Orphans) != 1 || resp.Orphans[0].Key != "1-old.mp4" {
+		t.Errorf("unexpected storage report %v", resp.StorageReport)
+	}
+}
+
+func TestDeleteOrphansHandlerWithInvalidMinAge(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+
+	handler := video.DeleteOrphansHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("DELETE", "/api/admin/storage/orphans?min_age=yesterday", nil, fakes.FakeProfile())
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	if fakeAdminService.Removed {
+		t.Errorf("orphans should not have been deleted")
+	}
+}
+
+func TestDeleteOrphansHandlerWithMinAgeBelowTheFloor(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+
+	handler := video.DeleteOrphansHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("DELETE", "/api/admin/storage/orphans?min_age=0", nil, fakes.FakeProfile())
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	if fakeAdminService.Removed {
+		t.Errorf("orphans should not have been deleted")
+	}
+}
+
+func TestThumbnailUploadHandler(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+	fakeAdminService.SetThumbnailReturns(&cohesioned.Thumbnail{ID: 1, VideoID: 1, Kind: cohesioned.ThumbnailCustom, SignedURL: "http://signed-thumbnail"}, nil)
+
+	handler := video.ThumbnailUploadHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("POST", "/api/video/1/thumbnail", bytes.NewBufferString("image"), fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	resp := &video.ThumbnailResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("Failed to unmarshall response json to ThumbnailResponse: %v", err)
+	}
+
+	if resp.Thumbnail == nil || resp.Kind != cohesioned.ThumbnailCustom || resp.SignedURL != "http://signed-thumbnail" {
+		t.Errorf("unexpected thumbnail %v", resp.Thumbnail)
+	}
+}
+
+func TestThumbnailUploadHandlerRejectsInvalidImages(t *testing.T) {
+	testVideo := fakes.FakeVideo()
+	testVideo.AddValidationError("thumbnail", "video/mp4 files can't be used as a thumbnail")
+
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(testVideo, nil)
+	fakeAdminService.SetThumbnailReturns(nil, video.ErrInvalidFile)
+
+	handler := video.ThumbnailUploadHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("POST", "/api/video/1/thumbnail", bytes.NewBufferString("not an image"), fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	resp := &cohesioned.APIResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("Failed to unmarshall response json to APIResponse: %v", err)
+	}
+
+	if len(resp.ValidationErrors) != 1 || resp.ValidationErrors[0].Field != "thumbnail" {
+		t.Errorf("expected a validation error for the thumbnail but got %v", resp.ValidationErrors)
+	}
+}
+
+func TestPlaceholderThumbnailHandler(t *testing.T) {
+	handler := video.PlaceholderThumbnailHandler(fakes.FakeRenderer)
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest("GET", video.PlaceholderThumbnailPath, nil)
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	if contentType := rr.Header().Get("Content-Type"); contentType != "image/svg+xml" {
+		t.Errorf("expected an svg image but got %s", contentType)
+	}
+}
+
+func TestCaptionsUploadHandler(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+	fakeAdminService.SetCaptionsReturns(&cohesioned.CaptionTrack{ID: 1, VideoID: 1, Language: "es", Label: "Español"}, nil)
+
+	handler := video.CaptionsUploadHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("PUT", "/api/video/1/captions/es?label=Espa%C3%B1ol", bytes.NewBufferString("WEBVTT"), fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1", "language": "es"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	if fakeAdminService.CaptionsLanguage != "es" || fakeAdminService.CaptionsLabel != "Español" {
+		t.Errorf("expected es captions labelled Español but got %s %s", fakeAdminService.CaptionsLanguage, fakeAdminService.CaptionsLabel)
+	}
+
+	resp := &video.CaptionsResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("Failed to unmarshall response json to CaptionsResponse: %v", err)
+	}
+
+	if resp.CaptionTrack == nil || resp.Language != "es" {
+		t.Errorf("unexpected caption track %v", resp.CaptionTrack)
+	}
+}
+
+func TestDeleteCaptionsHandlerWithoutTrack(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+	fakeAdminService.SetCaptionsReturns(nil, video.ErrNoCaptions)
+
+	handler := video.DeleteCaptionsHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("DELETE", "/api/video/1/captions/fr", nil, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1", "language": "fr"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+}
+
+func TestChaptersHandler(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+	fakeAdminService.ChaptersReturns([]*cohesioned.Chapter{{ID: 1, VideoID: 1, Title: "Introduction", Start: 0, End: 30}}, nil)
+
+	handler := video.ChaptersHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("GET", "/api/video/1/chapters", nil, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	resp := &video.ChapterResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("Failed to unmarshall response json to ChapterResponse: %v", err)
+	}
+
+	if len(resp.List) != 1 || resp.List[0].Title != "Introduction" {
+		t.Errorf("unexpected chapters %v", resp.List)
+	}
+}
+
+func TestChaptersHandlerWithUnknownVideo(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(nil, nil)
+
+	handler := video.ChaptersHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("GET", "/api/video/9/chapters", nil, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "9"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+}
+
+func TestAddChapterHandler(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+
+	handler := video.AddChapterHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	body := bytes.NewBufferString(`{"title": "Introduction", "start": 0, "end": 30.5}`)
+	req := fakes.NewRequestWithContext("POST", "/api/video/1/chapters", body, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	resp := &video.ChapterResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("Failed to unmarshall response json to ChapterResponse: %v", err)
+	}
+
+	if resp.Chapter == nil || resp.ID != 1 || resp.Title != "Introduction" || resp.End != 30.5 {
+		t.Errorf("unexpected chapter %v", resp.Chapter)
+	}
+}
+
+func TestAddChapterHandlerWithInvalidChapter(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+	fakeAdminService.ChaptersReturns(nil, video.ErrInvalidChapter)
+
+	handler := video.AddChapterHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("POST", "/api/video/1/chapters", bytes.NewBufferString(`{"start": 30, "end": 10}`), fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestUpdateChapterHandler(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+
+	handler := video.UpdateChapterHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	body := bytes.NewBufferString(`{"id": 7, "title": "Welcome", "start": 0, "end": 25}`)
+	req := fakes.NewRequestWithContext("PUT", "/api/video/1/chapters/3", body, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1", "chapter_id": "3"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	resp := &video.ChapterResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("Failed to unmarshall response json to ChapterResponse: %v", err)
+	}
+
+	if resp.Chapter == nil || resp.ID != 3 || resp.Title != "Welcome" {
+		t.Errorf("expected chapter 3 from the path to be updated but got %v", resp.Chapter)
+	}
+}
+
+func TestDeleteChapterHandlerWithoutChapter(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+	fakeAdminService.ChaptersReturns(nil, video.ErrNoChapter)
+
+	handler := video.DeleteChapterHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("DELETE", "/api/video/1/chapters/3", nil, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1", "chapter_id": "3"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+}
+
+func TestGetByIDHandlerIncludesTheResumePosition(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetWithSignedURLReturns(fakes.FakeVideo(), nil)
+	fakeAdminService.WatchProgressReturns(&cohesioned.WatchProgress{VideoID: 1, UserID: 1, StudentID: 2, Position: 42.5}, nil)
+
+	handler := video.GetByIDHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("GET", "/api/video/1?student_id=2", nil, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	resp := &video.VideoResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("Failed to unmarshall response json to VideoResponse: %v", err)
+	}
+
+	if resp.Video == nil || resp.Progress == nil || resp.Progress.Position != 42.5 {
+		t.Errorf("expected the video to resume at 42.5 seconds but got %v", resp.Video)
+	}
+}
+
+func TestGetByIDHandlerWithInvalidStudentID(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetWithSignedURLReturns(fakes.FakeVideo(), nil)
+
+	handler := video.GetByIDHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("GET", "/api/video/1?student_id=bobby", nil, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestWatchEventHandler(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+	fakeAdminService.WatchProgressReturns(&cohesioned.WatchProgress{VideoID: 1, UserID: 1, Position: 42.5}, nil)
+
+	handler := video.WatchEventHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	body := bytes.NewBufferString(`{"event": "pause", "position": 42.5, "user_id": 99}`)
+	req := fakes.NewRequestWithContext("POST", "/api/video/1/progress", body, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	watched := fakeAdminService.Watched
+	if watched == nil || watched.Event != cohesioned.PlaybackPause || watched.UserID != fakes.FakeProfile().ID {
+		t.Errorf("expected a pause event by the current user but got %v", watched)
+	}
+
+	resp := &video.WatchProgressResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("Failed to unmarshall response json to WatchProgressResponse: %v", err)
+	}
+
+	if resp.WatchProgress == nil || resp.Position != 42.5 {
+		t.Errorf("unexpected progress %v", resp.WatchProgress)
+	}
+}
+
+func TestWatchEventHandlerWithInvalidEvent(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+	fakeAdminService.WatchProgressReturns(nil, video.ErrInvalidWatchEvent)
+
+	handler := video.WatchEventHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("POST", "/api/video/1/progress", bytes.NewBufferString(`{"event": "rewind"}`), fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestRelatedHandler(t *testing.T) {
+	related := &cohesioned.RelatedVideo{Video: fakes.FakeVideo(), Curated: true}
+	related.ID = 2
+
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+	fakeAdminService.RelatedVideosReturns([]*cohesioned.RelatedVideo{related}, nil)
+
+	handler := video.RelatedHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("GET", "/api/video/1/related?limit=3", nil, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	resp := &video.RelatedResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("Failed to unmarshall response json to RelatedResponse: %v", err)
+	}
+
+	if len(resp.List) != 1 || resp.List[0].ID != 2 || !resp.List[0].Curated {
+		t.Errorf("unexpected related videos %v", resp.List)
+	}
+}
+
+func TestRelatedHandlerWithInvalidLimit(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+
+	handler := video.RelatedHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("GET", "/api/video/1/related?limit=100", nil, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestSetRelatedHandler(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+
+	handler := video.SetRelatedHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("PUT", "/api/video/1/related", bytes.NewBufferString(`{"related_ids": [3, 2]}`), fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	if ids := fakeAdminService.RelatedIDs; len(ids) != 2 || ids[0] != 3 || ids[1] != 2 {
+		t.Errorf("expected videos 3 and 2 to be related but got %v", ids)
+	}
+}
+
+func TestSetRelatedHandlerWithInvalidVideos(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+	fakeAdminService.RelatedVideosReturns(nil, video.ErrInvalidRelated)
+
+	handler := video.SetRelatedHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("PUT", "/api/video/1/related", bytes.NewBufferString(`{"related_ids": [1]}`), fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestAskQuestionHandler(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+
+	handler := video.AskQuestionHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	body := bytes.NewBufferString(`{"question": "Does this cover carrying?"}`)
+	req := fakes.NewRequestWithContext("POST", "/api/video/1/questions", body, fakes.FakeProfile())
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	if fakeAdminService.AskedBy != fakes.FakeProfile().ID {
+		t.Errorf("expected the question to be asked by the current user but got %d", fakeAdminService.AskedBy)
+	}
+
+	resp := &video.FAQResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+		t.Fatalf("Failed to unmarshall response json to FAQResponse: %v", err)
+	}
+
+	if resp.FAQ == nil || resp.Status != cohesioned.FAQPending || resp.Question != "Does this cover carrying?" {
+		t.Errorf("expected a pending question but got %v", resp.FAQ)
+	}
+}
+
+func TestUpdateFAQHandlerWithUnknownFAQ(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.GetReturns(fakes.FakeVideo(), nil)
+	fakeAdminService.FAQsReturns(nil, video.ErrNoFAQ)
+
+	handler := video.UpdateFAQHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	body := bytes.NewBufferString(`{"question": "Does this cover carrying?", "answer": "Yes", "status": "published"}`)
+	req := fakes.NewRequestWithContext("PUT", "/api/video/1/faqs/9", body, fakes.FakeAdmin())
+	req = mux.SetURLVars(req, map[string]string{"id": "1", "faq_id": "9"})
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+}
+
+func TestMyQuestionsHandlerOnlyListsTheCurrentUsersQuestions(t *testing.T) {
+	fakeAdminService := new(fakes.FakeVideoAdminService)
+	fakeAdminService.FAQsReturns([]*cohesioned.FAQ{{ID: 1, Question: "Does this cover carrying?", Status: cohesioned.FAQPending}}, nil)
+
+	handler := video.MyQuestionsHandler(fakes.FakeRenderer, fakeAdminService)
+	rr := httptest.NewRecorder()
+
+	req := fakes.NewRequestWithContext("GET", "/api/profile/questions?status=pending", nil, fakes.FakeProfile())
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	page := &cohesioned.Page{}
+	if err := json.NewDecoder(rr.Body).Decode(page); err != nil {
+		t.Fatalf("Failed to unmarshall response json to Page: %v", err)
+	}
+
+	if page.Total != 1 {
+		t.Errorf("expected 1 question but got %d", page.Total)
+	}
+
+	askedBy := false
+	for _, f := range fakeAdminService.QuestionOpts.Filters {
+		if f.Field == "asked_by" && f.Value == fakes.FakeProfile().ID {
+			askedBy = true
+		}
+	}
+
+	if !askedBy {
+		t.Errorf("expected the questions to be filtered by the current user but got %v", fakeAdminService.QuestionOpts.Filters)
+	}
+}
@@ -0,0 +1,135 @@
+package video
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+//HLSContentType is the content type of an HLS playlist
+const HLSContentType = "application/vnd.apple.mpegurl"
+
+//ErrNoHLSRendition is returned when a video has no HLS rendition with the requested id
+var ErrNoHLSRendition = errors.New("the video has no HLS rendition with that id")
+
+//VariantURI is where the master playlist of a video points at one of its HLS renditions, relative to the master playlist
+func VariantURI(r *cohesioned.Rendition) string {
+	return fmt.Sprintf("hls/%d.m3u8", r.ID)
+}
+
+//MasterPlaylist writes an HLS master playlist listing each HLS rendition as a variant stream, lowest bitrate first. Renditions that are single files are left out
+func MasterPlaylist(renditions []*cohesioned.Rendition) []byte {
+	var sorted []*cohesioned.Rendition
+	for _, r := range renditions {
+		if r.IsHLS() {
+			sorted = append(sorted, r)
+		}
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Bitrate < sorted[j].Bitrate
+	})
+
+	var buf bytes.Buffer
+	buf.WriteString("#EXTM3U\n")
+	for _, r := range sorted {
+		//BANDWIDTH is required, so renditions with an unknown bitrate still get one
+		bandwidth := r.Bitrate
+		if bandwidth < 1 {
+			bandwidth = 1
+		}
+
+		fmt.Fprintf(&buf, "#EXT-X-STREAM-INF:BANDWIDTH=%d", bandwidth)
+		if r.Width > 0 && r.Height > 0 {
+			fmt.Fprintf(&buf, ",RESOLUTION=%dx%d", r.Width, r.Height)
+		}
+
+		fmt.Fprintf(&buf, "\n%s\n", VariantURI(r))
+	}
+
+	return buf.Bytes()
+}
+
+//SignPlaylist rewrites each segment of an HLS media playlist with the url returned by sign. Segments are named relative to the playlist, so sign is given the segment's key in the bucket
+func SignPlaylist(playlist []byte, playlistKey string, sign func(key string) (string, error)) ([]byte, error) {
+	var buf bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(playlist))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) > 0 && !strings.HasPrefix(line, "#") && !strings.Contains(line, "://") {
+			url, err := sign(path.Join(path.Dir(playlistKey), line))
+			if err != nil {
+				return nil, err
+			}
+
+			line = url
+		}
+
+		fmt.Fprintf(&buf, "%s\n", line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+//HLSRenditions lists the video's HLS renditions
+func (s *adminService) HLSRenditions(videoID int64) ([]*cohesioned.Rendition, error) {
+	renditions, err := s.videoRepo.ListRenditions(videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	var hls []*cohesioned.Rendition
+	for _, r := range renditions {
+		if r.IsHLS() {
+			hls = append(hls, r)
+		}
+	}
+
+	return hls, nil
+}
+
+//VariantPlaylist reads the playlist of one of the video's HLS renditions, signing the url of each of its segments. It returns ErrNoHLSRendition if the video has no HLS rendition with that id
+func (s *adminService) VariantPlaylist(video *cohesioned.Video, renditionID int64) ([]byte, error) {
+	renditions, err := s.HLSRenditions(video.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rendition *cohesioned.Rendition
+	for _, r := range renditions {
+		if r.ID == renditionID {
+			rendition = r
+		}
+	}
+
+	if rendition == nil {
+		return nil, ErrNoHLSRendition
+	}
+
+	obj, err := s.store.Get(video.StorageBucket, rendition.ObjectKey)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read %s: %v", rendition.ObjectKey, err)
+	}
+
+	defer obj.Close()
+
+	playlist, err := ioutil.ReadAll(obj)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read %s: %v", rendition.ObjectKey, err)
+	}
+
+	return SignPlaylist(playlist, rendition.ObjectKey, func(key string) (string, error) {
+		return s.signedURL(video.StorageBucket, key)
+	})
+}
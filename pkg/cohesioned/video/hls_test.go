@@ -0,0 +1,49 @@
+package video
+
+import (
+	"testing"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+func TestMasterPlaylist(t *testing.T) {
+	renditions := []*cohesioned.Rendition{
+		{ID: 2, Name: "hls-2m", Width: 1280, Height: 720, Bitrate: 2500000, ObjectKey: "transcoded/hls-2m-1-video.mp4.m3u8"},
+		{ID: 1, Name: "hls-1m", Width: 854, Height: 480, Bitrate: 1000000, ObjectKey: "transcoded/hls-1m-1-video.mp4.m3u8"},
+		{ID: 4, Name: "hls-400k", ObjectKey: "transcoded/hls-400k-1-video.mp4.m3u8"},
+		{ID: 3, Name: "480p-16x9", Width: 854, Height: 480, Bitrate: 900000, ObjectKey: "transcoded/480p-16x9-1-video.mp4"},
+	}
+
+	expected := "#EXTM3U\n" +
+		"#EXT-X-STREAM-INF:BANDWIDTH=1\nhls/4.m3u8\n" +
+		"#EXT-X-STREAM-INF:BANDWIDTH=1000000,RESOLUTION=854x480\nhls/1.m3u8\n" +
+		"#EXT-X-STREAM-INF:BANDWIDTH=2500000,RESOLUTION=1280x720\nhls/2.m3u8\n"
+
+	if actual := string(MasterPlaylist(renditions)); actual != expected {
+		t.Errorf("unexpected playlist.\n\nExpected: %s\n\nActual: %s", expected, actual)
+	}
+
+	if renditions[0].Name != "hls-2m" {
+		t.Errorf("MasterPlaylist should not reorder the renditions it was given")
+	}
+}
+
+func TestSignPlaylist(t *testing.T) {
+	playlist := "#EXTM3U\n#EXT-X-TARGETDURATION:10\n#EXTINF:10.0,\nhls-1m-1-video.mp400000.ts\n#EXTINF:4.2,\nhls-1m-1-video.mp400001.ts\n#EXT-X-ENDLIST\n"
+
+	signed, err := SignPlaylist([]byte(playlist), "transcoded/hls-1m-1-video.mp4.m3u8", func(key string) (string, error) {
+		return "https://example.com/" + key + "?sig=1", nil
+	})
+
+	if err != nil {
+		t.Fatalf("Unexpected error signing playlist: %v", err)
+	}
+
+	expected := "#EXTM3U\n#EXT-X-TARGETDURATION:10\n#EXTINF:10.0,\n" +
+		"https://example.com/transcoded/hls-1m-1-video.mp400000.ts?sig=1\n#EXTINF:4.2,\n" +
+		"https://example.com/transcoded/hls-1m-1-video.mp400001.ts?sig=1\n#EXT-X-ENDLIST\n"
+
+	if string(signed) != expected {
+		t.Errorf("unexpected playlist.\n\nExpected: %s\n\nActual: %s", expected, string(signed))
+	}
+}
@@ -0,0 +1,53 @@
+package video
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+//TranscodingPoller refreshes the status of unfinished transcoding jobs in the background, so reading a video never waits on the transcoder
+type TranscodingPoller struct {
+	svc      AdminService
+	interval time.Duration
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+//NewTranscodingPoller starts refreshing the service's unfinished transcoding jobs every interval. An interval <= 0 disables polling
+func NewTranscodingPoller(svc AdminService, interval time.Duration) *TranscodingPoller {
+	p := &TranscodingPoller{
+		svc:      svc,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+
+	if interval > 0 {
+		go p.poll()
+	}
+
+	return p
+}
+
+//Close stops polling
+func (p *TranscodingPoller) Close() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+}
+
+func (p *TranscodingPoller) poll() {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.svc.RefreshTranscodingJobs(); err != nil {
+				fmt.Printf("Failed to refresh transcoding jobs: %v\n", err)
+			}
+		case <-p.stop:
+			return
+		}
+	}
+}
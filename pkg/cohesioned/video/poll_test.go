@@ -0,0 +1,63 @@
+package video_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/config"
+	"github.com/cohesion-education/api/pkg/cohesioned/video"
+)
+
+//refusingTranscoder fails the test if a job is refreshed
+type refusingTranscoder struct {
+	t *testing.T
+}
+
+func (tr *refusingTranscoder) Submit(v *cohesioned.Video, preset config.TranscodingPreset) (*cohesioned.TranscodingJob, error) {
+	return &cohesioned.TranscodingJob{VideoID: v.ID, Preset: preset.Name, Status: cohesioned.TranscodingPending}, nil
+}
+
+func (tr *refusingTranscoder) Refresh(job *cohesioned.TranscodingJob) (*cohesioned.Rendition, error) {
+	tr.t.Errorf("unexpected refresh of transcoding job %d", job.ID)
+	return nil, nil
+}
+
+func TestTranscodingPollerRefreshesInBackground(t *testing.T) {
+	svc := new(fakes.FakeVideoAdminService)
+
+	poller := video.NewTranscodingPoller(svc, 10*time.Millisecond)
+	defer poller.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&svc.Refreshes) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if atomic.LoadInt32(&svc.Refreshes) < 2 {
+		t.Errorf("expected the transcoding jobs to be refreshed in the background")
+	}
+}
+
+func TestGetWithSignedURLDoesNotRefreshTranscodingJobs(t *testing.T) {
+	v := fakes.FakeVideo()
+	v.StorageBucket = "videos"
+	v.StorageObjectName = "1-abc-test.mp4"
+
+	repo := new(fakes.FakeVideoRepo)
+	repo.GetReturns(v, nil)
+	repo.Jobs = []*cohesioned.TranscodingJob{{ID: 1, VideoID: v.ID, Preset: "480p", Status: cohesioned.TranscodingRunning}}
+
+	store := new(fakes.FakeBlobStore)
+	store.SignedURLReturns("http://signed", nil)
+
+	cfg := new(fakes.FakeAwsConfig)
+	cfg.GetVideoBucketReturns("videos")
+
+	svc := video.NewService(repo, new(fakes.FakeChapterRepo), new(fakes.FakeFAQRepo), new(fakes.FakeWatchRepo), new(fakes.FakeRelatedRepo), new(fakes.FakeTaxonomyRepo), new(fakes.FakeStandardRepo), new(fakes.FakeTeacherRepo), new(fakes.FakeNotificationRepo), store, &refusingTranscoder{t}, cfg)
+	if _, err := svc.GetWithSignedURL(v.ID); err != nil {
+		t.Fatalf("Unexpected error getting video: %v", err)
+	}
+}
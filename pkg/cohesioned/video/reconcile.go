@@ -0,0 +1,97 @@
+package video
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned/storage"
+)
+
+//DefaultOrphanMinAge is how old an object must be before ReconcileStorage treats it as an orphan. Younger objects may be uploads that haven't been completed yet
+const DefaultOrphanMinAge = 24 * time.Hour
+
+//MinOrphanDeleteAge is the smallest min_age orphans can be deleted with, so uploads through a url that is still valid aren't deleted
+const MinOrphanDeleteAge = time.Hour
+
+//StorageReport lists the objects in the video bucket that no video refers to
+type StorageReport struct {
+	Bucket string `json:"bucket"`
+	//Scanned is the number of objects in the bucket
+	Scanned int                   `json:"scanned"`
+	Orphans []*storage.ObjectInfo `json:"orphans"`
+	//Deleted is the number of orphans that were deleted
+	Deleted int `json:"deleted"`
+}
+
+//ReconcileStorage finds the objects in the video bucket that aren't a video's file, one of its renditions or thumbnails, the output of any configured preset for it, the output of an unfinished transcoding job, or a chunk of a resumable upload in progress.
+//Objects modified within minAge are skipped. Orphans are deleted if remove is true
+func (s *adminService) ReconcileStorage(minAge time.Duration, remove bool) (*StorageReport, error) {
+	keys, err := s.videoRepo.ListObjectKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	uploads, err := s.videoRepo.ListUploadVideoIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	referenced := make(map[string]bool)
+	var prefixes []string
+	tc := s.cfg.GetTranscodingConfig()
+	for _, key := range keys {
+		referenced[key] = true
+		prefixes = append(prefixes, tc.ThumbnailPrefix(key))
+
+		//videos transcoded before renditions were recorded only have their output in the bucket
+		for _, preset := range tc.AllPresets() {
+			prefixes = append(prefixes, tc.OutputBase(preset, key))
+		}
+	}
+
+	for _, id := range uploads {
+		prefixes = append(prefixes, chunkPrefix(id))
+	}
+
+	report := &StorageReport{Bucket: s.cfg.GetVideoBucket(), Orphans: []*storage.ObjectInfo{}}
+	objects, err := s.store.List(report.Bucket, "")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list the objects in %s: %v", report.Bucket, err)
+	}
+
+	cutoff := time.Now().Add(-minAge)
+	for _, obj := range objects {
+		report.Scanned++
+		if referenced[obj.Key] || hasAnyPrefix(obj.Key, prefixes) || obj.LastModified.After(cutoff) {
+			continue
+		}
+
+		report.Orphans = append(report.Orphans, obj)
+	}
+
+	if !remove {
+		return report, nil
+	}
+
+	for _, obj := range report.Orphans {
+		if err := s.store.Delete(report.Bucket, obj.Key); err != nil {
+			fmt.Printf("Failed to delete orphan %s from %s: %v\n", obj.Key, report.Bucket, err)
+			continue
+		}
+
+		report.Deleted++
+	}
+
+	return report, nil
+}
+
+func hasAnyPrefix(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
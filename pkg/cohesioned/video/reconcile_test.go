@@ -0,0 +1,63 @@
+package video_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/config"
+	"github.com/cohesion-education/api/pkg/cohesioned/storage"
+	"github.com/cohesion-education/api/pkg/cohesioned/video"
+)
+
+func TestReconcileStorage(t *testing.T) {
+	repo := new(fakes.FakeVideoRepo)
+	repo.ListObjectKeysReturns([]string{"1-abc-test.mp4", "transcoded/480p-1-abc-test.mp4"}, nil)
+	repo.SaveUpload(&cohesioned.VideoUpload{VideoID: 2})
+
+	old := time.Now().Add(-48 * time.Hour)
+	store := new(fakes.FakeBlobStore)
+	store.ListReturns([]*storage.ObjectInfo{
+		{Key: "1-abc-test.mp4", LastModified: old},
+		{Key: "transcoded/480p-1-abc-test.mp4", LastModified: old},
+		{Key: "transcoded/thumbnails/1-abc-test.mp4-192x108-00001.png", LastModified: old},
+		{Key: "transcoded/480p-16x9-1-abc-test.mp4", LastModified: old},
+		{Key: "uploads/2/00000000000000000000-00000000000000000005", LastModified: old},
+		{Key: "1-old-test.mp4", LastModified: old},
+		{Key: "uploads/3/00000000000000000000-00000000000000000005", LastModified: old},
+		{Key: "1-staged-test.mp4", LastModified: time.Now()},
+	}, nil)
+
+	//video 1 was transcoded before its rendition was recorded
+	cfg := new(fakes.FakeAwsConfig)
+	cfg.GetVideoBucketReturns("videos")
+	cfg.GetTranscodingConfigReturns(&config.TranscodingConfig{Transcoder: config.TranscoderNone, OutputPrefix: "transcoded/", Presets: []config.TranscodingPreset{{Name: "480p-16x9"}}})
+
+	svc := video.NewService(repo, new(fakes.FakeChapterRepo), new(fakes.FakeFAQRepo), new(fakes.FakeWatchRepo), new(fakes.FakeRelatedRepo), new(fakes.FakeTaxonomyRepo), new(fakes.FakeStandardRepo), new(fakes.FakeTeacherRepo), new(fakes.FakeNotificationRepo), store, nil, cfg)
+	report, err := svc.ReconcileStorage(24*time.Hour, false)
+	if err != nil {
+		t.Fatalf("Unexpected error reconciling storage: %v", err)
+	}
+
+	if report.Bucket != "videos" || report.Scanned != 8 || report.Deleted != 0 {
+		t.Errorf("unexpected report %v", report)
+	}
+
+	if len(report.Orphans) != 2 || report.Orphans[0].Key != "1-old-test.mp4" || report.Orphans[1].Key != "uploads/3/00000000000000000000-00000000000000000005" {
+		t.Errorf("expected the replaced file and abandoned upload to be orphans but got %v", report.Orphans)
+	}
+
+	if len(store.Deleted) != 0 {
+		t.Errorf("nothing should be deleted when only reporting but got %v", store.Deleted)
+	}
+
+	report, err = svc.ReconcileStorage(24*time.Hour, true)
+	if err != nil {
+		t.Fatalf("Unexpected error reconciling storage: %v", err)
+	}
+
+	if report.Deleted != 2 || len(store.Deleted) != 2 {
+		t.Errorf("expected both orphans to be deleted but got %v", store.Deleted)
+	}
+}
@@ -0,0 +1,118 @@
+package video
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+//DefaultRelatedLimit is how many related videos are listed when no limit is given
+const DefaultRelatedLimit = 5
+
+//MaxRelatedLimit is the most related videos that can be listed at once
+const MaxRelatedLimit = 20
+
+//ErrInvalidRelated is returned when related videos can't be linked. The reasons are added to the video's ValidationErrors
+var ErrInvalidRelated = errors.New("the related videos are not valid")
+
+//RelatedVideos lists up to limit videos to watch after the video. The videos an admin linked come first, in the order they were linked,
+//followed by suggested videos that share key terms, standards or taxonomy with the video - see Repo.SuggestRelated for how they are scored
+func (s *adminService) RelatedVideos(video *cohesioned.Video, limit int) ([]*cohesioned.RelatedVideo, error) {
+	ids, err := s.relatedRepo.ListRelatedIDs(video.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	related := make([]*cohesioned.RelatedVideo, 0, limit)
+	for _, id := range ids {
+		related = append(related, &cohesioned.RelatedVideo{Video: &cohesioned.Video{ID: id}, Curated: true})
+	}
+
+	if len(related) < limit {
+		suggested, err := s.relatedRepo.SuggestRelated(video.ID, limit-len(related))
+		if err != nil {
+			return nil, err
+		}
+
+		related = append(related, suggested...)
+	}
+
+	return s.loadRelated(related)
+}
+
+//SetRelated links the videos with the given IDs to the video, replacing any linked before. An empty list unlinks them all, leaving only suggestions
+func (s *adminService) SetRelated(video *cohesioned.Video, relatedIDs []int64) error {
+	if len(relatedIDs) > MaxRelatedLimit {
+		video.AddValidationError("related_ids", fmt.Sprintf("no more than %d videos can be related", MaxRelatedLimit))
+	}
+
+	seen := make(map[int64]bool)
+	for _, id := range relatedIDs {
+		if id == video.ID {
+			video.AddValidationError("related_ids", "a video can't be related to itself")
+		}
+
+		if seen[id] {
+			video.AddValidationError("related_ids", fmt.Sprintf("video %d is listed more than once", id))
+		}
+
+		seen[id] = true
+	}
+
+	if len(video.ValidationErrors) > 0 {
+		return ErrInvalidRelated
+	}
+
+	videos, err := s.videoRepo.FindByIDs(relatedIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range videos {
+		delete(seen, v.ID)
+	}
+
+	for id := range seen {
+		video.AddValidationError("related_ids", fmt.Sprintf("%d is not a valid video id", id))
+	}
+
+	if len(video.ValidationErrors) > 0 {
+		return ErrInvalidRelated
+	}
+
+	return s.relatedRepo.SetRelated(video.ID, relatedIDs)
+}
+
+//loadRelated replaces the ID only Video of each of the related videos with the full video, dropping any that no longer exist
+func (s *adminService) loadRelated(related []*cohesioned.RelatedVideo) ([]*cohesioned.RelatedVideo, error) {
+	ids := make([]int64, len(related))
+	for i, r := range related {
+		ids[i] = r.ID
+	}
+
+	videos, err := s.videoRepo.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int64]*cohesioned.Video)
+	for _, v := range videos {
+		byID[v.ID] = v
+	}
+
+	s.signThumbnails(videos...)
+	loaded := make([]*cohesioned.RelatedVideo, 0, len(related))
+	for _, r := range related {
+		if v, ok := byID[r.ID]; ok {
+			r.Video = v
+			loaded = append(loaded, r)
+		}
+	}
+
+	return loaded, nil
+}
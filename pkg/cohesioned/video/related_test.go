@@ -0,0 +1,118 @@
+package video_test
+
+import (
+	"testing"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/video"
+)
+
+func relatedVideos(ids ...int64) []*cohesioned.Video {
+	var list []*cohesioned.Video
+	for _, id := range ids {
+		v := fakes.FakeVideo()
+		v.ID = id
+		list = append(list, v)
+	}
+
+	return list
+}
+
+func newRelatedService(videoRepo video.Repo, relatedRepo video.RelatedRepo) video.AdminService {
+	cfg := new(fakes.FakeAwsConfig)
+	cfg.GetVideoBucketReturns("videos")
+
+	return video.NewService(videoRepo, new(fakes.FakeChapterRepo), new(fakes.FakeFAQRepo), new(fakes.FakeWatchRepo), relatedRepo, new(fakes.FakeTaxonomyRepo), new(fakes.FakeStandardRepo), new(fakes.FakeTeacherRepo), new(fakes.FakeNotificationRepo), new(fakes.FakeBlobStore), nil, cfg)
+}
+
+func TestRelatedVideosListsCuratedVideosFirst(t *testing.T) {
+	repo := new(fakes.FakeVideoRepo)
+	repo.ListReturns(relatedVideos(2, 3, 4, 5), nil)
+	relatedRepo := new(fakes.FakeRelatedRepo)
+	relatedRepo.Related = []int64{4, 2}
+	relatedRepo.Suggested = []*cohesioned.RelatedVideo{
+		{Video: &cohesioned.Video{ID: 5}, Score: 6},
+		{Video: &cohesioned.Video{ID: 3}, Score: 2},
+	}
+
+	related, err := newRelatedService(repo, relatedRepo).RelatedVideos(fakes.FakeVideo(), 3)
+	if err != nil {
+		t.Fatalf("Unexpected error listing related videos: %v", err)
+	}
+
+	if len(related) != 3 {
+		t.Fatalf("expected 3 related videos but got %d", len(related))
+	}
+
+	for i, expected := range []int64{4, 2, 5} {
+		if related[i].ID != expected {
+			t.Errorf("expected video %d at %d but got %d", expected, i, related[i].ID)
+		}
+	}
+
+	if !related[0].Curated || !related[1].Curated || related[2].Curated || related[2].Score != 6 {
+		t.Errorf("expected two curated videos then a suggestion but got %v", related)
+	}
+
+	if related[0].Title != "Test Video" || related[0].ThumbnailURL != "http://localhost:3001"+video.PlaceholderThumbnailPath {
+		t.Errorf("expected the related videos to be loaded but got %v", related[0].Video)
+	}
+}
+
+func TestRelatedVideosSkipsDeletedVideos(t *testing.T) {
+	repo := new(fakes.FakeVideoRepo)
+	repo.ListReturns(relatedVideos(2), nil)
+	relatedRepo := new(fakes.FakeRelatedRepo)
+	relatedRepo.Related = []int64{9, 2}
+
+	related, err := newRelatedService(repo, relatedRepo).RelatedVideos(fakes.FakeVideo(), video.DefaultRelatedLimit)
+	if err != nil {
+		t.Fatalf("Unexpected error listing related videos: %v", err)
+	}
+
+	if len(related) != 1 || related[0].ID != 2 {
+		t.Errorf("expected only video 2 but got %v", related)
+	}
+}
+
+func TestSetRelated(t *testing.T) {
+	repo := new(fakes.FakeVideoRepo)
+	repo.ListReturns(relatedVideos(2, 3), nil)
+	relatedRepo := new(fakes.FakeRelatedRepo)
+
+	if err := newRelatedService(repo, relatedRepo).SetRelated(fakes.FakeVideo(), []int64{3, 2}); err != nil {
+		t.Fatalf("Unexpected error setting related videos: %v", err)
+	}
+
+	if len(relatedRepo.Related) != 2 || relatedRepo.Related[0] != 3 || relatedRepo.Related[1] != 2 {
+		t.Errorf("expected videos 3 and 2 to be related in order but got %v", relatedRepo.Related)
+	}
+}
+
+func TestSetRelatedRejectsInvalidVideos(t *testing.T) {
+	lists := map[string][]int64{
+		"itself":    {1},
+		"duplicate": {2, 2},
+		"missing":   {2, 9},
+	}
+
+	for name, ids := range lists {
+		repo := new(fakes.FakeVideoRepo)
+		repo.ListReturns(relatedVideos(2), nil)
+		relatedRepo := new(fakes.FakeRelatedRepo)
+		v := fakes.FakeVideo()
+
+		if err := newRelatedService(repo, relatedRepo).SetRelated(v, ids); err != video.ErrInvalidRelated {
+			t.Errorf("expected ErrInvalidRelated for a %s video but got %v", name, err)
+		}
+
+		if !hasValidationError(v, "related_ids") {
+			t.Errorf("expected a validation error for a %s video but got %v", name, v.ValidationErrors)
+		}
+
+		if relatedRepo.Related != nil {
+			t.Errorf("nothing should be related for a %s video but got %v", name, relatedRepo.Related)
+		}
+	}
+}
@@ -1,14 +1,123 @@
 package video
 
 import (
+	"errors"
+
 	"github.com/cohesion-education/api/pkg/cohesioned"
 )
 
+//ErrDuplicateChecksum is returned by Repo.Update when another video already has the video's checksum
+var ErrDuplicateChecksum = errors.New("another video already has that checksum")
+
+//ErrUnknownTeacher is returned by Repo.Save and Repo.Update when one of the video's teachers was deleted after they were validated
+var ErrUnknownTeacher = errors.New("one of the video's teachers no longer exists")
+
 type Repo interface {
-	List() ([]*cohesioned.Video, error)
+	List(opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error)
 	Get(id int64) (*cohesioned.Video, error)
 	Delete(id int64) error
 	Save(video *cohesioned.Video) (int64, error)
 	Update(video *cohesioned.Video) error
 	FindByTaxonomyID(id int64) ([]*cohesioned.Video, error)
+	FindByChecksum(checksum string) (*cohesioned.Video, error)
+	FindByIDs(ids []int64) ([]*cohesioned.Video, error)
+	SetDuration(videoID int64, seconds float64) error
+	FindByTag(kinds []cohesioned.TagKind, name string, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error)
+	FindByJurisdiction(jurisdiction string, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error)
+	FindByTeacher(teacherID int64, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error)
+	ListTags(kinds ...cohesioned.TagKind) ([]*cohesioned.Tag, error)
+	SaveTranscodingJob(job *cohesioned.TranscodingJob) (int64, error)
+	UpdateTranscodingJob(job *cohesioned.TranscodingJob) error
+	ListTranscodingJobs(videoID int64) ([]*cohesioned.TranscodingJob, error)
+	ListUnfinishedTranscodingJobs() ([]*cohesioned.TranscodingJob, error)
+	SaveRendition(r *cohesioned.Rendition) (int64, error)
+	ListRenditions(videoID int64) ([]*cohesioned.Rendition, error)
+	DeleteRenditions(videoID int64) error
+	SaveThumbnail(t *cohesioned.Thumbnail) (int64, error)
+	ListThumbnails(videoID int64) ([]*cohesioned.Thumbnail, error)
+	DeleteThumbnail(id int64) error
+	DeleteThumbnails(videoID int64, kind cohesioned.ThumbnailKind) error
+	SaveCaptions(c *cohesioned.CaptionTrack) (int64, error)
+	ListCaptions(videoID int64) ([]*cohesioned.CaptionTrack, error)
+	DeleteCaptions(videoID int64, language string) error
+	ListTranscripts(videoIDs []int64) (map[int64]string, error)
+	GetUpload(videoID int64) (*cohesioned.VideoUpload, error)
+	SaveUpload(u *cohesioned.VideoUpload) (bool, error)
+	AddUploadChunk(videoID int64, chunk *cohesioned.UploadChunk) (bool, error)
+	ListUploadChunks(videoID int64) ([]*cohesioned.UploadChunk, error)
+	DeleteUpload(videoID int64) error
+	ListObjectKeys() ([]string, error)
+	ListUploadVideoIDs() ([]int64, error)
+	ListWithoutThumbnails() ([]*cohesioned.Video, error)
+	Search(terms []string, taxonomyIDs []int64, opts *cohesioned.ListOptions) ([]*cohesioned.SearchResult, int64, error)
+}
+
+//ChapterRepo stores the chapters of videos
+type ChapterRepo interface {
+	ListChapters(videoID int64) ([]*cohesioned.Chapter, error)
+	SaveChapter(c *cohesioned.Chapter) (int64, error)
+	UpdateChapter(c *cohesioned.Chapter) error
+	DeleteChapter(videoID, id int64) error
+}
+
+//FAQRepo stores the FAQs of videos and the questions students ask about them
+type FAQRepo interface {
+	ListFAQs(videoID int64, statuses ...cohesioned.FAQStatus) ([]*cohesioned.FAQ, error)
+	GetFAQ(videoID, id int64) (*cohesioned.FAQ, error)
+	ListQuestions(opts *cohesioned.ListOptions) ([]*cohesioned.FAQ, int64, error)
+	SaveFAQ(f *cohesioned.FAQ) (int64, error)
+	UpdateFAQ(f *cohesioned.FAQ) error
+	DeleteFAQ(videoID, id int64) error
+}
+
+//WatchRepo stores what students watch and reports how much of each video they finish
+type WatchRepo interface {
+	IsParentOf(userID, studentID int64) (bool, error)
+	SaveWatchEvent(e *cohesioned.WatchEvent) (int64, error)
+	SaveWatchProgress(p *cohesioned.WatchProgress) error
+	GetWatchProgress(videoID, userID, studentID int64) (*cohesioned.WatchProgress, error)
+	ListCompletionRates(opts *cohesioned.ListOptions) ([]*cohesioned.CompletionRate, int64, error)
+}
+
+//RelatedRepo stores the videos admins link to each other and suggests others
+type RelatedRepo interface {
+	ListRelatedIDs(videoID int64) ([]int64, error)
+	SetRelated(videoID int64, relatedIDs []int64) error
+	SuggestRelated(videoID int64, limit int) ([]*cohesioned.RelatedVideo, error)
+}
+
+//ListSpec is how the video list can be sorted and filtered. created_by filters by uploader
+var ListSpec = cohesioned.ListSpec{
+	SortFields:  []string{"id", "title", "created", "updated"},
+	DefaultSort: "-created",
+	Filters: map[string]cohesioned.FilterType{
+		"taxonomy_id": cohesioned.FilterInt,
+		"created_by":  cohesioned.FilterInt,
+		"created":     cohesioned.FilterDateRange,
+	},
+}
+
+//SearchSpec only allows search results to be paged - they are always sorted by relevance
+var SearchSpec = cohesioned.ListSpec{}
+
+//CompletionListSpec is how the completion rate report can be sorted and filtered
+var CompletionListSpec = cohesioned.ListSpec{
+	SortFields:  []string{"id", "title", "viewers", "completions", "completion_rate"},
+	DefaultSort: "-viewers",
+	Filters: map[string]cohesioned.FilterType{
+		"video_id":    cohesioned.FilterInt,
+		"taxonomy_id": cohesioned.FilterInt,
+	},
+}
+
+//QuestionListSpec is how the FAQs of every video can be sorted and filtered for moderation. The oldest come first so questions are answered in the order they were asked
+var QuestionListSpec = cohesioned.ListSpec{
+	SortFields:  []string{"id", "created", "answered"},
+	DefaultSort: "created",
+	Filters: map[string]cohesioned.FilterType{
+		"status":   cohesioned.FilterString,
+		"video_id": cohesioned.FilterInt,
+		"asked_by": cohesioned.FilterInt,
+		"created":  cohesioned.FilterDateRange,
+	},
 }
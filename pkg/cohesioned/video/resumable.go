@@ -0,0 +1,260 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/storage"
+)
+
+var (
+	//ErrUploadOffsetMismatch is returned for a chunk that doesn't start where the upload left off. The client should ask for the upload's progress and resume from there
+	ErrUploadOffsetMismatch = errors.New("the chunk does not start where the upload left off")
+	//ErrUploadSizeChanged is returned for a chunk of a file with a different size to the upload in progress
+	ErrUploadSizeChanged = errors.New("the chunk's total size does not match the upload in progress")
+	//ErrShortChunk is returned when the body of a chunk is shorter than its Content-Range
+	ErrShortChunk = errors.New("the chunk is shorter than its content range")
+)
+
+var contentRangePattern = regexp.MustCompile(`^bytes (\d+)-(\d+)/(\d+)$`)
+
+//ParseContentRange parses a Content-Range header such as bytes 0-1048575/5242880 into the chunk it describes and the total size of the file
+func ParseContentRange(header string) (*cohesioned.UploadChunk, int64, error) {
+	match := contentRangePattern.FindStringSubmatch(header)
+	if match == nil {
+		return nil, 0, fmt.Errorf("%q is not a valid content range - use bytes first-last/total", header)
+	}
+
+	first, _ := strconv.ParseInt(match[1], 10, 64)
+	last, _ := strconv.ParseInt(match[2], 10, 64)
+	total, err := strconv.ParseInt(match[3], 10, 64)
+	if err != nil || last < first || last >= total {
+		return nil, 0, fmt.Errorf("%q is not a valid content range", header)
+	}
+
+	return &cohesioned.UploadChunk{First: first, Last: last}, total, nil
+}
+
+//chunkKey is where a chunk of a resumable upload is kept until the upload is assembled
+func chunkKey(videoID int64, chunk *cohesioned.UploadChunk) string {
+	return fmt.Sprintf("%s%020d-%020d", chunkPrefix(videoID), chunk.First, chunk.Last)
+}
+
+func chunkPrefix(videoID int64) string {
+	return fmt.Sprintf("uploads/%d/", videoID)
+}
+
+//UploadProgress returns the video's resumable upload, or nil if there isn't one in progress
+func (s *adminService) UploadProgress(videoID int64) (*cohesioned.VideoUpload, error) {
+	return s.videoRepo.GetUpload(videoID)
+}
+
+//WriteChunk stores a chunk of a resumable upload of the video's file, starting the upload with its first chunk.
+//Once the last chunk is received the chunks are assembled into the video's file with SetFile. If that fails, sending any chunk of the complete upload again retries the assembly.
+//The upload is returned along with ErrUploadOffsetMismatch so the client can resume from its progress
+func (s *adminService) WriteChunk(ctx context.Context, video *cohesioned.Video, chunk *cohesioned.UploadChunk, total int64, fileType string, body io.Reader) (*cohesioned.VideoUpload, error) {
+	upload, err := s.videoRepo.GetUpload(video.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if upload == nil {
+		currentUser, _ := cohesioned.FromContext(ctx)
+		upload = &cohesioned.VideoUpload{
+			VideoID:     video.ID,
+			Size:        total,
+			FileType:    fileType,
+			Created:     time.Now(),
+			CreatedByID: currentUser.ID,
+		}
+
+		if chunk.First != 0 {
+			return upload, ErrUploadOffsetMismatch
+		}
+
+		if err := s.checkSize(video, total); err != nil {
+			return nil, err
+		}
+
+		saved, err := s.videoRepo.SaveUpload(upload)
+		if err != nil {
+			return nil, err
+		}
+
+		//another request started the upload first
+		if !saved {
+			return s.latestUpload(upload)
+		}
+	}
+
+	if upload.Size != total {
+		return upload, ErrUploadSizeChanged
+	}
+
+	//every chunk was received but the file couldn't be assembled, so try again
+	if upload.IsComplete() {
+		return upload, s.assemble(ctx, video, upload)
+	}
+
+	if chunk.First != upload.Received {
+		return upload, ErrUploadOffsetMismatch
+	}
+
+	bucket, key := s.cfg.GetVideoBucket(), chunkKey(video.ID, chunk)
+	length := chunk.Last - chunk.First + 1
+	counter := &countingReader{r: io.LimitReader(body, length)}
+	if err := s.store.Put(bucket, key, counter, ""); err != nil {
+		return nil, fmt.Errorf("Failed to store chunk: %v", err)
+	}
+
+	if counter.n != length {
+		s.store.Delete(bucket, key)
+		return upload, ErrShortChunk
+	}
+
+	added, err := s.videoRepo.AddUploadChunk(video.ID, chunk)
+	if err != nil {
+		return nil, err
+	}
+
+	//another request stored a chunk at this offset first. Its object is left for assemble to clean up, as it may share this chunk's key
+	if !added {
+		return s.latestUpload(upload)
+	}
+
+	upload.Received = chunk.Last + 1
+	upload.Updated = time.Now()
+	if !upload.IsComplete() {
+		return upload, nil
+	}
+
+	return upload, s.assemble(ctx, video, upload)
+}
+
+//latestUpload returns the progress of an upload another request has changed, along with ErrUploadOffsetMismatch so the client resumes from there
+func (s *adminService) latestUpload(upload *cohesioned.VideoUpload) (*cohesioned.VideoUpload, error) {
+	latest, err := s.videoRepo.GetUpload(upload.VideoID)
+	if err != nil || latest == nil {
+		return upload, ErrUploadOffsetMismatch
+	}
+
+	return latest, ErrUploadOffsetMismatch
+}
+
+//assemble joins the upload's chunks into the video's file, then deletes the chunks and the upload. They are deleted too if the file is rejected by SetFile, as resuming the upload can't fix it
+func (s *adminService) assemble(ctx context.Context, video *cohesioned.Video, upload *cohesioned.VideoUpload) error {
+	chunks, err := s.videoRepo.ListUploadChunks(video.ID)
+	if err != nil {
+		return err
+	}
+
+	reader := &chunkReader{store: s.store, bucket: s.cfg.GetVideoBucket()}
+	for _, chunk := range chunks {
+		reader.keys = append(reader.keys, chunkKey(video.ID, chunk))
+	}
+
+	defer reader.Close()
+
+	video.FileSize = upload.Size
+	if len(upload.FileType) > 0 {
+		video.FileType = upload.FileType
+	}
+
+	setErr := s.SetFile(ctx, reader, video)
+	if setErr != nil && setErr != ErrInvalidFile {
+		return setErr
+	}
+
+	s.discardChunks(video.ID)
+	if err := s.videoRepo.DeleteUpload(video.ID); err != nil {
+		return err
+	}
+
+	return setErr
+}
+
+//CancelUpload discards the video's resumable upload and the chunks received so far
+func (s *adminService) CancelUpload(videoID int64) error {
+	s.discardChunks(videoID)
+	return s.videoRepo.DeleteUpload(videoID)
+}
+
+//discardChunks deletes every object under the upload's chunk prefix, including any left behind by chunks that lost a race. Failures are only logged
+func (s *adminService) discardChunks(videoID int64) {
+	bucket := s.cfg.GetVideoBucket()
+	objects, err := s.store.List(bucket, chunkPrefix(videoID))
+	if err != nil {
+		fmt.Printf("Failed to list upload chunks of video %d: %v\n", videoID, err)
+		return
+	}
+
+	for _, obj := range objects {
+		if err := s.store.Delete(bucket, obj.Key); err != nil {
+			fmt.Printf("Failed to delete upload chunk %s: %v\n", obj.Key, err)
+		}
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+//chunkReader reads the objects at keys one after another, only opening each one when it is reached
+type chunkReader struct {
+	store   storage.BlobStore
+	bucket  string
+	keys    []string
+	current io.ReadCloser
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.keys) == 0 {
+				return 0, io.EOF
+			}
+
+			rc, err := r.store.Get(r.bucket, r.keys[0])
+			if err != nil {
+				return 0, fmt.Errorf("Failed to read upload chunk %s: %v", r.keys[0], err)
+			}
+
+			r.current = rc
+			r.keys = r.keys[1:]
+		}
+
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			r.current.Close()
+			r.current = nil
+			if n == 0 {
+				continue
+			}
+
+			return n, nil
+		}
+
+		return n, err
+	}
+}
+
+func (r *chunkReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+
+	return r.current.Close()
+}
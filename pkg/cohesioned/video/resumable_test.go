@@ -0,0 +1,123 @@
+package video_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/video"
+)
+
+func TestParseContentRange(t *testing.T) {
+	chunk, total, err := video.ParseContentRange("bytes 0-1048575/5242880")
+	if err != nil {
+		t.Fatalf("Unexpected error parsing content range: %v", err)
+	}
+
+	if chunk.First != 0 || chunk.Last != 1048575 || total != 5242880 {
+		t.Errorf("unexpected chunk %v of %d", chunk, total)
+	}
+
+	for _, header := range []string{"", "bytes 0-10/*", "bytes 10-5/20", "bytes 0-20/20", "items 0-1/2"} {
+		if _, _, err := video.ParseContentRange(header); err == nil {
+			t.Errorf("expected %q to be rejected", header)
+		}
+	}
+}
+
+func TestWriteChunkAssemblesTheFileOnceEveryChunkIsReceived(t *testing.T) {
+	store := new(fakes.FakeBlobStore)
+	svc := newUploadService(store)
+	v := fakes.FakeVideo()
+	ctx := context.WithValue(context.Background(), cohesioned.CurrentUserKey, fakes.FakeProfile())
+
+	upload, err := svc.WriteChunk(ctx, v, &cohesioned.UploadChunk{First: 0, Last: 5}, 12, "video/mp4", strings.NewReader("hello "))
+	if err != nil {
+		t.Fatalf("Unexpected error writing first chunk: %v", err)
+	}
+
+	if upload.Received != 6 || upload.IsComplete() {
+		t.Errorf("expected 6 of 12 bytes to be received but got %v", upload)
+	}
+
+	upload, err = svc.WriteChunk(ctx, v, &cohesioned.UploadChunk{First: 8, Last: 11}, 12, "video/mp4", strings.NewReader("rld!"))
+	if err != video.ErrUploadOffsetMismatch || upload.Received != 6 {
+		t.Errorf("expected a chunk past the upload's offset to be rejected with the upload's progress but got %v %v", upload, err)
+	}
+
+	if _, err := svc.WriteChunk(ctx, v, &cohesioned.UploadChunk{First: 6, Last: 11}, 12, "video/mp4", strings.NewReader("wor")); err != video.ErrShortChunk {
+		t.Errorf("expected a short chunk to be rejected but got %v", err)
+	}
+
+	if _, err := svc.WriteChunk(ctx, v, &cohesioned.UploadChunk{First: 6, Last: 11}, 20, "video/mp4", strings.NewReader("world!")); err != video.ErrUploadSizeChanged {
+		t.Errorf("expected a chunk of a different sized file to be rejected but got %v", err)
+	}
+
+	upload, err = svc.WriteChunk(ctx, v, &cohesioned.UploadChunk{First: 6, Last: 11}, 12, "video/mp4", strings.NewReader("world!"))
+	if err != nil {
+		t.Fatalf("Unexpected error writing last chunk: %v", err)
+	}
+
+	if !upload.IsComplete() {
+		t.Errorf("expected the upload to be complete but got %v", upload)
+	}
+
+	if file := string(store.Stored[v.StorageObjectName]); file != "hello world!" {
+		t.Errorf("expected the chunks to be assembled into the video's file but got %q", file)
+	}
+
+	//the type is sniffed from the file rather than taken from the client
+	if v.FileSize != 12 || v.FileType != "text/plain" || !strings.HasPrefix(v.StorageObjectName, "1-") || !strings.HasSuffix(v.StorageObjectName, "-test.mp4") {
+		t.Errorf("the assembled file was not recorded on the video: %v", v)
+	}
+
+	if progress, _ := svc.UploadProgress(v.ID); progress != nil {
+		t.Errorf("expected the upload to be removed once assembled but got %v", progress)
+	}
+}
+
+func TestWriteChunkMustStartAtTheBeginning(t *testing.T) {
+	svc := newUploadService(new(fakes.FakeBlobStore))
+	ctx := context.WithValue(context.Background(), cohesioned.CurrentUserKey, fakes.FakeProfile())
+
+	upload, err := svc.WriteChunk(ctx, fakes.FakeVideo(), &cohesioned.UploadChunk{First: 6, Last: 11}, 12, "", strings.NewReader("world!"))
+	if err != video.ErrUploadOffsetMismatch || upload.Received != 0 {
+		t.Errorf("expected a new upload to be resumed from 0 but got %v %v", upload, err)
+	}
+}
+
+func TestWriteChunkRetriesAssemblingACompleteUpload(t *testing.T) {
+	repo := new(fakes.FakeVideoRepo)
+	repo.SaveUpload(&cohesioned.VideoUpload{VideoID: 1, Size: 12})
+	repo.AddUploadChunk(1, &cohesioned.UploadChunk{First: 0, Last: 5})
+	repo.AddUploadChunk(1, &cohesioned.UploadChunk{First: 6, Last: 11})
+
+	//the chunks were kept when assembling them failed the first time
+	store := new(fakes.FakeBlobStore)
+	store.Put("videos", fmt.Sprintf("uploads/1/%020d-%020d", 0, 5), strings.NewReader("hello "), "")
+	store.Put("videos", fmt.Sprintf("uploads/1/%020d-%020d", 6, 11), strings.NewReader("world!"), "")
+
+	v := fakes.FakeVideo()
+	ctx := context.WithValue(context.Background(), cohesioned.CurrentUserKey, fakes.FakeProfile())
+	if _, err := newStorageService(repo, store).WriteChunk(ctx, v, &cohesioned.UploadChunk{First: 6, Last: 11}, 12, "video/mp4", strings.NewReader("world!")); err != nil {
+		t.Fatalf("Unexpected error retrying the upload: %v", err)
+	}
+
+	if file := string(store.Stored[v.StorageObjectName]); file != "hello world!" {
+		t.Errorf("expected the chunks to be assembled into the video's file but got %q", file)
+	}
+}
+
+func TestWriteChunkWhenAnotherRequestStartsTheUploadFirst(t *testing.T) {
+	repo := new(fakes.FakeVideoRepo)
+	repo.Racing = &cohesioned.VideoUpload{VideoID: 1, Size: 12, Received: 6}
+	ctx := context.WithValue(context.Background(), cohesioned.CurrentUserKey, fakes.FakeProfile())
+
+	upload, err := newStorageService(repo, new(fakes.FakeBlobStore)).WriteChunk(ctx, fakes.FakeVideo(), &cohesioned.UploadChunk{First: 0, Last: 5}, 12, "", strings.NewReader("hello "))
+	if err != video.ErrUploadOffsetMismatch || upload.Received != 6 {
+		t.Errorf("expected the chunk to be rejected with the other request's progress but got %v %v", upload, err)
+	}
+}
@@ -0,0 +1,157 @@
+package video
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+const (
+	maxSearchTerms = 10
+	//MinTermLength is the fewest characters a word must have to be searched for
+	MinTermLength = 3
+	//snippetLength is roughly how many characters of a field are shown around the first match
+	snippetLength = 160
+)
+
+var (
+	//nonSearchTermChars splits text into terms, keeping standards codes such as MAFS.1.OA.1.1 together as a single term
+	nonSearchTermChars = regexp.MustCompile(`[^\p{L}\p{N}.\-]+`)
+)
+
+var (
+	//ErrUnknownGrade is returned for a search narrowed to a grade that isn't in the taxonomy
+	ErrUnknownGrade = errors.New("there is no grade with that name")
+	//ErrUnknownSubject is returned for a search narrowed to a subject that isn't in the grade
+	ErrUnknownSubject = errors.New("the grade has no subject with that name")
+)
+
+//SearchQuery is a full-text search, optionally narrowed to a taxonomy subtree by ID or by grade and subject name
+type SearchQuery struct {
+	Text       string
+	TaxonomyID int64
+	Grade      string
+	Subject    string
+}
+
+//SearchTerms splits text into the lower-cased terms that are searched for and highlighted
+func SearchTerms(text string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+
+	for _, term := range nonSearchTermChars.Split(strings.ToLower(text), -1) {
+		term = strings.Trim(term, ".-")
+		if len(term) < MinTermLength || seen[term] {
+			continue
+		}
+
+		seen[term] = true
+		terms = append(terms, term)
+		if len(terms) == maxSearchTerms {
+			break
+		}
+	}
+
+	return terms
+}
+
+//booleanQuery is the MySQL boolean mode query that matches any of the terms. Plain words match as prefixes, codes containing . or - as phrases
+func booleanQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		if strings.ContainsAny(term, ".-") {
+			parts[i] = fmt.Sprintf(`"%s"`, term)
+		} else {
+			parts[i] = term + "*"
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+//Highlights returns a snippet of each of the video's searchable fields that contains one of the terms
+func Highlights(v *cohesioned.Video, terms []string) map[string]string {
+	highlights := make(map[string]string)
+	if len(terms) == 0 {
+		return highlights
+	}
+
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+
+	matcher := regexp.MustCompile(`(?i)(` + strings.Join(quoted, "|") + `)[\p{L}\p{N}]*`)
+
+	fields := map[string]string{
+		"title":                 v.Title,
+		"key_terms":             strings.Join(v.KeyTerms, ", "),
+		"state_standards":       strings.Join(v.StateStandards, ", "),
+		"common_core_standards": strings.Join(v.CommonCoreStandards, ", "),
+		"transcript":            v.Transcript,
+	}
+
+	for field, text := range fields {
+		if snippet, ok := highlight(text, matcher); ok {
+			highlights[field] = snippet
+		}
+	}
+
+	return highlights
+}
+
+func highlight(text string, matcher *regexp.Regexp) (string, bool) {
+	first := matcher.FindStringIndex(text)
+	if first == nil {
+		return "", false
+	}
+
+	start, end := 0, len(text)
+	if len(text) > snippetLength {
+		start = first[0] - snippetLength/4
+		if start < 0 {
+			start = 0
+		}
+
+		end = start + snippetLength
+		if end > len(text) {
+			end = len(text)
+		}
+
+		//don't cut words in half
+		if i := strings.LastIndex(text[:start], " "); start > 0 && i >= 0 {
+			start = i + 1
+		}
+
+		if i := strings.Index(text[end:], " "); end < len(text) && i >= 0 {
+			end += i
+		}
+	}
+
+	excerpt := text[start:end]
+	var b bytes.Buffer
+	if start > 0 {
+		b.WriteString("...")
+	}
+
+	last := 0
+	for _, match := range matcher.FindAllStringIndex(excerpt, -1) {
+		b.WriteString(html.EscapeString(excerpt[last:match[0]]))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(excerpt[match[0]:match[1]]))
+		b.WriteString("</em>")
+		last = match[1]
+	}
+
+	b.WriteString(html.EscapeString(excerpt[last:]))
+	if end < len(text) {
+		b.WriteString("...")
+	}
+
+	return b.String(), true
+}
@@ -0,0 +1,54 @@
+package video
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+func TestSearchTerms(t *testing.T) {
+	terms := SearchTerms("  Adding Fractions, adding a MAFS.1.OA.1.1 standard to it!")
+	expected := []string{"adding", "fractions", "mafs.1.oa.1.1", "standard"}
+
+	if !reflect.DeepEqual(expected, terms) {
+		t.Errorf("expected terms %v but got %v", expected, terms)
+	}
+}
+
+func TestBooleanQuery(t *testing.T) {
+	query := booleanQuery([]string{"fraction", "mafs.1.oa.1.1"})
+	if expected := `fraction* "mafs.1.oa.1.1"`; query != expected {
+		t.Errorf("expected %s but got %s", expected, query)
+	}
+}
+
+func TestHighlights(t *testing.T) {
+	v := &cohesioned.Video{
+		Title:          "Adding <Fractions>",
+		KeyTerms:       []string{"numerator", "denominator"},
+		StateStandards: []string{"MAFS.1.OA.1.1"},
+	}
+
+	highlights := Highlights(v, SearchTerms("fraction mafs.1.oa.1.1"))
+	expected := map[string]string{
+		"title":           "Adding &lt;<em>Fractions</em>&gt;",
+		"state_standards": "<em>MAFS.1.OA.1.1</em>",
+	}
+
+	if !reflect.DeepEqual(expected, highlights) {
+		t.Errorf("expected highlights %v but got %v", expected, highlights)
+	}
+}
+
+func TestHighlightsTranscript(t *testing.T) {
+	v := &cohesioned.Video{
+		Title:      "Adding Fractions",
+		Transcript: "Today we add two fractions with the same denominator.",
+	}
+
+	highlights := Highlights(v, SearchTerms("denominator"))
+	if expected := "Today we add two fractions with the same <em>denominator</em>."; highlights["transcript"] != expected {
+		t.Errorf("expected transcript highlight %s but got %v", expected, highlights)
+	}
+}
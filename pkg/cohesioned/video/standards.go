@@ -0,0 +1,61 @@
+package video
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+//checkStandards adds a validation error to the video for each of its standards that isn't in the catalog, other than those the previous version of the video already had.
+//State standards must belong to a state's jurisdiction and common core standards to CCSS
+func checkStandards(v, previous *cohesioned.Video, catalog []*cohesioned.Standard) {
+	commonCore := make(map[string]bool)
+	state := make(map[string]bool)
+	for _, s := range catalog {
+		if s.IsCommonCore() {
+			commonCore[strings.ToUpper(s.Code)] = true
+		} else {
+			state[strings.ToUpper(s.Code)] = true
+		}
+	}
+
+	tags := addedStandards(v, previous)
+	for _, code := range tags[cohesioned.TagStateStandard] {
+		if !state[strings.ToUpper(code)] {
+			v.AddValidationError("state_standards", fmt.Sprintf("%s is not a state standard in the standards catalog", code))
+		}
+	}
+
+	for _, code := range tags[cohesioned.TagCommonCoreStandard] {
+		if !commonCore[strings.ToUpper(code)] {
+			v.AddValidationError("common_core_standards", fmt.Sprintf("%s is not a common core standard in the standards catalog", code))
+		}
+	}
+}
+
+//addedStandards returns the video's standards of each kind that the previous version of the video didn't have. Every standard is added if previous is nil
+func addedStandards(v, previous *cohesioned.Video) map[cohesioned.TagKind][]string {
+	tags := v.Tags()
+	if previous == nil {
+		return tags
+	}
+
+	for kind, before := range previous.Tags() {
+		had := make(map[string]bool)
+		for _, code := range before {
+			had[strings.ToUpper(code)] = true
+		}
+
+		var added []string
+		for _, code := range tags[kind] {
+			if !had[strings.ToUpper(code)] {
+				added = append(added, code)
+			}
+		}
+
+		tags[kind] = added
+	}
+
+	return tags
+}
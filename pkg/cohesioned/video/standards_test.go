@@ -0,0 +1,51 @@
+package video
+
+import (
+	"testing"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+func TestCheckStandards(t *testing.T) {
+	catalog := []*cohesioned.Standard{
+		{Code: "MAFS.1.OA.1.1", Jurisdiction: "FL"},
+		{Code: "1.OA.A.1", Jurisdiction: cohesioned.CommonCoreJurisdiction},
+	}
+
+	v := &cohesioned.Video{
+		StateStandards:      []string{"mafs.1.oa.1.1", "1.OA.A.1"},
+		CommonCoreStandards: []string{"1.OA.A.1", "MADE.UP"},
+	}
+
+	checkStandards(v, nil, catalog)
+
+	if len(v.ValidationErrors) != 2 {
+		t.Fatalf("expected 2 validation errors but got %d: %v", len(v.ValidationErrors), v.ValidationErrors)
+	}
+
+	if v.ValidationErrors[0].Field != "state_standards" || v.ValidationErrors[1].Field != "common_core_standards" {
+		t.Errorf("expected errors for the common core standard listed as a state standard and the unknown standard, got %s and %s", v.ValidationErrors[0].Err, v.ValidationErrors[1].Err)
+	}
+}
+
+func TestCheckStandardsSkipsStandardsTheVideoAlreadyHad(t *testing.T) {
+	catalog := []*cohesioned.Standard{
+		{Code: "1.OA.A.1", Jurisdiction: cohesioned.CommonCoreJurisdiction},
+	}
+
+	previous := &cohesioned.Video{
+		StateStandards:      []string{"LEGACY.1"},
+		CommonCoreStandards: []string{"LEGACY.2"},
+	}
+
+	v := &cohesioned.Video{
+		StateStandards:      []string{"legacy.1", "MADE.UP"},
+		CommonCoreStandards: []string{"LEGACY.2", "1.OA.A.1"},
+	}
+
+	checkStandards(v, previous, catalog)
+
+	if len(v.ValidationErrors) != 1 || v.ValidationErrors[0].Field != "state_standards" {
+		t.Errorf("expected only the newly added unknown standard to be rejected but got %v", v.ValidationErrors)
+	}
+}
@@ -0,0 +1,43 @@
+package video
+
+import (
+	"fmt"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+//FindByTeacher finds the videos the teacher presents
+func (s *adminService) FindByTeacher(teacherID int64, opts *cohesioned.ListOptions) ([]*cohesioned.Video, int64, error) {
+	return s.signPage(s.videoRepo.FindByTeacher(teacherID, opts))
+}
+
+//ValidateTeachers adds a validation error to the video for each of its teachers that doesn't exist or is listed more than once
+func (s *adminService) ValidateTeachers(video *cohesioned.Video) error {
+	if len(video.TeacherIDs) == 0 {
+		return nil
+	}
+
+	teachers, err := s.teacherRepo.FindByIDs(video.TeacherIDs)
+	if err != nil {
+		return fmt.Errorf("Failed to look up teachers %v: %v", video.TeacherIDs, err)
+	}
+
+	exists := make(map[int64]bool)
+	for _, t := range teachers {
+		exists[t.ID] = true
+	}
+
+	seen := make(map[int64]bool)
+	for _, id := range video.TeacherIDs {
+		switch {
+		case seen[id]:
+			video.AddValidationError("teacher_ids", fmt.Sprintf("teacher %d is listed more than once", id))
+		case !exists[id]:
+			video.AddValidationError("teacher_ids", fmt.Sprintf("%d is not a valid teacher id", id))
+		}
+
+		seen[id] = true
+	}
+
+	return nil
+}
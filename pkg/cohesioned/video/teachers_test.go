@@ -0,0 +1,42 @@
+package video_test
+
+import (
+	"testing"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/video"
+)
+
+func TestValidateTeachers(t *testing.T) {
+	teacherRepo := new(fakes.FakeTeacherRepo)
+	teacherRepo.FindByIDsReturns([]*cohesioned.Teacher{{ID: 3, Name: "Ms. Frizzle"}}, nil)
+
+	svc := video.NewService(new(fakes.FakeVideoRepo), new(fakes.FakeChapterRepo), new(fakes.FakeFAQRepo), new(fakes.FakeWatchRepo), new(fakes.FakeRelatedRepo), new(fakes.FakeTaxonomyRepo), new(fakes.FakeStandardRepo), teacherRepo, new(fakes.FakeNotificationRepo), new(fakes.FakeBlobStore), nil, new(fakes.FakeAwsConfig))
+
+	v := fakes.FakeVideo()
+	v.TeacherIDs = []int64{3, 9, 3}
+	if err := svc.ValidateTeachers(v); err != nil {
+		t.Fatalf("Unexpected error validating teachers: %v", err)
+	}
+
+	if len(v.ValidationErrors) != 2 {
+		t.Fatalf("expected errors for the unknown and the repeated teacher but got %v", v.ValidationErrors)
+	}
+
+	for _, e := range v.ValidationErrors {
+		if e.Field != "teacher_ids" {
+			t.Errorf("expected a validation error for teacher_ids but got %s", e.Field)
+		}
+	}
+}
+
+func TestValidateTeachersAllowsVideosWithoutTeachers(t *testing.T) {
+	teacherRepo := new(fakes.FakeTeacherRepo)
+	svc := video.NewService(new(fakes.FakeVideoRepo), new(fakes.FakeChapterRepo), new(fakes.FakeFAQRepo), new(fakes.FakeWatchRepo), new(fakes.FakeRelatedRepo), new(fakes.FakeTaxonomyRepo), new(fakes.FakeStandardRepo), teacherRepo, new(fakes.FakeNotificationRepo), new(fakes.FakeBlobStore), nil, new(fakes.FakeAwsConfig))
+
+	v := fakes.FakeVideo()
+	if err := svc.ValidateTeachers(v); err != nil || len(v.ValidationErrors) != 0 {
+		t.Errorf("a video doesn't need a teacher but got %v %v", err, v.ValidationErrors)
+	}
+}
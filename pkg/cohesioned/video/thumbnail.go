@@ -0,0 +1,257 @@
+package video
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"io/ioutil"
+	"mime"
+	"path"
+	"regexp"
+	"strconv"
+	"time"
+
+	//decoders for the formats a poster image can be uploaded in
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+//MaxThumbnailSize is the largest poster image in bytes that can be uploaded
+const MaxThumbnailSize = 5 << 20
+
+//PlaceholderThumbnailPath is where PlaceholderThumbnailHandler serves the thumbnail of a video that has no thumbnails yet
+const PlaceholderThumbnailPath = "/api/video/thumbnail/placeholder.svg"
+
+//placeholderThumbnail is a plain 16:9 play button
+const placeholderThumbnail = `<svg xmlns="http://www.w3.org/2000/svg" width="192" height="108" viewBox="0 0 192 108">
+<rect width="192" height="108" fill="#d8dde3"/>
+<circle cx="96" cy="54" r="26" fill="#ffffff"/>
+<path d="M88 40 L112 54 L88 68 Z" fill="#8a96a3"/>
+</svg>
+`
+
+//thumbnailTypes are the content types a poster image can be uploaded as, with the extension it is stored under
+var thumbnailTypes = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+//generatedThumbnailSize matches the {resolution}-{count} suffix the transcoder gives the thumbnails it generates
+var generatedThumbnailSize = regexp.MustCompile(`-(\d+)x(\d+)-\d+\.\w+$`)
+
+//thumbnailKey is where a poster image for the video is stored. Each upload gets a new key so a cached poster is never served for a replaced one
+func thumbnailKey(video *cohesioned.Video, ext string) string {
+	return fmt.Sprintf("thumbnails/%d-custom-%s.%s", video.ID, strconv.FormatInt(time.Now().UnixNano(), 36), ext)
+}
+
+//SetThumbnail stores body as the video's poster image, replacing any poster uploaded before. The image is rejected with ErrInvalidFile if it isn't a JPEG, PNG or GIF or is larger than MaxThumbnailSize
+func (s *adminService) SetThumbnail(video *cohesioned.Video, body io.Reader) (*cohesioned.Thumbnail, error) {
+	contentType, r, err := sniff(body)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the thumbnail: %v", err)
+	}
+
+	ext, ok := thumbnailTypes[contentType]
+	if !ok {
+		return nil, invalidFile(video, "thumbnail", "%s files can't be used as a thumbnail - upload a JPEG, PNG or GIF image", contentType)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(r, MaxThumbnailSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the thumbnail: %v", err)
+	}
+
+	if len(data) > MaxThumbnailSize {
+		return nil, invalidFile(video, "thumbnail", "the image is larger than the maximum thumbnail size of %d bytes", MaxThumbnailSize)
+	}
+
+	size, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, invalidFile(video, "thumbnail", "the image could not be read: %v", err)
+	}
+
+	previous, err := s.thumbnails(video.ID, cohesioned.ThumbnailCustom)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket := s.cfg.GetVideoBucket()
+	t := &cohesioned.Thumbnail{
+		VideoID:     video.ID,
+		Kind:        cohesioned.ThumbnailCustom,
+		ObjectKey:   thumbnailKey(video, ext),
+		ContentType: contentType,
+		Width:       int64(size.Width),
+		Height:      int64(size.Height),
+		Created:     time.Now(),
+	}
+
+	if err := s.store.Put(bucket, t.ObjectKey, bytes.NewReader(data), contentType); err != nil {
+		return nil, fmt.Errorf("Failed to store the thumbnail: %v", err)
+	}
+
+	if t.ID, err = s.videoRepo.SaveThumbnail(t); err != nil {
+		s.discardObject(bucket, t.ObjectKey)
+		return nil, err
+	}
+
+	if err := s.discardThumbnails(previous); err != nil {
+		return nil, err
+	}
+
+	video.ThumbnailKey = t.ObjectKey
+	s.signThumbnails(video)
+	t.SignedURL = video.ThumbnailURL
+	return t, nil
+}
+
+//DeleteThumbnail deletes the video's poster image, so a generated thumbnail or the placeholder is shown instead
+func (s *adminService) DeleteThumbnail(video *cohesioned.Video) error {
+	custom, err := s.thumbnails(video.ID, cohesioned.ThumbnailCustom)
+	if err != nil {
+		return err
+	}
+
+	return s.discardThumbnails(custom)
+}
+
+//thumbnails lists the video's thumbnails of the given kind
+func (s *adminService) thumbnails(videoID int64, kind cohesioned.ThumbnailKind) ([]*cohesioned.Thumbnail, error) {
+	all, err := s.videoRepo.ListThumbnails(videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	var thumbnails []*cohesioned.Thumbnail
+	for _, t := range all {
+		if t.Kind == kind {
+			thumbnails = append(thumbnails, t)
+		}
+	}
+
+	return thumbnails, nil
+}
+
+//discardThumbnails deletes the thumbnails, then their images
+func (s *adminService) discardThumbnails(thumbnails []*cohesioned.Thumbnail) error {
+	for _, t := range thumbnails {
+		if err := s.videoRepo.DeleteThumbnail(t.ID); err != nil {
+			return err
+		}
+
+		s.discardObject(s.cfg.GetVideoBucket(), t.ObjectKey)
+	}
+
+	return nil
+}
+
+//recordThumbnails saves the thumbnails the transcoder generated from the video's file, returning how many there were
+func (s *adminService) recordThumbnails(video *cohesioned.Video) (int, error) {
+	if len(video.StorageObjectName) == 0 {
+		return 0, nil
+	}
+
+	prefix := s.cfg.GetTranscodingConfig().ThumbnailPrefix(video.StorageObjectName)
+	objects, err := s.store.List(s.cfg.GetVideoBucket(), prefix)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to list the thumbnails of video %d: %v", video.ID, err)
+	}
+
+	for _, obj := range objects {
+		t := &cohesioned.Thumbnail{
+			VideoID:     video.ID,
+			Kind:        cohesioned.ThumbnailGenerated,
+			ObjectKey:   obj.Key,
+			ContentType: obj.ContentType,
+			Created:     time.Now(),
+		}
+
+		if len(t.ContentType) == 0 {
+			t.ContentType = mime.TypeByExtension(path.Ext(obj.Key))
+		}
+
+		if size := generatedThumbnailSize.FindStringSubmatch(obj.Key); size != nil {
+			t.Width, _ = strconv.ParseInt(size[1], 10, 64)
+			t.Height, _ = strconv.ParseInt(size[2], 10, 64)
+		}
+
+		if t.ID, err = s.videoRepo.SaveThumbnail(t); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(objects), nil
+}
+
+//ThumbnailBackfill reports the thumbnails recorded for videos that had none
+type ThumbnailBackfill struct {
+	//Videos is the number of videos that had no thumbnails
+	Videos int `json:"videos"`
+	//Recorded is the number of thumbnails found for them in the video bucket
+	Recorded int `json:"recorded"`
+}
+
+//BackfillThumbnails records the thumbnails the transcoder generated for each video that has none, such as those transcoded before thumbnails were recorded
+func (s *adminService) BackfillThumbnails() (*ThumbnailBackfill, error) {
+	videos, err := s.videoRepo.ListWithoutThumbnails()
+	if err != nil {
+		return nil, err
+	}
+
+	backfill := &ThumbnailBackfill{Videos: len(videos)}
+	for _, video := range videos {
+		recorded, err := s.recordThumbnails(video)
+		if err != nil {
+			return nil, err
+		}
+
+		backfill.Recorded += recorded
+	}
+
+	return backfill, nil
+}
+
+//recordThumbnailsOf records the thumbnails generated by a job that has just completed. Only the job of the preferred preset generates thumbnails
+func (s *adminService) recordThumbnailsOf(job *cohesioned.TranscodingJob) error {
+	presets := s.cfg.GetTranscodingConfig().Presets
+	if job.Status != cohesioned.TranscodingComplete || len(presets) == 0 || job.Preset != presets[0].Name {
+		return nil
+	}
+
+	video, err := s.videoRepo.Get(job.VideoID)
+	if err != nil || video == nil {
+		//a video deleted while it was transcoding has nothing to record
+		return err
+	}
+
+	_, err = s.recordThumbnails(video)
+	return err
+}
+
+//placeholderThumbnailURL is the absolute url of the thumbnail shown for videos that have none
+func (s *adminService) placeholderThumbnailURL() string {
+	return s.cfg.GetStorageConfig().BaseURL + PlaceholderThumbnailPath
+}
+
+//signThumbnails sets the ThumbnailURL of each of the videos to a signed url of its poster, or to the placeholder if it has none
+func (s *adminService) signThumbnails(videos ...*cohesioned.Video) {
+	for _, video := range videos {
+		video.ThumbnailURL = s.placeholderThumbnailURL()
+		if len(video.ThumbnailKey) == 0 {
+			continue
+		}
+
+		signedURL, err := s.signedURL(s.cfg.GetVideoBucket(), video.ThumbnailKey)
+		if err != nil {
+			fmt.Printf("Failed to generate signed url for the thumbnail of video %d: %v\n", video.ID, err)
+			continue
+		}
+
+		video.ThumbnailURL = signedURL
+	}
+}
@@ -0,0 +1,203 @@
+package video_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/config"
+	"github.com/cohesion-education/api/pkg/cohesioned/storage"
+	"github.com/cohesion-education/api/pkg/cohesioned/video"
+)
+
+//completingTranscoder leaves jobs pending when they are submitted and completes them when they are refreshed
+type completingTranscoder struct{}
+
+func (t *completingTranscoder) Submit(v *cohesioned.Video, preset config.TranscodingPreset) (*cohesioned.TranscodingJob, error) {
+	return &cohesioned.TranscodingJob{VideoID: v.ID, Preset: preset.Name, Status: cohesioned.TranscodingPending}, nil
+}
+
+func (t *completingTranscoder) Refresh(job *cohesioned.TranscodingJob) (*cohesioned.Rendition, error) {
+	job.Status = cohesioned.TranscodingComplete
+	return &cohesioned.Rendition{VideoID: job.VideoID, Name: job.Preset, ObjectKey: job.OutputKey}, nil
+}
+
+func pngImage(t *testing.T, width, height int) []byte {
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("Failed to encode png: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestSetThumbnailReplacesThePreviousPoster(t *testing.T) {
+	repo := new(fakes.FakeVideoRepo)
+	repo.SaveThumbnail(&cohesioned.Thumbnail{ID: 1, VideoID: 1, Kind: cohesioned.ThumbnailCustom, ObjectKey: "thumbnails/1-custom-old.png"})
+	repo.SaveThumbnail(&cohesioned.Thumbnail{ID: 2, VideoID: 1, Kind: cohesioned.ThumbnailGenerated, ObjectKey: "thumbnails/1-abc-test.mp4-192x108-00001.png"})
+
+	store := new(fakes.FakeBlobStore)
+	store.SignedURLReturns("http://signed-thumbnail", nil)
+
+	v := fakes.FakeVideo()
+	thumbnail, err := newStorageService(repo, store).SetThumbnail(v, bytes.NewReader(pngImage(t, 640, 360)))
+	if err != nil {
+		t.Fatalf("Unexpected error setting thumbnail: %v", err)
+	}
+
+	if thumbnail.Kind != cohesioned.ThumbnailCustom || thumbnail.ContentType != "image/png" || thumbnail.Width != 640 || thumbnail.Height != 360 {
+		t.Errorf("unexpected thumbnail %v", thumbnail)
+	}
+
+	if !strings.HasPrefix(thumbnail.ObjectKey, "thumbnails/1-custom-") || !strings.HasSuffix(thumbnail.ObjectKey, ".png") {
+		t.Errorf("unexpected object key %s", thumbnail.ObjectKey)
+	}
+
+	if _, ok := store.Stored[thumbnail.ObjectKey]; !ok {
+		t.Errorf("expected the image to be stored at %s", thumbnail.ObjectKey)
+	}
+
+	if len(store.Deleted) != 1 || store.Deleted[0] != "thumbnails/1-custom-old.png" {
+		t.Errorf("expected the previous poster to be deleted but got %v", store.Deleted)
+	}
+
+	if len(repo.Thumbnails) != 2 || cohesioned.Poster(repo.Thumbnails) != thumbnail {
+		t.Errorf("expected the new poster to replace the old one but got %v", repo.Thumbnails)
+	}
+
+	if v.ThumbnailURL != "http://signed-thumbnail" || thumbnail.SignedURL != "http://signed-thumbnail" {
+		t.Errorf("expected the poster to be signed but got %s", v.ThumbnailURL)
+	}
+}
+
+func TestSetThumbnailRejectsFilesThatArentImages(t *testing.T) {
+	store := new(fakes.FakeBlobStore)
+	v := fakes.FakeVideo()
+
+	if _, err := newStorageService(new(fakes.FakeVideoRepo), store).SetThumbnail(v, bytes.NewReader(mp4Header)); err != video.ErrInvalidFile {
+		t.Fatalf("expected ErrInvalidFile but got %v", err)
+	}
+
+	if !hasValidationError(v, "thumbnail") {
+		t.Errorf("expected a validation error for the thumbnail but got %v", v.ValidationErrors)
+	}
+
+	if len(store.Stored) != 0 {
+		t.Errorf("nothing should be stored for a rejected thumbnail but got %v", store.Stored)
+	}
+}
+
+func TestDeleteThumbnailOnlyDeletesThePoster(t *testing.T) {
+	repo := new(fakes.FakeVideoRepo)
+	repo.SaveThumbnail(&cohesioned.Thumbnail{ID: 1, Kind: cohesioned.ThumbnailCustom, ObjectKey: "thumbnails/1-custom-old.png"})
+	repo.SaveThumbnail(&cohesioned.Thumbnail{ID: 2, Kind: cohesioned.ThumbnailGenerated, ObjectKey: "thumbnails/1-abc-test.mp4-192x108-00001.png"})
+
+	store := new(fakes.FakeBlobStore)
+	if err := newStorageService(repo, store).DeleteThumbnail(fakes.FakeVideo()); err != nil {
+		t.Fatalf("Unexpected error deleting thumbnail: %v", err)
+	}
+
+	if len(repo.Thumbnails) != 1 || repo.Thumbnails[0].Kind != cohesioned.ThumbnailGenerated {
+		t.Errorf("expected only the generated thumbnail to be left but got %v", repo.Thumbnails)
+	}
+
+	if len(store.Deleted) != 1 || store.Deleted[0] != "thumbnails/1-custom-old.png" {
+		t.Errorf("expected the poster image to be deleted but got %v", store.Deleted)
+	}
+}
+
+func TestListSignsThumbnailsOrUsesThePlaceholder(t *testing.T) {
+	withPoster := fakes.FakeVideo()
+	withPoster.ThumbnailKey = "thumbnails/1-custom-abc.png"
+	withoutPoster := fakes.FakeVideo()
+	withoutPoster.ID = 2
+
+	repo := new(fakes.FakeVideoRepo)
+	repo.ListReturns([]*cohesioned.Video{withPoster, withoutPoster}, nil)
+
+	store := new(fakes.FakeBlobStore)
+	store.SignedURLReturns("http://signed-thumbnail", nil)
+
+	list, _, err := newStorageService(repo, store).List(&cohesioned.ListOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error listing videos: %v", err)
+	}
+
+	if list[0].ThumbnailURL != "http://signed-thumbnail" {
+		t.Errorf("expected a signed thumbnail url but got %s", list[0].ThumbnailURL)
+	}
+
+	if list[1].ThumbnailURL != "http://localhost:3001"+video.PlaceholderThumbnailPath {
+		t.Errorf("expected the placeholder thumbnail but got %s", list[1].ThumbnailURL)
+	}
+}
+
+func TestTranscodingJobsRecordsGeneratedThumbnails(t *testing.T) {
+	v := fakes.FakeVideo()
+	v.StorageBucket = "videos"
+	v.StorageObjectName = "1-abc-test.mp4"
+
+	repo := new(fakes.FakeVideoRepo)
+	repo.GetReturns(v, nil)
+	repo.Jobs = []*cohesioned.TranscodingJob{
+		{ID: 1, VideoID: v.ID, Preset: "480p", Status: cohesioned.TranscodingRunning},
+		{ID: 2, VideoID: v.ID, Preset: "720p", Status: cohesioned.TranscodingRunning},
+	}
+
+	store := new(fakes.FakeBlobStore)
+	store.ListReturns([]*storage.ObjectInfo{
+		{Key: "transcoded/thumbnails/1-abc-test.mp4-192x108-00001.png"},
+		{Key: "transcoded/thumbnails/1-abc-test.mp4-192x108-00002.png"},
+		{Key: "transcoded/thumbnails/1-other-test.mp4-192x108-00001.png"},
+	}, nil)
+
+	cfg := new(fakes.FakeAwsConfig)
+	cfg.GetVideoBucketReturns("videos")
+	cfg.GetTranscodingConfigReturns(&config.TranscodingConfig{
+		OutputPrefix: "transcoded/",
+		Presets:      []config.TranscodingPreset{{Name: "480p"}, {Name: "720p"}},
+	})
+
+	svc := video.NewService(repo, new(fakes.FakeChapterRepo), new(fakes.FakeFAQRepo), new(fakes.FakeWatchRepo), new(fakes.FakeRelatedRepo), new(fakes.FakeTaxonomyRepo), new(fakes.FakeStandardRepo), new(fakes.FakeTeacherRepo), new(fakes.FakeNotificationRepo), store, new(completingTranscoder), cfg)
+	if err := svc.RefreshTranscodingJobs(); err != nil {
+		t.Fatalf("Unexpected error refreshing transcoding jobs: %v", err)
+	}
+
+	if len(repo.Thumbnails) != 2 {
+		t.Fatalf("expected the thumbnails of the preferred preset's job to be recorded once but got %v", repo.Thumbnails)
+	}
+
+	for _, thumbnail := range repo.Thumbnails {
+		if thumbnail.Kind != cohesioned.ThumbnailGenerated || thumbnail.Width != 192 || thumbnail.Height != 108 || thumbnail.ContentType != "image/png" {
+			t.Errorf("unexpected thumbnail %v", thumbnail)
+		}
+	}
+}
+
+func TestBackfillThumbnailsRecordsThumbnailsOfVideosWithoutAny(t *testing.T) {
+	repo := new(fakes.FakeVideoRepo)
+	repo.ListWithoutThumbnailsReturns([]*cohesioned.Video{{ID: 1, StorageObjectName: "1-abc-test.mp4"}})
+
+	store := new(fakes.FakeBlobStore)
+	store.ListReturns([]*storage.ObjectInfo{
+		{Key: "thumbnails/1-abc-test.mp4-192x108-00001.png"},
+		{Key: "thumbnails/1-abc-test.mp4-192x108-00002.png"},
+	}, nil)
+
+	backfill, err := newStorageService(repo, store).BackfillThumbnails()
+	if err != nil {
+		t.Fatalf("Unexpected error backfilling thumbnails: %v", err)
+	}
+
+	if backfill.Videos != 1 || backfill.Recorded != 2 {
+		t.Errorf("expected 2 thumbnails recorded for 1 video but got %+v", backfill)
+	}
+
+	if len(repo.Thumbnails) != 2 || repo.Thumbnails[0].VideoID != 1 || repo.Thumbnails[0].Kind != cohesioned.ThumbnailGenerated || repo.Thumbnails[0].Width != 192 {
+		t.Errorf("unexpected thumbnails %v", repo.Thumbnails)
+	}
+}
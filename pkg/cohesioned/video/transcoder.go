@@ -0,0 +1,178 @@
+package video
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elastictranscoder"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/config"
+)
+
+//Transcoder converts a video's uploaded file into the renditions that are played back
+type Transcoder interface {
+	//Submit starts transcoding the video's file into the preset, returning the job to track it by
+	Submit(v *cohesioned.Video, preset config.TranscodingPreset) (*cohesioned.TranscodingJob, error)
+	//Refresh updates the job's Status and Error from the transcoder, returning the rendition it produced once it is complete
+	Refresh(job *cohesioned.TranscodingJob) (*cohesioned.Rendition, error)
+}
+
+//NewTranscoder returns the Transcoder configured by cfg, or nil if transcoding is turned off
+func NewTranscoder(cfg config.AwsConfig) Transcoder {
+	tc := cfg.GetTranscodingConfig()
+
+	switch tc.Transcoder {
+	case config.TranscoderElastic:
+		return &elasticTranscoder{cfg: cfg, tc: tc}
+	case config.TranscoderStub:
+		return &StubTranscoder{}
+	default:
+		return nil
+	}
+}
+
+//hlsSegmentDuration is the target length in seconds of each segment of an HLS rendition
+const hlsSegmentDuration = "10"
+
+type elasticTranscoder struct {
+	cfg config.AwsConfig
+	tc  *config.TranscodingConfig
+}
+
+func (t *elasticTranscoder) client() (*elastictranscoder.ElasticTranscoder, error) {
+	sess, err := t.cfg.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("Error creating session %v", err)
+	}
+
+	return elastictranscoder.New(sess), nil
+}
+
+func (t *elasticTranscoder) Submit(v *cohesioned.Video, preset config.TranscodingPreset) (*cohesioned.TranscodingJob, error) {
+	svc, err := t.client()
+	if err != nil {
+		return nil, err
+	}
+
+	job := &cohesioned.TranscodingJob{
+		VideoID:   v.ID,
+		Preset:    preset.Name,
+		Status:    cohesioned.TranscodingPending,
+		OutputKey: t.tc.OutputKey(preset, v.StorageObjectName),
+	}
+
+	output := &elastictranscoder.CreateJobOutput{
+		Key:      aws.String(fmt.Sprintf("%s-%s", preset.Name, v.StorageObjectName)),
+		PresetId: aws.String(preset.PresetID),
+	}
+
+	//Elastic Transcoder adds the playlist extension to the key and numbers each segment after it
+	if preset.HLS {
+		output.SegmentDuration = aws.String(hlsSegmentDuration)
+	}
+
+	//thumbnails are only generated once, from the preferred preset
+	if len(t.tc.Presets) > 0 && preset == t.tc.Presets[0] {
+		output.ThumbnailPattern = aws.String(fmt.Sprintf("thumbnails/%s-{resolution}-{count}", v.StorageObjectName))
+	}
+
+	input := &elastictranscoder.CreateJobInput{}
+	input.SetPipelineId(t.tc.PipelineID)
+	input.SetOutputKeyPrefix(t.tc.OutputPrefix)
+	input.SetInput(&elastictranscoder.JobInput{
+		Key: aws.String(v.StorageObjectName),
+	})
+	input.SetOutput(output)
+
+	resp, err := svc.CreateJob(input)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create job: %v", err)
+	}
+
+	job.ExternalID = aws.StringValue(resp.Job.Id)
+	job.Status = elasticStatus(aws.StringValue(resp.Job.Status))
+	return job, nil
+}
+
+func (t *elasticTranscoder) Refresh(job *cohesioned.TranscodingJob) (*cohesioned.Rendition, error) {
+	svc, err := t.client()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := svc.ReadJob(&elastictranscoder.ReadJobInput{Id: aws.String(job.ExternalID)})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read job %s: %v", job.ExternalID, err)
+	}
+
+	job.Status = elasticStatus(aws.StringValue(resp.Job.Status))
+	output := resp.Job.Output
+	if output == nil {
+		return nil, nil
+	}
+
+	switch job.Status {
+	case cohesioned.TranscodingFailed:
+		job.Error = aws.StringValue(output.StatusDetail)
+	case cohesioned.TranscodingComplete:
+		rendition := &cohesioned.Rendition{
+			VideoID:   job.VideoID,
+			Name:      job.Preset,
+			Width:     aws.Int64Value(output.Width),
+			Height:    aws.Int64Value(output.Height),
+			ObjectKey: job.OutputKey,
+			Size:      aws.Int64Value(output.FileSize),
+		}
+
+		if duration := aws.Int64Value(output.Duration); duration > 0 {
+			rendition.Bitrate = rendition.Size * 8 / duration
+			rendition.Duration = float64(duration)
+		}
+
+		return rendition, nil
+	}
+
+	return nil, nil
+}
+
+//elasticStatus maps an Elastic Transcoder job status (Submitted, Progressing, Complete, Canceled or Error) onto a TranscodingStatus
+func elasticStatus(status string) cohesioned.TranscodingStatus {
+	switch status {
+	case "Submitted":
+		return cohesioned.TranscodingPending
+	case "Progressing":
+		return cohesioned.TranscodingRunning
+	case "Complete":
+		return cohesioned.TranscodingComplete
+	default:
+		return cohesioned.TranscodingFailed
+	}
+}
+
+//StubTranscoder completes every job as soon as it is submitted, using the original file as the rendition.
+//It needs no network access, so uploads can be exercised offline and in tests
+type StubTranscoder struct{}
+
+func (t *StubTranscoder) Submit(v *cohesioned.Video, preset config.TranscodingPreset) (*cohesioned.TranscodingJob, error) {
+	if preset.HLS {
+		return nil, errors.New("the stub transcoder can't write HLS playlists")
+	}
+
+	return &cohesioned.TranscodingJob{
+		VideoID:    v.ID,
+		Preset:     preset.Name,
+		ExternalID: fmt.Sprintf("stub-%d-%s", v.ID, preset.Name),
+		Status:     cohesioned.TranscodingComplete,
+		OutputKey:  v.StorageObjectName,
+	}, nil
+}
+
+//Refresh returns a rendition for the original file. Its size and bitrate aren't known
+func (t *StubTranscoder) Refresh(job *cohesioned.TranscodingJob) (*cohesioned.Rendition, error) {
+	return &cohesioned.Rendition{
+		VideoID:   job.VideoID,
+		Name:      job.Preset,
+		ObjectKey: job.OutputKey,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package video
+
+import (
+	"testing"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/config"
+)
+
+func TestStubTranscoderUsesTheOriginalFile(t *testing.T) {
+	v := &cohesioned.Video{ID: 4, StorageObjectName: "4-video.mp4"}
+
+	job, err := new(StubTranscoder).Submit(v, config.TranscodingPreset{Name: "480p-16x9"})
+	if err != nil {
+		t.Fatalf("Unexpected error submitting job: %v", err)
+	}
+
+	if job.Status != cohesioned.TranscodingComplete || job.OutputKey != v.StorageObjectName || job.VideoID != v.ID {
+		t.Errorf("expected a complete job for the original file but got %v", job)
+	}
+}
+
+func TestElasticStatus(t *testing.T) {
+	expected := map[string]cohesioned.TranscodingStatus{
+		"Submitted":   cohesioned.TranscodingPending,
+		"Progressing": cohesioned.TranscodingRunning,
+		"Complete":    cohesioned.TranscodingComplete,
+		"Canceled":    cohesioned.TranscodingFailed,
+		"Error":       cohesioned.TranscodingFailed,
+	}
+
+	for status, want := range expected {
+		if got := elasticStatus(status); got != want {
+			t.Errorf("expected %s to be %s but got %s", status, want, got)
+		}
+	}
+}
+
+func TestStubTranscoderRefreshReturnsTheOriginalFileAsARendition(t *testing.T) {
+	job := &cohesioned.TranscodingJob{VideoID: 4, Preset: "480p-16x9", Status: cohesioned.TranscodingComplete, OutputKey: "4-video.mp4"}
+
+	rendition, err := new(StubTranscoder).Refresh(job)
+	if err != nil {
+		t.Fatalf("Unexpected error refreshing job: %v", err)
+	}
+
+	if rendition == nil || rendition.VideoID != job.VideoID || rendition.Name != job.Preset || rendition.ObjectKey != job.OutputKey {
+		t.Errorf("expected a rendition of the original file but got %v", rendition)
+	}
+}
@@ -0,0 +1,217 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/storage"
+)
+
+const (
+	//minPartSize is the smallest part of a multipart upload, matching the part size used when uploading through the API
+	minPartSize = 10 * 1024 * 1024
+	//maxParts is the most parts S3 allows in a multipart upload
+	maxParts = 10000
+)
+
+var (
+	//ErrFileNotUploaded is returned when completing an upload before the file is in storage
+	ErrFileNotUploaded = errors.New("the file has not been uploaded")
+	//ErrInvalidObjectName is returned for an upload to an object that isn't one of the video's upload targets
+	ErrInvalidObjectName = errors.New("the object name is not an upload target of the video")
+)
+
+//UploadTarget is where a client uploads a video's file to directly, without going through the API.
+//Single uploads PUT the whole file to URL with Headers. Multipart uploads PUT each PartSize chunk of the file to the url of its part and keep the ETag header of each response to complete the upload with
+type UploadTarget struct {
+	ObjectName string            `json:"object_name"`
+	Method     string            `json:"method"`
+	URL        string            `json:"url,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	UploadID   string            `json:"upload_id,omitempty"`
+	PartSize   int64             `json:"part_size,omitempty"`
+	Parts      []*UploadPart     `json:"parts,omitempty"`
+	Expires    time.Time         `json:"expires"`
+}
+
+type UploadPart struct {
+	PartNumber int64  `json:"part_number"`
+	URL        string `json:"url"`
+}
+
+//CompletedUpload is sent once the file has been uploaded to the ObjectName of its UploadTarget. UploadID and Parts are only needed for multipart uploads
+type CompletedUpload struct {
+	ObjectName string                   `json:"object_name"`
+	UploadID   string                   `json:"upload_id,omitempty"`
+	Parts      []*storage.CompletedPart `json:"parts,omitempty"`
+}
+
+//objectName is a new key to store the video's file under. Every upload gets its own key, so a replacement file is staged without touching the file in use and only swapped in once the video is saved
+//Slashes in the file name are replaced so the key stays in the top level of the bucket, where isObjectOf looks for it
+func objectName(video *cohesioned.Video) string {
+	fileName := strings.NewReplacer("/", "-", "\\", "-").Replace(video.FileName)
+	return fmt.Sprintf("%d-%s-%s", video.ID, strconv.FormatInt(time.Now().UnixNano(), 36), fileName)
+}
+
+//isObjectOf returns true if key is one the video's file could have been uploaded to
+func isObjectOf(video *cohesioned.Video, key string) bool {
+	return strings.HasPrefix(key, fmt.Sprintf("%d-", video.ID)) && !strings.Contains(key, "/")
+}
+
+//partSize splits a file of fileSize into parts of at least minPartSize, growing them for files too large to fit in maxParts
+func partSize(fileSize int64) int64 {
+	size := int64(minPartSize)
+	if fileSize > size*maxParts {
+		size = (fileSize + maxParts - 1) / maxParts
+	}
+
+	return size
+}
+
+//PresignUpload returns a url the video's file can be PUT to directly
+func (s *adminService) PresignUpload(video *cohesioned.Video, contentType string) (*UploadTarget, error) {
+	bucket, key := s.cfg.GetVideoBucket(), objectName(video)
+	ttl := s.cfg.GetStorageConfig().UploadURLTTL
+
+	signedURL, err := s.store.SignedPutURL(bucket, key, contentType, ttl)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to sign upload url: %v", err)
+	}
+
+	target := &UploadTarget{
+		ObjectName: key,
+		Method:     http.MethodPut,
+		URL:        signedURL,
+		Expires:    time.Now().Add(ttl),
+	}
+
+	//the content type is part of the signature, so the upload must send the same one
+	if len(contentType) > 0 {
+		target.Headers = map[string]string{"Content-Type": contentType}
+	}
+
+	return target, nil
+}
+
+//StartMultipartUpload starts uploading the video's file in parts, returning a url for each part of a file of fileSize
+func (s *adminService) StartMultipartUpload(video *cohesioned.Video, contentType string, fileSize int64) (*UploadTarget, error) {
+	bucket, key := s.cfg.GetVideoBucket(), objectName(video)
+	ttl := s.cfg.GetStorageConfig().UploadURLTTL
+
+	uploadID, err := s.store.CreateMultipartUpload(bucket, key, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	target := &UploadTarget{
+		ObjectName: key,
+		Method:     http.MethodPut,
+		UploadID:   uploadID,
+		PartSize:   partSize(fileSize),
+		Expires:    time.Now().Add(ttl),
+	}
+
+	parts := (fileSize + target.PartSize - 1) / target.PartSize
+	for n := int64(1); n <= parts; n++ {
+		partURL, err := s.store.SignedPartURL(bucket, key, uploadID, n, ttl)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign url for part %d: %v", n, err)
+		}
+
+		target.Parts = append(target.Parts, &UploadPart{PartNumber: n, URL: partURL})
+	}
+
+	return target, nil
+}
+
+//CompleteUpload checks the video's file is in storage and validates it as SetFile does, reading the whole file back to checksum it.
+//It then records the file's size, type and checksum, swaps it in for the video's current file and starts post-processing it. A file that fails validation is deleted
+func (s *adminService) CompleteUpload(ctx context.Context, video *cohesioned.Video, upload *CompletedUpload) error {
+	bucket, key := s.cfg.GetVideoBucket(), upload.ObjectName
+	if !isObjectOf(video, key) {
+		return ErrInvalidObjectName
+	}
+
+	if len(upload.UploadID) > 0 {
+		if err := s.store.CompleteMultipartUpload(bucket, key, upload.UploadID, upload.Parts); err != nil {
+			return err
+		}
+	}
+
+	info, err := s.store.Head(bucket, key)
+	if err == storage.ErrNotFound {
+		return ErrFileNotUploaded
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkSize(video, info.Size); err != nil {
+		s.discardObject(bucket, key)
+		return err
+	}
+
+	fileType, checksum, err := s.inspect(bucket, key)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkType(video, fileType); err != nil {
+		s.discardObject(bucket, key)
+		return err
+	}
+
+	if err := s.checkDuplicate(video, checksum); err != nil {
+		s.discardObject(bucket, key)
+		return err
+	}
+
+	prevBucket, prevKey := video.StorageBucket, video.StorageObjectName
+	video.StorageBucket = bucket
+	video.StorageObjectName = key
+	video.FileSize = info.Size
+	video.FileType = fileType
+	video.Checksum = checksum
+
+	return s.swapFile(ctx, video, prevBucket, prevKey)
+}
+
+//inspect reads an uploaded object to sniff its type and checksum it
+func (s *adminService) inspect(bucket, key string) (string, string, error) {
+	obj, err := s.store.Get(bucket, key)
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to read %s: %v", key, err)
+	}
+
+	defer obj.Close()
+
+	fileType, body, err := sniff(obj)
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to read %s: %v", key, err)
+	}
+
+	check := newFileCheck(body, 0)
+	if _, err := io.Copy(ioutil.Discard, check); err != nil {
+		return "", "", fmt.Errorf("Failed to read %s: %v", key, err)
+	}
+
+	return fileType, check.Checksum(), nil
+}
+
+//AbortUpload discards the parts of a multipart upload of the video's file to objectName
+func (s *adminService) AbortUpload(video *cohesioned.Video, objectName, uploadID string) error {
+	if !isObjectOf(video, objectName) {
+		return ErrInvalidObjectName
+	}
+
+	return s.store.AbortMultipartUpload(s.cfg.GetVideoBucket(), objectName, uploadID)
+}
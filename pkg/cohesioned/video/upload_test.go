@@ -0,0 +1,235 @@
+package video_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/storage"
+	"github.com/cohesion-education/api/pkg/cohesioned/video"
+)
+
+//mp4Header is the start of an mp4 file's ftyp box
+var mp4Header = []byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom")
+
+func newUploadService(store storage.BlobStore) video.AdminService {
+	return newStorageService(new(fakes.FakeVideoRepo), store)
+}
+
+func newStorageService(repo video.Repo, store storage.BlobStore) video.AdminService {
+	cfg := new(fakes.FakeAwsConfig)
+	cfg.GetVideoBucketReturns("videos")
+
+	return video.NewService(repo, new(fakes.FakeChapterRepo), new(fakes.FakeFAQRepo), new(fakes.FakeWatchRepo), new(fakes.FakeRelatedRepo), new(fakes.FakeTaxonomyRepo), new(fakes.FakeStandardRepo), new(fakes.FakeTeacherRepo), new(fakes.FakeNotificationRepo), store, nil, cfg)
+}
+
+func TestStartMultipartUploadSignsEachPart(t *testing.T) {
+	store := new(fakes.FakeBlobStore)
+	store.CreateMultipartUploadReturns("upload-1", nil)
+	store.SignedURLReturns("http://fake-part-url", nil)
+
+	svc := newUploadService(store)
+	target, err := svc.StartMultipartUpload(fakes.FakeVideo(), "video/mp4", 25*1024*1024)
+	if err != nil {
+		t.Fatalf("Unexpected error starting upload: %v", err)
+	}
+
+	if target.UploadID != "upload-1" || !strings.HasPrefix(target.ObjectName, "1-") || !strings.HasSuffix(target.ObjectName, "-test.mp4") {
+		t.Errorf("unexpected upload target %v", target)
+	}
+
+	if target.PartSize != 10*1024*1024 || len(target.Parts) != 3 || target.Parts[2].PartNumber != 3 {
+		t.Errorf("expected 3 parts of 10MB but got %d parts of %d", len(target.Parts), target.PartSize)
+	}
+}
+
+func TestStartMultipartUploadGrowsPartsForHugeFiles(t *testing.T) {
+	store := new(fakes.FakeBlobStore)
+	svc := newUploadService(store)
+
+	//200GB doesn't fit in 10,000 parts of 10MB
+	target, err := svc.StartMultipartUpload(fakes.FakeVideo(), "video/mp4", 200*1024*1024*1024)
+	if err != nil {
+		t.Fatalf("Unexpected error starting upload: %v", err)
+	}
+
+	if len(target.Parts) > 10000 {
+		t.Errorf("expected no more than 10000 parts but got %d", len(target.Parts))
+	}
+}
+
+func TestCompleteUploadRecordsTheUploadedFile(t *testing.T) {
+	store := new(fakes.FakeBlobStore)
+	store.HeadReturns(&storage.ObjectInfo{Key: "1-abc-test.mp4", Size: 1234, ContentType: "video/mp4"}, nil)
+	store.Put("videos", "1-abc-test.mp4", bytes.NewReader(mp4Header), "video/mp4")
+
+	svc := newUploadService(store)
+	v := fakes.FakeVideo()
+	parts := []*storage.CompletedPart{{PartNumber: 1, ETag: "etag-1"}}
+
+	ctx := context.WithValue(context.Background(), cohesioned.CurrentUserKey, fakes.FakeProfile())
+	if err := svc.CompleteUpload(ctx, v, &video.CompletedUpload{ObjectName: "1-abc-test.mp4", UploadID: "upload-1", Parts: parts}); err != nil {
+		t.Fatalf("Unexpected error completing upload: %v", err)
+	}
+
+	if len(store.Completed) != 1 {
+		t.Errorf("expected the multipart upload to be completed")
+	}
+
+	if v.StorageBucket != "videos" || v.StorageObjectName != "1-abc-test.mp4" || v.FileSize != 1234 || v.FileType != "video/mp4" {
+		t.Errorf("the uploaded file was not recorded on the video: %v", v)
+	}
+}
+
+func TestCompleteUploadBeforeTheFileIsUploaded(t *testing.T) {
+	store := new(fakes.FakeBlobStore)
+	store.HeadReturns(nil, storage.ErrNotFound)
+
+	svc := newUploadService(store)
+	if err := svc.CompleteUpload(context.Background(), fakes.FakeVideo(), &video.CompletedUpload{ObjectName: "1-abc-test.mp4"}); err != video.ErrFileNotUploaded {
+		t.Errorf("expected ErrFileNotUploaded but got %v", err)
+	}
+}
+
+func TestCompleteUploadRejectsAnotherVideosObject(t *testing.T) {
+	store := new(fakes.FakeBlobStore)
+	store.HeadReturns(&storage.ObjectInfo{Key: "2-abc-test.mp4", Size: 1234}, nil)
+
+	svc := newUploadService(store)
+	for _, name := range []string{"2-abc-test.mp4", "1-abc/../2-test.mp4", "11-abc-test.mp4"} {
+		if err := svc.CompleteUpload(context.Background(), fakes.FakeVideo(), &video.CompletedUpload{ObjectName: name}); err != video.ErrInvalidObjectName {
+			t.Errorf("expected %s to be rejected with ErrInvalidObjectName but got %v", name, err)
+		}
+	}
+}
+
+func TestPresignUploadStagesEachUploadUnderItsOwnKey(t *testing.T) {
+	svc := newUploadService(new(fakes.FakeBlobStore))
+
+	first, err := svc.PresignUpload(fakes.FakeVideo(), "video/mp4")
+	if err != nil {
+		t.Fatalf("Unexpected error presigning upload: %v", err)
+	}
+
+	second, err := svc.PresignUpload(fakes.FakeVideo(), "video/mp4")
+	if err != nil {
+		t.Fatalf("Unexpected error presigning upload: %v", err)
+	}
+
+	if first.ObjectName == second.ObjectName {
+		t.Errorf("expected each upload to get its own object name but both were %s", first.ObjectName)
+	}
+}
+
+func TestPresignUploadReplacesSlashesInTheFileName(t *testing.T) {
+	store := new(fakes.FakeBlobStore)
+	svc := newUploadService(store)
+	v := fakes.FakeVideo()
+	v.FileName = `lessons/week 1\fractions.mp4`
+
+	target, err := svc.PresignUpload(v, "video/mp4")
+	if err != nil {
+		t.Fatalf("Unexpected error presigning upload: %v", err)
+	}
+
+	if strings.ContainsAny(target.ObjectName, `/\`) || !strings.HasSuffix(target.ObjectName, "-lessons-week 1-fractions.mp4") {
+		t.Fatalf("expected the slashes to be replaced but got %s", target.ObjectName)
+	}
+
+	store.HeadReturns(&storage.ObjectInfo{Key: target.ObjectName, Size: 1234, ContentType: "video/mp4"}, nil)
+	store.Put("videos", target.ObjectName, bytes.NewReader(mp4Header), "video/mp4")
+
+	ctx := context.WithValue(context.Background(), cohesioned.CurrentUserKey, fakes.FakeProfile())
+	if err := svc.CompleteUpload(ctx, v, &video.CompletedUpload{ObjectName: target.ObjectName}); err != nil {
+		t.Errorf("Unexpected error completing upload to %s: %v", target.ObjectName, err)
+	}
+}
+
+func TestSetFileReplacesThePreviousFile(t *testing.T) {
+	repo := new(fakes.FakeVideoRepo)
+	repo.Renditions = []*cohesioned.Rendition{{Name: "480p", ObjectKey: "transcoded/480p-1-old-test.mp4"}}
+	repo.Jobs = []*cohesioned.TranscodingJob{{Preset: "480p", Status: cohesioned.TranscodingRunning}}
+
+	store := new(fakes.FakeBlobStore)
+	store.ListReturns([]*storage.ObjectInfo{
+		{Key: "thumbnails/1-old-test.mp4-192x108-00001.png"},
+		{Key: "thumbnails/1-other-test.mp4-192x108-00001.png"},
+	}, nil)
+
+	svc := newStorageService(repo, store)
+	v := fakes.FakeVideo()
+	v.StorageBucket = "videos"
+	v.StorageObjectName = "1-old-test.mp4"
+
+	ctx := context.WithValue(context.Background(), cohesioned.CurrentUserKey, fakes.FakeProfile())
+	if err := svc.SetFile(ctx, strings.NewReader("new file"), v); err != nil {
+		t.Fatalf("Unexpected error setting file: %v", err)
+	}
+
+	if v.StorageObjectName == "1-old-test.mp4" || string(store.Stored[v.StorageObjectName]) != "new file" {
+		t.Errorf("expected the new file to be stored under a new key but got %s", v.StorageObjectName)
+	}
+
+	expected := []string{"1-old-test.mp4", "transcoded/480p-1-old-test.mp4", "thumbnails/1-old-test.mp4-192x108-00001.png"}
+	if strings.Join(store.Deleted, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected %v to be deleted but got %v", expected, store.Deleted)
+	}
+
+	if len(repo.Renditions) != 0 {
+		t.Errorf("expected the renditions of the previous file to be deleted but got %v", repo.Renditions)
+	}
+
+	if repo.Jobs[0].Status != cohesioned.TranscodingFailed {
+		t.Errorf("expected the unfinished transcoding job of the previous file to be failed but got %s", repo.Jobs[0].Status)
+	}
+}
+
+func TestSetFileKeepsThePreviousFileWhenTheVideoIsNotSaved(t *testing.T) {
+	repo := new(fakes.FakeVideoRepo)
+	repo.UpdateReturns(errors.New("db unavailable"))
+
+	store := new(fakes.FakeBlobStore)
+	svc := newStorageService(repo, store)
+	v := fakes.FakeVideo()
+	v.StorageBucket = "videos"
+	v.StorageObjectName = "1-old-test.mp4"
+
+	ctx := context.WithValue(context.Background(), cohesioned.CurrentUserKey, fakes.FakeProfile())
+	if err := svc.SetFile(ctx, strings.NewReader("new file"), v); err == nil {
+		t.Fatal("expected an error when the video can't be saved")
+	}
+
+	if v.StorageObjectName != "1-old-test.mp4" {
+		t.Errorf("expected the video to keep its previous file but got %s", v.StorageObjectName)
+	}
+
+	if len(store.Deleted) != 1 || store.Deleted[0] == "1-old-test.mp4" {
+		t.Errorf("expected only the new file to be deleted but got %v", store.Deleted)
+	}
+}
+
+func TestDeleteRemovesTheFileAndItsRenditions(t *testing.T) {
+	v := fakes.FakeVideo()
+	v.StorageBucket = "videos"
+	v.StorageObjectName = "1-abc-test.mp4"
+
+	repo := new(fakes.FakeVideoRepo)
+	repo.GetReturns(v, nil)
+	repo.Renditions = []*cohesioned.Rendition{{Name: "480p", ObjectKey: "transcoded/480p-1-abc-test.mp4"}}
+
+	store := new(fakes.FakeBlobStore)
+	store.ListReturns([]*storage.ObjectInfo{{Key: "thumbnails/1-abc-test.mp4-192x108-00001.png"}}, nil)
+
+	svc := video.NewService(repo, new(fakes.FakeChapterRepo), new(fakes.FakeFAQRepo), new(fakes.FakeWatchRepo), new(fakes.FakeRelatedRepo), new(fakes.FakeTaxonomyRepo), new(fakes.FakeStandardRepo), new(fakes.FakeTeacherRepo), new(fakes.FakeNotificationRepo), store, nil, new(fakes.FakeAwsConfig))
+	if err := svc.Delete(v.ID); err != nil {
+		t.Fatalf("Unexpected error deleting video: %v", err)
+	}
+
+	if len(store.Deleted) != 3 {
+		t.Errorf("expected the file, its rendition and thumbnail to be deleted but got %v", store.Deleted)
+	}
+}
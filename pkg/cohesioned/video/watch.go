@@ -0,0 +1,71 @@
+package video
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cohesion-education/api/pkg/cohesioned"
+)
+
+//ErrInvalidWatchEvent is returned when a watch event can't be recorded. The reasons are added to the event's ValidationErrors
+var ErrInvalidWatchEvent = errors.New("the watch event is not valid")
+
+//RecordWatchEvent saves an event sent by the player and moves the viewer's progress through the video to the event's position.
+//A complete event marks the video as completed and resets the position, so it starts over the next time it is played
+func (s *adminService) RecordWatchEvent(video *cohesioned.Video, event *cohesioned.WatchEvent) (*cohesioned.WatchProgress, error) {
+	event.ID = 0
+	event.VideoID = video.ID
+	if !event.Validate() {
+		return nil, ErrInvalidWatchEvent
+	}
+
+	if event.StudentID > 0 {
+		ok, err := s.watchRepo.IsParentOf(event.UserID, event.StudentID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			event.AddValidationError("student_id", fmt.Sprintf("student %d is not one of your students", event.StudentID))
+			return nil, ErrInvalidWatchEvent
+		}
+	}
+
+	//players can report a position slightly past the end of the file
+	if video.Duration > 0 && event.Position > video.Duration {
+		event.Position = video.Duration
+	}
+
+	event.Created = time.Now()
+	id, err := s.watchRepo.SaveWatchEvent(event)
+	if err != nil {
+		return nil, err
+	}
+
+	event.ID = id
+	progress := &cohesioned.WatchProgress{
+		VideoID:   video.ID,
+		UserID:    event.UserID,
+		StudentID: event.StudentID,
+		Position:  event.Position,
+		Completed: event.Event == cohesioned.PlaybackComplete,
+		Created:   event.Created,
+		Updated:   event.Created,
+	}
+
+	if progress.Completed {
+		progress.Position = 0
+	}
+
+	if err := s.watchRepo.SaveWatchProgress(progress); err != nil {
+		return nil, err
+	}
+
+	return s.watchRepo.GetWatchProgress(video.ID, event.UserID, event.StudentID)
+}
+
+//WatchProgress returns where the profile, or one of its students, is up to in the video, or nil if they haven't started it. studentID is 0 for the profile itself
+func (s *adminService) WatchProgress(videoID, userID, studentID int64) (*cohesioned.WatchProgress, error) {
+	return s.watchRepo.GetWatchProgress(videoID, userID, studentID)
+}
@@ -0,0 +1,107 @@
+package video_test
+
+import (
+	"testing"
+
+	"github.com/cohesion-education/api/fakes"
+	"github.com/cohesion-education/api/pkg/cohesioned"
+	"github.com/cohesion-education/api/pkg/cohesioned/video"
+)
+
+func newWatchService(repo video.WatchRepo) video.AdminService {
+	return video.NewService(new(fakes.FakeVideoRepo), new(fakes.FakeChapterRepo), new(fakes.FakeFAQRepo), repo, new(fakes.FakeRelatedRepo), new(fakes.FakeTaxonomyRepo), new(fakes.FakeStandardRepo), new(fakes.FakeTeacherRepo), new(fakes.FakeNotificationRepo), new(fakes.FakeBlobStore), nil, new(fakes.FakeAwsConfig))
+}
+
+func TestRecordWatchEventMovesTheResumePosition(t *testing.T) {
+	repo := new(fakes.FakeWatchRepo)
+	svc := newWatchService(repo)
+	v := fakes.FakeVideo()
+	v.Duration = 120
+
+	progress, err := svc.RecordWatchEvent(v, &cohesioned.WatchEvent{UserID: 1, Event: cohesioned.PlaybackPause, Position: 42.5})
+	if err != nil {
+		t.Fatalf("Unexpected error recording watch event: %v", err)
+	}
+
+	if progress == nil || progress.Position != 42.5 || progress.Completed {
+		t.Errorf("expected to resume at 42.5 seconds but got %v", progress)
+	}
+
+	if len(repo.WatchEvents) != 1 || repo.WatchEvents[0].VideoID != v.ID || repo.WatchEvents[0].Created.IsZero() {
+		t.Errorf("expected the event to be saved but got %v", repo.WatchEvents)
+	}
+
+	if progress, _ := svc.RecordWatchEvent(v, &cohesioned.WatchEvent{UserID: 1, Event: cohesioned.PlaybackHeartbeat, Position: 125}); progress.Position != 120 {
+		t.Errorf("expected a position past the end to be clamped to the duration but got %v", progress.Position)
+	}
+}
+
+func TestRecordWatchEventCompletesTheVideo(t *testing.T) {
+	repo := new(fakes.FakeWatchRepo)
+	svc := newWatchService(repo)
+	v := fakes.FakeVideo()
+
+	progress, err := svc.RecordWatchEvent(v, &cohesioned.WatchEvent{UserID: 1, Event: cohesioned.PlaybackComplete, Position: 120})
+	if err != nil {
+		t.Fatalf("Unexpected error recording watch event: %v", err)
+	}
+
+	if !progress.Completed || progress.Position != 0 {
+		t.Errorf("expected the video to be completed and start over but got %v", progress)
+	}
+
+	progress, _ = svc.RecordWatchEvent(v, &cohesioned.WatchEvent{UserID: 1, Event: cohesioned.PlaybackPause, Position: 30})
+	if !progress.Completed || progress.Position != 30 {
+		t.Errorf("expected the video to stay completed when it is watched again but got %v", progress)
+	}
+}
+
+func TestRecordWatchEventForAStudent(t *testing.T) {
+	repo := new(fakes.FakeWatchRepo)
+	repo.Students = map[int64]int64{2: 1}
+	svc := newWatchService(repo)
+	v := fakes.FakeVideo()
+
+	if _, err := svc.RecordWatchEvent(v, &cohesioned.WatchEvent{UserID: 1, StudentID: 2, Event: cohesioned.PlaybackPlay, Position: 10}); err != nil {
+		t.Fatalf("Unexpected error recording watch event: %v", err)
+	}
+
+	if progress, _ := svc.WatchProgress(v.ID, 1, 2); progress == nil || progress.Position != 10 {
+		t.Errorf("expected the student's progress to be saved but got %v", progress)
+	}
+
+	if progress, _ := svc.WatchProgress(v.ID, 1, 0); progress != nil {
+		t.Errorf("the student's progress should be kept apart from the parent's but got %v", progress)
+	}
+
+	event := &cohesioned.WatchEvent{UserID: 3, StudentID: 2, Event: cohesioned.PlaybackPlay, Position: 10}
+	if _, err := svc.RecordWatchEvent(v, event); err != video.ErrInvalidWatchEvent {
+		t.Fatalf("expected ErrInvalidWatchEvent for another parent's student but got %v", err)
+	}
+
+	if len(event.ValidationErrors) != 1 || event.ValidationErrors[0].Field != "student_id" {
+		t.Errorf("expected a validation error for the student but got %v", event.ValidationErrors)
+	}
+}
+
+func TestRecordWatchEventRejectsInvalidEvents(t *testing.T) {
+	events := map[string]*cohesioned.WatchEvent{
+		"event":    {UserID: 1, Event: "rewind", Position: 10},
+		"position": {UserID: 1, Event: cohesioned.PlaybackSeek, Position: -1},
+	}
+
+	for field, event := range events {
+		repo := new(fakes.FakeWatchRepo)
+		if _, err := newWatchService(repo).RecordWatchEvent(fakes.FakeVideo(), event); err != video.ErrInvalidWatchEvent {
+			t.Errorf("expected ErrInvalidWatchEvent for an invalid %s but got %v", field, err)
+		}
+
+		if len(event.ValidationErrors) != 1 || event.ValidationErrors[0].Field != field {
+			t.Errorf("expected a validation error for the %s but got %v", field, event.ValidationErrors)
+		}
+
+		if len(repo.WatchEvents) != 0 {
+			t.Errorf("the invalid event should not be saved but got %v", repo.WatchEvents)
+		}
+	}
+}
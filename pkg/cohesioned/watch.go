@@ -0,0 +1,80 @@
+package cohesioned
+
+import (
+	"fmt"
+	"time"
+)
+
+//PlaybackEvent is what the player was doing when a WatchEvent was sent
+type PlaybackEvent string
+
+const (
+	PlaybackPlay  PlaybackEvent = "play"
+	PlaybackPause PlaybackEvent = "pause"
+	PlaybackSeek  PlaybackEvent = "seek"
+	//PlaybackHeartbeat is sent periodically while the video plays
+	PlaybackHeartbeat PlaybackEvent = "heartbeat"
+	PlaybackComplete  PlaybackEvent = "complete"
+)
+
+//PlaybackEvents returns every PlaybackEvent the player can send
+func PlaybackEvents() []PlaybackEvent {
+	return []PlaybackEvent{PlaybackPlay, PlaybackPause, PlaybackSeek, PlaybackHeartbeat, PlaybackComplete}
+}
+
+//WatchEvent records a profile, or one of its students, watching a video. Position is how far into the video the player was in seconds
+type WatchEvent struct {
+	Validatable
+	ID      int64 `json:"id"`
+	VideoID int64 `json:"video_id"`
+	UserID  int64 `json:"user_id"`
+	//StudentID is the student the profile was watching for, or 0 if the profile watched for itself
+	StudentID int64         `json:"student_id,omitempty"`
+	Event     PlaybackEvent `json:"event"`
+	Position  float64       `json:"position"`
+	Created   time.Time     `json:"created"`
+}
+
+//Validate checks that the event is one the player sends and that its position isn't negative
+func (e *WatchEvent) Validate() bool {
+	known := false
+	for _, event := range PlaybackEvents() {
+		if e.Event == event {
+			known = true
+		}
+	}
+
+	if !known {
+		e.AddValidationError("event", fmt.Sprintf("%s is not one of %v", e.Event, PlaybackEvents()))
+	}
+
+	if e.Position < 0 {
+		e.AddValidationError("position", "position can't be negative")
+	}
+
+	if e.StudentID < 0 {
+		e.AddValidationError("student_id", "student_id can't be negative")
+	}
+
+	return len(e.ValidationErrors) == 0
+}
+
+//WatchProgress is where a profile, or one of its students, is up to in a video. Position is where playback should resume, in seconds
+type WatchProgress struct {
+	VideoID   int64     `json:"video_id"`
+	UserID    int64     `json:"user_id"`
+	StudentID int64     `json:"student_id,omitempty"`
+	Position  float64   `json:"position"`
+	Completed bool      `json:"completed"`
+	Created   time.Time `json:"created"`
+	Updated   time.Time `json:"updated"`
+}
+
+//CompletionRate is how many of the profiles and students that started a video went on to finish it
+type CompletionRate struct {
+	VideoID     int64   `json:"video_id"`
+	Title       string  `json:"title"`
+	Viewers     int64   `json:"viewers"`
+	Completions int64   `json:"completions"`
+	Rate        float64 `json:"completion_rate"`
+}
@@ -39,10 +39,33 @@ func SetupDB(db *sql.DB) error {
 
 func CleanupDB(db *sql.DB) error {
 	cleanupSql := `
+		delete from quiz_attempt_answer;
+		delete from quiz_attempt;
+		delete from quiz_question;
+		delete from quiz;
+		delete from video_upload_chunk;
+		delete from video_upload;
+		delete from notification;
+		delete from video_faq;
+		delete from video_teacher;
+		delete from video_related;
+		delete from video_watch_progress;
+		delete from video_watch_event;
+		delete from video_chapter;
+		delete from video_caption;
+		delete from video_thumbnail;
+		delete from video_rendition;
+		delete from transcoding_job;
+		delete from video_tag_map;
+		delete from video_tag;
 		delete from video;
+		delete from teacher;
+		delete from standard;
 		delete from taxonomy where parent_id is not null;
 		delete from taxonomy;
 		delete from student;
+		delete from api_key;
+		delete from impersonation_audit;
 		delete from user;
 		alter table video auto_increment = 1;
 		alter table taxonomy auto_increment = 1;